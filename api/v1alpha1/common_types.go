@@ -69,4 +69,8 @@ const (
 	ConditionInvalid ConditionType = "Invalid"
 	// ConditionComputeApiReady is set when the compute API is not yet ready
 	ConditionComputeApiReady ConditionType = "ComputeApiReady"
+	// ConditionProjectCreated is set when the GCP operation creating the project is pending, failed or succeeded
+	ConditionProjectCreated ConditionType = "ProjectCreated"
+	// ConditionDrifted is true when the GCP project doesn't match its configuration anymore
+	ConditionDrifted ConditionType = "Drifted"
 )
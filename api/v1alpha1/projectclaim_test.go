@@ -105,6 +105,65 @@ func TestProjectClaimValidate(t *testing.T) {
 			},
 			expectedErr: nil,
 		},
+		{
+			name: "valid workload identity claim",
+			claim: ProjectClaim{
+				ObjectMeta: metav1.ObjectMeta{Namespace: claimNamespace},
+				Spec: ProjectClaimSpec{
+					GCPCredentialSecret: NamespacedName{Namespace: claimNamespace, Name: "creds"},
+					CredentialMode:      CredentialModeWorkloadIdentityFederation,
+					WorkloadIdentity:    &WorkloadIdentityConfig{IssuerURI: "https://issuer.example.com"},
+				},
+			},
+			expectedErr: nil,
+		},
+		{
+			name: "invalid workload identity claim without issuer",
+			claim: ProjectClaim{
+				ObjectMeta: metav1.ObjectMeta{Namespace: claimNamespace},
+				Spec: ProjectClaimSpec{
+					GCPCredentialSecret: NamespacedName{Namespace: claimNamespace, Name: "creds"},
+					CredentialMode:      CredentialModeWorkloadIdentityFederation,
+				},
+			},
+			expectedErr: ErrWorkloadIdentityIssuerMissing,
+		},
+		{
+			name: "valid adopting claim",
+			claim: ProjectClaim{
+				ObjectMeta: metav1.ObjectMeta{Namespace: claimNamespace},
+				Spec: ProjectClaimSpec{
+					GCPCredentialSecret: NamespacedName{Namespace: claimNamespace, Name: "creds"},
+					GCPProjectID:        "existing-project",
+					AdoptProject:        true,
+				},
+			},
+			expectedErr: nil,
+		},
+		{
+			name: "invalid adopting claim without project ID",
+			claim: ProjectClaim{
+				ObjectMeta: metav1.ObjectMeta{Namespace: claimNamespace},
+				Spec: ProjectClaimSpec{
+					GCPCredentialSecret: NamespacedName{Namespace: claimNamespace, Name: "creds"},
+					AdoptProject:        true,
+				},
+			},
+			expectedErr: ErrAdoptedProjectIDMissing,
+		},
+		{
+			name: "invalid adopting CCS claim",
+			claim: ProjectClaim{
+				ObjectMeta: metav1.ObjectMeta{Namespace: claimNamespace},
+				Spec: ProjectClaimSpec{
+					GCPCredentialSecret: NamespacedName{Namespace: claimNamespace, Name: "creds"},
+					GCPProjectID:        "customer-project",
+					CCS:                 true,
+					AdoptProject:        true,
+				},
+			},
+			expectedErr: ErrAdoptCCSProject,
+		},
 	}
 
 	for _, test := range tests {
@@ -116,3 +175,64 @@ func TestProjectClaimValidate(t *testing.T) {
 		})
 	}
 }
+
+func TestProjectClaimReferencesSecret(t *testing.T) {
+	claim := ProjectClaim{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "tenant-ns", Name: "claim"},
+		Spec: ProjectClaimSpec{
+			GCPCredentialSecret: NamespacedName{Name: "creds"},
+			CCSSecretRef:        NamespacedName{Namespace: "tenant-ns", Name: "ccs-secret"},
+		},
+	}
+
+	tests := []struct {
+		name      string
+		ccs       bool
+		namespace string
+		secret    string
+		expected  bool
+	}{
+		{name: "GCP credentials secret in the namespace of the claim", namespace: "tenant-ns", secret: "creds", expected: true},
+		{name: "GCP credentials secret in another namespace", namespace: "other-ns", secret: "creds", expected: false},
+		{name: "CCS secret of a non-CCS claim", namespace: "tenant-ns", secret: "ccs-secret", expected: false},
+		{name: "CCS secret of a CCS claim", ccs: true, namespace: "tenant-ns", secret: "ccs-secret", expected: true},
+		{name: "unrelated secret", ccs: true, namespace: "tenant-ns", secret: "other", expected: false},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			claim.Spec.CCS = test.ccs
+			if got := claim.ReferencesSecret(test.namespace, test.secret); got != test.expected {
+				t.Errorf("got %v, wanted %v", got, test.expected)
+			}
+		})
+	}
+}
+
+func TestProjectClaimDeletion(t *testing.T) {
+	tests := []struct {
+		name              string
+		deletionPolicy    DeletionPolicy
+		annotations       map[string]string
+		expectedPolicy    DeletionPolicy
+		expectedProtected bool
+	}{
+		{name: "defaults", expectedPolicy: DeletionPolicyDelete},
+		{name: "retained", deletionPolicy: DeletionPolicyRetain, expectedPolicy: DeletionPolicyRetain},
+		{name: "protected", annotations: map[string]string{DeletionProtectionAnnotation: "true"}, expectedPolicy: DeletionPolicyDelete, expectedProtected: true},
+		{name: "protection disabled", annotations: map[string]string{DeletionProtectionAnnotation: "false"}, expectedPolicy: DeletionPolicyDelete},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			claim := ProjectClaim{
+				ObjectMeta: metav1.ObjectMeta{Annotations: test.annotations},
+				Spec:       ProjectClaimSpec{DeletionPolicy: test.deletionPolicy},
+			}
+			if got := claim.GetDeletionPolicy(); got != test.expectedPolicy {
+				t.Errorf("got deletion policy %v, wanted %v", got, test.expectedPolicy)
+			}
+			if got := claim.IsDeletionProtected(); got != test.expectedProtected {
+				t.Errorf("got protected %v, wanted %v", got, test.expectedProtected)
+			}
+		})
+	}
+}
@@ -27,8 +27,41 @@ var (
 	ErrCCSSecretRefNamespaceMismatch = errors.New("ccsSecretRef.namespace must match the ProjectClaim namespace")
 	// ErrGCPCredentialSecretNamespaceMismatch is returned when GCPCredentialSecret.Namespace does not match the ProjectClaim namespace.
 	ErrGCPCredentialSecretNamespaceMismatch = errors.New("gcpCredentialSecret.namespace must match the ProjectClaim namespace")
+	// ErrWorkloadIdentityIssuerMissing is returned when the WorkloadIdentityFederation credential mode has no issuer to trust.
+	ErrWorkloadIdentityIssuerMissing = errors.New("workloadIdentity.issuerURI is required by the WorkloadIdentityFederation credential mode")
+	// ErrAdoptedProjectIDMissing is returned when a ProjectClaim adopts a project without naming it.
+	ErrAdoptedProjectIDMissing = errors.New("gcpProjectID is required to adopt a project")
+	// ErrAdoptCCSProject is returned when a CCS ProjectClaim adopts a project, CCS projects are never created by the operator.
+	ErrAdoptCCSProject = errors.New("CCS projects can't be adopted")
 )
 
+// CredentialMode selects the credentials the operator writes into the GCPCredentialSecret of a ProjectClaim
+type CredentialMode string
+
+const (
+	// CredentialModeServiceAccountKey writes a JSON key of the managed service account
+	CredentialModeServiceAccountKey CredentialMode = "ServiceAccountKey"
+	// CredentialModeWorkloadIdentityFederation writes an external_account credential config, which exchanges
+	// tokens of a trusted OIDC issuer for short-lived tokens of the managed service account
+	CredentialModeWorkloadIdentityFederation CredentialMode = "WorkloadIdentityFederation"
+)
+
+// DeletionPolicy decides what happens to the GCP project of a ProjectClaim once the ProjectClaim is deleted
+type DeletionPolicy string
+
+const (
+	// DeletionPolicyDelete deletes the GCP project
+	DeletionPolicyDelete DeletionPolicy = "Delete"
+	// DeletionPolicyRetain keeps the GCP project, but removes the IAM bindings and the service account of the operator
+	// and labels the project as orphaned
+	DeletionPolicyRetain DeletionPolicy = "Retain"
+	// DeletionPolicyOrphan leaves the GCP project and the credentials secret untouched
+	DeletionPolicyOrphan DeletionPolicy = "Orphan"
+)
+
+// DeletionProtectionAnnotation blocks the deletion of a ProjectClaim and its GCP project while it is set to "true"
+const DeletionProtectionAnnotation = "gcp.managed.openshift.io/deletion-protection"
+
 // ProjectClaimSpec defines the desired state of ProjectClaim
 // +k8s:openapi-gen=true
 type ProjectClaimSpec struct {
@@ -43,6 +76,59 @@ type ProjectClaimSpec struct {
 	CCSSecretRef      NamespacedName `json:"ccsSecretRef,omitempty"`
 	CCSProjectID      string         `json:"ccsProjectID,omitempty"`
 	SharedVPCAccess   bool           `json:"sharedVPCAccess,omitempty"`
+	// AdditionalAPIs are GCP service APIs to enable on the project on top of the operator's default list
+	// +listType=atomic
+	// +optional
+	AdditionalAPIs []string `json:"additionalAPIs,omitempty"`
+	// CredentialMode selects the credentials written into GCPCredentialSecret, ServiceAccountKey if unset
+	// +kubebuilder:validation:Enum=ServiceAccountKey;WorkloadIdentityFederation
+	// +optional
+	CredentialMode CredentialMode `json:"credentialMode,omitempty"`
+	// WorkloadIdentity configures the identity provider trusted by the WorkloadIdentityFederation credential mode
+	// +optional
+	WorkloadIdentity *WorkloadIdentityConfig `json:"workloadIdentity,omitempty"`
+	// DeletionPolicy decides what happens to the GCP project once the ProjectClaim is deleted, Delete if unset
+	// +kubebuilder:validation:Enum=Delete;Retain;Orphan
+	// +optional
+	DeletionPolicy DeletionPolicy `json:"deletionPolicy,omitempty"`
+	// RestoreDeletedProject restores the GCP project of GCPProjectID, or CCSProjectID of CCS claims, if it is pending deletion,
+	// e.g. when the ProjectClaim of a deleted project is recreated within the recovery period of GCP.
+	// GCPProjectID is used instead of a generated project ID then.
+	// +optional
+	RestoreDeletedProject bool `json:"restoreDeletedProject,omitempty"`
+	// AdoptProject takes over the existing GCP project of GCPProjectID in the parent folder instead of creating one.
+	// The project must be labeled with the name of the ProjectClaim as claim_name, and mustn't be billed to another billing account.
+	// It doesn't apply to CCS projects.
+	// +optional
+	AdoptProject bool `json:"adoptProject,omitempty"`
+	// ClusterID is the ID of the cluster using the project, the project is labeled with it as cluster_id
+	// +optional
+	ClusterID string `json:"clusterID,omitempty"`
+	// CostCenter is the cost center the project is labeled with as cost_center,
+	// it overrides the cost_center of the projectLabels of the operator configuration
+	// +optional
+	CostCenter string `json:"costCenter,omitempty"`
+}
+
+// WorkloadIdentityConfig describes the OIDC identity provider whose tokens are exchanged for credentials of the managed service account
+// +k8s:openapi-gen=true
+type WorkloadIdentityConfig struct {
+	// IssuerURI is the URL of the OIDC issuer, e.g. the service account issuer of the cluster using the project
+	IssuerURI string `json:"issuerURI"`
+	// AllowedAudiences are the accepted audiences of the exchanged tokens. If empty, tokens must be issued for
+	// the full resource name of the workload identity provider.
+	// +listType=atomic
+	// +optional
+	AllowedAudiences []string `json:"allowedAudiences,omitempty"`
+	// Subjects are the token subjects allowed to impersonate the managed service account,
+	// e.g. system:serviceaccount:openshift-machine-api:machine-api-controllers. Every subject of the issuer is allowed if empty.
+	// +listType=atomic
+	// +optional
+	Subjects []string `json:"subjects,omitempty"`
+	// TokenFile is the path of the OIDC token read by the consumers of the credential config,
+	// /var/run/secrets/openshift/serviceaccount/token if unset
+	// +optional
+	TokenFile string `json:"tokenFile,omitempty"`
 }
 
 // ProjectClaimStatus defines the observed state of ProjectClaim
@@ -73,6 +159,7 @@ const (
 // +kubebuilder:object:root=true
 // +k8s:openapi-gen=true
 // +kubebuilder:subresource:status
+// +kubebuilder:storageversion
 // +kubebuilder:printcolumn:name="State",type="string",JSONPath=".status.state",description="Status of the project claim"
 // +kubebuilder:printcolumn:name="GCPProjectID",type="string",JSONPath=".spec.gcpProjectID",description="ID of the GCP Project that has been created"
 // +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp",description="Age since the project claim was created"
@@ -108,9 +195,57 @@ func (p *ProjectClaim) Validate() error {
 		return ErrGCPCredentialSecretNamespaceMismatch
 	}
 
+	if p.Spec.CredentialMode == CredentialModeWorkloadIdentityFederation && (p.Spec.WorkloadIdentity == nil || p.Spec.WorkloadIdentity.IssuerURI == "") {
+		return ErrWorkloadIdentityIssuerMissing
+	}
+
+	if p.Spec.AdoptProject {
+		if p.Spec.CCS {
+			return ErrAdoptCCSProject
+		}
+		if p.Spec.GCPProjectID == "" {
+			return ErrAdoptedProjectIDMissing
+		}
+	}
+
 	return nil
 }
 
+// ProjectReferenceLink returns the name of the ProjectReference the operator creates for the ProjectClaim
+func (p *ProjectClaim) ProjectReferenceLink() NamespacedName {
+	return NamespacedName{
+		Name:      p.GetNamespace() + "-" + p.GetName(),
+		Namespace: ProjectReferenceNamespace,
+	}
+}
+
+// GetDeletionPolicy returns the DeletionPolicy of the ProjectClaim, DeletionPolicyDelete if unset
+func (p *ProjectClaim) GetDeletionPolicy() DeletionPolicy {
+	if p.Spec.DeletionPolicy == "" {
+		return DeletionPolicyDelete
+	}
+	return p.Spec.DeletionPolicy
+}
+
+// IsDeletionProtected returns true if the DeletionProtectionAnnotation blocks the deletion of the ProjectClaim
+func (p *ProjectClaim) IsDeletionProtected() bool {
+	return p.GetAnnotations()[DeletionProtectionAnnotation] == "true"
+}
+
+// ReferencesSecret returns true if the ProjectClaim references the secret namespace/name, either as the secret
+// the operator writes its GCP credentials to, or for CCS as the secret holding the credentials of the CCS project.
+// Secret references without a namespace are in the namespace of the ProjectClaim.
+func (p *ProjectClaim) ReferencesSecret(namespace, name string) bool {
+	matches := func(ref NamespacedName) bool {
+		refNamespace := ref.Namespace
+		if refNamespace == "" {
+			refNamespace = p.GetNamespace()
+		}
+		return refNamespace == namespace && ref.Name == name
+	}
+	return matches(p.Spec.GCPCredentialSecret) || (p.Spec.CCS && matches(p.Spec.CCSSecretRef))
+}
+
 func init() {
 	SchemeBuilder.Register(&ProjectClaim{}, &ProjectClaimList{})
 }
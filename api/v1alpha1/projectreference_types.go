@@ -38,6 +38,10 @@ type ProjectReferenceSpec struct {
 	CCSSecretRef       NamespacedName `json:"ccsSecretRef,omitempty"`
 	ServiceAccountName string         `json:"serviceAccountName,omitempty"`
 	SharedVPCAccess    bool           `json:"sharedVPCAccess,omitempty"`
+	// AdditionalAPIs are GCP service APIs to enable on the project on top of the operator's default list
+	// +listType=atomic
+	// +optional
+	AdditionalAPIs []string `json:"additionalAPIs,omitempty"`
 }
 
 // ProjectReferenceStatus defines the observed state of ProjectReference
@@ -46,6 +50,71 @@ type ProjectReferenceStatus struct {
 	// +listType=atomic
 	Conditions []Condition           `json:"conditions"`
 	State      ProjectReferenceState `json:"state"`
+	// ProjectCreationOperation is the name of the GCP operation creating the project.
+	// It is set until the operation is done.
+	// +optional
+	ProjectCreationOperation string `json:"projectCreationOperation,omitempty"`
+	// APIs are the GCP service APIs the operator enables on the project, in the order they are enabled
+	// +listType=map
+	// +listMapKey=name
+	// +optional
+	APIs []APIStatus `json:"apis,omitempty"`
+	// GrantedRoles are the project roles the operator granted, per IAM member
+	// +listType=map
+	// +listMapKey=member
+	// +optional
+	GrantedRoles []IAMBinding `json:"grantedRoles,omitempty"`
+	// ServiceAccountKey is the service account key in the credentials secret
+	// +optional
+	ServiceAccountKey *ServiceAccountKeyStatus `json:"serviceAccountKey,omitempty"`
+	// WorkloadIdentityProvider is the resource name of the workload identity provider
+	// the credentials secret exchanges tokens with, in the WorkloadIdentityFederation credential mode
+	// +optional
+	WorkloadIdentityProvider string `json:"workloadIdentityProvider,omitempty"`
+	// Adopted is true if the operator took over an existing project instead of creating it
+	// +optional
+	Adopted bool `json:"adopted,omitempty"`
+}
+
+// ServiceAccountKeyStatus tracks the service account key in the credentials secret and its rotation
+// +k8s:openapi-gen=true
+type ServiceAccountKeyStatus struct {
+	// Name is the resource name of the key
+	Name string `json:"name"`
+	// CreationTime is when the key was created
+	CreationTime metav1.Time `json:"creationTime"`
+	// NextRotationTime is when the key gets replaced, it is unset if keys aren't rotated
+	// +optional
+	NextRotationTime *metav1.Time `json:"nextRotationTime,omitempty"`
+	// PreviousKeysExpirationTime is when the keys replaced by the last rotation get deleted
+	// +optional
+	PreviousKeysExpirationTime *metav1.Time `json:"previousKeysExpirationTime,omitempty"`
+	// PreviousKeyNames are the resource names of the keys replaced by the last rotation, which get deleted at PreviousKeysExpirationTime
+	// +listType=atomic
+	// +optional
+	PreviousKeyNames []string `json:"previousKeyNames,omitempty"`
+}
+
+// IAMBinding lists the project roles granted to an IAM member
+// +k8s:openapi-gen=true
+type IAMBinding struct {
+	// Member is the IAM member, e.g. group:sre@example.com
+	Member string `json:"member"`
+	// Roles granted to the member
+	// +listType=atomic
+	Roles []string `json:"roles"`
+}
+
+// APIStatus is the state of a GCP service API on the project
+// +k8s:openapi-gen=true
+type APIStatus struct {
+	// Name of the API, e.g. compute.googleapis.com
+	Name string `json:"name"`
+	// Enabled is true once the API is enabled on the project
+	Enabled bool `json:"enabled"`
+	// Message is the error of the last attempt to enable the API
+	// +optional
+	Message string `json:"message,omitempty"`
 }
 
 // ProjectReferenceState is a valid value from ProjectReference.Status
@@ -69,6 +138,7 @@ const (
 
 // +kubebuilder:object:root=true
 // +kubebuilder:subresource:status
+// +kubebuilder:storageversion
 // +k8s:openapi-gen=true
 // +kubebuilder:printcolumn:name="State",type="string",JSONPath=".status.state",description="Status of the ProjectReference"
 // +kubebuilder:printcolumn:name="ClaimName",type="string",JSONPath=".spec.projectClaimCRLink.name",description="Name of corresponding project claim CR"
@@ -8,6 +8,21 @@ import (
 	runtime "k8s.io/apimachinery/pkg/runtime"
 )
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *APIStatus) DeepCopyInto(out *APIStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new APIStatus.
+func (in *APIStatus) DeepCopy() *APIStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(APIStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Condition) DeepCopyInto(out *Condition) {
 	*out = *in
@@ -25,6 +40,26 @@ func (in *Condition) DeepCopy() *Condition {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *IAMBinding) DeepCopyInto(out *IAMBinding) {
+	*out = *in
+	if in.Roles != nil {
+		in, out := &in.Roles, &out.Roles
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IAMBinding.
+func (in *IAMBinding) DeepCopy() *IAMBinding {
+	if in == nil {
+		return nil
+	}
+	out := new(IAMBinding)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *LegalEntity) DeepCopyInto(out *LegalEntity) {
 	*out = *in
@@ -126,6 +161,16 @@ func (in *ProjectClaimSpec) DeepCopyInto(out *ProjectClaimSpec) {
 		copy(*out, *in)
 	}
 	out.CCSSecretRef = in.CCSSecretRef
+	if in.AdditionalAPIs != nil {
+		in, out := &in.AdditionalAPIs, &out.AdditionalAPIs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.WorkloadIdentity != nil {
+		in, out := &in.WorkloadIdentity, &out.WorkloadIdentity
+		*out = new(WorkloadIdentityConfig)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProjectClaimSpec.
@@ -165,7 +210,7 @@ func (in *ProjectReference) DeepCopyInto(out *ProjectReference) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	out.Spec = in.Spec
+	in.Spec.DeepCopyInto(&out.Spec)
 	in.Status.DeepCopyInto(&out.Status)
 }
 
@@ -225,6 +270,11 @@ func (in *ProjectReferenceSpec) DeepCopyInto(out *ProjectReferenceSpec) {
 	out.ProjectClaimCRLink = in.ProjectClaimCRLink
 	out.LegalEntity = in.LegalEntity
 	out.CCSSecretRef = in.CCSSecretRef
+	if in.AdditionalAPIs != nil {
+		in, out := &in.AdditionalAPIs, &out.AdditionalAPIs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProjectReferenceSpec.
@@ -247,6 +297,23 @@ func (in *ProjectReferenceStatus) DeepCopyInto(out *ProjectReferenceStatus) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.APIs != nil {
+		in, out := &in.APIs, &out.APIs
+		*out = make([]APIStatus, len(*in))
+		copy(*out, *in)
+	}
+	if in.GrantedRoles != nil {
+		in, out := &in.GrantedRoles, &out.GrantedRoles
+		*out = make([]IAMBinding, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.ServiceAccountKey != nil {
+		in, out := &in.ServiceAccountKey, &out.ServiceAccountKey
+		*out = new(ServiceAccountKeyStatus)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProjectReferenceStatus.
@@ -258,3 +325,57 @@ func (in *ProjectReferenceStatus) DeepCopy() *ProjectReferenceStatus {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ServiceAccountKeyStatus) DeepCopyInto(out *ServiceAccountKeyStatus) {
+	*out = *in
+	in.CreationTime.DeepCopyInto(&out.CreationTime)
+	if in.NextRotationTime != nil {
+		in, out := &in.NextRotationTime, &out.NextRotationTime
+		*out = (*in).DeepCopy()
+	}
+	if in.PreviousKeysExpirationTime != nil {
+		in, out := &in.PreviousKeysExpirationTime, &out.PreviousKeysExpirationTime
+		*out = (*in).DeepCopy()
+	}
+	if in.PreviousKeyNames != nil {
+		in, out := &in.PreviousKeyNames, &out.PreviousKeyNames
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ServiceAccountKeyStatus.
+func (in *ServiceAccountKeyStatus) DeepCopy() *ServiceAccountKeyStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ServiceAccountKeyStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *WorkloadIdentityConfig) DeepCopyInto(out *WorkloadIdentityConfig) {
+	*out = *in
+	if in.AllowedAudiences != nil {
+		in, out := &in.AllowedAudiences, &out.AllowedAudiences
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Subjects != nil {
+		in, out := &in.Subjects, &out.Subjects
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WorkloadIdentityConfig.
+func (in *WorkloadIdentityConfig) DeepCopy() *WorkloadIdentityConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(WorkloadIdentityConfig)
+	in.DeepCopyInto(out)
+	return out
+}
@@ -14,12 +14,95 @@ import (
 
 func GetOpenAPIDefinitions(ref common.ReferenceCallback) map[string]common.OpenAPIDefinition {
 	return map[string]common.OpenAPIDefinition{
-		"github.com/openshift/gcp-project-operator/api/v1alpha1.ProjectClaim":           schema_openshift_gcp_project_operator_api_v1alpha1_ProjectClaim(ref),
-		"github.com/openshift/gcp-project-operator/api/v1alpha1.ProjectClaimSpec":       schema_openshift_gcp_project_operator_api_v1alpha1_ProjectClaimSpec(ref),
-		"github.com/openshift/gcp-project-operator/api/v1alpha1.ProjectClaimStatus":     schema_openshift_gcp_project_operator_api_v1alpha1_ProjectClaimStatus(ref),
-		"github.com/openshift/gcp-project-operator/api/v1alpha1.ProjectReference":       schema_openshift_gcp_project_operator_api_v1alpha1_ProjectReference(ref),
-		"github.com/openshift/gcp-project-operator/api/v1alpha1.ProjectReferenceSpec":   schema_openshift_gcp_project_operator_api_v1alpha1_ProjectReferenceSpec(ref),
-		"github.com/openshift/gcp-project-operator/api/v1alpha1.ProjectReferenceStatus": schema_openshift_gcp_project_operator_api_v1alpha1_ProjectReferenceStatus(ref),
+		"github.com/openshift/gcp-project-operator/api/v1alpha1.APIStatus":               schema_openshift_gcp_project_operator_api_v1alpha1_APIStatus(ref),
+		"github.com/openshift/gcp-project-operator/api/v1alpha1.IAMBinding":              schema_openshift_gcp_project_operator_api_v1alpha1_IAMBinding(ref),
+		"github.com/openshift/gcp-project-operator/api/v1alpha1.ProjectClaim":            schema_openshift_gcp_project_operator_api_v1alpha1_ProjectClaim(ref),
+		"github.com/openshift/gcp-project-operator/api/v1alpha1.ProjectClaimSpec":        schema_openshift_gcp_project_operator_api_v1alpha1_ProjectClaimSpec(ref),
+		"github.com/openshift/gcp-project-operator/api/v1alpha1.ProjectClaimStatus":      schema_openshift_gcp_project_operator_api_v1alpha1_ProjectClaimStatus(ref),
+		"github.com/openshift/gcp-project-operator/api/v1alpha1.ProjectReference":        schema_openshift_gcp_project_operator_api_v1alpha1_ProjectReference(ref),
+		"github.com/openshift/gcp-project-operator/api/v1alpha1.ProjectReferenceSpec":    schema_openshift_gcp_project_operator_api_v1alpha1_ProjectReferenceSpec(ref),
+		"github.com/openshift/gcp-project-operator/api/v1alpha1.ProjectReferenceStatus":  schema_openshift_gcp_project_operator_api_v1alpha1_ProjectReferenceStatus(ref),
+		"github.com/openshift/gcp-project-operator/api/v1alpha1.ServiceAccountKeyStatus": schema_openshift_gcp_project_operator_api_v1alpha1_ServiceAccountKeyStatus(ref),
+		"github.com/openshift/gcp-project-operator/api/v1alpha1.WorkloadIdentityConfig":  schema_openshift_gcp_project_operator_api_v1alpha1_WorkloadIdentityConfig(ref),
+	}
+}
+
+func schema_openshift_gcp_project_operator_api_v1alpha1_APIStatus(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "APIStatus is the state of a GCP service API on the project",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"name": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Name of the API, e.g. compute.googleapis.com",
+							Default:     "",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"enabled": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Enabled is true once the API is enabled on the project",
+							Default:     false,
+							Type:        []string{"boolean"},
+							Format:      "",
+						},
+					},
+					"message": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Message is the error of the last attempt to enable the API",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+				},
+				Required: []string{"name", "enabled"},
+			},
+		},
+	}
+}
+
+func schema_openshift_gcp_project_operator_api_v1alpha1_IAMBinding(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "IAMBinding lists the project roles granted to an IAM member",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"member": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Member is the IAM member, e.g. group:sre@example.com",
+							Default:     "",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"roles": {
+						VendorExtensible: spec.VendorExtensible{
+							Extensions: spec.Extensions{
+								"x-kubernetes-list-type": "atomic",
+							},
+						},
+						SchemaProps: spec.SchemaProps{
+							Description: "Roles granted to the member",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: "",
+										Type:    []string{"string"},
+										Format:  "",
+									},
+								},
+							},
+						},
+					},
+				},
+				Required: []string{"member", "roles"},
+			},
+		},
 	}
 }
 
@@ -151,12 +234,80 @@ func schema_openshift_gcp_project_operator_api_v1alpha1_ProjectClaimSpec(ref com
 							Format: "",
 						},
 					},
+					"additionalAPIs": {
+						VendorExtensible: spec.VendorExtensible{
+							Extensions: spec.Extensions{
+								"x-kubernetes-list-type": "atomic",
+							},
+						},
+						SchemaProps: spec.SchemaProps{
+							Description: "AdditionalAPIs are GCP service APIs to enable on the project on top of the operator's default list",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: "",
+										Type:    []string{"string"},
+										Format:  "",
+									},
+								},
+							},
+						},
+					},
+					"credentialMode": {
+						SchemaProps: spec.SchemaProps{
+							Description: "CredentialMode selects the credentials written into GCPCredentialSecret, ServiceAccountKey if unset",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"workloadIdentity": {
+						SchemaProps: spec.SchemaProps{
+							Description: "WorkloadIdentity configures the identity provider trusted by the WorkloadIdentityFederation credential mode",
+							Ref:         ref("github.com/openshift/gcp-project-operator/api/v1alpha1.WorkloadIdentityConfig"),
+						},
+					},
+					"deletionPolicy": {
+						SchemaProps: spec.SchemaProps{
+							Description: "DeletionPolicy decides what happens to the GCP project once the ProjectClaim is deleted, Delete if unset",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"restoreDeletedProject": {
+						SchemaProps: spec.SchemaProps{
+							Description: "RestoreDeletedProject restores the GCP project of GCPProjectID, or CCSProjectID of CCS claims, if it is pending deletion, e.g. when the ProjectClaim of a deleted project is recreated within the recovery period of GCP. GCPProjectID is used instead of a generated project ID then.",
+							Type:        []string{"boolean"},
+							Format:      "",
+						},
+					},
+					"adoptProject": {
+						SchemaProps: spec.SchemaProps{
+							Description: "AdoptProject takes over the existing GCP project of GCPProjectID in the parent folder instead of creating one. The project must be labeled with the name of the ProjectClaim as claim_name, and mustn't be billed to another billing account. It doesn't apply to CCS projects.",
+							Type:        []string{"boolean"},
+							Format:      "",
+						},
+					},
+					"clusterID": {
+						SchemaProps: spec.SchemaProps{
+							Description: "ClusterID is the ID of the cluster using the project, the project is labeled with it as cluster_id",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"costCenter": {
+						SchemaProps: spec.SchemaProps{
+							Description: "CostCenter is the cost center the project is labeled with as cost_center, it overrides the cost_center of the projectLabels of the operator configuration",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
 				},
 				Required: []string{"legalEntity", "gcpCredentialSecret", "region"},
 			},
 		},
 		Dependencies: []string{
-			"github.com/openshift/gcp-project-operator/api/v1alpha1.LegalEntity", "github.com/openshift/gcp-project-operator/api/v1alpha1.NamespacedName"},
+			"github.com/openshift/gcp-project-operator/api/v1alpha1.LegalEntity", "github.com/openshift/gcp-project-operator/api/v1alpha1.NamespacedName", "github.com/openshift/gcp-project-operator/api/v1alpha1.WorkloadIdentityConfig"},
 	}
 }
 
@@ -297,6 +448,26 @@ func schema_openshift_gcp_project_operator_api_v1alpha1_ProjectReferenceSpec(ref
 							Format: "",
 						},
 					},
+					"additionalAPIs": {
+						VendorExtensible: spec.VendorExtensible{
+							Extensions: spec.Extensions{
+								"x-kubernetes-list-type": "atomic",
+							},
+						},
+						SchemaProps: spec.SchemaProps{
+							Description: "AdditionalAPIs are GCP service APIs to enable on the project on top of the operator's default list",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: "",
+										Type:    []string{"string"},
+										Format:  "",
+									},
+								},
+							},
+						},
+					},
 				},
 				Required: []string{"projectClaimCRLink", "legalEntity"},
 			},
@@ -338,11 +509,213 @@ func schema_openshift_gcp_project_operator_api_v1alpha1_ProjectReferenceStatus(r
 							Format:  "",
 						},
 					},
+					"projectCreationOperation": {
+						SchemaProps: spec.SchemaProps{
+							Description: "ProjectCreationOperation is the name of the GCP operation creating the project. It is set until the operation is done.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"apis": {
+						VendorExtensible: spec.VendorExtensible{
+							Extensions: spec.Extensions{
+								"x-kubernetes-list-map-keys": []interface{}{
+									"name",
+								},
+								"x-kubernetes-list-type": "map",
+							},
+						},
+						SchemaProps: spec.SchemaProps{
+							Description: "APIs are the GCP service APIs the operator enables on the project, in the order they are enabled",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: map[string]interface{}{},
+										Ref:     ref("github.com/openshift/gcp-project-operator/api/v1alpha1.APIStatus"),
+									},
+								},
+							},
+						},
+					},
+					"grantedRoles": {
+						VendorExtensible: spec.VendorExtensible{
+							Extensions: spec.Extensions{
+								"x-kubernetes-list-map-keys": []interface{}{
+									"member",
+								},
+								"x-kubernetes-list-type": "map",
+							},
+						},
+						SchemaProps: spec.SchemaProps{
+							Description: "GrantedRoles are the project roles the operator granted, per IAM member",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: map[string]interface{}{},
+										Ref:     ref("github.com/openshift/gcp-project-operator/api/v1alpha1.IAMBinding"),
+									},
+								},
+							},
+						},
+					},
+					"serviceAccountKey": {
+						SchemaProps: spec.SchemaProps{
+							Description: "ServiceAccountKey is the service account key in the credentials secret",
+							Ref:         ref("github.com/openshift/gcp-project-operator/api/v1alpha1.ServiceAccountKeyStatus"),
+						},
+					},
+					"workloadIdentityProvider": {
+						SchemaProps: spec.SchemaProps{
+							Description: "WorkloadIdentityProvider is the resource name of the workload identity provider the credentials secret exchanges tokens with, in the WorkloadIdentityFederation credential mode",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"adopted": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Adopted is true if the operator took over an existing project instead of creating it",
+							Type:        []string{"boolean"},
+							Format:      "",
+						},
+					},
 				},
 				Required: []string{"conditions", "state"},
 			},
 		},
 		Dependencies: []string{
-			"github.com/openshift/gcp-project-operator/api/v1alpha1.Condition"},
+			"github.com/openshift/gcp-project-operator/api/v1alpha1.APIStatus", "github.com/openshift/gcp-project-operator/api/v1alpha1.Condition", "github.com/openshift/gcp-project-operator/api/v1alpha1.IAMBinding", "github.com/openshift/gcp-project-operator/api/v1alpha1.ServiceAccountKeyStatus"},
+	}
+}
+
+func schema_openshift_gcp_project_operator_api_v1alpha1_ServiceAccountKeyStatus(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "ServiceAccountKeyStatus tracks the service account key in the credentials secret and its rotation",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"name": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Name is the resource name of the key",
+							Default:     "",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"creationTime": {
+						SchemaProps: spec.SchemaProps{
+							Description: "CreationTime is when the key was created",
+							Ref:         ref("k8s.io/apimachinery/pkg/apis/meta/v1.Time"),
+						},
+					},
+					"nextRotationTime": {
+						SchemaProps: spec.SchemaProps{
+							Description: "NextRotationTime is when the key gets replaced, it is unset if keys aren't rotated",
+							Ref:         ref("k8s.io/apimachinery/pkg/apis/meta/v1.Time"),
+						},
+					},
+					"previousKeysExpirationTime": {
+						SchemaProps: spec.SchemaProps{
+							Description: "PreviousKeysExpirationTime is when the keys replaced by the last rotation get deleted",
+							Ref:         ref("k8s.io/apimachinery/pkg/apis/meta/v1.Time"),
+						},
+					},
+					"previousKeyNames": {
+						VendorExtensible: spec.VendorExtensible{
+							Extensions: spec.Extensions{
+								"x-kubernetes-list-type": "atomic",
+							},
+						},
+						SchemaProps: spec.SchemaProps{
+							Description: "PreviousKeyNames are the resource names of the keys replaced by the last rotation, which get deleted at PreviousKeysExpirationTime",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: "",
+										Type:    []string{"string"},
+										Format:  "",
+									},
+								},
+							},
+						},
+					},
+				},
+				Required: []string{"name", "creationTime"},
+			},
+		},
+		Dependencies: []string{
+			"k8s.io/apimachinery/pkg/apis/meta/v1.Time"},
+	}
+}
+
+func schema_openshift_gcp_project_operator_api_v1alpha1_WorkloadIdentityConfig(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "WorkloadIdentityConfig describes the OIDC identity provider whose tokens are exchanged for credentials of the managed service account",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"issuerURI": {
+						SchemaProps: spec.SchemaProps{
+							Description: "IssuerURI is the URL of the OIDC issuer, e.g. the service account issuer of the cluster using the project",
+							Default:     "",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"allowedAudiences": {
+						VendorExtensible: spec.VendorExtensible{
+							Extensions: spec.Extensions{
+								"x-kubernetes-list-type": "atomic",
+							},
+						},
+						SchemaProps: spec.SchemaProps{
+							Description: "AllowedAudiences are the accepted audiences of the exchanged tokens. If empty, tokens must be issued for the full resource name of the workload identity provider.",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: "",
+										Type:    []string{"string"},
+										Format:  "",
+									},
+								},
+							},
+						},
+					},
+					"subjects": {
+						VendorExtensible: spec.VendorExtensible{
+							Extensions: spec.Extensions{
+								"x-kubernetes-list-type": "atomic",
+							},
+						},
+						SchemaProps: spec.SchemaProps{
+							Description: "Subjects are the token subjects allowed to impersonate the managed service account, e.g. system:serviceaccount:openshift-machine-api:machine-api-controllers. Every subject of the issuer is allowed if empty.",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: "",
+										Type:    []string{"string"},
+										Format:  "",
+									},
+								},
+							},
+						},
+					},
+					"tokenFile": {
+						SchemaProps: spec.SchemaProps{
+							Description: "TokenFile is the path of the OIDC token read by the consumers of the credential config, /var/run/secrets/openshift/serviceaccount/token if unset",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+				},
+				Required: []string{"issuerURI"},
+			},
+		},
 	}
 }
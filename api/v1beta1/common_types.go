@@ -0,0 +1,68 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+)
+
+// LegalEntity contains Red Hat specific identifiers to the original creator the clusters
+type LegalEntity struct {
+	Name string `json:"name"`
+	ID   string `json:"id"`
+}
+
+// ObjectReference contains the name of a object and its namespace
+type ObjectReference struct {
+	Name      string `json:"name"`
+	Namespace string `json:"namespace"`
+}
+
+// CCSSpec configures a project that the customer brings, instead of one created by the operator
+// +k8s:openapi-gen=true
+type CCSSpec struct {
+	// SecretRef is the secret with the service account key of the customer, in the namespace of the ProjectClaim
+	SecretRef corev1.SecretReference `json:"secretRef"`
+	// ProjectID is the ID of the customer's project
+	// +optional
+	ProjectID string `json:"projectID,omitempty"`
+}
+
+// SharedVPCSpec grants the managed service account the shared VPC roles of the operator config,
+// so the project can use a shared VPC of a host project
+// +k8s:openapi-gen=true
+type SharedVPCSpec struct{}
+
+// Condition types of ProjectClaims and ProjectReferences
+const (
+	// ConditionReady is set when a Project custom resource state changes Ready state
+	ConditionReady = "Ready"
+	// ConditionPending is set when a project custom resource state changes to Pending
+	ConditionPending = "Pending"
+	// ConditionVerification is set when a project custom resource state changes to Verification state
+	ConditionVerification = "Verification"
+	// ConditionError is set when a project custom resource state changes to Error
+	ConditionError = "Error"
+	// ConditionInvalid is set when a project custom resource has an invalid or unsupported configuration
+	ConditionInvalid = "Invalid"
+	// ConditionComputeApiReady is set when the compute API is not yet ready
+	ConditionComputeApiReady = "ComputeApiReady"
+	// ConditionProjectCreated is set when the GCP operation creating the project is pending, failed or succeeded
+	ConditionProjectCreated = "ProjectCreated"
+	// ConditionDrifted is true when the GCP project doesn't match its configuration anymore
+	ConditionDrifted = "Drifted"
+)
@@ -0,0 +1,92 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	gcpv1alpha1 "github.com/openshift/gcp-project-operator/api/v1alpha1"
+)
+
+// The v1alpha1 conditions have a LastProbeTime, which is dropped in v1beta1,
+// and metav1.Condition has an ObservedGeneration, which is dropped in v1alpha1.
+// The operator writes statuses in v1alpha1, so neither is lost in practice.
+
+func conditionsToHub(conditions []metav1.Condition) []gcpv1alpha1.Condition {
+	if conditions == nil {
+		return nil
+	}
+	hubConditions := make([]gcpv1alpha1.Condition, 0, len(conditions))
+	for _, condition := range conditions {
+		hubConditions = append(hubConditions, gcpv1alpha1.Condition{
+			Type:               gcpv1alpha1.ConditionType(condition.Type),
+			Status:             corev1.ConditionStatus(condition.Status),
+			LastTransitionTime: condition.LastTransitionTime,
+			Reason:             condition.Reason,
+			Message:            condition.Message,
+		})
+	}
+	return hubConditions
+}
+
+func conditionsFromHub(hubConditions []gcpv1alpha1.Condition) []metav1.Condition {
+	if hubConditions == nil {
+		return nil
+	}
+	conditions := make([]metav1.Condition, 0, len(hubConditions))
+	for _, hubCondition := range hubConditions {
+		conditions = append(conditions, metav1.Condition{
+			Type:               string(hubCondition.Type),
+			Status:             metav1.ConditionStatus(hubCondition.Status),
+			LastTransitionTime: hubCondition.LastTransitionTime,
+			Reason:             hubCondition.Reason,
+			Message:            hubCondition.Message,
+		})
+	}
+	return conditions
+}
+
+func secretReferenceToHub(secretRef corev1.SecretReference) gcpv1alpha1.NamespacedName {
+	return gcpv1alpha1.NamespacedName{Name: secretRef.Name, Namespace: secretRef.Namespace}
+}
+
+func secretReferenceFromHub(hubName gcpv1alpha1.NamespacedName) corev1.SecretReference {
+	return corev1.SecretReference{Name: hubName.Name, Namespace: hubName.Namespace}
+}
+
+// objectReferenceToHub returns the empty NamespacedName v1alpha1 uses for unset links if ref is nil
+func objectReferenceToHub(ref *ObjectReference) gcpv1alpha1.NamespacedName {
+	if ref == nil {
+		return gcpv1alpha1.NamespacedName{}
+	}
+	return gcpv1alpha1.NamespacedName{Name: ref.Name, Namespace: ref.Namespace}
+}
+
+func objectReferenceFromHub(hubName gcpv1alpha1.NamespacedName) *ObjectReference {
+	if hubName == (gcpv1alpha1.NamespacedName{}) {
+		return nil
+	}
+	return &ObjectReference{Name: hubName.Name, Namespace: hubName.Namespace}
+}
+
+func sharedVPCFromHub(sharedVPCAccess bool) *SharedVPCSpec {
+	if !sharedVPCAccess {
+		return nil
+	}
+	return &SharedVPCSpec{}
+}
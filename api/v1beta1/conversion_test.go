@@ -0,0 +1,270 @@
+package v1beta1
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	gcpv1alpha1 "github.com/openshift/gcp-project-operator/api/v1alpha1"
+)
+
+var transitionTime = metav1.Date(2022, 1, 2, 3, 4, 5, 0, time.UTC)
+
+func TestProjectClaimConversion(t *testing.T) {
+	tests := []struct {
+		name     string
+		hub      gcpv1alpha1.ProjectClaim
+		expected ProjectClaim
+	}{
+		{
+			name: "new claim",
+			hub: gcpv1alpha1.ProjectClaim{
+				ObjectMeta: metav1.ObjectMeta{Name: "claim", Namespace: "tenant"},
+				Spec: gcpv1alpha1.ProjectClaimSpec{
+					LegalEntity:         gcpv1alpha1.LegalEntity{Name: "entity", ID: "1234"},
+					GCPCredentialSecret: gcpv1alpha1.NamespacedName{Name: "gcp-secret", Namespace: "tenant"},
+					Region:              "us-east1",
+				},
+			},
+			expected: ProjectClaim{
+				ObjectMeta: metav1.ObjectMeta{Name: "claim", Namespace: "tenant"},
+				Spec: ProjectClaimSpec{
+					LegalEntity:         LegalEntity{Name: "entity", ID: "1234"},
+					GCPCredentialSecret: corev1.SecretReference{Name: "gcp-secret", Namespace: "tenant"},
+					Region:              "us-east1",
+				},
+			},
+		},
+		{
+			name: "adopting claim",
+			hub: gcpv1alpha1.ProjectClaim{
+				ObjectMeta: metav1.ObjectMeta{Name: "claim", Namespace: "tenant"},
+				Spec: gcpv1alpha1.ProjectClaimSpec{
+					LegalEntity:         gcpv1alpha1.LegalEntity{Name: "entity", ID: "1234"},
+					GCPCredentialSecret: gcpv1alpha1.NamespacedName{Name: "gcp-secret", Namespace: "tenant"},
+					Region:              "us-east1",
+					GCPProjectID:        "existing-project",
+					AdoptProject:        true,
+					ClusterID:           "cluster-1234",
+					CostCenter:          "cc-42",
+				},
+			},
+			expected: ProjectClaim{
+				ObjectMeta: metav1.ObjectMeta{Name: "claim", Namespace: "tenant"},
+				Spec: ProjectClaimSpec{
+					LegalEntity:         LegalEntity{Name: "entity", ID: "1234"},
+					GCPCredentialSecret: corev1.SecretReference{Name: "gcp-secret", Namespace: "tenant"},
+					Region:              "us-east1",
+					GCPProjectID:        "existing-project",
+					AdoptProject:        true,
+					ClusterID:           "cluster-1234",
+					CostCenter:          "cc-42",
+				},
+			},
+		},
+		{
+			name: "ready CCS claim",
+			hub: gcpv1alpha1.ProjectClaim{
+				ObjectMeta: metav1.ObjectMeta{Name: "claim", Namespace: "tenant"},
+				Spec: gcpv1alpha1.ProjectClaimSpec{
+					LegalEntity:            gcpv1alpha1.LegalEntity{Name: "entity", ID: "1234"},
+					GCPCredentialSecret:    gcpv1alpha1.NamespacedName{Name: "gcp-secret", Namespace: "tenant"},
+					Region:                 "us-east1",
+					GCPProjectID:           "customer-project",
+					ProjectReferenceCRLink: gcpv1alpha1.NamespacedName{Name: "tenant-claim", Namespace: gcpv1alpha1.ProjectReferenceNamespace},
+					AvailabilityZones:      []string{"us-east1-b", "us-east1-c"},
+					CCS:                    true,
+					CCSSecretRef:           gcpv1alpha1.NamespacedName{Name: "ccs-secret", Namespace: "tenant"},
+					CCSProjectID:           "customer-project",
+					SharedVPCAccess:        true,
+					AdditionalAPIs:         []string{"file.googleapis.com"},
+					CredentialMode:         gcpv1alpha1.CredentialModeWorkloadIdentityFederation,
+					WorkloadIdentity:       &gcpv1alpha1.WorkloadIdentityConfig{IssuerURI: "https://issuer.example.com", Subjects: []string{"system:serviceaccount:ns:sa"}},
+					DeletionPolicy:         gcpv1alpha1.DeletionPolicyRetain,
+					RestoreDeletedProject:  true,
+				},
+				Status: gcpv1alpha1.ProjectClaimStatus{
+					Conditions: []gcpv1alpha1.Condition{
+						{Type: gcpv1alpha1.ConditionReady, Status: corev1.ConditionTrue, LastTransitionTime: transitionTime, Reason: "ProjectReady", Message: "ready"},
+					},
+					State: gcpv1alpha1.ClaimStatusReady,
+				},
+			},
+			expected: ProjectClaim{
+				ObjectMeta: metav1.ObjectMeta{Name: "claim", Namespace: "tenant"},
+				Spec: ProjectClaimSpec{
+					LegalEntity:           LegalEntity{Name: "entity", ID: "1234"},
+					GCPCredentialSecret:   corev1.SecretReference{Name: "gcp-secret", Namespace: "tenant"},
+					Region:                "us-east1",
+					GCPProjectID:          "customer-project",
+					ProjectReferenceRef:   &ObjectReference{Name: "tenant-claim", Namespace: gcpv1alpha1.ProjectReferenceNamespace},
+					AvailabilityZones:     []string{"us-east1-b", "us-east1-c"},
+					CCS:                   &CCSSpec{SecretRef: corev1.SecretReference{Name: "ccs-secret", Namespace: "tenant"}, ProjectID: "customer-project"},
+					SharedVPC:             &SharedVPCSpec{},
+					AdditionalAPIs:        []string{"file.googleapis.com"},
+					CredentialMode:        CredentialModeWorkloadIdentityFederation,
+					WorkloadIdentity:      &WorkloadIdentityConfig{IssuerURI: "https://issuer.example.com", Subjects: []string{"system:serviceaccount:ns:sa"}},
+					DeletionPolicy:        DeletionPolicyRetain,
+					RestoreDeletedProject: true,
+				},
+				Status: ProjectClaimStatus{
+					Conditions: []metav1.Condition{
+						{Type: ConditionReady, Status: metav1.ConditionTrue, LastTransitionTime: transitionTime, Reason: "ProjectReady", Message: "ready"},
+					},
+					State: ClaimStatusReady,
+				},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			claim := ProjectClaim{}
+			if err := claim.ConvertFrom(&test.hub); err != nil {
+				t.Fatalf("ConvertFrom failed: %v", err)
+			}
+			if !reflect.DeepEqual(claim, test.expected) {
+				t.Errorf("got %+v, wanted %+v", claim, test.expected)
+			}
+
+			hub := gcpv1alpha1.ProjectClaim{}
+			if err := claim.ConvertTo(&hub); err != nil {
+				t.Fatalf("ConvertTo failed: %v", err)
+			}
+			if !reflect.DeepEqual(hub, test.hub) {
+				t.Errorf("round trip got %+v, wanted %+v", hub, test.hub)
+			}
+		})
+	}
+}
+
+func TestProjectClaimConversionDropsProbeTime(t *testing.T) {
+	hub := gcpv1alpha1.ProjectClaim{
+		Status: gcpv1alpha1.ProjectClaimStatus{
+			Conditions: []gcpv1alpha1.Condition{
+				{Type: gcpv1alpha1.ConditionError, Status: corev1.ConditionTrue, LastProbeTime: metav1.Now(), LastTransitionTime: transitionTime, Reason: "ReconcileError"},
+			},
+		},
+	}
+
+	claim := ProjectClaim{}
+	if err := claim.ConvertFrom(&hub); err != nil {
+		t.Fatalf("ConvertFrom failed: %v", err)
+	}
+	roundTrip := gcpv1alpha1.ProjectClaim{}
+	if err := claim.ConvertTo(&roundTrip); err != nil {
+		t.Fatalf("ConvertTo failed: %v", err)
+	}
+
+	expected := gcpv1alpha1.Condition{Type: gcpv1alpha1.ConditionError, Status: corev1.ConditionTrue, LastTransitionTime: transitionTime, Reason: "ReconcileError"}
+	if !reflect.DeepEqual(roundTrip.Status.Conditions, []gcpv1alpha1.Condition{expected}) {
+		t.Errorf("got %+v, wanted %+v", roundTrip.Status.Conditions, expected)
+	}
+}
+
+func TestProjectReferenceConversion(t *testing.T) {
+	tests := []struct {
+		name     string
+		hub      gcpv1alpha1.ProjectReference
+		expected ProjectReference
+	}{
+		{
+			name: "new reference",
+			hub: gcpv1alpha1.ProjectReference{
+				ObjectMeta: metav1.ObjectMeta{Name: "tenant-claim", Namespace: gcpv1alpha1.ProjectReferenceNamespace},
+				Spec: gcpv1alpha1.ProjectReferenceSpec{
+					ProjectClaimCRLink: gcpv1alpha1.NamespacedName{Name: "claim", Namespace: "tenant"},
+					LegalEntity:        gcpv1alpha1.LegalEntity{Name: "entity", ID: "1234"},
+				},
+			},
+			expected: ProjectReference{
+				ObjectMeta: metav1.ObjectMeta{Name: "tenant-claim", Namespace: gcpv1alpha1.ProjectReferenceNamespace},
+				Spec: ProjectReferenceSpec{
+					ProjectClaimRef: ObjectReference{Name: "claim", Namespace: "tenant"},
+					LegalEntity:     LegalEntity{Name: "entity", ID: "1234"},
+				},
+			},
+		},
+		{
+			name: "ready CCS reference",
+			hub: gcpv1alpha1.ProjectReference{
+				ObjectMeta: metav1.ObjectMeta{Name: "tenant-claim", Namespace: gcpv1alpha1.ProjectReferenceNamespace},
+				Spec: gcpv1alpha1.ProjectReferenceSpec{
+					GCPProjectID:       "customer-project",
+					ProjectClaimCRLink: gcpv1alpha1.NamespacedName{Name: "claim", Namespace: "tenant"},
+					LegalEntity:        gcpv1alpha1.LegalEntity{Name: "entity", ID: "1234"},
+					CCS:                true,
+					CCSSecretRef:       gcpv1alpha1.NamespacedName{Name: "ccs-secret", Namespace: "tenant"},
+					ServiceAccountName: "osd-managed-admin-abcdefgh",
+					SharedVPCAccess:    true,
+					AdditionalAPIs:     []string{"file.googleapis.com"},
+				},
+				Status: gcpv1alpha1.ProjectReferenceStatus{
+					Conditions: []gcpv1alpha1.Condition{
+						{Type: gcpv1alpha1.ConditionProjectCreated, Status: corev1.ConditionTrue, LastTransitionTime: transitionTime, Reason: "OperationDone"},
+					},
+					State:        gcpv1alpha1.ProjectReferenceStatusReady,
+					APIs:         []gcpv1alpha1.APIStatus{{Name: "compute.googleapis.com", Enabled: true}},
+					GrantedRoles: []gcpv1alpha1.IAMBinding{{Member: "group:sre@example.com", Roles: []string{"roles/viewer"}}},
+					ServiceAccountKey: &gcpv1alpha1.ServiceAccountKeyStatus{
+						Name:             "projects/customer-project/serviceAccounts/sa/keys/key",
+						CreationTime:     transitionTime,
+						NextRotationTime: &transitionTime,
+					},
+					WorkloadIdentityProvider: "projects/123/locations/global/workloadIdentityPools/osd-managed/providers/osd-managed-oidc",
+					Adopted:                  true,
+				},
+			},
+			expected: ProjectReference{
+				ObjectMeta: metav1.ObjectMeta{Name: "tenant-claim", Namespace: gcpv1alpha1.ProjectReferenceNamespace},
+				Spec: ProjectReferenceSpec{
+					GCPProjectID:       "customer-project",
+					ProjectClaimRef:    ObjectReference{Name: "claim", Namespace: "tenant"},
+					LegalEntity:        LegalEntity{Name: "entity", ID: "1234"},
+					CCS:                &ProjectReferenceCCSSpec{SecretRef: corev1.SecretReference{Name: "ccs-secret", Namespace: "tenant"}},
+					ServiceAccountName: "osd-managed-admin-abcdefgh",
+					SharedVPC:          &SharedVPCSpec{},
+					AdditionalAPIs:     []string{"file.googleapis.com"},
+				},
+				Status: ProjectReferenceStatus{
+					Conditions: []metav1.Condition{
+						{Type: ConditionProjectCreated, Status: metav1.ConditionTrue, LastTransitionTime: transitionTime, Reason: "OperationDone"},
+					},
+					State:        ProjectReferenceStatusReady,
+					APIs:         []APIStatus{{Name: "compute.googleapis.com", Enabled: true}},
+					GrantedRoles: []IAMBinding{{Member: "group:sre@example.com", Roles: []string{"roles/viewer"}}},
+					ServiceAccountKey: &ServiceAccountKeyStatus{
+						Name:             "projects/customer-project/serviceAccounts/sa/keys/key",
+						CreationTime:     transitionTime,
+						NextRotationTime: &transitionTime,
+					},
+					WorkloadIdentityProvider: "projects/123/locations/global/workloadIdentityPools/osd-managed/providers/osd-managed-oidc",
+					Adopted:                  true,
+				},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			reference := ProjectReference{}
+			if err := reference.ConvertFrom(&test.hub); err != nil {
+				t.Fatalf("ConvertFrom failed: %v", err)
+			}
+			if !reflect.DeepEqual(reference, test.expected) {
+				t.Errorf("got %+v, wanted %+v", reference, test.expected)
+			}
+
+			hub := gcpv1alpha1.ProjectReference{}
+			if err := reference.ConvertTo(&hub); err != nil {
+				t.Fatalf("ConvertTo failed: %v", err)
+			}
+			if !reflect.DeepEqual(hub, test.hub) {
+				t.Errorf("round trip got %+v, wanted %+v", hub, test.hub)
+			}
+		})
+	}
+}
@@ -0,0 +1,95 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	"sigs.k8s.io/controller-runtime/pkg/conversion"
+
+	gcpv1alpha1 "github.com/openshift/gcp-project-operator/api/v1alpha1"
+)
+
+// ConvertTo converts the ProjectClaim to the hub version v1alpha1
+func (src *ProjectClaim) ConvertTo(dstRaw conversion.Hub) error {
+	dst := dstRaw.(*gcpv1alpha1.ProjectClaim)
+	dst.ObjectMeta = src.ObjectMeta
+
+	dst.Spec = gcpv1alpha1.ProjectClaimSpec{
+		LegalEntity:            gcpv1alpha1.LegalEntity(src.Spec.LegalEntity),
+		GCPCredentialSecret:    secretReferenceToHub(src.Spec.GCPCredentialSecret),
+		Region:                 src.Spec.Region,
+		GCPProjectID:           src.Spec.GCPProjectID,
+		ProjectReferenceCRLink: objectReferenceToHub(src.Spec.ProjectReferenceRef),
+		AvailabilityZones:      src.Spec.AvailabilityZones,
+		SharedVPCAccess:        src.Spec.SharedVPC != nil,
+		AdditionalAPIs:         src.Spec.AdditionalAPIs,
+		CredentialMode:         gcpv1alpha1.CredentialMode(src.Spec.CredentialMode),
+		WorkloadIdentity:       (*gcpv1alpha1.WorkloadIdentityConfig)(src.Spec.WorkloadIdentity.DeepCopy()),
+		DeletionPolicy:         gcpv1alpha1.DeletionPolicy(src.Spec.DeletionPolicy),
+		RestoreDeletedProject:  src.Spec.RestoreDeletedProject,
+		AdoptProject:           src.Spec.AdoptProject,
+		ClusterID:              src.Spec.ClusterID,
+		CostCenter:             src.Spec.CostCenter,
+	}
+	if src.Spec.CCS != nil {
+		dst.Spec.CCS = true
+		dst.Spec.CCSSecretRef = secretReferenceToHub(src.Spec.CCS.SecretRef)
+		dst.Spec.CCSProjectID = src.Spec.CCS.ProjectID
+	}
+
+	dst.Status = gcpv1alpha1.ProjectClaimStatus{
+		Conditions: conditionsToHub(src.Status.Conditions),
+		State:      gcpv1alpha1.ClaimStatus(src.Status.State),
+	}
+	return nil
+}
+
+// ConvertFrom converts the hub version v1alpha1 to the ProjectClaim.
+// The ccsSecretRef and ccsProjectID of v1alpha1 claims without ccs are unused and dropped.
+func (dst *ProjectClaim) ConvertFrom(srcRaw conversion.Hub) error {
+	src := srcRaw.(*gcpv1alpha1.ProjectClaim)
+	dst.ObjectMeta = src.ObjectMeta
+
+	dst.Spec = ProjectClaimSpec{
+		LegalEntity:           LegalEntity(src.Spec.LegalEntity),
+		GCPCredentialSecret:   secretReferenceFromHub(src.Spec.GCPCredentialSecret),
+		Region:                src.Spec.Region,
+		GCPProjectID:          src.Spec.GCPProjectID,
+		ProjectReferenceRef:   objectReferenceFromHub(src.Spec.ProjectReferenceCRLink),
+		AvailabilityZones:     src.Spec.AvailabilityZones,
+		SharedVPC:             sharedVPCFromHub(src.Spec.SharedVPCAccess),
+		AdditionalAPIs:        src.Spec.AdditionalAPIs,
+		CredentialMode:        CredentialMode(src.Spec.CredentialMode),
+		WorkloadIdentity:      (*WorkloadIdentityConfig)(src.Spec.WorkloadIdentity.DeepCopy()),
+		DeletionPolicy:        DeletionPolicy(src.Spec.DeletionPolicy),
+		RestoreDeletedProject: src.Spec.RestoreDeletedProject,
+		AdoptProject:          src.Spec.AdoptProject,
+		ClusterID:             src.Spec.ClusterID,
+		CostCenter:            src.Spec.CostCenter,
+	}
+	if src.Spec.CCS {
+		dst.Spec.CCS = &CCSSpec{
+			SecretRef: secretReferenceFromHub(src.Spec.CCSSecretRef),
+			ProjectID: src.Spec.CCSProjectID,
+		}
+	}
+
+	dst.Status = ProjectClaimStatus{
+		Conditions: conditionsFromHub(src.Status.Conditions),
+		State:      ClaimStatus(src.Status.State),
+	}
+	return nil
+}
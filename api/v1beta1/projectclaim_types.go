@@ -0,0 +1,181 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// CredentialMode selects the credentials the operator writes into the GCPCredentialSecret of a ProjectClaim
+// +kubebuilder:validation:Enum=ServiceAccountKey;WorkloadIdentityFederation
+type CredentialMode string
+
+const (
+	// CredentialModeServiceAccountKey writes a JSON key of the managed service account
+	CredentialModeServiceAccountKey CredentialMode = "ServiceAccountKey"
+	// CredentialModeWorkloadIdentityFederation writes an external_account credential config, which exchanges
+	// tokens of a trusted OIDC issuer for short-lived tokens of the managed service account
+	CredentialModeWorkloadIdentityFederation CredentialMode = "WorkloadIdentityFederation"
+)
+
+// DeletionPolicy decides what happens to the GCP project of a ProjectClaim once the ProjectClaim is deleted
+// +kubebuilder:validation:Enum=Delete;Retain;Orphan
+type DeletionPolicy string
+
+const (
+	// DeletionPolicyDelete deletes the GCP project
+	DeletionPolicyDelete DeletionPolicy = "Delete"
+	// DeletionPolicyRetain keeps the GCP project, but removes the IAM bindings and the service account of the operator
+	// and labels the project as orphaned
+	DeletionPolicyRetain DeletionPolicy = "Retain"
+	// DeletionPolicyOrphan leaves the GCP project and the credentials secret untouched
+	DeletionPolicyOrphan DeletionPolicy = "Orphan"
+)
+
+// ProjectClaimSpec defines the desired state of ProjectClaim
+// +k8s:openapi-gen=true
+type ProjectClaimSpec struct {
+	LegalEntity LegalEntity `json:"legalEntity"`
+	// GCPCredentialSecret is the secret the operator writes the credentials of the project into,
+	// in the namespace of the ProjectClaim
+	GCPCredentialSecret corev1.SecretReference `json:"gcpCredentialSecret"`
+	// Region is the GCP region of the cluster using the project, e.g. us-east1
+	// +kubebuilder:validation:Pattern=`^[a-z]+-[a-z]+[0-9]+$`
+	Region string `json:"region"`
+	// GCPProjectID is the ID of the project, it is set by the operator
+	// +optional
+	GCPProjectID string `json:"gcpProjectID,omitempty"`
+	// ProjectReferenceRef is the ProjectReference the operator creates for the ProjectClaim
+	// +optional
+	ProjectReferenceRef *ObjectReference `json:"projectReferenceRef,omitempty"`
+	// +listType=atomic
+	// +optional
+	AvailabilityZones []string `json:"availabilityZones,omitempty"`
+	// CCS configures the customer's project to use instead of creating one
+	// +optional
+	CCS *CCSSpec `json:"ccs,omitempty"`
+	// SharedVPC grants access to a shared VPC when set
+	// +optional
+	SharedVPC *SharedVPCSpec `json:"sharedVPC,omitempty"`
+	// AdditionalAPIs are GCP service APIs to enable on the project on top of the operator's default list
+	// +listType=atomic
+	// +optional
+	AdditionalAPIs []string `json:"additionalAPIs,omitempty"`
+	// CredentialMode selects the credentials written into GCPCredentialSecret, ServiceAccountKey if unset
+	// +optional
+	CredentialMode CredentialMode `json:"credentialMode,omitempty"`
+	// WorkloadIdentity configures the identity provider trusted by the WorkloadIdentityFederation credential mode
+	// +optional
+	WorkloadIdentity *WorkloadIdentityConfig `json:"workloadIdentity,omitempty"`
+	// DeletionPolicy decides what happens to the GCP project once the ProjectClaim is deleted, Delete if unset
+	// +optional
+	DeletionPolicy DeletionPolicy `json:"deletionPolicy,omitempty"`
+	// RestoreDeletedProject restores the GCP project of GCPProjectID, or CCS.ProjectID of CCS claims, if it is pending deletion,
+	// e.g. when the ProjectClaim of a deleted project is recreated within the recovery period of GCP.
+	// GCPProjectID is used instead of a generated project ID then.
+	// +optional
+	RestoreDeletedProject bool `json:"restoreDeletedProject,omitempty"`
+	// AdoptProject takes over the existing GCP project of GCPProjectID in the parent folder instead of creating one.
+	// The project must be labeled with the name of the ProjectClaim as claim_name, and mustn't be billed to another billing account.
+	// It doesn't apply to CCS projects.
+	// +optional
+	AdoptProject bool `json:"adoptProject,omitempty"`
+	// ClusterID is the ID of the cluster using the project, the project is labeled with it as cluster_id
+	// +optional
+	ClusterID string `json:"clusterID,omitempty"`
+	// CostCenter is the cost center the project is labeled with as cost_center,
+	// it overrides the cost_center of the projectLabels of the operator configuration
+	// +optional
+	CostCenter string `json:"costCenter,omitempty"`
+}
+
+// WorkloadIdentityConfig describes the OIDC identity provider whose tokens are exchanged for credentials of the managed service account
+// +k8s:openapi-gen=true
+type WorkloadIdentityConfig struct {
+	// IssuerURI is the URL of the OIDC issuer, e.g. the service account issuer of the cluster using the project
+	IssuerURI string `json:"issuerURI"`
+	// AllowedAudiences are the accepted audiences of the exchanged tokens. If empty, tokens must be issued for
+	// the full resource name of the workload identity provider.
+	// +listType=atomic
+	// +optional
+	AllowedAudiences []string `json:"allowedAudiences,omitempty"`
+	// Subjects are the token subjects allowed to impersonate the managed service account,
+	// e.g. system:serviceaccount:openshift-machine-api:machine-api-controllers. Every subject of the issuer is allowed if empty.
+	// +listType=atomic
+	// +optional
+	Subjects []string `json:"subjects,omitempty"`
+	// TokenFile is the path of the OIDC token read by the consumers of the credential config,
+	// /var/run/secrets/openshift/serviceaccount/token if unset
+	// +optional
+	TokenFile string `json:"tokenFile,omitempty"`
+}
+
+// ProjectClaimStatus defines the observed state of ProjectClaim
+// +k8s:openapi-gen=true
+type ProjectClaimStatus struct {
+	// +listType=map
+	// +listMapKey=type
+	// +optional
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+	// +optional
+	State ClaimStatus `json:"state,omitempty"`
+}
+
+// ClaimStatus is a valid value from ProjectClaim.Status
+// +kubebuilder:validation:Enum=Pending;PendingProject;Ready;Error;Verification
+type ClaimStatus string
+
+const (
+	// ClaimStatusPending pending status for a claim
+	ClaimStatusPending ClaimStatus = "Pending"
+	// ClaimStatusPendingProject pending project status for a claim
+	ClaimStatusPendingProject ClaimStatus = "PendingProject"
+	// ClaimStatusReady ready status for a claim
+	ClaimStatusReady ClaimStatus = "Ready"
+	// ClaimStatusError error status for a claim
+	ClaimStatusError ClaimStatus = "Error"
+	// ClaimStatusVerification pending verification status for a claim
+	ClaimStatusVerification ClaimStatus = "Verification"
+)
+
+// ProjectClaim is the Schema for the projectclaims API
+// +kubebuilder:object:root=true
+// +k8s:openapi-gen=true
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="State",type="string",JSONPath=".status.state",description="Status of the project claim"
+// +kubebuilder:printcolumn:name="GCPProjectID",type="string",JSONPath=".spec.gcpProjectID",description="ID of the GCP Project that has been created"
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp",description="Age since the project claim was created"
+type ProjectClaim struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   ProjectClaimSpec   `json:"spec,omitempty"`
+	Status ProjectClaimStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// ProjectClaimList contains a list of ProjectClaim
+type ProjectClaimList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []ProjectClaim `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&ProjectClaim{}, &ProjectClaimList{})
+}
@@ -0,0 +1,93 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	"sigs.k8s.io/controller-runtime/pkg/conversion"
+
+	gcpv1alpha1 "github.com/openshift/gcp-project-operator/api/v1alpha1"
+)
+
+// ConvertTo converts the ProjectReference to the hub version v1alpha1
+func (src *ProjectReference) ConvertTo(dstRaw conversion.Hub) error {
+	dst := dstRaw.(*gcpv1alpha1.ProjectReference)
+	dst.ObjectMeta = src.ObjectMeta
+
+	dst.Spec = gcpv1alpha1.ProjectReferenceSpec{
+		GCPProjectID:       src.Spec.GCPProjectID,
+		ProjectClaimCRLink: gcpv1alpha1.NamespacedName{Name: src.Spec.ProjectClaimRef.Name, Namespace: src.Spec.ProjectClaimRef.Namespace},
+		LegalEntity:        gcpv1alpha1.LegalEntity(src.Spec.LegalEntity),
+		ServiceAccountName: src.Spec.ServiceAccountName,
+		SharedVPCAccess:    src.Spec.SharedVPC != nil,
+		AdditionalAPIs:     src.Spec.AdditionalAPIs,
+	}
+	if src.Spec.CCS != nil {
+		dst.Spec.CCS = true
+		dst.Spec.CCSSecretRef = secretReferenceToHub(src.Spec.CCS.SecretRef)
+	}
+
+	dst.Status = gcpv1alpha1.ProjectReferenceStatus{
+		Conditions:               conditionsToHub(src.Status.Conditions),
+		State:                    gcpv1alpha1.ProjectReferenceState(src.Status.State),
+		ProjectCreationOperation: src.Status.ProjectCreationOperation,
+		ServiceAccountKey:        (*gcpv1alpha1.ServiceAccountKeyStatus)(src.Status.ServiceAccountKey.DeepCopy()),
+		WorkloadIdentityProvider: src.Status.WorkloadIdentityProvider,
+		Adopted:                  src.Status.Adopted,
+	}
+	for _, api := range src.Status.APIs {
+		dst.Status.APIs = append(dst.Status.APIs, gcpv1alpha1.APIStatus(api))
+	}
+	for _, binding := range src.Status.GrantedRoles {
+		dst.Status.GrantedRoles = append(dst.Status.GrantedRoles, gcpv1alpha1.IAMBinding(binding))
+	}
+	return nil
+}
+
+// ConvertFrom converts the hub version v1alpha1 to the ProjectReference.
+// The ccsSecretRef of v1alpha1 references without ccs is unused and dropped.
+func (dst *ProjectReference) ConvertFrom(srcRaw conversion.Hub) error {
+	src := srcRaw.(*gcpv1alpha1.ProjectReference)
+	dst.ObjectMeta = src.ObjectMeta
+
+	dst.Spec = ProjectReferenceSpec{
+		GCPProjectID:       src.Spec.GCPProjectID,
+		ProjectClaimRef:    ObjectReference{Name: src.Spec.ProjectClaimCRLink.Name, Namespace: src.Spec.ProjectClaimCRLink.Namespace},
+		LegalEntity:        LegalEntity(src.Spec.LegalEntity),
+		ServiceAccountName: src.Spec.ServiceAccountName,
+		SharedVPC:          sharedVPCFromHub(src.Spec.SharedVPCAccess),
+		AdditionalAPIs:     src.Spec.AdditionalAPIs,
+	}
+	if src.Spec.CCS {
+		dst.Spec.CCS = &ProjectReferenceCCSSpec{SecretRef: secretReferenceFromHub(src.Spec.CCSSecretRef)}
+	}
+
+	dst.Status = ProjectReferenceStatus{
+		Conditions:               conditionsFromHub(src.Status.Conditions),
+		State:                    ProjectReferenceState(src.Status.State),
+		ProjectCreationOperation: src.Status.ProjectCreationOperation,
+		ServiceAccountKey:        (*ServiceAccountKeyStatus)(src.Status.ServiceAccountKey.DeepCopy()),
+		WorkloadIdentityProvider: src.Status.WorkloadIdentityProvider,
+		Adopted:                  src.Status.Adopted,
+	}
+	for _, api := range src.Status.APIs {
+		dst.Status.APIs = append(dst.Status.APIs, APIStatus(api))
+	}
+	for _, binding := range src.Status.GrantedRoles {
+		dst.Status.GrantedRoles = append(dst.Status.GrantedRoles, IAMBinding(binding))
+	}
+	return nil
+}
@@ -0,0 +1,173 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ProjectReferenceSpec defines the desired state of ProjectReference
+// +k8s:openapi-gen=true
+type ProjectReferenceSpec struct {
+	// GCPProjectID is the ID of the project, it is generated by the operator unless the project belongs to the customer
+	// +optional
+	GCPProjectID string `json:"gcpProjectID,omitempty"`
+	// ProjectClaimRef is the ProjectClaim the ProjectReference was created for
+	ProjectClaimRef ObjectReference `json:"projectClaimRef"`
+	LegalEntity     LegalEntity     `json:"legalEntity"`
+	// CCS configures the customer's project to use instead of creating one
+	// +optional
+	CCS *ProjectReferenceCCSSpec `json:"ccs,omitempty"`
+	// ServiceAccountName is the name of the managed service account
+	// +optional
+	ServiceAccountName string `json:"serviceAccountName,omitempty"`
+	// SharedVPC grants access to a shared VPC when set
+	// +optional
+	SharedVPC *SharedVPCSpec `json:"sharedVPC,omitempty"`
+	// AdditionalAPIs are GCP service APIs to enable on the project on top of the operator's default list
+	// +listType=atomic
+	// +optional
+	AdditionalAPIs []string `json:"additionalAPIs,omitempty"`
+}
+
+// ProjectReferenceCCSSpec configures the customer's project of a ProjectReference, whose ID is the GCPProjectID
+// +k8s:openapi-gen=true
+type ProjectReferenceCCSSpec struct {
+	// SecretRef is the secret with the service account key of the customer, in the namespace of the ProjectClaim
+	SecretRef corev1.SecretReference `json:"secretRef"`
+}
+
+// ProjectReferenceStatus defines the observed state of ProjectReference
+// +k8s:openapi-gen=true
+type ProjectReferenceStatus struct {
+	// +listType=map
+	// +listMapKey=type
+	// +optional
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+	// +optional
+	State ProjectReferenceState `json:"state,omitempty"`
+	// ProjectCreationOperation is the name of the GCP operation creating the project.
+	// It is set until the operation is done.
+	// +optional
+	ProjectCreationOperation string `json:"projectCreationOperation,omitempty"`
+	// APIs are the GCP service APIs the operator enables on the project, in the order they are enabled
+	// +listType=map
+	// +listMapKey=name
+	// +optional
+	APIs []APIStatus `json:"apis,omitempty"`
+	// GrantedRoles are the project roles the operator granted, per IAM member
+	// +listType=map
+	// +listMapKey=member
+	// +optional
+	GrantedRoles []IAMBinding `json:"grantedRoles,omitempty"`
+	// ServiceAccountKey is the service account key in the credentials secret
+	// +optional
+	ServiceAccountKey *ServiceAccountKeyStatus `json:"serviceAccountKey,omitempty"`
+	// WorkloadIdentityProvider is the resource name of the workload identity provider
+	// the credentials secret exchanges tokens with, in the WorkloadIdentityFederation credential mode
+	// +optional
+	WorkloadIdentityProvider string `json:"workloadIdentityProvider,omitempty"`
+	// Adopted is true if the operator took over an existing project instead of creating it
+	// +optional
+	Adopted bool `json:"adopted,omitempty"`
+}
+
+// ServiceAccountKeyStatus tracks the service account key in the credentials secret and its rotation
+// +k8s:openapi-gen=true
+type ServiceAccountKeyStatus struct {
+	// Name is the resource name of the key
+	Name string `json:"name"`
+	// CreationTime is when the key was created
+	CreationTime metav1.Time `json:"creationTime"`
+	// NextRotationTime is when the key gets replaced, it is unset if keys aren't rotated
+	// +optional
+	NextRotationTime *metav1.Time `json:"nextRotationTime,omitempty"`
+	// PreviousKeysExpirationTime is when the keys replaced by the last rotation get deleted
+	// +optional
+	PreviousKeysExpirationTime *metav1.Time `json:"previousKeysExpirationTime,omitempty"`
+	// PreviousKeyNames are the resource names of the keys replaced by the last rotation, which get deleted at PreviousKeysExpirationTime
+	// +listType=atomic
+	// +optional
+	PreviousKeyNames []string `json:"previousKeyNames,omitempty"`
+}
+
+// IAMBinding lists the project roles granted to an IAM member
+// +k8s:openapi-gen=true
+type IAMBinding struct {
+	// Member is the IAM member, e.g. group:sre@example.com
+	Member string `json:"member"`
+	// Roles granted to the member
+	// +listType=atomic
+	Roles []string `json:"roles"`
+}
+
+// APIStatus is the state of a GCP service API on the project
+// +k8s:openapi-gen=true
+type APIStatus struct {
+	// Name of the API, e.g. compute.googleapis.com
+	Name string `json:"name"`
+	// Enabled is true once the API is enabled on the project
+	Enabled bool `json:"enabled"`
+	// Message is the error of the last attempt to enable the API
+	// +optional
+	Message string `json:"message,omitempty"`
+}
+
+// ProjectReferenceState is a valid value from ProjectReference.Status
+// +kubebuilder:validation:Enum=Creating;Ready;Error;Verification
+type ProjectReferenceState string
+
+const (
+	// ProjectReferenceStatusCreating creating status for a ProjectReference CR
+	ProjectReferenceStatusCreating ProjectReferenceState = "Creating"
+	// ProjectReferenceStatusReady ready status for a ProjectReference CR
+	ProjectReferenceStatusReady ProjectReferenceState = "Ready"
+	// ProjectReferenceStatusError error status for a ProjectReference CR
+	ProjectReferenceStatusError ProjectReferenceState = "Error"
+	// ProjectReferenceStatusVerification pending verification status for a ProjectReference CR
+	ProjectReferenceStatusVerification ProjectReferenceState = "Verification"
+)
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +k8s:openapi-gen=true
+// +kubebuilder:printcolumn:name="State",type="string",JSONPath=".status.state",description="Status of the ProjectReference"
+// +kubebuilder:printcolumn:name="ClaimName",type="string",JSONPath=".spec.projectClaimRef.name",description="Name of corresponding project claim CR"
+// +kubebuilder:printcolumn:name="ClaimNameSpace",type="string",JSONPath=".spec.projectClaimRef.namespace",description="Namesspace of corresponding project claim CR"
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp",description="Age since the ProjectReference was created"
+// ProjectReference is the Schema for the projectreferences API
+type ProjectReference struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   ProjectReferenceSpec   `json:"spec,omitempty"`
+	Status ProjectReferenceStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// ProjectReferenceList contains a list of ProjectReference
+type ProjectReferenceList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []ProjectReference `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&ProjectReference{}, &ProjectReferenceList{})
+}
@@ -19,14 +19,25 @@ package projectclaim
 import (
 	"context"
 
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/events"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	gcpv1alpha1 "github.com/openshift/gcp-project-operator/api/v1alpha1"
 	condition "github.com/openshift/gcp-project-operator/pkg/condition"
+	"github.com/openshift/gcp-project-operator/pkg/configmap"
+	"github.com/openshift/gcp-project-operator/pkg/tracing"
 	gcputil "github.com/openshift/gcp-project-operator/pkg/util"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
 	ctrl "sigs.k8s.io/controller-runtime"
 )
 
@@ -51,12 +62,14 @@ type CustomResourceAdapter interface {
 // ProjectClaimReconciler reconciles a ProjectClaim object
 type ProjectClaimReconciler struct {
 	client.Client
-	Scheme *runtime.Scheme
+	Scheme   *runtime.Scheme
+	Recorder events.EventRecorder
 }
 
 //+kubebuilder:rbac:groups=gcp.managed.openshift.io,resources=projectclaims,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups=gcp.managed.openshift.io,resources=projectclaims/status,verbs=get;update;patch
 //+kubebuilder:rbac:groups=gcp.managed.openshift.io,resources=projectclaims/finalizers,verbs=update
+//+kubebuilder:rbac:groups=events.k8s.io,resources=events,verbs=create;patch
 
 // Reconcile is part of the main kubernetes reconciliation loop which aims to
 // move the current state of the cluster closer to the desired state.
@@ -69,10 +82,15 @@ type ProjectClaimReconciler struct {
 // - https://pkg.go.dev/sigs.k8s.io/controller-runtime@v0.11.0/pkg/reconcile
 func (r *ProjectClaimReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
 	reqLogger := log.FromContext(ctx)
+	ctx, span := tracing.Tracer().Start(ctx, "ProjectClaimReconciler.Reconcile", trace.WithAttributes(
+		tracing.NamespaceKey.String(req.Namespace),
+		tracing.NameKey.String(req.Name),
+	))
+	defer span.End()
 
 	// Fetch the ProjectClaim instance
 	instance := &gcpv1alpha1.ProjectClaim{}
-	err := r.Get(context.TODO(), req.NamespacedName, instance)
+	err := r.Get(ctx, req.NamespacedName, instance)
 	if err != nil {
 		if errors.IsNotFound(err) {
 			// Request object not found, could have been deleted after reconcile request.
@@ -83,11 +101,15 @@ func (r *ProjectClaimReconciler) Reconcile(ctx context.Context, req ctrl.Request
 	}
 
 	conditionManager := condition.NewConditionManager()
-	adapter := NewProjectClaimAdapter(instance, reqLogger, r.Client, conditionManager)
-	result, err := r.ReconcileHandler(adapter)
+	adapter := NewProjectClaimAdapter(ctx, instance, reqLogger, r.Client, conditionManager, r.Recorder)
+	result, err := r.ReconcileHandler(ctx, adapter)
 	reason := "ReconcileError"
 	_, _ = adapter.SetProjectClaimCondition(gcpv1alpha1.ConditionError, reason, err)
 
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
 	return result, err
 }
 
@@ -98,7 +120,8 @@ type ReconcileOperation func() (gcputil.OperationResult, error)
 // Note:
 // The Controller will requeue the Request to be processed again if the returned error is non-nil or
 // Result.Requeue is true, otherwise upon completion it will remove the work from the queue.
-func (r *ProjectClaimReconciler) ReconcileHandler(adapter CustomResourceAdapter) (ctrl.Result, error) {
+// Every operation is traced in a child span of ctx.
+func (r *ProjectClaimReconciler) ReconcileHandler(ctx context.Context, adapter CustomResourceAdapter) (ctrl.Result, error) {
 	operations := []ReconcileOperation{
 		adapter.EnsureProjectClaimFakeProcessed,
 		adapter.EnsureProjectClaimDeletionProcessed,
@@ -113,7 +136,9 @@ func (r *ProjectClaimReconciler) ReconcileHandler(adapter CustomResourceAdapter)
 		adapter.EnsureProjectClaimStatePendingProject,
 	}
 	for _, operation := range operations {
+		_, span := tracing.StartOperation(ctx, operation)
 		result, err := operation()
+		tracing.End(span, err)
 		if err != nil || result.RequeueRequest {
 			return ctrl.Result{RequeueAfter: result.RequeueDelay}, err
 		}
@@ -125,8 +150,58 @@ func (r *ProjectClaimReconciler) ReconcileHandler(adapter CustomResourceAdapter)
 }
 
 // SetupWithManager sets up the controller with the Manager.
+// Besides ProjectClaims it watches their ProjectReferences, the metadata of the secrets they reference and the operator ConfigMap,
+// so their changes are reconciled right away.
 func (r *ProjectClaimReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	return ctrl.NewControllerManagedBy(mgr).
 		For(&gcpv1alpha1.ProjectClaim{}).
+		Watches(&gcpv1alpha1.ProjectReference{}, handler.EnqueueRequestsFromMapFunc(RequestsForProjectReference)).
+		Watches(&corev1.Secret{}, handler.EnqueueRequestsFromMapFunc(r.RequestsForSecret), builder.OnlyMetadata).
+		Watches(&corev1.ConfigMap{}, handler.EnqueueRequestsFromMapFunc(r.RequestsForOperatorConfigMap),
+			builder.WithPredicates(predicate.NewPredicateFuncs(configmap.IsOperatorConfigMap))).
 		Complete(r)
 }
+
+// RequestsForProjectReference returns the request of the ProjectClaim of the ProjectReference reference
+func RequestsForProjectReference(_ context.Context, reference client.Object) []reconcile.Request {
+	projectReference, ok := reference.(*gcpv1alpha1.ProjectReference)
+	if !ok {
+		return nil
+	}
+	link := projectReference.Spec.ProjectClaimCRLink
+	if link.Name == "" {
+		return nil
+	}
+	return []reconcile.Request{{NamespacedName: types.NamespacedName{Namespace: link.Namespace, Name: link.Name}}}
+}
+
+// RequestsForSecret returns the requests of the ProjectClaims referencing secret
+func (r *ProjectClaimReconciler) RequestsForSecret(ctx context.Context, secret client.Object) []reconcile.Request {
+	// ProjectClaims may only reference secrets in their own namespace
+	claims := &gcpv1alpha1.ProjectClaimList{}
+	if err := r.List(ctx, claims, client.InNamespace(secret.GetNamespace())); err != nil {
+		log.FromContext(ctx).Error(err, "could not list the ProjectClaims referencing a secret", "secret", client.ObjectKeyFromObject(secret))
+		return nil
+	}
+	requests := []reconcile.Request{}
+	for _, claim := range claims.Items {
+		if claim.ReferencesSecret(secret.GetNamespace(), secret.GetName()) {
+			requests = append(requests, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(&claim)})
+		}
+	}
+	return requests
+}
+
+// RequestsForOperatorConfigMap returns the requests of all ProjectClaims, whose regions are checked against the operator ConfigMap
+func (r *ProjectClaimReconciler) RequestsForOperatorConfigMap(ctx context.Context, _ client.Object) []reconcile.Request {
+	claims := &gcpv1alpha1.ProjectClaimList{}
+	if err := r.List(ctx, claims); err != nil {
+		log.FromContext(ctx).Error(err, "could not list the ProjectClaims")
+		return nil
+	}
+	requests := make([]reconcile.Request, 0, len(claims.Items))
+	for _, claim := range claims.Items {
+		requests = append(requests, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(&claim)})
+	}
+	return requests
+}
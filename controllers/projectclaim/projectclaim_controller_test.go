@@ -2,9 +2,15 @@ package projectclaim_test
 
 import (
 	"context"
+	"fmt"
 	"time"
 
 	"github.com/openshift/gcp-project-operator/pkg/util/mocks"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace/noop"
 	"go.uber.org/mock/gomock"
 	"k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime/schema"
@@ -15,6 +21,7 @@ import (
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 	. "github.com/openshift/gcp-project-operator/controllers/projectclaim"
+	"github.com/openshift/gcp-project-operator/pkg/tracing"
 	gcputil "github.com/openshift/gcp-project-operator/pkg/util"
 	mockclaim "github.com/openshift/gcp-project-operator/pkg/util/mocks/projectclaim"
 	testStructs "github.com/openshift/gcp-project-operator/pkg/util/mocks/structs"
@@ -77,7 +84,7 @@ var _ = Describe("ProjectclaimController", func() {
 			Context("When the ProjectClaim is fake", func() {
 				It("Creates a Fake Secret, updates ProjectClaim with fake specs, sets status to Ready, and does not requeue", func() {
 					mockAdapter.EXPECT().EnsureProjectClaimFakeProcessed().Return(gcputil.StopProcessing())
-					res, err := reconciler.ReconcileHandler(mockAdapter)
+					res, err := reconciler.ReconcileHandler(context.TODO(), mockAdapter)
 					Expect(err).ToNot(HaveOccurred())
 					Expect(res.Requeue).To(Equal(false))
 					Expect(res.RequeueAfter).To(Equal(0 * time.Second))
@@ -97,7 +104,7 @@ var _ = Describe("ProjectclaimController", func() {
 				Context("When the ProjectReferenceLink does not exist", func() {
 					It("Creates a ProjectReference, Links reference, sets status to Pending, and does not requeue", func() {
 						mockAdapter.EXPECT().EnsureProjectReferenceLink().Return(gcputil.StopProcessing())
-						res, err := reconciler.ReconcileHandler(mockAdapter)
+						res, err := reconciler.ReconcileHandler(context.TODO(), mockAdapter)
 						Expect(err).ToNot(HaveOccurred())
 						Expect(res.Requeue).To(Equal(false))
 						Expect(res.RequeueAfter).To(Equal(0 * time.Second))
@@ -112,7 +119,7 @@ var _ = Describe("ProjectclaimController", func() {
 					Context("When the Finalizer does not exist", func() {
 						It("Adds the finalizer and does not requeue", func() {
 							mockAdapter.EXPECT().EnsureFinalizer().Return(gcputil.StopProcessing())
-							res, err := reconciler.ReconcileHandler(mockAdapter)
+							res, err := reconciler.ReconcileHandler(context.TODO(), mockAdapter)
 							Expect(err).ToNot(HaveOccurred())
 							Expect(res.Requeue).To(Equal(false))
 							Expect(res.RequeueAfter).To(Equal(0 * time.Second))
@@ -127,7 +134,7 @@ var _ = Describe("ProjectclaimController", func() {
 						Context("When it's a CCS cluster", func() {
 							It("Sets finalizer at the ccs secret", func() {
 								mockAdapter.EXPECT().EnsureCCSSecretFinalizer().Return(gcputil.StopProcessing())
-								res, err := reconciler.ReconcileHandler(mockAdapter)
+								res, err := reconciler.ReconcileHandler(context.TODO(), mockAdapter)
 								Expect(err).ToNot(HaveOccurred())
 								Expect(res.Requeue).To(Equal(false))
 								Expect(res.RequeueAfter).To(Equal(0 * time.Second))
@@ -141,7 +148,7 @@ var _ = Describe("ProjectclaimController", func() {
 							})
 							It("Sets the state to PendingProject", func() {
 								mockAdapter.EXPECT().EnsureProjectClaimStatePendingProject()
-								res, err := reconciler.ReconcileHandler(mockAdapter)
+								res, err := reconciler.ReconcileHandler(context.TODO(), mockAdapter)
 								Expect(err).ToNot(HaveOccurred())
 								Expect(res.Requeue).To(Equal(false))
 								Expect(res.RequeueAfter).To(Equal(0 * time.Second))
@@ -153,11 +160,39 @@ var _ = Describe("ProjectclaimController", func() {
 			})
 		})
 
+		Context("When tracing is enabled", func() {
+			var recorder *tracetest.SpanRecorder
+			BeforeEach(func() {
+				recorder = tracetest.NewSpanRecorder()
+				otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+			})
+			AfterEach(func() {
+				otel.SetTracerProvider(noop.NewTracerProvider())
+			})
+			It("Traces every operation in a child span", func() {
+				ctx, parent := tracing.Tracer().Start(context.TODO(), "Reconcile")
+				mockAdapter.EXPECT().EnsureProjectClaimFakeProcessed().Return(gcputil.ContinueProcessing())
+				mockAdapter.EXPECT().EnsureProjectClaimDeletionProcessed().Return(gcputil.RequeueWithError(fmt.Errorf("deletion failed")))
+				_, err := reconciler.ReconcileHandler(ctx, mockAdapter)
+				Expect(err).To(HaveOccurred())
+				parent.End()
+
+				spans := recorder.Ended()
+				Expect(spans).To(HaveLen(3))
+				Expect(spans[0].Name()).To(Equal("EnsureProjectClaimFakeProcessed"))
+				Expect(spans[1].Name()).To(Equal("EnsureProjectClaimDeletionProcessed"))
+				Expect(spans[1].Status().Code).To(Equal(codes.Error))
+				for _, span := range spans[:2] {
+					Expect(span.Parent().SpanID()).To(Equal(parent.SpanContext().SpanID()))
+				}
+			})
+		})
+
 		Context("When the ProjectClaim gets deleted", func() {
 			Context("When the ProjectClaim is fake", func() {
 				It("finalizes the projectclaim", func() {
 					mockAdapter.EXPECT().EnsureProjectClaimFakeProcessed().Return(gcputil.StopProcessing())
-					_, err := reconciler.ReconcileHandler(mockAdapter)
+					_, err := reconciler.ReconcileHandler(context.TODO(), mockAdapter)
 					Expect(err).ToNot(HaveOccurred())
 				})
 			})
@@ -165,7 +200,7 @@ var _ = Describe("ProjectclaimController", func() {
 				It("finalizes the projectclaim", func() {
 					mockAdapter.EXPECT().EnsureProjectClaimFakeProcessed().Return(gcputil.ContinueProcessing())
 					mockAdapter.EXPECT().EnsureProjectClaimDeletionProcessed().Return(gcputil.StopProcessing())
-					_, err := reconciler.ReconcileHandler(mockAdapter)
+					_, err := reconciler.ReconcileHandler(context.TODO(), mockAdapter)
 					Expect(err).ToNot(HaveOccurred())
 				})
 			})
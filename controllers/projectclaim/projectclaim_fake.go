@@ -3,7 +3,6 @@ package projectclaim
 // This file contains the functions for handling a fake ProjectClaim, that doesn't actually allocate resources on GCP
 
 import (
-	"context"
 	"encoding/base64"
 	"fmt"
 
@@ -16,12 +15,12 @@ import (
 )
 
 func (c *ProjectClaimAdapter) CreateFakeSecret() error {
-	if !gcputil.SecretExists(c.client, c.projectClaim.Spec.GCPCredentialSecret.Name, c.projectClaim.Spec.GCPCredentialSecret.Namespace) {
+	if !gcputil.SecretExists(c.ctx, c.client, c.projectClaim.Spec.GCPCredentialSecret.Name, c.projectClaim.Spec.GCPCredentialSecret.Namespace) {
 		privateKeyString, err := base64.StdEncoding.DecodeString("SS1hbS1mYWtlLXBhc3M=")
 		if err != nil {
 			return err
 		}
-		if err := c.client.Create(context.TODO(), gcputil.NewGCPSecretCR(string(privateKeyString), types.NamespacedName{Namespace: c.projectClaim.Spec.GCPCredentialSecret.Namespace, Name: c.projectClaim.Spec.GCPCredentialSecret.Name})); err != nil {
+		if err := c.client.Create(c.ctx, gcputil.NewGCPSecretCR(string(privateKeyString), types.NamespacedName{Namespace: c.projectClaim.Spec.GCPCredentialSecret.Namespace, Name: c.projectClaim.Spec.GCPCredentialSecret.Name})); err != nil {
 			return err
 		}
 	}
@@ -30,7 +29,7 @@ func (c *ProjectClaimAdapter) CreateFakeSecret() error {
 
 func (c *ProjectClaimAdapter) DeleteFakeSecret() error {
 	secret := &corev1.Secret{}
-	err := c.client.Get(context.TODO(), types.NamespacedName{
+	err := c.client.Get(c.ctx, types.NamespacedName{
 		Name:      c.projectClaim.Spec.GCPCredentialSecret.Name,
 		Namespace: c.projectClaim.Spec.GCPCredentialSecret.Namespace},
 		secret,
@@ -39,7 +38,7 @@ func (c *ProjectClaimAdapter) DeleteFakeSecret() error {
 		return err
 	}
 
-	err = c.client.Delete(context.TODO(), secret)
+	err = c.client.Delete(c.ctx, secret)
 	if err != nil {
 		return err
 	}
@@ -59,7 +58,7 @@ func (c *ProjectClaimAdapter) UpdateFakeProjectClaimSpecs() (bool, error) {
 			"fake-az-b",
 			"fake-az-c",
 		}
-		err := c.client.Update(context.TODO(), c.projectClaim)
+		err := c.client.Update(c.ctx, c.projectClaim)
 		if err != nil {
 			return true, err
 		}
@@ -72,7 +71,7 @@ func (c *ProjectClaimAdapter) UpdateFakeProjectClaimState() (bool, error) {
 	if c.projectClaim.Status.State != gcpv1alpha1.ClaimStatusReady {
 		c.projectClaim.Status.Conditions = []gcpv1alpha1.Condition{}
 		c.projectClaim.Status.State = gcpv1alpha1.ClaimStatusReady
-		err := c.client.Status().Update(context.TODO(), c.projectClaim)
+		err := c.client.Status().Update(c.ctx, c.projectClaim)
 		if err != nil {
 			return true, err
 		}
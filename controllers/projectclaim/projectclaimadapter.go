@@ -3,17 +3,18 @@ package projectclaim
 import (
 	"context"
 	"fmt"
-	"time"
 
 	"github.com/go-logr/logr"
 	"github.com/openshift/gcp-project-operator/pkg/configmap"
 	"k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/meta"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/events"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	gcpv1alpha1 "github.com/openshift/gcp-project-operator/api/v1alpha1"
 	condition "github.com/openshift/gcp-project-operator/pkg/condition"
+	"github.com/openshift/gcp-project-operator/pkg/metrics"
 	gcputil "github.com/openshift/gcp-project-operator/pkg/util"
 	operrors "github.com/openshift/gcp-project-operator/pkg/util/errors"
 	corev1 "k8s.io/api/core/v1"
@@ -21,11 +22,13 @@ import (
 )
 
 type ProjectClaimAdapter struct {
+	ctx              context.Context
 	projectClaim     *gcpv1alpha1.ProjectClaim
 	logger           logr.Logger
 	client           client.Client
 	projectReference *gcpv1alpha1.ProjectReference
 	conditionManager condition.Conditions
+	recorder         events.EventRecorder
 }
 
 type ObjectState bool
@@ -40,9 +43,9 @@ const CCSSecretFinalizer string = "finalizer.gcp.managed.openshift.io/ccs" //#no
 const RegionCheckFailed string = "RegionCheckFailed"
 const FakeProjectClaim string = "managed.openshift.com/fake"
 
-func NewProjectClaimAdapter(projectClaim *gcpv1alpha1.ProjectClaim, logger logr.Logger, client client.Client, manager condition.Conditions) *ProjectClaimAdapter {
+func NewProjectClaimAdapter(ctx context.Context, projectClaim *gcpv1alpha1.ProjectClaim, logger logr.Logger, client client.Client, manager condition.Conditions, recorder events.EventRecorder) *ProjectClaimAdapter {
 	projectReference := newMatchingProjectReference(projectClaim)
-	return &ProjectClaimAdapter{projectClaim, logger, client, projectReference, manager}
+	return &ProjectClaimAdapter{ctx, projectClaim, logger, client, projectReference, manager, recorder}
 }
 
 // newMatchingProjectReference creates a ProjectReference CR from a ProjectClaim
@@ -50,12 +53,16 @@ func newMatchingProjectReference(projectClaim *gcpv1alpha1.ProjectClaim) *gcpv1a
 	gcpProjectID := ""
 	if projectClaim.Spec.CCS {
 		gcpProjectID = projectClaim.Spec.CCSProjectID
+	} else if projectClaim.Spec.RestoreDeletedProject || projectClaim.Spec.AdoptProject {
+		// projects pending deletion and projects to adopt are looked up by their existing ID
+		gcpProjectID = projectClaim.Spec.GCPProjectID
 	}
 
+	link := projectClaim.ProjectReferenceLink()
 	return &gcpv1alpha1.ProjectReference{
 		ObjectMeta: metav1.ObjectMeta{
-			Name:      projectClaim.GetNamespace() + "-" + projectClaim.GetName(),
-			Namespace: gcpv1alpha1.ProjectReferenceNamespace,
+			Name:      link.Name,
+			Namespace: link.Namespace,
 		},
 		Spec: gcpv1alpha1.ProjectReferenceSpec{
 			GCPProjectID: gcpProjectID,
@@ -63,10 +70,13 @@ func newMatchingProjectReference(projectClaim *gcpv1alpha1.ProjectClaim) *gcpv1a
 				Name:      projectClaim.GetName(),
 				Namespace: projectClaim.GetNamespace(),
 			},
-			LegalEntity:     *projectClaim.Spec.LegalEntity.DeepCopy(),
-			CCS:             projectClaim.Spec.CCS,
-			CCSSecretRef:    *projectClaim.Spec.CCSSecretRef.DeepCopy(),
-			SharedVPCAccess: projectClaim.Spec.SharedVPCAccess,
+			LegalEntity:  *projectClaim.Spec.LegalEntity.DeepCopy(),
+			CCS:          projectClaim.Spec.CCS,
+			CCSSecretRef: *projectClaim.Spec.CCSSecretRef.DeepCopy(),
+			// generated here to spare the ProjectReference controller an update
+			ServiceAccountName: gcputil.ManagedServiceAccountName(projectClaim.GetUID()),
+			SharedVPCAccess:    projectClaim.Spec.SharedVPCAccess,
+			AdditionalAPIs:     append([]string(nil), projectClaim.Spec.AdditionalAPIs...),
 		},
 	}
 }
@@ -74,7 +84,7 @@ func newMatchingProjectReference(projectClaim *gcpv1alpha1.ProjectClaim) *gcpv1a
 // ProjectReferenceExists checks whether a matching ProjectReference already exists
 func (c *ProjectClaimAdapter) ProjectReferenceExists() (bool, error) {
 	found := &gcpv1alpha1.ProjectReference{}
-	err := c.client.Get(context.TODO(), types.NamespacedName{Name: c.projectReference.Name, Namespace: c.projectReference.Namespace}, found)
+	err := c.client.Get(c.ctx, types.NamespacedName{Name: c.projectReference.Name, Namespace: c.projectReference.Namespace}, found)
 	if err != nil {
 		if errors.IsNotFound(err) {
 			return false, nil
@@ -98,12 +108,12 @@ func (c *ProjectClaimAdapter) EnsureProjectClaimValidated() (gcputil.OperationRe
 	return gcputil.ContinueProcessing()
 }
 
-// EnsureProjectClaimDeletionProcessed deletes ProjectClaim in cases a deletion was triggered
+// EnsureProjectClaimDeletionProcessed deletes ProjectClaim in cases a deletion was triggered.
+// The ProjectReference is watched, so the ProjectClaim is finalized as soon as the ProjectReference is gone.
 func (c *ProjectClaimAdapter) EnsureProjectClaimDeletionProcessed() (gcputil.OperationResult, error) {
 	if c.IsProjectClaimDeletion() {
-		crState, err := c.FinalizeProjectClaim()
-		if crState == ObjectUnchanged || err != nil {
-			return gcputil.RequeueAfter(5*time.Second, err)
+		if _, err := c.FinalizeProjectClaim(); err != nil {
+			return gcputil.RequeueWithError(err)
 		}
 		return gcputil.StopProcessing()
 	}
@@ -123,7 +133,15 @@ func (c *ProjectClaimAdapter) IsProjectReferenceDeletion() bool {
 // EnsureProjectClaimFinalizerDeleted removes finalizer of a ProjectClaim
 func (c *ProjectClaimAdapter) EnsureProjectClaimFinalizerDeleted() error {
 	c.logger.Info("Deleting ProjectClaim Finalizer")
-	return c.deleteFinalizer(c.projectClaim, ProjectClaimFinalizer)
+	finalized := gcputil.Contains(c.projectClaim.GetFinalizers(), ProjectClaimFinalizer)
+	if err := c.deleteFinalizer(c.projectClaim, ProjectClaimFinalizer); err != nil {
+		return err
+	}
+	if finalized {
+		metrics.ObserveProjectClaimDeleted(c.projectClaim)
+		c.recorder.Eventf(c.projectClaim, nil, corev1.EventTypeNormal, gcputil.EventReasonDeletionCompleted, "Delete", "Deleted the ProjectReference, the GCP project was cleaned up with deletion policy %s", c.projectClaim.GetDeletionPolicy())
+	}
+	return nil
 }
 
 // EnsureCCSSecretFinalizerDeleted deletes the finalizer for the access credentials secret to the CCS gcp project
@@ -154,7 +172,7 @@ func (c *ProjectClaimAdapter) deleteFinalizer(object client.Object, finalizer st
 	finalizers := metadata.GetFinalizers()
 	if gcputil.Contains(finalizers, finalizer) {
 		metadata.SetFinalizers(gcputil.Filter(finalizers, finalizer))
-		return c.client.Update(context.TODO(), object)
+		return c.client.Update(c.ctx, object)
 	}
 	return nil
 }
@@ -168,10 +186,11 @@ func (c *ProjectClaimAdapter) FinalizeProjectClaim() (ObjectState, error) {
 
 	projectReferenceDeletionRequested := c.IsProjectReferenceDeletion()
 	if projectReferenceExists && !projectReferenceDeletionRequested {
-		err := c.client.Delete(context.TODO(), c.projectReference)
+		err := c.client.Delete(c.ctx, c.projectReference)
 		if err != nil {
 			return ObjectUnchanged, err
 		}
+		c.recorder.Eventf(c.projectClaim, c.projectReference, corev1.EventTypeNormal, gcputil.EventReasonDeletionStarted, "Delete", "Deleting ProjectReference %s/%s", c.projectReference.Namespace, c.projectReference.Name)
 	}
 
 	// Assure the finalizer is not deleted as long as ProjectReference exists
@@ -196,7 +215,7 @@ func (c *ProjectClaimAdapter) EnsureProjectClaimInitialized() (gcputil.Operation
 
 	if c.projectClaim.Status.Conditions == nil {
 		c.projectClaim.Status.Conditions = []gcpv1alpha1.Condition{}
-		err := c.client.Status().Update(context.TODO(), c.projectClaim)
+		err := c.client.Status().Update(c.ctx, c.projectClaim)
 		if err != nil {
 			return gcputil.RequeueWithError(operrors.Wrap(err, "failed to initialize projectclaim"))
 		}
@@ -215,7 +234,7 @@ func (c *ProjectClaimAdapter) EnsureProjectReferenceLink() (gcputil.OperationRes
 		return gcputil.ContinueProcessing()
 	}
 	c.projectClaim.Spec.ProjectReferenceCRLink = expectedLink
-	err := c.client.Update(context.TODO(), c.projectClaim)
+	err := c.client.Update(c.ctx, c.projectClaim)
 	if err != nil {
 		return gcputil.RequeueWithError(err)
 	}
@@ -255,7 +274,7 @@ func (c *ProjectClaimAdapter) addFinalizer(object client.Object, finalizer strin
 	finalizers := metadata.GetFinalizers()
 	if !gcputil.Contains(finalizers, finalizer) {
 		metadata.SetFinalizers(append(finalizers, finalizer))
-		return c.client.Update(context.TODO(), object)
+		return c.client.Update(c.ctx, object)
 	}
 	return nil
 }
@@ -267,7 +286,7 @@ func (c *ProjectClaimAdapter) getCCSSecret() (*corev1.Secret, error) {
 		Namespace: c.projectClaim.Spec.CCSSecretRef.Namespace,
 		Name:      c.projectClaim.Spec.CCSSecretRef.Name,
 	}
-	err := c.client.Get(context.TODO(), secretName, secret)
+	err := c.client.Get(c.ctx, secretName, secret)
 	if err != nil {
 		return nil, err
 	}
@@ -283,7 +302,7 @@ func (c *ProjectClaimAdapter) EnsureProjectReferenceExists() (gcputil.OperationR
 
 	if !projectReferenceExists {
 		return gcputil.RequeueOnErrorOrContinue(
-			c.client.Create(context.TODO(), c.projectReference))
+			c.client.Create(c.ctx, c.projectReference))
 	}
 	return gcputil.ContinueProcessing()
 }
@@ -345,7 +364,7 @@ func (c *ProjectClaimAdapter) IsRegionSupported() (bool, error) {
 		return true, nil
 	}
 
-	operatorConfigMap, err := configmap.GetOperatorConfigMap(c.client)
+	operatorConfigMap, err := configmap.GetOperatorConfigMap(c.ctx, c.client)
 	if err != nil {
 		return true, operrors.Wrap(err, "could not find the OperatorConfigMap")
 	}
@@ -364,6 +383,9 @@ func (c *ProjectClaimAdapter) EnsureRegionSupported() (gcputil.OperationResult,
 	}
 
 	if !supported {
+		if c.projectClaim.Status.State != gcpv1alpha1.ClaimStatusError {
+			c.recorder.Eventf(c.projectClaim, nil, corev1.EventTypeWarning, gcputil.EventReasonRegionNotSupported, "ValidateRegion", "Region %s is not supported", c.projectClaim.Spec.Region)
+		}
 		c.projectClaim.Status.State = gcpv1alpha1.ClaimStatusError
 		err = operrors.ErrRegionNotSupported
 	}
@@ -376,7 +398,7 @@ func (c *ProjectClaimAdapter) EnsureRegionSupported() (gcputil.OperationResult,
 
 // StatusUpdate updates the project claim status
 func (c *ProjectClaimAdapter) StatusUpdate() error {
-	if err := c.client.Status().Update(context.TODO(), c.projectClaim); err != nil {
+	if err := c.client.Status().Update(c.ctx, c.projectClaim); err != nil {
 		return operrors.Wrap(err, fmt.Sprintf("failed to update ProjectClaim state for %s", c.projectClaim.Name))
 	}
 
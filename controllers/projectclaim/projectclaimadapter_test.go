@@ -12,6 +12,7 @@ import (
 	"k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/tools/events"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	. "github.com/onsi/ginkgo"
@@ -36,6 +37,7 @@ var _ = Describe("Customresourceadapter", func() {
 		mockConditions      *mockconditions.MockConditions
 		ccsSecret           corev1.Secret
 		GCPCredentialSecret corev1.Secret
+		recorder            *events.FakeRecorder
 	)
 
 	BeforeEach(func() {
@@ -44,6 +46,7 @@ var _ = Describe("Customresourceadapter", func() {
 		mockClient = mocks.NewMockClient(mockCtrl)
 		mockConditions = mockconditions.NewMockConditions(mockCtrl)
 		mockStatusWriter = mocks.NewMockStatusWriter(mockCtrl)
+		recorder = events.NewFakeRecorder(10)
 		ccsSecret = corev1.Secret{
 			ObjectMeta: metav1.ObjectMeta{
 				Name:      "secret-name",
@@ -58,7 +61,7 @@ var _ = Describe("Customresourceadapter", func() {
 		}
 	})
 	JustBeforeEach(func() {
-		adapter = NewProjectClaimAdapter(projectClaim, logf.Log.WithName("Test Logger"), mockClient, mockConditions)
+		adapter = NewProjectClaimAdapter(context.TODO(), projectClaim, logf.Log.WithName("Test Logger"), mockClient, mockConditions, recorder)
 	})
 
 	AfterEach(func() {
@@ -148,6 +151,7 @@ disabledRegions:
 					res, err := adapter.EnsureRegionSupported()
 					Expect(err).NotTo(HaveOccurred())
 					Expect(res).To(Equal(util.StopOperationResult()))
+					Expect(recorder.Events).To(Receive(Equal("Warning RegionNotSupported Region europe-west3 is not supported")))
 				})
 			})
 			Context("when it is a CCS cluster", func() {
@@ -191,6 +195,7 @@ disabledRegions:
 					Expect(err).ToNot(HaveOccurred())
 					Expect(crStatus).To(Equal(ObjectModified))
 					Expect(matcher.ActualProjectClaim.Finalizers).ToNot(ContainElement(ProjectClaimFinalizer))
+					Expect(recorder.Events).To(Receive(HavePrefix("Normal DeletionCompleted")))
 				})
 			})
 
@@ -229,6 +234,7 @@ disabledRegions:
 				crStatus, err := adapter.FinalizeProjectClaim()
 				Expect(err).ToNot(HaveOccurred())
 				Expect(crStatus).To(Equal(ObjectUnchanged))
+				Expect(recorder.Events).To(Receive(HavePrefix("Normal DeletionStarted")))
 			})
 		})
 	})
@@ -478,6 +484,8 @@ disabledRegions:
 				Expect(matcher.ActualProjectReference.Spec.ProjectClaimCRLink.Name).To(Equal(projectClaim.Name))
 				Expect(matcher.ActualProjectReference.Spec.ProjectClaimCRLink.Namespace).To(Equal(projectClaim.Namespace))
 				Expect(matcher.ActualProjectReference.Spec.LegalEntity).To(Equal(projectClaim.Spec.LegalEntity))
+				Expect(matcher.ActualProjectReference.Spec.ServiceAccountName).To(MatchRegexp(`^osd-managed-admin-[a-z0-9]{8}$`))
+				Expect(matcher.ActualProjectReference.Spec.ServiceAccountName).To(Equal(util.ManagedServiceAccountName(projectClaim.GetUID())))
 			})
 		})
 
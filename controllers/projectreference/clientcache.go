@@ -0,0 +1,111 @@
+package projectreference
+
+import (
+	"sync"
+	"time"
+
+	"golang.org/x/sync/singleflight"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/openshift/gcp-project-operator/pkg/gcpclient"
+)
+
+// clientIdleTimeout is how long a GCP client is cached without being used,
+// e.g. after the projects using its credentials were deleted
+const clientIdleTimeout = time.Hour
+
+// clientKey identifies the GCP client built from a version of a credentials secret
+type clientKey struct {
+	secret          types.NamespacedName
+	secretUID       types.UID
+	resourceVersion string
+}
+
+func (k clientKey) String() string {
+	return k.secret.String() + "/" + string(k.secretUID) + "/" + k.resourceVersion
+}
+
+type cachedClient struct {
+	client   gcpclient.Client
+	lastUsed time.Time
+}
+
+// clientCache reuses the GCP clients across reconciles, so their services and OAuth token sources
+// aren't built from scratch every time. The clients are cached per credentials secret and shared by
+// all the projects using it, see gcpclient.Client.ForProject. A client is rebuilt once its secret changes.
+// The zero value is ready to use.
+type clientCache struct {
+	mu      sync.Mutex
+	clients map[clientKey]*cachedClient
+	// builds makes concurrent reconciles wait for the client being built from the same secret,
+	// without holding mu while building it
+	builds singleflight.Group
+}
+
+// get returns the cached client of secret, or the client returned by build
+func (c *clientCache) get(secret *corev1.Secret, build func() (gcpclient.Client, error)) (gcpclient.Client, error) {
+	key := clientKey{
+		secret:          types.NamespacedName{Namespace: secret.Namespace, Name: secret.Name},
+		secretUID:       secret.UID,
+		resourceVersion: secret.ResourceVersion,
+	}
+	if client, ok := c.lookup(key); ok {
+		return client, nil
+	}
+
+	client, err, _ := c.builds.Do(key.String(), func() (any, error) {
+		if client, ok := c.lookup(key); ok {
+			return client, nil
+		}
+		client, err := build()
+		if err != nil {
+			return nil, err
+		}
+		c.store(key, client)
+		return client, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return client.(gcpclient.Client), nil
+}
+
+// lookup returns the cached client of key and prunes the idle ones
+func (c *clientCache) lookup(key clientKey) (gcpclient.Client, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	now := time.Now()
+	c.prune(now)
+
+	cached, ok := c.clients[key]
+	if !ok {
+		return nil, false
+	}
+	cached.lastUsed = now
+	return cached.client, true
+}
+
+// store caches client for key, replacing the clients built from older versions of the same secret
+func (c *clientCache) store(key clientKey, client gcpclient.Client) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.clients == nil {
+		c.clients = map[clientKey]*cachedClient{}
+	}
+	for cachedKey := range c.clients {
+		if cachedKey.secret == key.secret && cachedKey.secretUID == key.secretUID {
+			delete(c.clients, cachedKey)
+		}
+	}
+	c.clients[key] = &cachedClient{client: client, lastUsed: time.Now()}
+}
+
+// prune drops the clients that weren't used for clientIdleTimeout. Callers must hold c.mu.
+func (c *clientCache) prune(now time.Time) {
+	for key, cached := range c.clients {
+		if now.Sub(cached.lastUsed) > clientIdleTimeout {
+			delete(c.clients, key)
+		}
+	}
+}
@@ -2,22 +2,29 @@ package projectreference
 
 import (
 	"context"
-	"crypto/sha256"
 	"encoding/base64"
+	"encoding/json"
 	"fmt"
-	"net/http"
+	"maps"
+	"path"
+	"reflect"
+	"slices"
 	"strings"
 	"time"
 
 	"github.com/go-logr/logr"
-	"github.com/google/uuid"
 	"github.com/openshift/gcp-project-operator/pkg/configmap"
 	"github.com/openshift/gcp-project-operator/pkg/gcpclient"
+	"github.com/openshift/gcp-project-operator/pkg/metrics"
+	"github.com/openshift/gcp-project-operator/pkg/naming"
 	"github.com/openshift/gcp-project-operator/pkg/util"
+	"golang.org/x/oauth2/google"
 	"google.golang.org/api/cloudresourcemanager/v1"
-	"google.golang.org/api/googleapi"
+	cloudresourcemanagerv3 "google.golang.org/api/cloudresourcemanager/v3"
 	"google.golang.org/api/iam/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/events"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	gcpv1alpha1 "github.com/openshift/gcp-project-operator/api/v1alpha1"
@@ -25,12 +32,33 @@ import (
 	operrors "github.com/openshift/gcp-project-operator/pkg/util/errors"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
-	utilrand "k8s.io/apimachinery/pkg/util/rand"
 )
 
 const (
-	osdServiceAccountNameDefault = "osd-managed-admin"
+	osdServiceAccountNameDefault = util.ManagedServiceAccountNamePrefix
 	FinalizerName                = "finalizer.gcp.managed.openshift.io"
+
+	// projectCreationPollInterval is how often a pending project creation operation is checked
+	projectCreationPollInterval = 5 * time.Second
+
+	// projectVisibilityTimeout is how long GCP may take to show a created project, or to fail the creation of an ID
+	// with a conflict while the project isn't visible. A project invisible for longer was deleted or purged outside
+	// of the operator, and an ID conflicting for longer belongs to a project of someone else.
+	projectVisibilityTimeout = 10 * time.Minute
+
+	// workloadIdentityPoolID and workloadIdentityProviderID name the workload identity federation
+	// resources trusted by credentials secrets in the WorkloadIdentityFederation credential mode
+	workloadIdentityPoolID     = "osd-managed"
+	workloadIdentityProviderID = "osd-managed-oidc"
+	// workloadIdentityPollInterval is how often new workload identity federation resources are checked until they are usable
+	workloadIdentityPollInterval = 5 * time.Second
+	// defaultWorkloadIdentityTokenFile is where OpenShift components find their projected service account token
+	defaultWorkloadIdentityTokenFile = "/var/run/secrets/openshift/serviceaccount/token"
+
+	// adoptionRetryInterval is how often a project that can't be adopted is checked again
+	adoptionRetryInterval = 5 * time.Minute
+	// maxProjectIDAttempts is how often a project ID is generated until one isn't in use
+	maxProjectIDAttempts = 5
 )
 
 // OSDRequiredAPIS is list of API's, required to setup
@@ -94,6 +122,7 @@ var OSDSharedVPCRoles = []string{
 
 // ReferenceAdapter is used to do all the processing of the ProjectReference type inside the reconcile loop
 type ReferenceAdapter struct {
+	ctx              context.Context
 	ProjectClaim     *gcpv1alpha1.ProjectClaim
 	ProjectReference *gcpv1alpha1.ProjectReference
 	logger           logr.Logger
@@ -101,22 +130,26 @@ type ReferenceAdapter struct {
 	gcpClient        gcpclient.Client
 	conditionManager condition.Conditions
 	OperatorConfig   configmap.OperatorConfigMap
+	recorder         events.EventRecorder
 }
 
 // NewReferenceAdapter creates an adapter to turn what is requested in a ProjectReference into a GCP project and write the output back.
 func NewReferenceAdapter(
+	ctx context.Context,
 	projectReference *gcpv1alpha1.ProjectReference,
 	logger logr.Logger, client client.Client,
 	gcpClient gcpclient.Client,
 	manager condition.Conditions,
 	cm configmap.OperatorConfigMap,
+	recorder events.EventRecorder,
 ) (*ReferenceAdapter, error) {
-	projectClaim, err := getMatchingClaimLink(projectReference, client)
+	projectClaim, err := getMatchingClaimLink(ctx, projectReference, client)
 	if err != nil {
 		return &ReferenceAdapter{}, err
 	}
 
 	r := &ReferenceAdapter{
+		ctx:              ctx,
 		ProjectClaim:     projectClaim,
 		ProjectReference: projectReference,
 		logger:           logger,
@@ -124,10 +157,26 @@ func NewReferenceAdapter(
 		gcpClient:        gcpClient,
 		conditionManager: manager,
 		OperatorConfig:   cm,
+		recorder:         recorder,
 	}
 	return r, nil
 }
 
+// event records an event on the ProjectReference, and on its ProjectClaim where users look for it
+func (r *ReferenceAdapter) event(eventtype, reason, action, note string, args ...interface{}) {
+	r.recorder.Eventf(r.ProjectReference, nil, eventtype, reason, action, note, args...)
+	r.recorder.Eventf(r.ProjectClaim, r.ProjectReference, eventtype, reason, action, note, args...)
+}
+
+// recordGCPError records a Warning event if err was returned by a GCP API
+func (r *ReferenceAdapter) recordGCPError(err error) {
+	gcpErr, ok := operrors.GCPError(err)
+	if !ok {
+		return
+	}
+	r.event(corev1.EventTypeWarning, util.EventReasonGCPError, "Reconcile", "%s", util.EventNote(fmt.Sprintf("GCP API call failed with %d: %s", gcpErr.Code, gcpErr.Message)))
+}
+
 // EnsureProjectClaimReady sets the ProjectClaim to Ready after the ProjectReference was reconciled correctly and gcp project has been created
 func EnsureProjectClaimReady(r *ReferenceAdapter) (util.OperationResult, error) {
 	if r.ProjectReference.Status.State != gcpv1alpha1.ProjectReferenceStatusReady {
@@ -135,7 +184,25 @@ func EnsureProjectClaimReady(r *ReferenceAdapter) (util.OperationResult, error)
 	}
 
 	if r.ProjectReference.Status.State == gcpv1alpha1.ProjectReferenceStatusReady && r.ProjectClaim.Status.State == gcpv1alpha1.ClaimStatusReady {
-		return util.StopProcessing()
+		if err := r.seedGrantedRoles(); err != nil {
+			return util.RequeueWithError(err)
+		}
+		if !r.grantedRolesConverged() {
+			r.logger.Info("Configured role sets changed, updating IAM policy")
+			return util.RequeueOnErrorOrStop(r.convergeGrantedRoles())
+		}
+		if result, err := r.convergeCredentialMode(); err != nil || result.RequeueRequest {
+			return result, err
+		}
+		driftResult, err := r.checkDrift()
+		if err != nil {
+			return driftResult, err
+		}
+		rotationResult, err := r.rotateCredentials()
+		if err != nil {
+			return rotationResult, err
+		}
+		return earliestRequeue(driftResult, rotationResult), nil
 	}
 
 	res, err := r.ensureClaimAvailabilityZonesSet()
@@ -145,32 +212,220 @@ func EnsureProjectClaimReady(r *ReferenceAdapter) (util.OperationResult, error)
 
 	idModified := r.ensureClaimProjectIDSet()
 	if idModified {
-		err := r.kubeClient.Update(context.TODO(), r.ProjectClaim)
+		err := r.kubeClient.Update(r.ctx, r.ProjectClaim)
 		if err != nil {
 			return util.RequeueWithError(operrors.Wrap(err, "error updating ProjectClaim spec"))
 		}
 	}
+	becameReady := r.ProjectClaim.Status.State != gcpv1alpha1.ClaimStatusReady
 	r.ProjectClaim.Status.State = gcpv1alpha1.ClaimStatusReady
-	if err := r.kubeClient.Status().Update(context.TODO(), r.ProjectClaim); err != nil {
+	if err := r.kubeClient.Status().Update(r.ctx, r.ProjectClaim); err != nil {
 		return util.RequeueWithError(operrors.Wrap(err, "error updating ProjectClaim status"))
 	}
+	if becameReady {
+		metrics.ObserveProjectClaimReady(r.ProjectClaim)
+	}
 	return util.StopProcessing()
 }
 
-// VerifyProjectClaimPending waits until the ProjectClaim has been initialized, meaning is in state PendingProject
+// VerifyProjectClaimPending waits until the ProjectClaim has been initialized, meaning is in state PendingProject.
+// The ProjectClaim is watched, so its initialization reconciles the ProjectReference again.
 func VerifyProjectClaimPending(r *ReferenceAdapter) (util.OperationResult, error) {
 	if r.ProjectClaim.Status.State != gcpv1alpha1.ClaimStatusPendingProject {
-		return util.RequeueAfter(5*time.Second, nil)
+		return util.StopProcessing()
 	}
 	return util.ContinueProcessing()
 }
 
+// projectDrift lists the differences between a project and its configuration
+type projectDrift struct {
+	differences           []string
+	serviceAccountMissing bool
+}
+
+// checkDrift checks Ready projects for drift every ResyncInterval, records the result in the Drifted condition,
+// and repairs the drift if RepairDrift is configured.
+func (r *ReferenceAdapter) checkDrift() (util.OperationResult, error) {
+	interval := r.OperatorConfig.ResyncInterval
+	if interval <= 0 {
+		return util.StopProcessing()
+	}
+
+	conditions := &r.ProjectReference.Status.Conditions
+	if r.conditionManager.HasCondition(conditions, gcpv1alpha1.ConditionDrifted) {
+		condition, _ := r.conditionManager.FindCondition(conditions, gcpv1alpha1.ConditionDrifted)
+		// projects that drifted are checked on every reconcile until they are repaired
+		if next := condition.LastProbeTime.Add(interval); condition.Status == corev1.ConditionFalse && time.Now().Before(next) {
+			return util.RequeueAfter(time.Until(next), nil)
+		}
+	}
+
+	r.logger.V(1).Info("Checking project for drift")
+	drift, err := r.detectDrift()
+	if err != nil {
+		return util.RequeueWithError(operrors.Wrap(err, "could not check project for drift"))
+	}
+
+	differences := strings.Join(drift.differences, "; ")
+	switch {
+	case len(drift.differences) == 0:
+		r.conditionManager.SetCondition(conditions, gcpv1alpha1.ConditionDrifted, corev1.ConditionFalse, "NoDriftDetected", "The project matches its configuration")
+	case !r.OperatorConfig.RepairDrift:
+		r.logger.Info("Project drifted", "drift", differences)
+		r.conditionManager.SetCondition(conditions, gcpv1alpha1.ConditionDrifted, corev1.ConditionTrue, "DriftDetected", differences)
+	default:
+		r.logger.Info("Repairing project drift", "drift", differences)
+		result, err := r.repairDrift(drift)
+		if err != nil || result.RequeueRequest {
+			r.conditionManager.SetCondition(conditions, gcpv1alpha1.ConditionDrifted, corev1.ConditionTrue, "DriftRepairInProgress", differences)
+			if updateErr := r.StatusUpdate(); updateErr != nil && err == nil {
+				return util.RequeueWithError(updateErr)
+			}
+			if err != nil {
+				return util.RequeueWithError(operrors.Wrap(err, "could not repair project drift"))
+			}
+			return result, nil
+		}
+		r.conditionManager.SetCondition(conditions, gcpv1alpha1.ConditionDrifted, corev1.ConditionFalse, "DriftRepaired", "Repaired: "+differences)
+	}
+
+	if err := r.StatusUpdate(); err != nil {
+		return util.RequeueWithError(err)
+	}
+	return util.RequeueAfter(interval, nil)
+}
+
+// detectDrift compares the live state of the project with the state EnsureProjectCreated and EnsureProjectConfigured produce.
+// It doesn't change anything.
+func (r *ReferenceAdapter) detectDrift() (projectDrift, error) {
+	drift := projectDrift{differences: []string{}}
+	projectID := r.ProjectReference.Spec.GCPProjectID
+
+	enabledAPIs, err := r.gcpClient.ListAPIs(r.ctx, projectID)
+	if err != nil {
+		return drift, err
+	}
+	requiredAPIs := r.requiredAPIs()
+	if !r.isCCS() {
+		requiredAPIs = append(requiredAPIs, "cloudbilling.googleapis.com")
+	}
+	for _, api := range requiredAPIs {
+		if !util.Contains(enabledAPIs, api) {
+			drift.differences = append(drift.differences, fmt.Sprintf("API %s is not enabled", api))
+		}
+	}
+
+	if !r.isCCS() && util.Contains(enabledAPIs, "cloudbilling.googleapis.com") {
+		info, err := r.gcpClient.GetBillingInfo(r.ctx, projectID)
+		if err != nil {
+			return drift, err
+		}
+		billingAccount := r.billingAccountName()
+		if info.BillingAccountName != billingAccount || !info.BillingEnabled {
+			drift.differences = append(drift.differences, fmt.Sprintf("project isn't billed to %s", billingAccount))
+		}
+	}
+
+	if !r.isCCS() {
+		project, err := r.gcpClient.GetProject(r.ctx, projectID)
+		if err != nil {
+			return drift, err
+		}
+		missing := missingLabels(project.Labels, r.projectLabels())
+		for _, key := range slices.Sorted(maps.Keys(missing)) {
+			drift.differences = append(drift.differences, fmt.Sprintf("label %s=%s is missing", key, missing[key]))
+		}
+		unbound, err := r.unboundTags(project.ProjectNumber)
+		if err != nil {
+			return drift, err
+		}
+		for _, tagValue := range unbound {
+			drift.differences = append(drift.differences, fmt.Sprintf("tag %s isn't bound", tagValue.NamespacedName))
+		}
+	}
+
+	expectedRoles := map[string][]string{}
+	members := []string{}
+	serviceAccountName := r.ProjectReference.Spec.ServiceAccountName
+	serviceAccount, err := r.gcpClient.GetServiceAccount(r.ctx, serviceAccountName)
+	if err != nil {
+		if !operrors.IsNotFound(err) {
+			return drift, err
+		}
+		drift.serviceAccountMissing = true
+		drift.differences = append(drift.differences, fmt.Sprintf("service account %s is missing", serviceAccountName))
+	} else {
+		member := util.IamMember(serviceAccount.Email, util.ServiceAccount)
+		members = append(members, member)
+		expectedRoles[member] = r.serviceAccountRoles()
+	}
+	groups, groupRoles := r.consoleAccessRoles()
+	for _, email := range groups {
+		member := util.IamMember(email, util.GoogleGroup)
+		members = append(members, member)
+		expectedRoles[member] = groupRoles[email]
+	}
+
+	policy, err := r.gcpClient.GetIamPolicy(r.ctx, projectID)
+	if err != nil {
+		return drift, err
+	}
+	roleMembers := map[string][]string{}
+	for _, binding := range policy.Bindings {
+		roleMembers[binding.Role] = append(roleMembers[binding.Role], binding.Members...)
+	}
+	for _, member := range members {
+		for _, role := range expectedRoles[member] {
+			if !util.Contains(roleMembers[role], member) {
+				drift.differences = append(drift.differences, fmt.Sprintf("%s is missing role %s", member, role))
+			}
+		}
+	}
+
+	return drift, nil
+}
+
+// repairDrift configures the project again. The credentials secret is recreated if the service account it belongs to is gone.
+func (r *ReferenceAdapter) repairDrift(drift projectDrift) (util.OperationResult, error) {
+	if !r.isCCS() {
+		if err := r.configureBillingAPI(); err != nil {
+			return util.RequeueWithError(err)
+		}
+	}
+
+	if drift.serviceAccountMissing {
+		secret := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{
+			Name:      r.ProjectClaim.Spec.GCPCredentialSecret.Name,
+			Namespace: r.ProjectClaim.Spec.GCPCredentialSecret.Namespace,
+		}}
+		if err := r.kubeClient.Delete(r.ctx, secret); client.IgnoreNotFound(err) != nil {
+			return util.RequeueWithError(operrors.Wrap(err, "could not delete stale credentials secret"))
+		}
+	}
+
+	return EnsureProjectConfigured(r)
+}
+
+// earliestRequeue merges the results of two independent operations, requeuing as soon as either of them asks for it
+func earliestRequeue(a, b util.OperationResult) util.OperationResult {
+	switch {
+	case !a.RequeueRequest:
+		return b
+	case !b.RequeueRequest:
+		return a
+	case a.RequeueDelay <= b.RequeueDelay:
+		return a
+	default:
+		return b
+	}
+}
+
 func EnsureProjectReferenceStatusCreating(adapter *ReferenceAdapter) (util.OperationResult, error) {
 	if adapter.ProjectReference.Status.State != "" {
 		return util.ContinueProcessing()
 	}
 	adapter.ProjectReference.Status.State = gcpv1alpha1.ProjectReferenceStatusCreating
-	err := adapter.kubeClient.Status().Update(context.TODO(), adapter.ProjectReference)
+	err := adapter.kubeClient.Status().Update(adapter.ctx, adapter.ProjectReference)
 	if err != nil {
 		err = operrors.Wrap(err, "error updating ProjectReference status")
 		return util.RequeueWithError(err)
@@ -221,16 +476,112 @@ func EnsureProjectID(adapter *ReferenceAdapter) (util.OperationResult, error) {
 	return util.StopProcessing()
 }
 
+// EnsureProjectAdopted takes over the existing project of a ProjectClaim with AdoptProject, which EnsureProjectCreated
+// then configures like a project it created. Before the finalizer is added, the project is verified to be ACTIVE,
+// in the parent folder, labeled with the name of the ProjectClaim, and not billed to another billing account.
+// Projects failing the verification are left alone, they aren't cleaned up once the ProjectClaim is deleted either.
+func EnsureProjectAdopted(r *ReferenceAdapter) (util.OperationResult, error) {
+	if r.isCCS() || !r.ProjectClaim.Spec.AdoptProject || r.ProjectReference.Status.Adopted {
+		return util.ContinueProcessing()
+	}
+
+	projectID := r.ProjectReference.Spec.GCPProjectID
+	project, projectExists, err := r.getProject(projectID)
+	if err != nil {
+		return util.RequeueWithError(operrors.Wrap(err, fmt.Sprintf("could not get project %s to adopt", projectID)))
+	}
+	rejection, err := r.adoptionRejection(project, projectExists)
+	if err != nil {
+		return util.RequeueWithError(operrors.Wrap(err, fmt.Sprintf("could not verify project %s to adopt", projectID)))
+	}
+	if rejection != "" {
+		return r.rejectAdoption(rejection)
+	}
+
+	if project.Labels[naming.OrphanedLabel] != "" {
+		r.logger.Info("Removing orphaned label from adopted project")
+		if err := r.gcpClient.UpdateProjectLabels(r.ctx, projectID, nil, []string{naming.OrphanedLabel}); err != nil {
+			return util.RequeueWithError(operrors.Wrap(err, fmt.Sprintf("could not remove the orphaned label of project %s", projectID)))
+		}
+	}
+
+	r.ProjectReference.Status.Adopted = true
+	r.conditionManager.SetCondition(&r.ProjectReference.Status.Conditions, gcpv1alpha1.ConditionProjectCreated, corev1.ConditionTrue, "ProjectAdopted", fmt.Sprintf("Adopted existing project %s", projectID))
+	if err := r.StatusUpdate(); err != nil {
+		return util.RequeueWithError(operrors.Wrap(err, "error updating ProjectReference status"))
+	}
+	r.event(corev1.EventTypeNormal, util.EventReasonProjectAdopted, "AdoptProject", "Adopted GCP project %s", projectID)
+	return util.ContinueProcessing()
+}
+
+// adoptionRejection returns why project can't be adopted, or "" if it can
+func (r *ReferenceAdapter) adoptionRejection(project *cloudresourcemanager.Project, projectExists bool) (string, error) {
+	projectID := r.ProjectReference.Spec.GCPProjectID
+	if !projectExists {
+		return fmt.Sprintf("project %s doesn't exist or isn't accessible", projectID), nil
+	}
+	if project.LifecycleState != "ACTIVE" {
+		return fmt.Sprintf("project %s is %s", projectID, project.LifecycleState), nil
+	}
+	if project.Parent == nil || project.Parent.Type != "folder" || project.Parent.Id != r.OperatorConfig.ParentFolderID {
+		return fmt.Sprintf("project %s isn't in the parent folder %s", projectID, r.OperatorConfig.ParentFolderID), nil
+	}
+	if claimName := naming.LabelValue(r.ProjectClaim.Name); project.Labels[naming.ClaimNameLabel] != claimName {
+		return fmt.Sprintf("project %s isn't labeled %s=%s", projectID, naming.ClaimNameLabel, claimName), nil
+	}
+
+	// projects without the Cloud Billing API aren't billed through it, EnsureProjectCreated links them
+	enabledAPIs, err := r.gcpClient.ListAPIs(r.ctx, projectID)
+	if err != nil {
+		return "", err
+	}
+	if !util.Contains(enabledAPIs, "cloudbilling.googleapis.com") {
+		return "", nil
+	}
+	info, err := r.gcpClient.GetBillingInfo(r.ctx, projectID)
+	if err != nil {
+		return "", err
+	}
+	if billingAccount := r.billingAccountName(); info.BillingEnabled && info.BillingAccountName != billingAccount {
+		return fmt.Sprintf("project %s is billed to %s instead of %s", projectID, info.BillingAccountName, billingAccount), nil
+	}
+	return "", nil
+}
+
+// rejectAdoption puts the ProjectReference in Error because of rejection, and checks the project again later
+func (r *ReferenceAdapter) rejectAdoption(rejection string) (util.OperationResult, error) {
+	r.logger.Info("Project can't be adopted", "reason", rejection)
+	r.ProjectReference.Status.State = gcpv1alpha1.ProjectReferenceStatusError
+	r.conditionManager.SetCondition(&r.ProjectReference.Status.Conditions, gcpv1alpha1.ConditionProjectCreated, corev1.ConditionFalse, "ProjectAdoptionRejected", rejection)
+	if err := r.StatusUpdate(); err != nil {
+		return util.RequeueWithError(operrors.Wrap(err, "error updating ProjectReference status"))
+	}
+	r.event(corev1.EventTypeWarning, util.EventReasonAdoptionRejected, "AdoptProject", "%s", util.EventNote(rejection))
+	return util.RequeueAfter(adoptionRetryInterval, nil)
+}
+
+// billingAccountName returns the resource name of the configured billing account
+func (r *ReferenceAdapter) billingAccountName() string {
+	return fmt.Sprintf("billingAccounts/%s", strings.TrimSuffix(r.OperatorConfig.BillingAccount, "\n"))
+}
+
 func EnsureProjectCreated(r *ReferenceAdapter) (util.OperationResult, error) {
 	if r.isCCS() {
-		return util.ContinueProcessing()
+		return util.RequeueOnErrorOrContinue(r.restoreCCSProject())
+	}
+
+	if r.ProjectReference.Status.ProjectCreationOperation != "" {
+		result, err := r.pollProjectCreation()
+		if err != nil || result.RequeueOrCancel() {
+			return result, err
+		}
 	}
 
 	err := r.createProject(r.OperatorConfig.ParentFolderID)
 	if err != nil {
 		if err == operrors.ErrInactiveProject {
 			r.ProjectReference.Status.State = gcpv1alpha1.ProjectReferenceStatusError
-			err := r.kubeClient.Status().Update(context.TODO(), r.ProjectReference)
+			err := r.kubeClient.Status().Update(r.ctx, r.ProjectReference)
 			if err != nil {
 				return util.RequeueWithError(operrors.Wrap(err, "error updating ProjectReference status"))
 			}
@@ -238,6 +589,10 @@ func EnsureProjectCreated(r *ReferenceAdapter) (util.OperationResult, error) {
 		}
 		return util.RequeueWithError(operrors.Wrap(err, "could not create project"))
 	}
+	if r.ProjectReference.Status.ProjectCreationOperation != "" {
+		r.logger.V(1).Info("Waiting for project creation", "operation", r.ProjectReference.Status.ProjectCreationOperation)
+		return util.RequeueAfter(projectCreationPollInterval, nil)
+	}
 
 	// should this be it's own function?
 	r.logger.V(1).Info("Configuring Billing APIS")
@@ -254,6 +609,13 @@ func (r *ReferenceAdapter) isCCS() bool {
 }
 
 func EnsureProjectConfigured(r *ReferenceAdapter) (util.OperationResult, error) {
+	if !r.isCCS() {
+		r.logger.V(1).Info("Labeling project")
+		if err := r.labelProject(); err != nil {
+			return util.RequeueWithError(operrors.Wrap(err, "error labeling project"))
+		}
+	}
+
 	r.logger.V(1).Info("Configuring APIS")
 	err := r.configureAPIS()
 	if err != nil {
@@ -263,54 +625,133 @@ func EnsureProjectConfigured(r *ReferenceAdapter) (util.OperationResult, error)
 	osdServiceAccountName := r.ProjectReference.Spec.ServiceAccountName
 	r.logger.V(1).Info("Configuring Service Account " + osdServiceAccountName)
 
-	serviceAccountRoles := OSDRequiredRoles
 	if r.ProjectReference.Spec.SharedVPCAccess {
 		r.logger.V(1).Info("Adding shared VPC access " + osdServiceAccountName)
-		serviceAccountRoles = append(serviceAccountRoles, OSDSharedVPCRoles...)
 	}
 
-	result, err := r.configureServiceAccount(serviceAccountRoles)
+	result, err := r.configureServiceAccount(r.serviceAccountRoles())
 	if err != nil || result.RequeueRequest {
 		return result, err
 	}
 
+	if r.workloadIdentityEnabled() {
+		r.logger.V(1).Info("Configuring Workload Identity Federation")
+		result, err = r.configureWorkloadIdentity()
+		if err != nil || result.RequeueRequest {
+			return result, err
+		}
+	}
+
 	r.logger.V(1).Info("Creating Credentials")
 	result, err = r.createCredentials()
 	if err != nil || result.RequeueRequest {
 		return result, err
 	}
 
-	if r.isCCS() {
-		r.logger.V(1).Info("Configuring IAM to allow console access")
-		for _, email := range r.OperatorConfig.CCSConsoleAccess {
-			// TODO(yeya24): Use google API to check whether this email is
-			// for a group or a service account.
-			if err := r.SetIAMPolicy(email, OSDSREConsoleAccessRoles, util.GoogleGroup); err != nil {
-				return util.RequeueWithError(err)
-			}
+	r.logger.V(1).Info("Configuring IAM to allow console access")
+	if err := r.configureConsoleAccess(); err != nil {
+		return util.RequeueWithError(err)
+	}
+	return util.ContinueProcessing()
+}
+
+// projectLabels returns the labels of the project: the projectLabels of the OperatorConfigMap, and the labels derived from
+// the ProjectClaim, sanitized to the label values GCP accepts. The labels of unset claim fields, e.g. the cluster ID, are left out.
+func (r *ReferenceAdapter) projectLabels() map[string]string {
+	labels := maps.Clone(r.OperatorConfig.ProjectLabels)
+	if labels == nil {
+		labels = map[string]string{}
+	}
+	claimLabels := map[string]string{
+		naming.ClaimNameLabel:      r.ProjectClaim.Name,
+		naming.ClaimNamespaceLabel: r.ProjectClaim.Namespace,
+		naming.LegalEntityIDLabel:  r.ProjectReference.Spec.LegalEntity.ID,
+		naming.ClusterIDLabel:      r.ProjectClaim.Spec.ClusterID,
+		naming.CostCenterLabel:     r.ProjectClaim.Spec.CostCenter,
+	}
+	for key, value := range claimLabels {
+		if value != "" {
+			labels[key] = naming.LabelValue(value)
 		}
+	}
+	return labels
+}
 
-		for _, email := range r.OperatorConfig.CCSReadOnlyConsoleAccess {
-			if err := r.SetIAMPolicy(email, OSDReadOnlyConsoleAccessRoles, util.GoogleGroup); err != nil {
-				return util.RequeueWithError(err)
-			}
+// missingLabels returns the labels of expected that labels lacks, or has with another value
+func missingLabels(labels, expected map[string]string) map[string]string {
+	missing := map[string]string{}
+	for key, value := range expected {
+		if current, ok := labels[key]; !ok || current != value {
+			missing[key] = value
 		}
 	}
-	return util.ContinueProcessing()
+	return missing
+}
+
+// labelProject adds the missing projectLabels to the project and binds the missing ProjectTags.
+// The labels and tags set by others are kept, and the ones the operator doesn't set anymore are left on the project.
+func (r *ReferenceAdapter) labelProject() error {
+	projectID := r.ProjectReference.Spec.GCPProjectID
+	project, err := r.gcpClient.GetProject(r.ctx, projectID)
+	if err != nil {
+		return operrors.Wrap(err, fmt.Sprintf("could not get project %s", projectID))
+	}
+
+	if missing := missingLabels(project.Labels, r.projectLabels()); len(missing) > 0 {
+		if err := r.gcpClient.UpdateProjectLabels(r.ctx, projectID, missing, nil); err != nil {
+			return operrors.Wrap(err, fmt.Sprintf("could not label project %s", projectID))
+		}
+		r.event(corev1.EventTypeNormal, util.EventReasonProjectLabeled, "LabelProject", "Labeled GCP project %s with %s",
+			projectID, strings.Join(slices.Sorted(maps.Keys(missing)), ", "))
+	}
+
+	unbound, err := r.unboundTags(project.ProjectNumber)
+	if err != nil {
+		return err
+	}
+	for _, tagValue := range unbound {
+		if err := r.gcpClient.CreateTagBinding(r.ctx, project.ProjectNumber, tagValue.Name); err != nil {
+			return operrors.Wrap(err, fmt.Sprintf("could not bind tag %s to project %s", tagValue.NamespacedName, projectID))
+		}
+		r.event(corev1.EventTypeNormal, util.EventReasonTagBound, "BindTag", "Bound tag %s to GCP project %s", tagValue.NamespacedName, projectID)
+	}
+	return nil
+}
+
+// unboundTags returns the tag values of ProjectTags that aren't bound to the project projectNumber
+func (r *ReferenceAdapter) unboundTags(projectNumber int64) ([]*cloudresourcemanagerv3.TagValue, error) {
+	if len(r.OperatorConfig.ProjectTags) == 0 {
+		return nil, nil
+	}
+	bound, err := r.gcpClient.ListTagBindings(r.ctx, projectNumber)
+	if err != nil {
+		return nil, operrors.Wrap(err, "could not list the tags of the project")
+	}
+	unbound := []*cloudresourcemanagerv3.TagValue{}
+	for _, namespacedName := range r.OperatorConfig.ProjectTags {
+		tagValue, err := r.gcpClient.GetTagValue(r.ctx, namespacedName)
+		if err != nil {
+			return nil, operrors.Wrap(err, fmt.Sprintf("could not get tag value %s", namespacedName))
+		}
+		if !util.Contains(bound, tagValue.Name) {
+			unbound = append(unbound, tagValue)
+		}
+	}
+	return unbound, nil
 }
 
 func EnsureStateReady(r *ReferenceAdapter) (util.OperationResult, error) {
 	if r.ProjectReference.Status.State != gcpv1alpha1.ProjectReferenceStatusReady {
 		r.logger.V(1).Info("Setting Status on projectReference")
 		r.ProjectReference.Status.State = gcpv1alpha1.ProjectReferenceStatusReady
-		return util.RequeueOnErrorOrStop(r.kubeClient.Status().Update(context.TODO(), r.ProjectReference))
+		return util.RequeueOnErrorOrStop(r.kubeClient.Status().Update(r.ctx, r.ProjectReference))
 	}
 	return util.ContinueProcessing()
 }
 
-func getMatchingClaimLink(projectReference *gcpv1alpha1.ProjectReference, client client.Client) (*gcpv1alpha1.ProjectClaim, error) {
+func getMatchingClaimLink(ctx context.Context, projectReference *gcpv1alpha1.ProjectReference, client client.Client) (*gcpv1alpha1.ProjectClaim, error) {
 	projectClaim := &gcpv1alpha1.ProjectClaim{}
-	err := client.Get(context.TODO(), types.NamespacedName{Name: projectReference.Spec.ProjectClaimCRLink.Name, Namespace: projectReference.Spec.ProjectClaimCRLink.Namespace}, projectClaim)
+	err := client.Get(ctx, types.NamespacedName{Name: projectReference.Spec.ProjectClaimCRLink.Name, Namespace: projectReference.Spec.ProjectClaimCRLink.Namespace}, projectClaim)
 	if err != nil {
 		return &gcpv1alpha1.ProjectClaim{}, err
 
@@ -318,31 +759,74 @@ func getMatchingClaimLink(projectReference *gcpv1alpha1.ProjectReference, client
 	return projectClaim, nil
 }
 
-// UpdateProjectID updates the ProjectReference with a unique ID for the ProjectID
+// UpdateProjectID updates the ProjectReference with a unique ID for the ProjectID, generated from the ProjectIDTemplate.
+// IDs in use by another ProjectReference or by a project of the organization are generated again, up to maxProjectIDAttempts times.
 func (r *ReferenceAdapter) UpdateProjectID() error {
-	projectID, err := GenerateProjectID()
-	if err != nil {
-		return err
+	for attempt := 0; attempt < maxProjectIDAttempts; attempt++ {
+		projectID, err := naming.ProjectID(r.OperatorConfig.ProjectIDTemplate, r.namingData())
+		if err != nil {
+			return err
+		}
+		inUse, err := r.projectIDInUse(projectID)
+		if err != nil {
+			return err
+		}
+		if inUse {
+			r.logger.Info("Generated project ID is in use, generating another one", "projectID", projectID)
+			continue
+		}
+		r.ProjectReference.Spec.GCPProjectID = projectID
+		return r.kubeClient.Update(r.ctx, r.ProjectReference)
 	}
-	r.ProjectReference.Spec.GCPProjectID = projectID
-	return r.kubeClient.Update(context.TODO(), r.ProjectReference)
+	return fmt.Errorf("the projectIDTemplate generated project IDs in use %d times in a row", maxProjectIDAttempts)
 }
 
-func (r *ReferenceAdapter) UpdateServiceAccountName() error {
-	const serviceAccountNameSuffixLength = 8
-	// using k8s library instead of local implementation
-	serviceAccountNameSuffix := utilrand.String(serviceAccountNameSuffixLength)
+// projectIDInUse returns true if projectID is the ID of another ProjectReference, or of a project the operator can see.
+// Projects of other organizations are only detected when the creation of the project fails, which clears the ID.
+func (r *ReferenceAdapter) projectIDInUse(projectID string) (bool, error) {
+	references := &gcpv1alpha1.ProjectReferenceList{}
+	if err := r.kubeClient.List(r.ctx, references); err != nil {
+		return false, operrors.Wrap(err, "could not list ProjectReferences")
+	}
+	for _, reference := range references.Items {
+		if reference.Spec.GCPProjectID == projectID && reference.UID != r.ProjectReference.UID {
+			return true, nil
+		}
+	}
+	_, projectExists, err := r.getProject(projectID)
+	return projectExists, err
+}
+
+// namingData is what the project ID and display name templates are rendered with
+func (r *ReferenceAdapter) namingData() naming.Data {
+	return naming.Data{
+		ClaimName:       r.ProjectClaim.Name,
+		ClaimNamespace:  r.ProjectClaim.Namespace,
+		LegalEntityID:   r.ProjectReference.Spec.LegalEntity.ID,
+		LegalEntityName: r.ProjectReference.Spec.LegalEntity.Name,
+		ProjectID:       r.ProjectReference.Spec.GCPProjectID,
+	}
+}
 
-	r.ProjectReference.Spec.ServiceAccountName = serviceAccountNameTemplate(serviceAccountNameSuffix)
-	return r.kubeClient.Update(context.TODO(), r.ProjectReference)
+func (r *ReferenceAdapter) UpdateServiceAccountName() error {
+	r.ProjectReference.Spec.ServiceAccountName = util.NewManagedServiceAccountName()
+	return r.kubeClient.Update(r.ctx, r.ProjectReference)
 }
 
+// EnsureDeletionProcessed cleans up the project of a deleted ProjectReference, unless the ProjectClaim is protected
+// from deletion. The ProjectClaim is watched, so the cleanup starts as soon as its protection is removed.
 func EnsureDeletionProcessed(adapter *ReferenceAdapter) (util.OperationResult, error) {
 	// Cleanup
 	if adapter.IsDeletionRequested() {
+		if adapter.ProjectClaim.IsDeletionProtected() {
+			adapter.logger.Info("Deletion is blocked by the deletion protection of the ProjectClaim")
+			adapter.event(corev1.EventTypeWarning, util.EventReasonDeletionBlocked, "Delete", "Deletion of GCP project %s is blocked by the %s annotation of the ProjectClaim",
+				adapter.ProjectReference.Spec.GCPProjectID, gcpv1alpha1.DeletionProtectionAnnotation)
+			return util.StopProcessing()
+		}
 		err := adapter.EnsureProjectCleanedUp()
 		if err != nil {
-			return util.RequeueAfter(5*time.Second, err)
+			return util.RequeueWithError(err)
 		}
 		return util.StopProcessing()
 	}
@@ -359,7 +843,7 @@ func (r *ReferenceAdapter) IsDeletionRequested() bool {
 func EnsureFinalizerAdded(r *ReferenceAdapter) (util.OperationResult, error) {
 	if !util.Contains(r.ProjectReference.GetFinalizers(), FinalizerName) {
 		r.ProjectReference.SetFinalizers(append(r.ProjectReference.GetFinalizers(), FinalizerName))
-		return util.RequeueOnErrorOrStop(r.kubeClient.Update(context.TODO(), r.ProjectReference))
+		return util.RequeueOnErrorOrStop(r.kubeClient.Update(r.ctx, r.ProjectReference))
 	}
 	return util.ContinueProcessing()
 }
@@ -370,41 +854,55 @@ func (r *ReferenceAdapter) EnsureFinalizerDeleted() error {
 	finalizers := r.ProjectReference.GetFinalizers()
 	if util.Contains(finalizers, FinalizerName) {
 		r.ProjectReference.SetFinalizers(util.Filter(finalizers, FinalizerName))
-		return r.kubeClient.Update(context.TODO(), r.ProjectReference)
+		return r.kubeClient.Update(r.ctx, r.ProjectReference)
 	}
 	return nil
 }
 
-// EnsureProjectCleanedUp deletes the project, the secret and the finalizer if they still exist
+// EnsureProjectCleanedUp cleans up the project according to the DeletionPolicy of the ProjectClaim,
+// then deletes the secret and the finalizer if they still exist.
+// Orphaned projects are left untouched, only the finalizer is deleted.
 func (r *ReferenceAdapter) EnsureProjectCleanedUp() error {
 	var err error
 
-	err = r.deleteServiceAccount()
-	if err != nil {
-		return err
-	}
-
-	if !r.isCCS() {
-		err = r.deleteProject()
+	policy := r.ProjectClaim.GetDeletionPolicy()
+	// a project that was never adopted isn't the operator's to clean up
+	if policy != gcpv1alpha1.DeletionPolicyOrphan && (!r.ProjectClaim.Spec.AdoptProject || r.ProjectReference.Status.Adopted) {
+		err = r.deleteServiceAccount()
 		if err != nil {
 			return err
 		}
-	}
 
-	err = r.ensureCCSProjectCleanedUp(r.isCCS())
-	if err != nil {
-		return err
-	}
+		if !r.isCCS() {
+			if policy == gcpv1alpha1.DeletionPolicyRetain {
+				err = r.retainProject()
+			} else {
+				err = r.deleteProject()
+			}
+			if err != nil {
+				return err
+			}
+		}
 
-	err = r.deleteCredentials()
-	if err != nil {
-		return err
+		err = r.ensureCCSProjectCleanedUp(r.isCCS())
+		if err != nil {
+			return err
+		}
+
+		err = r.deleteCredentials()
+		if err != nil {
+			return err
+		}
 	}
 
+	finalized := util.Contains(r.ProjectReference.GetFinalizers(), FinalizerName)
 	err = r.EnsureFinalizerDeleted()
 	if err != nil {
 		return err
 	}
+	if finalized {
+		r.event(corev1.EventTypeNormal, util.EventReasonDeletionCompleted, "Delete", "Cleaned up GCP project %s with deletion policy %s", r.ProjectReference.Spec.GCPProjectID, policy)
+	}
 
 	return nil
 }
@@ -424,24 +922,36 @@ func (r *ReferenceAdapter) ensureCCSProjectCleanedUp(isCCS bool) error {
 			return err
 		}
 	}
+	return r.deleteWorkloadIdentityPool()
+}
+
+// deleteWorkloadIdentityPool deletes the workload identity pool of the WorkloadIdentityFederation credential mode
+func (r *ReferenceAdapter) deleteWorkloadIdentityPool() error {
+	if r.ProjectReference.Status.WorkloadIdentityProvider == "" {
+		return nil
+	}
+	r.logger.Info("Deleting workload identity pool")
+	err := r.gcpClient.DeleteWorkloadIdentityPool(r.ctx, r.ProjectReference.Spec.GCPProjectID, workloadIdentityPoolID)
+	if err != nil && !operrors.IsNotFound(err) {
+		return err
+	}
 	return nil
 }
 
+// GenerateProjectID returns a random project ID generated by naming.DefaultProjectIDTemplate
 func GenerateProjectID() (string, error) {
-	guid := uuid.New().String()
-	hashing := sha256.New()
-	_, err := hashing.Write([]byte(guid))
-	if err != nil {
-		return "", err
-	}
-	uuidsum := fmt.Sprintf("%x", hashing.Sum(nil))
-	shortuuid := uuidsum[0:8]
-	return "o-" + shortuuid, nil
+	return naming.ProjectID(naming.DefaultProjectIDTemplate, naming.Data{})
 }
 
 func (r *ReferenceAdapter) clearProjectID() error {
 	r.ProjectReference.Spec.GCPProjectID = ""
-	return r.kubeClient.Update(context.TODO(), r.ProjectReference)
+	return r.kubeClient.Update(r.ctx, r.ProjectReference)
+}
+
+// projectIDPinned returns true if the ProjectClaim asks for the project ID of the ProjectReference,
+// which is kept then instead of trying again with another one
+func (r *ReferenceAdapter) projectIDPinned() bool {
+	return r.ProjectClaim.Spec.GCPProjectID != "" && r.ProjectClaim.Spec.GCPProjectID == r.ProjectReference.Spec.GCPProjectID
 }
 
 // deleteProject checks the Project's lifecycle state of the projectReference.Spec.GCPProjectID instance in Google GCP
@@ -464,21 +974,47 @@ func (r *ReferenceAdapter) deleteProject() error {
 		return operrors.Wrap(operrors.ErrUnexpectedLifecycleState, fmt.Sprintf("unexpected lifecycleState for %s", project.LifecycleState))
 	case "ACTIVE":
 		r.logger.Info("Deleting Project")
-		_, err := r.gcpClient.DeleteProject(project.ProjectId)
-		return err
+		if _, err := r.gcpClient.DeleteProject(r.ctx, project.ProjectId); err != nil {
+			return err
+		}
+		r.event(corev1.EventTypeNormal, util.EventReasonDeletionStarted, "Delete", "Requested the deletion of GCP project %s", project.ProjectId)
+		return nil
 	default:
 		return fmt.Errorf("ProjectReference Controller is unable to understand the project.LifecycleState %s", project.LifecycleState)
 	}
 }
 
+// retainProject keeps the project of the Retain deletion policy. The service account and its IAM bindings are deleted
+// by the caller, this deletes the workload identity pool and labels the project as orphaned.
+func (r *ReferenceAdapter) retainProject() error {
+	if err := r.deleteWorkloadIdentityPool(); err != nil {
+		return err
+	}
+
+	project, projectExists, err := r.getProject(r.ProjectReference.Spec.GCPProjectID)
+	if err != nil {
+		return err
+	}
+	if !projectExists || project.LifecycleState != "ACTIVE" || project.Labels[naming.OrphanedLabel] == "true" {
+		return nil
+	}
+
+	r.logger.Info("Labeling retained project as orphaned")
+	if err := r.gcpClient.UpdateProjectLabels(r.ctx, project.ProjectId, map[string]string{naming.OrphanedLabel: "true"}, nil); err != nil {
+		return operrors.Wrap(err, fmt.Sprintf("could not label project %s as orphaned", project.ProjectId))
+	}
+	r.event(corev1.EventTypeNormal, util.EventReasonProjectRetained, "Delete", "Retained GCP project %s and labeled it as orphaned", project.ProjectId)
+	return nil
+}
+
 func (r *ReferenceAdapter) deleteServiceAccount() error {
 	serviceAccountName := r.ProjectReference.Spec.ServiceAccountName
 
 	r.logger.V(1).Info("SA delete started", "serviceAccountName", serviceAccountName)
 
-	sa, err := r.gcpClient.GetServiceAccount(serviceAccountName)
+	sa, err := r.gcpClient.GetServiceAccount(r.ctx, serviceAccountName)
 	if err != nil {
-		if matchesNotFoundError(err) {
+		if operrors.IsNotFound(err) {
 			return nil
 		}
 
@@ -491,7 +1027,7 @@ func (r *ReferenceAdapter) deleteServiceAccount() error {
 		return operrors.Wrap(err, "could not delete the IAM policy, something happened")
 	}
 
-	if err := r.gcpClient.DeleteServiceAccount(sa.Email); err != nil {
+	if err := r.gcpClient.DeleteServiceAccount(r.ctx, sa.Email); err != nil {
 		return operrors.Wrap(err, "could not delete the SA, something happened")
 	}
 
@@ -512,79 +1048,384 @@ func (r *ReferenceAdapter) createProject(parentFolderID string) error {
 			r.logger.V(1).Info("Project lifecycleState == ACTIVE") //TODO: change message to be more consice
 			return nil
 		case "DELETE_REQUESTED":
-			return operrors.ErrInactiveProject
+			return r.undeleteProject(project.ProjectId)
 		default:
 			return operrors.Wrap(operrors.ErrUnexpectedLifecycleState, fmt.Sprintf("unexpected lifecycleState for %s", project.LifecycleState))
 		}
 	}
 
-	r.logger.Info("Creating Project")
-	// If we cannot create the project clear the projectID from spec so we can try again with another unique key
-	_, creationFailed := r.gcpClient.CreateProject(parentFolderID, r.ProjectClaim.Name)
-	if creationFailed != nil {
-		r.logger.V(1).Info("Clearing gcpProjectID from ProjectReferenceSpec")
-		//Todo() We need to requeue here ot it will continue to the next step.
-		if err = r.clearProjectID(); err != nil {
-			return operrors.Wrap(creationFailed, fmt.Sprintf("could not clear project ID: %v", err))
+	projectID := r.ProjectReference.Spec.GCPProjectID
+	conditions := &r.ProjectReference.Status.Conditions
+	condition, found := r.conditionManager.FindCondition(conditions, gcpv1alpha1.ConditionProjectCreated)
+	if found && condition.Status == corev1.ConditionTrue {
+		// GCP is eventually consistent, the project may not be listed right after its creation
+		if time.Since(condition.LastTransitionTime.Time) < projectVisibilityTimeout {
+			return fmt.Errorf("project %s was created but is not visible yet", projectID)
 		}
-
-		return operrors.Wrap(creationFailed, fmt.Sprintf("could not create project. Parent Folder ID: %s, Requested Project ID: %s", parentFolderID, r.ProjectReference.Spec.GCPProjectID))
+		// the project was deleted outside of the operator, it is created again on the next reconcile
+		message := fmt.Sprintf("project %s was created but hasn't been visible for %s", projectID, projectVisibilityTimeout)
+		r.conditionManager.SetCondition(conditions, gcpv1alpha1.ConditionProjectCreated, corev1.ConditionFalse, "ProjectNotFound", message)
+		if err := r.StatusUpdate(); err != nil {
+			return err
+		}
+		r.event(corev1.EventTypeWarning, util.EventReasonProjectNotFound, "CreateProject", "GCP project %s can't be found anymore, creating it again", projectID)
+		return fmt.Errorf("%s", message)
 	}
 
-	return nil
+	displayName, err := naming.DisplayName(r.OperatorConfig.ProjectNameTemplate, r.namingData())
+	if err != nil {
+		return err
+	}
+
+	r.logger.Info("Creating Project", "displayName", displayName)
+	// If we cannot create the project clear the projectID from spec so we can try again with another unique key
+	operation, creationFailed := r.gcpClient.CreateProject(r.ctx, parentFolderID, displayName, r.projectLabels())
+	if creationFailed != nil {
+		reason := "ProjectCreationFailed"
+		conflictingSince := time.Now()
+		if operrors.IsConflict(creationFailed) {
+			reason = "ProjectIDConflict"
+			if found && condition.Reason == reason {
+				conflictingSince = condition.LastTransitionTime.Time
+			}
+		}
+		r.conditionManager.SetCondition(conditions, gcpv1alpha1.ConditionProjectCreated, corev1.ConditionFalse, reason, creationFailed.Error())
+		if err = r.StatusUpdate(); err != nil {
+			return operrors.Wrap(creationFailed, fmt.Sprintf("could not update ProjectReference status: %v", err))
+		}
+
+		if !r.projectIDPinned() && !creationMayBePending(creationFailed, conflictingSince) {
+			r.logger.V(1).Info("Clearing gcpProjectID from ProjectReferenceSpec")
+			//Todo() We need to requeue here ot it will continue to the next step.
+			if err = r.clearProjectID(); err != nil {
+				return operrors.Wrap(creationFailed, fmt.Sprintf("could not clear project ID: %v", err))
+			}
+		}
+
+		return operrors.Wrap(creationFailed, fmt.Sprintf("could not create project. Parent Folder ID: %s, Requested Project ID: %s", parentFolderID, r.ProjectReference.Spec.GCPProjectID))
+	}
+
+	// The project only exists once the operation is done, which is checked on the next reconciles
+	r.ProjectReference.Status.ProjectCreationOperation = operation.Name
+	r.conditionManager.SetCondition(conditions, gcpv1alpha1.ConditionProjectCreated, corev1.ConditionFalse, "ProjectCreationInProgress", fmt.Sprintf("Waiting for operation %s", operation.Name))
+	return r.StatusUpdate()
+}
+
+// creationMayBePending returns true if the project may have been created despite creationFailed, so its ID must be kept.
+// Creations failing on the side of GCP may have succeeded, and GCP answers 409 while the creation of a project
+// that isn't visible yet is pending, e.g. once a failed status update lost its operation. The ID is only given up
+// once it has been conflicting since conflictingSince for longer than projectVisibilityTimeout.
+func creationMayBePending(creationFailed error, conflictingSince time.Time) bool {
+	if operrors.IsConflict(creationFailed) {
+		return time.Since(conflictingSince) < projectVisibilityTimeout
+	}
+	return operrors.IsRetryable(creationFailed)
 }
 
+// undeleteProject restores projectID, which is pending deletion, if the ProjectClaim opted in with RestoreDeletedProject.
+// Otherwise the project is inactive and can only be restored manually.
+func (r *ReferenceAdapter) undeleteProject(projectID string) error {
+	if !r.ProjectClaim.Spec.RestoreDeletedProject {
+		r.event(corev1.EventTypeWarning, util.EventReasonProjectPendingDeletion, "CreateProject",
+			"GCP project %s is pending deletion, set spec.restoreDeletedProject of the ProjectClaim to restore it", projectID)
+		return operrors.ErrInactiveProject
+	}
+
+	r.logger.Info("Restoring project pending deletion", "projectID", projectID)
+	if err := r.gcpClient.UndeleteProject(r.ctx, projectID); err != nil {
+		return operrors.Wrap(err, fmt.Sprintf("could not restore project %s", projectID))
+	}
+	r.event(corev1.EventTypeNormal, util.EventReasonProjectRestored, "RestoreProject", "Restored GCP project %s, which was pending deletion", projectID)
+	return nil
+}
+
+// restoreCCSProject restores the CCS project if it is pending deletion and the ProjectClaim opted in with RestoreDeletedProject
+func (r *ReferenceAdapter) restoreCCSProject() error {
+	if !r.ProjectClaim.Spec.RestoreDeletedProject {
+		return nil
+	}
+	project, projectExists, err := r.getProject(r.ProjectReference.Spec.GCPProjectID)
+	if err != nil {
+		return err
+	}
+	if !projectExists || project.LifecycleState != "DELETE_REQUESTED" {
+		return nil
+	}
+	return r.undeleteProject(project.ProjectId)
+}
+
+// pollProjectCreation checks the operation creating the project, and forgets it once done.
+// A failed operation is surfaced in the ProjectCreated condition, and the project ID is cleared to try again with another one
+// unless the ProjectClaim pinned it.
+func (r *ReferenceAdapter) pollProjectCreation() (util.OperationResult, error) {
+	conditions := &r.ProjectReference.Status.Conditions
+	name := r.ProjectReference.Status.ProjectCreationOperation
+	operation, err := r.gcpClient.GetOperation(r.ctx, name)
+	if err != nil {
+		return util.RequeueWithError(operrors.Wrap(err, fmt.Sprintf("could not get project creation operation %s", name)))
+	}
+	if !operation.Done {
+		r.logger.V(1).Info("Project creation still in progress", "operation", name)
+		return util.RequeueAfter(projectCreationPollInterval, nil)
+	}
+
+	r.ProjectReference.Status.ProjectCreationOperation = ""
+	if operation.Error != nil {
+		creationFailed := fmt.Errorf("operation %s failed with code %d: %s", name, operation.Error.Code, operation.Error.Message)
+		r.conditionManager.SetCondition(conditions, gcpv1alpha1.ConditionProjectCreated, corev1.ConditionFalse, "ProjectCreationFailed", creationFailed.Error())
+		if err := r.StatusUpdate(); err != nil {
+			return util.RequeueWithError(err)
+		}
+		if !r.projectIDPinned() {
+			r.logger.V(1).Info("Clearing gcpProjectID from ProjectReferenceSpec")
+			if err := r.clearProjectID(); err != nil {
+				return util.RequeueWithError(operrors.Wrap(creationFailed, fmt.Sprintf("could not clear project ID: %v", err)))
+			}
+		}
+		return util.RequeueWithError(operrors.Wrap(creationFailed, "could not create project"))
+	}
+
+	r.conditionManager.SetCondition(conditions, gcpv1alpha1.ConditionProjectCreated, corev1.ConditionTrue, "ProjectCreationSucceeded", fmt.Sprintf("Operation %s done", name))
+	if err := r.StatusUpdate(); err != nil {
+		return util.RequeueWithError(err)
+	}
+	r.event(corev1.EventTypeNormal, util.EventReasonProjectCreated, "CreateProject", "Created GCP project %s", r.ProjectReference.Spec.GCPProjectID)
+	return util.ContinueProcessing()
+}
+
+// getProject looks up projectId in GCP. GCP answers 403 rather than 404 for projects that don't exist,
+// so both mean the project doesn't exist.
 func (r *ReferenceAdapter) getProject(projectId string) (*cloudresourcemanager.Project, bool, error) {
-	// Get existing projects
-	projects, err := r.gcpClient.ListProjects()
+	project, err := r.gcpClient.GetProject(r.ctx, projectId)
 	if err != nil {
+		if operrors.IsNotFound(err) || operrors.IsPermissionDenied(err) {
+			return nil, false, nil
+		}
 		return nil, false, err
 	}
 
-	projectMap := convertProjectsToMap(projects)
-	project, exists := projectMap[projectId]
-
-	return project, exists, err
+	return project, true, nil
 }
 
 func (r *ReferenceAdapter) configureBillingAPI() error {
-	enabledAPIs, err := r.gcpClient.ListAPIs(r.ProjectReference.Spec.GCPProjectID)
+	enabledAPIs, err := r.gcpClient.ListAPIs(r.ctx, r.ProjectReference.Spec.GCPProjectID)
 	if err != nil {
 		return err
 	}
 
 	if !util.Contains(enabledAPIs, "cloudbilling.googleapis.com") {
 		r.logger.Info("Enabling Billing API")
-		err := r.gcpClient.EnableAPI(r.ProjectReference.Spec.GCPProjectID, "cloudbilling.googleapis.com")
+		err := r.gcpClient.EnableAPI(r.ctx, r.ProjectReference.Spec.GCPProjectID, "cloudbilling.googleapis.com")
 		if err != nil {
 			return operrors.Wrap(err, fmt.Sprintf("Error enabling cloudbilling.googleapis.com api for project %s", r.ProjectReference.Spec.GCPProjectID))
 		}
+		r.event(corev1.EventTypeNormal, util.EventReasonAPIEnabled, "EnableAPI", "Enabled API cloudbilling.googleapis.com")
 	}
 
-	err = r.gcpClient.CreateCloudBillingAccount(r.ProjectReference.Spec.GCPProjectID, r.OperatorConfig.BillingAccount)
+	err = r.gcpClient.CreateCloudBillingAccount(r.ctx, r.ProjectReference.Spec.GCPProjectID, r.OperatorConfig.BillingAccount)
 	if err != nil {
 		return operrors.Wrap(err, "error creating CloudBilling")
 	}
+	r.event(corev1.EventTypeNormal, util.EventReasonBillingLinked, "LinkBilling", "Linked GCP project %s to billing account %s", r.ProjectReference.Spec.GCPProjectID, r.OperatorConfig.BillingAccount)
+
+	return nil
+}
+
+// serviceAccountRoles returns the roles to grant to the managed service account
+func (r *ReferenceAdapter) serviceAccountRoles() []string {
+	roles := append([]string{}, roleSet(r.OperatorConfig.ServiceAccountRoles, OSDRequiredRoles)...)
+	if r.ProjectReference.Spec.SharedVPCAccess {
+		roles = append(roles, roleSet(r.OperatorConfig.SharedVPCRoles, OSDSharedVPCRoles)...)
+	}
+	return roles
+}
+
+// consoleAccessRoles returns the console access groups of CCS projects, and the roles to grant to each one.
+// A group may be listed for both kinds of console access, its roles are merged so that they are granted at once.
+func (r *ReferenceAdapter) consoleAccessRoles() ([]string, map[string][]string) {
+	groups := []string{}
+	groupRoles := map[string][]string{}
+	if !r.isCCS() {
+		return groups, groupRoles
+	}
 
+	addGroups := func(emails []string, roles []string) {
+		for _, email := range emails {
+			if _, ok := groupRoles[email]; !ok {
+				groups = append(groups, email)
+				groupRoles[email] = []string{}
+			}
+			for _, role := range roles {
+				if !util.Contains(groupRoles[email], role) {
+					groupRoles[email] = append(groupRoles[email], role)
+				}
+			}
+		}
+	}
+	addGroups(r.OperatorConfig.CCSConsoleAccess, roleSet(r.OperatorConfig.CCSConsoleAccessRoles, OSDSREConsoleAccessRoles))
+	addGroups(r.OperatorConfig.CCSReadOnlyConsoleAccess, roleSet(r.OperatorConfig.CCSReadOnlyConsoleAccessRoles, OSDReadOnlyConsoleAccessRoles))
+	return groups, groupRoles
+}
+
+// configureConsoleAccess grants the console access roles to the configured groups,
+// and revokes the roles granted to the groups that aren't configured anymore.
+func (r *ReferenceAdapter) configureConsoleAccess() error {
+	groups, groupRoles := r.consoleAccessRoles()
+	for _, email := range groups {
+		// TODO(yeya24): Use google API to check whether this email is
+		// for a group or a service account.
+		if err := r.SetIAMPolicy(email, groupRoles[email], util.GoogleGroup); err != nil {
+			return err
+		}
+	}
+
+	for _, binding := range append([]gcpv1alpha1.IAMBinding{}, r.ProjectReference.Status.GrantedRoles...) {
+		if email, ok := strings.CutPrefix(binding.Member, "group:"); ok {
+			if _, configured := groupRoles[email]; !configured {
+				if err := r.SetIAMPolicy(email, []string{}, util.GoogleGroup); err != nil {
+					return err
+				}
+			}
+		}
+	}
 	return nil
 }
 
+// seedGrantedRoles records the roles bound to the managed service account as its granted roles,
+// for projects configured before the operator recorded them. Otherwise the service account would be
+// left out of the convergence of the role sets.
+func (r *ReferenceAdapter) seedGrantedRoles() error {
+	for _, binding := range r.ProjectReference.Status.GrantedRoles {
+		if strings.HasPrefix(binding.Member, "serviceAccount:") {
+			return nil
+		}
+	}
+
+	serviceAccount, err := r.gcpClient.GetServiceAccount(r.ctx, r.ProjectReference.Spec.ServiceAccountName)
+	if err != nil {
+		if operrors.IsNotFound(err) {
+			// recreating the service account records its roles
+			return nil
+		}
+		return err
+	}
+	policy, err := r.gcpClient.GetIamPolicy(r.ctx, r.ProjectReference.Spec.GCPProjectID)
+	if err != nil {
+		return err
+	}
+	member := util.IamMember(serviceAccount.Email, util.ServiceAccount)
+	roles := []string{}
+	for _, binding := range policy.Bindings {
+		if util.Contains(binding.Members, member) {
+			roles = append(roles, binding.Role)
+		}
+	}
+	if len(roles) == 0 {
+		return r.SetIAMPolicy(serviceAccount.Email, r.serviceAccountRoles(), util.ServiceAccount)
+	}
+	r.logger.Info("Recording the roles of the service account", "member", member)
+	return r.recordGrantedRoles(member, roles)
+}
+
+// grantedRolesConverged returns true if the roles recorded in the ProjectReference status
+// match the configured role sets
+func (r *ReferenceAdapter) grantedRolesConverged() bool {
+	granted := r.ProjectReference.Status.GrantedRoles
+	groups, groupRoles := r.consoleAccessRoles()
+	expected := map[string][]string{}
+	for _, email := range groups {
+		expected[util.IamMember(email, util.GoogleGroup)] = groupRoles[email]
+	}
+	for _, binding := range granted {
+		if strings.HasPrefix(binding.Member, "serviceAccount:") {
+			expected[binding.Member] = r.serviceAccountRoles()
+		}
+	}
+
+	if len(expected) != len(granted) {
+		return false
+	}
+	for _, binding := range granted {
+		if !reflect.DeepEqual(expected[binding.Member], binding.Roles) {
+			return false
+		}
+	}
+	return true
+}
+
+// convergeGrantedRoles updates the IAM policy of the project after the configured role sets changed
+func (r *ReferenceAdapter) convergeGrantedRoles() error {
+	for _, binding := range append([]gcpv1alpha1.IAMBinding{}, r.ProjectReference.Status.GrantedRoles...) {
+		if email, ok := strings.CutPrefix(binding.Member, "serviceAccount:"); ok {
+			if err := r.SetIAMPolicy(email, r.serviceAccountRoles(), util.ServiceAccount); err != nil {
+				return err
+			}
+		}
+	}
+	return r.configureConsoleAccess()
+}
+
+// roleSet returns the configured roles, or defaults if none are configured
+func roleSet(configured []string, defaults []string) []string {
+	if len(configured) > 0 {
+		return configured
+	}
+	return defaults
+}
+
+// requiredAPIs returns the APIs to enable on the project: the operator's configured list, or OSDRequiredAPIS
+// if there is none, followed by the AdditionalAPIs of the ProjectReference.
+func (r *ReferenceAdapter) requiredAPIs() []string {
+	defaults := OSDRequiredAPIS
+	if len(r.OperatorConfig.RequiredAPIs) > 0 {
+		defaults = r.OperatorConfig.RequiredAPIs
+	}
+
+	requested := append(append([]string{}, defaults...), r.ProjectReference.Spec.AdditionalAPIs...)
+	if r.workloadIdentityEnabled() {
+		// tokens are exchanged by the Security Token Service
+		requested = append(requested, "sts.googleapis.com")
+	}
+
+	apis := []string{}
+	for _, api := range requested {
+		if !util.Contains(apis, api) {
+			apis = append(apis, api)
+		}
+	}
+	return apis
+}
+
+// configureAPIS enables the required APIs in order and records their state in the ProjectReference status.
+// It stops at the first API that can't be enabled, as later APIs may depend on it.
 func (r *ReferenceAdapter) configureAPIS() error {
-	enabledAPIs, err := r.gcpClient.ListAPIs(r.ProjectReference.Spec.GCPProjectID)
+	enabledAPIs, err := r.gcpClient.ListAPIs(r.ctx, r.ProjectReference.Spec.GCPProjectID)
 	if err != nil {
 		return err
 	}
 
-	for _, api := range OSDRequiredAPIS {
+	apiStatus := []gcpv1alpha1.APIStatus{}
+	for _, api := range r.requiredAPIs() {
 		if !util.Contains(enabledAPIs, api) {
-			err = r.gcpClient.EnableAPI(r.ProjectReference.Spec.GCPProjectID, api)
+			err = r.gcpClient.EnableAPI(r.ctx, r.ProjectReference.Spec.GCPProjectID, api)
 			if err != nil {
-				return operrors.Wrap(err, fmt.Sprintf("error enabling %s api for project %s", api, r.ProjectReference.Spec.GCPProjectID))
+				err = operrors.Wrap(err, fmt.Sprintf("error enabling %s api for project %s", api, r.ProjectReference.Spec.GCPProjectID))
+				apiStatus = append(apiStatus, gcpv1alpha1.APIStatus{Name: api, Enabled: false, Message: err.Error()})
+				break
 			}
+			r.event(corev1.EventTypeNormal, util.EventReasonAPIEnabled, "EnableAPI", "Enabled API %s", api)
 		}
+		apiStatus = append(apiStatus, gcpv1alpha1.APIStatus{Name: api, Enabled: true})
 	}
 
-	return nil
+	if !reflect.DeepEqual(r.ProjectReference.Status.APIs, apiStatus) {
+		r.ProjectReference.Status.APIs = apiStatus
+		if updateErr := r.StatusUpdate(); updateErr != nil && err == nil {
+			return updateErr
+		}
+	}
+
+	return err
 }
 
 func (r *ReferenceAdapter) configureServiceAccount(policies []string) (util.OperationResult, error) {
@@ -592,18 +1433,19 @@ func (r *ReferenceAdapter) configureServiceAccount(policies []string) (util.Oper
 	var serviceAccount *iam.ServiceAccount
 
 	osdServiceAccountName := r.ProjectReference.Spec.ServiceAccountName
-	serviceAccount, err := r.gcpClient.GetServiceAccount(osdServiceAccountName)
+	serviceAccount, err := r.gcpClient.GetServiceAccount(r.ctx, osdServiceAccountName)
 	if err != nil {
 		// Create OSDManged Service account
 		r.logger.Info("Creating Service Account")
-		account, err := r.gcpClient.CreateServiceAccount(osdServiceAccountName, osdServiceAccountName)
+		account, err := r.gcpClient.CreateServiceAccount(r.ctx, osdServiceAccountName, osdServiceAccountName)
 		if err != nil {
-			if matchesAlreadyExistsError(err) {
+			if operrors.IsConflict(err) {
 				r.logger.V(2).Info("Service Account not yet fully initialized. Retrying in 30 seconds.")
 				return util.RequeueAfter(30*time.Second, nil)
 			}
 			return util.RequeueWithError(operrors.Wrap(err, fmt.Sprintf("could not create service account for %s", osdServiceAccountName)))
 		}
+		r.event(corev1.EventTypeNormal, util.EventReasonServiceAccountCreated, "CreateServiceAccount", "Created service account %s", account.Email)
 		serviceAccount = account
 	}
 
@@ -617,25 +1459,31 @@ func (r *ReferenceAdapter) configureServiceAccount(policies []string) (util.Oper
 }
 
 func (r *ReferenceAdapter) createCredentials() (util.OperationResult, error) {
-	if util.SecretExists(r.kubeClient, r.ProjectClaim.Spec.GCPCredentialSecret.Name, r.ProjectClaim.Spec.GCPCredentialSecret.Namespace) {
-		return util.ContinueProcessing()
+	existing, err := util.GetSecret(r.ctx, r.kubeClient, r.ProjectClaim.Spec.GCPCredentialSecret.Name, r.ProjectClaim.Spec.GCPCredentialSecret.Namespace)
+	if err == nil {
+		return util.RequeueOnErrorOrContinue(r.ensureCredentialMode(existing))
 	}
 
 	r.logger.Info("Creating credentials")
 	osdServiceAccountName := r.ProjectReference.Spec.ServiceAccountName
-	serviceAccount, err := r.gcpClient.GetServiceAccount(osdServiceAccountName)
+	serviceAccount, err := r.gcpClient.GetServiceAccount(r.ctx, osdServiceAccountName)
 	if err != nil {
-		if matchesNotFoundError(err) {
+		if operrors.IsNotFound(err) {
 			r.logger.V(1).Info("Service Account not yet fully initialized. Retrying in 30 seconds.")
 			return util.RequeueAfter(30*time.Second, nil)
 		}
 		return util.RequeueWithError(operrors.Wrap(err, "could not get service account"))
 	}
+
+	if r.workloadIdentityEnabled() {
+		return util.RequeueOnErrorOrContinue(r.createExternalAccountCredentials(serviceAccount.Email))
+	}
 	r.logger.V(1).Info("Creating Service AccountKey")
-	key, err := r.gcpClient.CreateServiceAccountKey(serviceAccount.Email)
+	key, err := r.gcpClient.CreateServiceAccountKey(r.ctx, serviceAccount.Email)
 	if err != nil {
 		return util.RequeueWithError(operrors.Wrap(err, fmt.Sprintf("could not create service account key for %s", serviceAccount.Email)))
 	}
+	r.event(corev1.EventTypeNormal, util.EventReasonServiceAccountKeyCreated, "CreateServiceAccountKey", "Created key %s of service account %s", path.Base(key.Name), serviceAccount.Email)
 
 	r.logger.V(2).Info("Create secret for the key and store it")
 	privateKeyString, err := base64.StdEncoding.DecodeString(key.PrivateKeyData)
@@ -649,14 +1497,432 @@ func (r *ReferenceAdapter) createCredentials() (util.OperationResult, error) {
 	})
 
 	r.logger.V(1).Info(fmt.Sprintf("Creating Secret %s in namespace %s", r.ProjectClaim.Spec.GCPCredentialSecret.Name, r.ProjectClaim.Spec.GCPCredentialSecret.Namespace))
-	createErr := r.kubeClient.Create(context.TODO(), secret)
+	createErr := r.kubeClient.Create(r.ctx, secret)
 	if createErr != nil {
 		return util.RequeueWithError(operrors.Wrap(createErr, fmt.Sprintf("could not create service account secret for %s", r.ProjectClaim.Spec.GCPCredentialSecret.Name)))
 	}
+	r.event(corev1.EventTypeNormal, util.EventReasonCredentialsWritten, "WriteCredentials", "Wrote the service account key to secret %s/%s", secret.Namespace, secret.Name)
+
+	err = r.recordServiceAccountKey(&gcpv1alpha1.ServiceAccountKeyStatus{Name: key.Name, CreationTime: metav1.Now()})
+	if err != nil {
+		return util.RequeueWithError(err)
+	}
 
 	return util.ContinueProcessing()
 }
 
+// createExternalAccountCredentials creates the credentials secret holding an external_account credential config
+// for the workload identity provider, no service account key is created.
+func (r *ReferenceAdapter) createExternalAccountCredentials(serviceAccountEmail string) error {
+	creds, err := r.externalAccountCredentials(serviceAccountEmail)
+	if err != nil {
+		return err
+	}
+	secret := util.NewGCPSecretCR(creds, types.NamespacedName{
+		Namespace: r.ProjectClaim.Spec.GCPCredentialSecret.Namespace,
+		Name:      r.ProjectClaim.Spec.GCPCredentialSecret.Name,
+	})
+	r.logger.V(1).Info(fmt.Sprintf("Creating Secret %s in namespace %s", secret.Name, secret.Namespace))
+	if err := r.kubeClient.Create(r.ctx, secret); err != nil {
+		return operrors.Wrap(err, fmt.Sprintf("could not create service account secret for %s", secret.Name))
+	}
+	r.event(corev1.EventTypeNormal, util.EventReasonCredentialsWritten, "WriteCredentials", "Wrote the workload identity credential config to secret %s/%s", secret.Namespace, secret.Name)
+	return r.clearServiceAccountKey()
+}
+
+func (r *ReferenceAdapter) externalAccountCredentials(serviceAccountEmail string) (string, error) {
+	tokenFile := r.ProjectClaim.Spec.WorkloadIdentity.TokenFile
+	if tokenFile == "" {
+		tokenFile = defaultWorkloadIdentityTokenFile
+	}
+	creds, err := util.NewExternalAccountCredentials(r.ProjectReference.Status.WorkloadIdentityProvider, serviceAccountEmail, tokenFile)
+	if err != nil {
+		return "", operrors.Wrap(err, "could not create external_account credentials")
+	}
+	return creds, nil
+}
+
+// convergeCredentialMode replaces the credentials of a ready project when the credential mode of the ProjectClaim changed,
+// and recreates them if their secret was deleted
+func (r *ReferenceAdapter) convergeCredentialMode() (util.OperationResult, error) {
+	if r.workloadIdentityEnabled() && r.ProjectReference.Status.WorkloadIdentityProvider == "" {
+		r.logger.Info("Credential mode changed, configuring Workload Identity Federation")
+		result, err := r.configureWorkloadIdentity()
+		if err != nil || result.RequeueRequest {
+			return result, err
+		}
+	}
+	secret, err := util.GetSecret(r.ctx, r.kubeClient, r.ProjectClaim.Spec.GCPCredentialSecret.Name, r.ProjectClaim.Spec.GCPCredentialSecret.Namespace)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			r.logger.Info("Credentials secret was deleted, recreating it")
+			return r.createCredentials()
+		}
+		return util.RequeueWithError(operrors.Wrap(err, "could not get the credentials secret"))
+	}
+	return util.RequeueOnErrorOrContinue(r.ensureCredentialMode(secret))
+}
+
+// ensureCredentialMode replaces the credentials in secret when the credential mode of the ProjectClaim changed.
+// Switching to WorkloadIdentityFederation deletes the key of the secret and the replaced keys awaiting deletion,
+// so none of the long-lived credentials issued by the operator remain.
+func (r *ReferenceAdapter) ensureCredentialMode(secret *corev1.Secret) error {
+	data, ok := secret.Data["osServiceAccount.json"]
+	if !ok {
+		data = secret.Data["key.json"]
+	}
+	// secrets that can't be parsed are left alone, they weren't written by the operator
+	credType, err := gcpclient.CredentialsType(data)
+	if err != nil {
+		return nil
+	}
+	switch {
+	case r.workloadIdentityEnabled() && credType != google.ExternalAccount:
+		r.logger.Info("Replacing the service account key with workload identity federation credentials")
+		serviceAccount, err := r.gcpClient.GetServiceAccount(r.ctx, r.ProjectReference.Spec.ServiceAccountName)
+		if err != nil {
+			return operrors.Wrap(err, "could not get service account")
+		}
+		creds, err := r.externalAccountCredentials(serviceAccount.Email)
+		if err != nil {
+			return err
+		}
+		var keyNames []string
+		if key := r.ProjectReference.Status.ServiceAccountKey; key != nil {
+			keyNames = append(keyNames, key.PreviousKeyNames...)
+		}
+		if replaced := r.secretKeyName(secret); replaced != "" {
+			keyNames = append(keyNames, replaced)
+		}
+		secret.Data = util.NewGCPSecretCR(creds, types.NamespacedName{Namespace: secret.Namespace, Name: secret.Name}).Data
+		if err := r.kubeClient.Update(r.ctx, secret); err != nil {
+			return operrors.Wrap(err, fmt.Sprintf("could not update service account secret for %s", secret.Name))
+		}
+		if err := r.deleteServiceAccountKeys(keyNames); err != nil {
+			return operrors.Wrap(err, "could not delete the replaced service account keys")
+		}
+		return r.clearServiceAccountKey()
+	case !r.workloadIdentityEnabled() && credType == google.ExternalAccount:
+		r.logger.Info("Replacing workload identity federation credentials with a service account key")
+		_, err := r.replaceCredentials(nil)
+		return err
+	}
+	return nil
+}
+
+// clearServiceAccountKey removes the key from the ProjectReference status once the credentials secret holds none
+func (r *ReferenceAdapter) clearServiceAccountKey() error {
+	if r.ProjectReference.Status.ServiceAccountKey == nil {
+		return nil
+	}
+	r.ProjectReference.Status.ServiceAccountKey = nil
+	return r.StatusUpdate()
+}
+
+func (r *ReferenceAdapter) workloadIdentityEnabled() bool {
+	return r.ProjectClaim.Spec.CredentialMode == gcpv1alpha1.CredentialModeWorkloadIdentityFederation
+}
+
+// configureWorkloadIdentity sets up the workload identity pool and the OIDC provider trusted by the credentials secret,
+// and lets the identities of the provider impersonate the managed service account.
+// Pools and providers are created asynchronously, the reconcile is requeued until they can be used.
+func (r *ReferenceAdapter) configureWorkloadIdentity() (util.OperationResult, error) {
+	projectID := r.ProjectReference.Spec.GCPProjectID
+	config := r.ProjectClaim.Spec.WorkloadIdentity
+	if config == nil || config.IssuerURI == "" {
+		return util.RequeueWithError(gcpv1alpha1.ErrWorkloadIdentityIssuerMissing)
+	}
+
+	pool, err := r.gcpClient.GetWorkloadIdentityPool(r.ctx, projectID, workloadIdentityPoolID)
+	switch {
+	case err != nil && operrors.IsNotFound(err):
+		r.logger.Info("Creating workload identity pool", "pool", workloadIdentityPoolID)
+		err := r.gcpClient.CreateWorkloadIdentityPool(r.ctx, projectID, workloadIdentityPoolID, &iam.WorkloadIdentityPool{
+			DisplayName: "OSD managed",
+			Description: fmt.Sprintf("Identities of ProjectClaim %s/%s", r.ProjectClaim.Namespace, r.ProjectClaim.Name),
+		})
+		if err != nil && !operrors.IsConflict(err) {
+			return util.RequeueWithError(operrors.Wrap(err, "could not create workload identity pool"))
+		}
+		return util.RequeueAfter(workloadIdentityPollInterval, nil)
+	case err != nil:
+		return util.RequeueWithError(operrors.Wrap(err, "could not get workload identity pool"))
+	case pool.State == "DELETED":
+		// pool IDs stay reserved for 30 days after the pool was deleted, e.g. by a previous claim on a CCS project
+		r.logger.Info("Restoring deleted workload identity pool", "pool", workloadIdentityPoolID)
+		if err := r.gcpClient.UndeleteWorkloadIdentityPool(r.ctx, projectID, workloadIdentityPoolID); err != nil {
+			return util.RequeueWithError(operrors.Wrap(err, "could not restore workload identity pool"))
+		}
+		return util.RequeueAfter(workloadIdentityPollInterval, nil)
+	}
+
+	oidc := &iam.Oidc{IssuerUri: config.IssuerURI, AllowedAudiences: config.AllowedAudiences}
+	provider, err := r.gcpClient.GetWorkloadIdentityPoolProvider(r.ctx, projectID, workloadIdentityPoolID, workloadIdentityProviderID)
+	switch {
+	case err != nil && operrors.IsNotFound(err):
+		r.logger.Info("Creating workload identity provider", "provider", workloadIdentityProviderID, "issuer", config.IssuerURI)
+		err := r.gcpClient.CreateWorkloadIdentityPoolProvider(r.ctx, projectID, workloadIdentityPoolID, workloadIdentityProviderID, &iam.WorkloadIdentityPoolProvider{
+			DisplayName:      "OSD managed OIDC",
+			AttributeMapping: map[string]string{"google.subject": "assertion.sub"},
+			Oidc:             oidc,
+		})
+		if err != nil && !operrors.IsConflict(err) {
+			return util.RequeueWithError(operrors.Wrap(err, "could not create workload identity provider"))
+		}
+		return util.RequeueAfter(workloadIdentityPollInterval, nil)
+	case err != nil:
+		return util.RequeueWithError(operrors.Wrap(err, "could not get workload identity provider"))
+	case provider.Oidc == nil || provider.Oidc.IssuerUri != oidc.IssuerUri || !slices.Equal(provider.Oidc.AllowedAudiences, oidc.AllowedAudiences):
+		r.logger.Info("Updating workload identity provider", "provider", workloadIdentityProviderID, "issuer", config.IssuerURI)
+		err := r.gcpClient.UpdateWorkloadIdentityPoolProvider(r.ctx, projectID, workloadIdentityPoolID, workloadIdentityProviderID, &iam.WorkloadIdentityPoolProvider{Oidc: oidc}, "oidc")
+		if err != nil {
+			return util.RequeueWithError(operrors.Wrap(err, "could not update workload identity provider"))
+		}
+	}
+
+	// principals of the pool are identified by project number
+	project, err := r.gcpClient.GetProject(r.ctx, projectID)
+	if err != nil {
+		return util.RequeueWithError(operrors.Wrap(err, "could not get project"))
+	}
+	poolName := fmt.Sprintf("projects/%d/locations/global/workloadIdentityPools/%s", project.ProjectNumber, workloadIdentityPoolID)
+	if err := r.bindWorkloadIdentityUsers(poolName, config.Subjects); err != nil {
+		return util.RequeueWithError(operrors.Wrap(err, "could not allow workload identities to impersonate the service account"))
+	}
+
+	providerName := fmt.Sprintf("%s/providers/%s", poolName, workloadIdentityProviderID)
+	if r.ProjectReference.Status.WorkloadIdentityProvider != providerName {
+		r.ProjectReference.Status.WorkloadIdentityProvider = providerName
+		if err := r.StatusUpdate(); err != nil {
+			return util.RequeueWithError(err)
+		}
+	}
+	return util.ContinueProcessing()
+}
+
+// bindWorkloadIdentityUsers grants roles/iam.workloadIdentityUser on the managed service account to subjects of the pool poolName,
+// or to the whole pool if subjects is empty. Identities of the pool that are no longer allowed lose the role.
+func (r *ReferenceAdapter) bindWorkloadIdentityUsers(poolName string, subjects []string) error {
+	const role = "roles/iam.workloadIdentityUser"
+	principalPrefix := "principal://iam.googleapis.com/" + poolName + "/"
+	principalSetPrefix := "principalSet://iam.googleapis.com/" + poolName + "/"
+	members := []string{principalSetPrefix + "*"}
+	if len(subjects) > 0 {
+		members = []string{}
+		for _, subject := range subjects {
+			members = append(members, principalPrefix+"subject/"+subject)
+		}
+	}
+
+	serviceAccount, err := r.gcpClient.GetServiceAccount(r.ctx, r.ProjectReference.Spec.ServiceAccountName)
+	if err != nil {
+		return err
+	}
+	policy, err := r.gcpClient.GetServiceAccountIamPolicy(r.ctx, serviceAccount.Email)
+	if err != nil {
+		return err
+	}
+
+	var binding *iam.Binding
+	for _, b := range policy.Bindings {
+		if b.Role == role && b.Condition == nil {
+			binding = b
+			break
+		}
+	}
+	if binding == nil {
+		binding = &iam.Binding{Role: role}
+		policy.Bindings = append(policy.Bindings, binding)
+	}
+	granted := []string{}
+	for _, member := range binding.Members {
+		// members outside of the pool weren't granted by the operator
+		if !strings.HasPrefix(member, principalPrefix) && !strings.HasPrefix(member, principalSetPrefix) {
+			granted = append(granted, member)
+		}
+	}
+	granted = append(granted, members...)
+	if slices.Equal(binding.Members, granted) {
+		return nil
+	}
+	binding.Members = granted
+	_, err = r.gcpClient.SetServiceAccountIamPolicy(r.ctx, serviceAccount.Email, policy)
+	return err
+}
+
+// rotateCredentials replaces the key in the credentials secret once it is older than ServiceAccountKeyMaxAge.
+// The replaced keys are deleted after ServiceAccountKeyGracePeriod, which gives consumers time to pick up the new key.
+func (r *ReferenceAdapter) rotateCredentials() (util.OperationResult, error) {
+	if r.workloadIdentityEnabled() {
+		// there is no key to rotate
+		return util.StopProcessing()
+	}
+
+	maxAge := r.OperatorConfig.ServiceAccountKeyMaxAge
+	key := r.ProjectReference.Status.ServiceAccountKey.DeepCopy()
+	if key == nil {
+		if maxAge <= 0 {
+			return util.StopProcessing()
+		}
+		// credentials created before keys were tracked are as old as their secret
+		secret, err := util.GetSecret(r.ctx, r.kubeClient, r.ProjectClaim.Spec.GCPCredentialSecret.Name, r.ProjectClaim.Spec.GCPCredentialSecret.Namespace)
+		if err != nil {
+			return util.RequeueWithError(operrors.Wrap(err, "could not get the credentials secret"))
+		}
+		key = &gcpv1alpha1.ServiceAccountKeyStatus{CreationTime: secret.CreationTimestamp}
+	}
+
+	now := time.Now()
+	if key.PreviousKeysExpirationTime != nil && !now.Before(key.PreviousKeysExpirationTime.Time) {
+		r.logger.Info("Deleting replaced service account keys")
+		secret, err := util.GetSecret(r.ctx, r.kubeClient, r.ProjectClaim.Spec.GCPCredentialSecret.Name, r.ProjectClaim.Spec.GCPCredentialSecret.Namespace)
+		if err != nil {
+			return util.RequeueWithError(operrors.Wrap(err, "could not get the credentials secret"))
+		}
+		// a rotation that couldn't be rolled back leaves the replaced key in the secret
+		active := r.secretKeyName(secret)
+		keyNames := slices.DeleteFunc(slices.Clone(key.PreviousKeyNames), func(name string) bool { return name == active })
+		if err := r.deleteServiceAccountKeys(keyNames); err != nil {
+			return util.RequeueWithError(operrors.Wrap(err, "could not delete replaced service account keys"))
+		}
+		key.PreviousKeysExpirationTime = nil
+		key.PreviousKeyNames = nil
+	}
+
+	if maxAge > 0 && !now.Before(key.CreationTime.Add(maxAge)) {
+		r.logger.Info("Rotating service account key")
+		rotated, err := r.replaceCredentials(key)
+		if err != nil {
+			return util.RequeueWithError(operrors.Wrap(err, "could not rotate service account key"))
+		}
+		key = rotated
+	}
+
+	if err := r.recordServiceAccountKey(key); err != nil {
+		return util.RequeueWithError(err)
+	}
+
+	var next *metav1.Time
+	for _, t := range []*metav1.Time{key.NextRotationTime, key.PreviousKeysExpirationTime} {
+		if t != nil && (next == nil || t.Before(next)) {
+			next = t
+		}
+	}
+	if next == nil {
+		return util.StopProcessing()
+	}
+	return util.RequeueAfter(time.Until(next.Time), nil)
+}
+
+// replaceCredentials writes a new key into the credentials secret. The secret is updated in a single
+// write, which fails instead of overwriting the secret if it changed since it was read.
+// The replaced key is added to the keys of previous that are still to be deleted. The new key is recorded
+// in the ProjectReference status before it is written, so that no key is lost track of if either write fails.
+func (r *ReferenceAdapter) replaceCredentials(previous *gcpv1alpha1.ServiceAccountKeyStatus) (*gcpv1alpha1.ServiceAccountKeyStatus, error) {
+	secret, err := util.GetSecret(r.ctx, r.kubeClient, r.ProjectClaim.Spec.GCPCredentialSecret.Name, r.ProjectClaim.Spec.GCPCredentialSecret.Namespace)
+	if err != nil {
+		return nil, operrors.Wrap(err, "could not get the credentials secret")
+	}
+	var previousKeyNames []string
+	if previous != nil {
+		previousKeyNames = append(previousKeyNames, previous.PreviousKeyNames...)
+	}
+	if replaced := r.secretKeyName(secret); replaced != "" {
+		previousKeyNames = append(previousKeyNames, replaced)
+	}
+	serviceAccount, err := r.gcpClient.GetServiceAccount(r.ctx, r.ProjectReference.Spec.ServiceAccountName)
+	if err != nil {
+		return nil, operrors.Wrap(err, "could not get service account")
+	}
+	key, err := r.gcpClient.CreateServiceAccountKey(r.ctx, serviceAccount.Email)
+	if err != nil {
+		return nil, operrors.Wrap(err, fmt.Sprintf("could not create service account key for %s", serviceAccount.Email))
+	}
+	r.event(corev1.EventTypeNormal, util.EventReasonServiceAccountKeyCreated, "CreateServiceAccountKey", "Created key %s of service account %s", path.Base(key.Name), serviceAccount.Email)
+	// the new key isn't used by anyone until the secret is written
+	deleteKey := func() {
+		if deleteErr := r.gcpClient.DeleteServiceAccountKey(r.ctx, key.Name); deleteErr != nil {
+			r.logger.Error(deleteErr, "could not delete unused service account key", "key", key.Name)
+		}
+	}
+	privateKeyString, err := base64.StdEncoding.DecodeString(key.PrivateKeyData)
+	if err != nil {
+		deleteKey()
+		return nil, operrors.Wrap(err, "could not decode secret")
+	}
+
+	replacement := &gcpv1alpha1.ServiceAccountKeyStatus{Name: key.Name, CreationTime: metav1.Now(), PreviousKeyNames: previousKeyNames}
+	if len(previousKeyNames) > 0 {
+		gracePeriod := r.OperatorConfig.ServiceAccountKeyGracePeriod
+		if gracePeriod <= 0 {
+			gracePeriod = configmap.DefaultServiceAccountKeyGracePeriod
+		}
+		expiration := metav1.NewTime(replacement.CreationTime.Add(gracePeriod))
+		replacement.PreviousKeysExpirationTime = &expiration
+	}
+	recorded := r.ProjectReference.Status.ServiceAccountKey.DeepCopy()
+	if err := r.recordServiceAccountKey(replacement); err != nil {
+		r.ProjectReference.Status.ServiceAccountKey = recorded
+		deleteKey()
+		return nil, operrors.Wrap(err, "could not record the new service account key")
+	}
+
+	secret.Data = util.NewGCPSecretCR(string(privateKeyString), types.NamespacedName{Namespace: secret.Namespace, Name: secret.Name}).Data
+	if err := r.kubeClient.Update(r.ctx, secret); err != nil {
+		deleteKey()
+		r.ProjectReference.Status.ServiceAccountKey = recorded
+		if statusErr := r.StatusUpdate(); statusErr != nil {
+			r.logger.Error(statusErr, "could not restore the service account key status", "key", key.Name)
+		}
+		return nil, operrors.Wrap(err, fmt.Sprintf("could not update service account secret for %s", secret.Name))
+	}
+	r.event(corev1.EventTypeNormal, util.EventReasonCredentialsWritten, "WriteCredentials", "Wrote the service account key to secret %s/%s", secret.Namespace, secret.Name)
+	return replacement, nil
+}
+
+// secretKeyName returns the resource name of the service account key the operator wrote into secret,
+// or "" if secret holds no service account key
+func (r *ReferenceAdapter) secretKeyName(secret *corev1.Secret) string {
+	data, err := util.GCPCredentialsFromSecret(secret)
+	if err != nil {
+		return ""
+	}
+	var credentials struct {
+		ClientEmail  string `json:"client_email"`
+		PrivateKeyID string `json:"private_key_id"`
+	}
+	if err := json.Unmarshal(data, &credentials); err != nil || credentials.ClientEmail == "" || credentials.PrivateKeyID == "" {
+		return ""
+	}
+	return fmt.Sprintf("projects/%s/serviceAccounts/%s/keys/%s", r.ProjectReference.Spec.GCPProjectID, credentials.ClientEmail, credentials.PrivateKeyID)
+}
+
+// deleteServiceAccountKeys deletes the keys named keyNames. Only keys recorded by the operator are passed,
+// the keys other parties created for the service account are left alone.
+func (r *ReferenceAdapter) deleteServiceAccountKeys(keyNames []string) error {
+	for _, keyName := range keyNames {
+		r.logger.V(1).Info("Deleting service account key", "key", keyName)
+		if err := r.gcpClient.DeleteServiceAccountKey(r.ctx, keyName); err != nil && !operrors.IsNotFound(err) {
+			return err
+		}
+	}
+	return nil
+}
+
+// recordServiceAccountKey stores key as the key of the credentials secret in the ProjectReference status
+func (r *ReferenceAdapter) recordServiceAccountKey(key *gcpv1alpha1.ServiceAccountKeyStatus) error {
+	key.NextRotationTime = nil
+	if maxAge := r.OperatorConfig.ServiceAccountKeyMaxAge; maxAge > 0 {
+		next := metav1.NewTime(key.CreationTime.Add(maxAge))
+		key.NextRotationTime = &next
+	}
+	if reflect.DeepEqual(r.ProjectReference.Status.ServiceAccountKey, key) {
+		return nil
+	}
+	r.ProjectReference.Status.ServiceAccountKey = key
+	return r.StatusUpdate()
+}
+
 func (r *ReferenceAdapter) deleteCredentials() error {
 	secret := types.NamespacedName{
 		Namespace: r.ProjectClaim.Spec.GCPCredentialSecret.Namespace,
@@ -665,15 +1931,15 @@ func (r *ReferenceAdapter) deleteCredentials() error {
 	r.logger.Info("Deleting Credentials")
 
 	r.logger.V(2).Info("Check if the Secret exists")
-	if util.SecretExists(r.kubeClient, secret.Name, secret.Namespace) {
+	if util.SecretExists(r.ctx, r.kubeClient, secret.Name, secret.Namespace) {
 		r.logger.V(2).Info("Getting Secret")
-		key, err := util.GetSecret(r.kubeClient, secret.Name, secret.Namespace)
+		key, err := util.GetSecret(r.ctx, r.kubeClient, secret.Name, secret.Namespace)
 		if err != nil {
 			return operrors.Wrap(err, fmt.Sprintf("could not get the service account secret for %s", secret.Name))
 		}
 
 		r.logger.V(2).Info("Deleting secret")
-		err = r.kubeClient.Delete(context.TODO(), key)
+		err = r.kubeClient.Delete(r.ctx, key)
 		if err != nil {
 			return operrors.Wrap(err, fmt.Sprintf("could not delete service account secret for %s", secret.Name))
 		}
@@ -690,7 +1956,7 @@ func (r *ReferenceAdapter) ensureClaimAvailabilityZonesSet() (util.OperationResu
 		return util.ContinueProcessing()
 	}
 
-	zones, err := r.gcpClient.ListAvailabilityZones(r.ProjectReference.Spec.GCPProjectID, r.ProjectClaim.Spec.Region)
+	zones, err := r.gcpClient.ListAvailabilityZones(r.ctx, r.ProjectReference.Spec.GCPProjectID, r.ProjectClaim.Spec.Region)
 	if err != nil {
 		return r.handleAvailabilityZonesError(err)
 	}
@@ -698,7 +1964,7 @@ func (r *ReferenceAdapter) ensureClaimAvailabilityZonesSet() (util.OperationResu
 	r.conditionManager.SetCondition(conditions, gcpv1alpha1.ConditionComputeApiReady, corev1.ConditionTrue, "QueryAvailabilityZonesSucceeded", "ComputeAPI ready, successfully queried availability zones")
 
 	r.ProjectClaim.Spec.AvailabilityZones = zones
-	err = r.kubeClient.Update(context.TODO(), r.ProjectClaim)
+	err = r.kubeClient.Update(r.ctx, r.ProjectClaim)
 	if err != nil {
 		return util.RequeueWithError(operrors.Wrap(err, "error updating ProjectClaim spec"))
 	}
@@ -707,7 +1973,7 @@ func (r *ReferenceAdapter) ensureClaimAvailabilityZonesSet() (util.OperationResu
 }
 
 func (r *ReferenceAdapter) handleAvailabilityZonesError(err error) (util.OperationResult, error) {
-	if !matchesComputeApiNotReadyError(err) {
+	if service, disabled := operrors.IsServiceDisabled(err); !disabled || service != "compute.googleapis.com" {
 		return util.RequeueWithError(err)
 	}
 
@@ -756,13 +2022,23 @@ type AddorUpdateBindingResponse struct {
 
 // AddOrUpdateBindings gets the policy and checks if the bindings match the required roles
 func (r *ReferenceAdapter) AddOrUpdateBindings(serviceAccountEmail string, policies []string, memberType util.IamMemberType) (AddorUpdateBindingResponse, error) {
-	policy, err := r.gcpClient.GetIamPolicy(r.ProjectReference.Spec.GCPProjectID)
+	policy, err := r.gcpClient.GetIamPolicy(r.ctx, r.ProjectReference.Spec.GCPProjectID)
 	if err != nil {
 		return AddorUpdateBindingResponse{}, err
 	}
 
+	// Revoking the roles the operator granted before that aren't required anymore
+	staleRoles := []string{}
+	for _, role := range r.grantedRoles(util.IamMember(serviceAccountEmail, memberType)) {
+		if !util.Contains(policies, role) {
+			staleRoles = append(staleRoles, role)
+		}
+	}
+	bindings, removed := util.RemoveRoles(policy.Bindings, staleRoles, serviceAccountEmail, memberType)
+
 	//Checking if policy is modified
-	newBindings, modified := util.AddOrUpdateBinding(policy.Bindings, policies, serviceAccountEmail, memberType)
+	newBindings, modified := util.AddOrUpdateBinding(bindings, policies, serviceAccountEmail, memberType)
+	modified = modified || removed
 
 	// add new bindings to policy
 	policy.Bindings = newBindings
@@ -772,7 +2048,9 @@ func (r *ReferenceAdapter) AddOrUpdateBindings(serviceAccountEmail string, polic
 	}, nil
 }
 
-// SetIAMPolicy attempts to update policy if the policy needs to be modified
+// SetIAMPolicy attempts to update policy if the policy needs to be modified.
+// Roles previously granted to the member by the operator and missing from policies are revoked,
+// and policies are recorded as the member's granted roles in the ProjectReference status.
 func (r *ReferenceAdapter) SetIAMPolicy(serviceAccountEmail string, policies []string, memberType util.IamMemberType) error {
 	// Checking if policy needs to be updated
 	var retry int
@@ -788,22 +2066,58 @@ func (r *ReferenceAdapter) SetIAMPolicy(serviceAccountEmail string, policies []s
 			setIamPolicyRequest := &cloudresourcemanager.SetIamPolicyRequest{
 				Policy: addorUpdateResponse.policy,
 			}
-			_, err = r.gcpClient.SetIamPolicy(setIamPolicyRequest)
+			_, err = r.gcpClient.SetIamPolicy(r.ctx, setIamPolicyRequest)
 			if err != nil {
-				ae, ok := err.(*googleapi.Error)
+				if operrors.IsConflict(err) {
+					metrics.IAMPolicyConflicts.Inc()
+				}
 				// retry rules below:
 
-				if ok && ae.Code == http.StatusConflict && retry < 3 {
+				if operrors.IsConflict(err) && retry < 3 {
 					retry++
-					time.Sleep(time.Second)
+					if err := util.Sleep(r.ctx, time.Second); err != nil {
+						return err
+					}
 					continue
 				}
 				return err
 			}
-			return nil
 		}
+		return r.recordGrantedRoles(util.IamMember(serviceAccountEmail, memberType), policies)
+	}
+}
+
+// grantedRoles returns the roles the operator granted to member
+func (r *ReferenceAdapter) grantedRoles(member string) []string {
+	for _, binding := range r.ProjectReference.Status.GrantedRoles {
+		if binding.Member == member {
+			return binding.Roles
+		}
+	}
+	return nil
+}
+
+// recordGrantedRoles stores roles as the roles granted to member in the ProjectReference status
+func (r *ReferenceAdapter) recordGrantedRoles(member string, roles []string) error {
+	granted := &r.ProjectReference.Status.GrantedRoles
+	for i := range *granted {
+		if (*granted)[i].Member == member {
+			if reflect.DeepEqual((*granted)[i].Roles, roles) {
+				return nil
+			}
+			if len(roles) == 0 {
+				*granted = append((*granted)[:i], (*granted)[i+1:]...)
+			} else {
+				(*granted)[i].Roles = append([]string{}, roles...)
+			}
+			return r.StatusUpdate()
+		}
+	}
+	if len(roles) == 0 {
 		return nil
 	}
+	*granted = append(*granted, gcpv1alpha1.IAMBinding{Member: member, Roles: append([]string{}, roles...)})
+	return r.StatusUpdate()
 }
 
 func (r *ReferenceAdapter) DeleteIAMPolicy(serviceAccountEmail string, memberType util.IamMemberType) error {
@@ -811,7 +2125,7 @@ func (r *ReferenceAdapter) DeleteIAMPolicy(serviceAccountEmail string, memberTyp
 	var retry int
 	for {
 
-		policies, err := r.gcpClient.GetIamPolicy(r.ProjectReference.Spec.GCPProjectID)
+		policies, err := r.gcpClient.GetIamPolicy(r.ctx, r.ProjectReference.Spec.GCPProjectID)
 		if err != nil {
 			return err
 		}
@@ -825,14 +2139,18 @@ func (r *ReferenceAdapter) DeleteIAMPolicy(serviceAccountEmail string, memberTyp
 		setIamPolicyRequest := &cloudresourcemanager.SetIamPolicyRequest{
 			Policy: policies,
 		}
-		_, err = r.gcpClient.SetIamPolicy(setIamPolicyRequest)
+		_, err = r.gcpClient.SetIamPolicy(r.ctx, setIamPolicyRequest)
 		if err != nil {
-			ae, ok := err.(*googleapi.Error)
+			if operrors.IsConflict(err) {
+				metrics.IAMPolicyConflicts.Inc()
+			}
 			// retry rules below:
 
-			if ok && ae.Code == http.StatusConflict && retry < 3 {
+			if operrors.IsConflict(err) && retry < 3 {
 				retry++
-				time.Sleep(time.Second)
+				if err := util.Sleep(r.ctx, time.Second); err != nil {
+					return err
+				}
 				continue
 			}
 			return err
@@ -864,35 +2182,10 @@ func (r *ReferenceAdapter) SetProjectReferenceCondition(reason string, err error
 
 // StatusUpdate updates the project reference status
 func (r *ReferenceAdapter) StatusUpdate() error {
-	err := r.kubeClient.Status().Update(context.TODO(), r.ProjectReference)
+	err := r.kubeClient.Status().Update(r.ctx, r.ProjectReference)
 	if err != nil {
 		return operrors.Wrap(err, fmt.Sprintf("failed to update ProjectReference status of %s", r.ProjectReference.Name))
 	}
 
 	return nil
 }
-
-// convertProjectsToMap converts []*cloudresourcemanager.Project map[string]*cloudresourcemanager.Project with the projectID as the map key
-func convertProjectsToMap(projects []*cloudresourcemanager.Project) map[string]*cloudresourcemanager.Project {
-	projectMap := make(map[string]*cloudresourcemanager.Project)
-
-	for _, project := range projects {
-		projectMap[project.ProjectId] = project
-	}
-
-	return projectMap
-}
-
-func matchesAlreadyExistsError(err error) bool {
-	return strings.HasPrefix(err.Error(), "googleapi: Error 409:")
-}
-
-func matchesNotFoundError(err error) bool {
-	return strings.HasPrefix(err.Error(), "googleapi: Error 404:") ||
-		(strings.Contains(err.Error(), "400 Bad Request") && strings.Contains(err.Error(), "Invalid grant: account not found"))
-}
-
-func matchesComputeApiNotReadyError(err error) bool {
-	return strings.HasPrefix(err.Error(), "googleapi: Error 403: Compute Engine API has not been used in project") ||
-		strings.HasPrefix(err.Error(), "googleapi: Error 403: Access Not Configured. Compute Engine API has not been used in project")
-}
@@ -1,17 +1,24 @@
 package projectreference_test
 
 import (
+	"context"
 	"errors"
+	"net/http"
 	"strings"
 	"time"
 
 	"github.com/openshift/gcp-project-operator/pkg/configmap"
+	"github.com/openshift/gcp-project-operator/pkg/naming"
 	"github.com/openshift/gcp-project-operator/pkg/util"
 	"github.com/openshift/gcp-project-operator/pkg/util/mocks"
 	"go.uber.org/mock/gomock"
+	"google.golang.org/api/cloudbilling/v1"
 	"google.golang.org/api/cloudresourcemanager/v1"
+	cloudresourcemanagerv3 "google.golang.org/api/cloudresourcemanager/v3"
+	"google.golang.org/api/googleapi"
 	"google.golang.org/api/iam/v1"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/events"
 
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
@@ -22,6 +29,7 @@ import (
 	mockGCP "github.com/openshift/gcp-project-operator/pkg/util/mocks/gcpclient"
 	testStructs "github.com/openshift/gcp-project-operator/pkg/util/mocks/structs"
 	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
 )
@@ -41,6 +49,15 @@ var (
 	}
 )
 
+// projectLabels returns the labels the operator sets on the project of claim and reference without further configuration
+func projectLabels(claim *gcpv1alpha1.ProjectClaim, reference *gcpv1alpha1.ProjectReference) map[string]string {
+	return map[string]string{
+		naming.ClaimNameLabel:      naming.LabelValue(claim.Name),
+		naming.ClaimNamespaceLabel: naming.LabelValue(claim.Namespace),
+		naming.LegalEntityIDLabel:  naming.LabelValue(reference.Spec.LegalEntity.ID),
+	}
+}
+
 var _ = Describe("ProjectreferenceAdapter", func() {
 	var (
 		adapter          *ReferenceAdapter
@@ -53,6 +70,7 @@ var _ = Describe("ProjectreferenceAdapter", func() {
 		err              error
 		mockCtrl         *gomock.Controller
 		configMap        configmap.OperatorConfigMap
+		recorder         *events.FakeRecorder
 	)
 
 	BeforeEach(func() {
@@ -63,6 +81,7 @@ var _ = Describe("ProjectreferenceAdapter", func() {
 		mockKubeClient = mocks.NewMockClient(mockCtrl)
 		mockGCPClient = mockGCP.NewMockClient(mockCtrl)
 		mockConditions = mockconditions.NewMockConditions(mockCtrl)
+		recorder = events.NewFakeRecorder(100)
 		configMap = configmap.OperatorConfigMap{
 			BillingAccount: "fake-account",
 			ParentFolderID: "fake-folderID",
@@ -74,7 +93,7 @@ var _ = Describe("ProjectreferenceAdapter", func() {
 	JustBeforeEach(func() {
 		claimLink := types.NamespacedName{Name: projectReference.Spec.ProjectClaimCRLink.Name, Namespace: projectReference.Spec.ProjectClaimCRLink.Namespace}
 		mockKubeClient.EXPECT().Get(gomock.Any(), claimLink, gomock.Any()).SetArg(2, *projectClaim)
-		adapter, err = NewReferenceAdapter(projectReference, logf.Log.WithName("Test Logger"), mockKubeClient, mockGCPClient, mockConditions, configMap)
+		adapter, err = NewReferenceAdapter(context.TODO(), projectReference, logf.Log.WithName("Test Logger"), mockKubeClient, mockGCPClient, mockConditions, configMap, recorder)
 		Expect(err).NotTo(HaveOccurred())
 	})
 	Context("generated project names", func() {
@@ -123,9 +142,62 @@ var _ = Describe("ProjectreferenceAdapter", func() {
 				projectReference.Status.State = gcpv1alpha1.ProjectReferenceStatusReady
 			})
 
+			Context("When the roles of the service account of a Ready ProjectClaim weren't recorded", func() {
+				// policy returns the IAM policy of a project configured with other role sets
+				policy := func() *cloudresourcemanager.Policy {
+					return &cloudresourcemanager.Policy{Bindings: []*cloudresourcemanager.Binding{
+						{Role: OSDRequiredRoles[0], Members: []string{"serviceAccount:foo"}},
+						{Role: "roles/editor", Members: []string{"serviceAccount:foo", "group:customer"}},
+					}}
+				}
+
+				BeforeEach(func() {
+					projectClaim.Status.State = gcpv1alpha1.ClaimStatusReady
+					projectReference.Spec.ServiceAccountName = "osd-managed-admin"
+				})
+
+				It("records them and converges them with the configured roles", func() {
+					mockGCPClient.EXPECT().GetServiceAccount(gomock.Any(), "osd-managed-admin").Return(&iam.ServiceAccount{Email: "foo"}, nil)
+					mockGCPClient.EXPECT().GetIamPolicy(gomock.Any(), gomock.Any()).Return(policy(), nil)
+					mockKubeClient.EXPECT().Status().Return(mockStatusWriter).Times(2)
+					mockStatusWriter.EXPECT().Update(gomock.Any(), gomock.Any()).Do(func(_ context.Context, obj *gcpv1alpha1.ProjectReference, _ ...interface{}) {
+						Expect(obj.Status.GrantedRoles).To(Equal([]gcpv1alpha1.IAMBinding{{Member: "serviceAccount:foo", Roles: []string{OSDRequiredRoles[0], "roles/editor"}}}))
+					}).Return(nil)
+					mockGCPClient.EXPECT().GetIamPolicy(gomock.Any(), gomock.Any()).Return(policy(), nil)
+					mockGCPClient.EXPECT().SetIamPolicy(gomock.Any(), gomock.Any()).DoAndReturn(
+						func(_ context.Context, request *cloudresourcemanager.SetIamPolicyRequest) (*cloudresourcemanager.Policy, error) {
+							roles := []string{}
+							for _, binding := range request.Policy.Bindings {
+								if util.Contains(binding.Members, "serviceAccount:foo") {
+									roles = append(roles, binding.Role)
+								}
+							}
+							Expect(roles).To(ConsistOf(OSDRequiredRoles))
+							return request.Policy, nil
+						})
+					mockStatusWriter.EXPECT().Update(gomock.Any(), gomock.Any()).Return(nil)
+					result, err := EnsureProjectClaimReady(adapter)
+					Expect(err).NotTo(HaveOccurred())
+					Expect(result).To(Equal(stopProcessingResult))
+					Expect(projectReference.Status.GrantedRoles).To(Equal([]gcpv1alpha1.IAMBinding{{Member: "serviceAccount:foo", Roles: OSDRequiredRoles}}))
+				})
+			})
+
 			Context("When ProjectClaim is in Ready state", func() {
+				var credentials string
+
 				BeforeEach(func() {
 					projectClaim.Status.State = gcpv1alpha1.ClaimStatusReady
+					projectReference.Status.GrantedRoles = []gcpv1alpha1.IAMBinding{{Member: "serviceAccount:foo", Roles: OSDRequiredRoles}}
+					credentials = `{"type":"service_account","client_email":"foo","private_key_id":"1"}`
+				})
+				JustBeforeEach(func() {
+					secretName := types.NamespacedName{Name: projectClaim.Spec.GCPCredentialSecret.Name, Namespace: projectClaim.Spec.GCPCredentialSecret.Namespace}
+					if credentials == "" {
+						mockKubeClient.EXPECT().Get(gomock.Any(), secretName, gomock.Any()).Return(kerrors.NewNotFound(corev1.Resource("secrets"), secretName.Name)).Times(2)
+						return
+					}
+					mockKubeClient.EXPECT().Get(gomock.Any(), secretName, gomock.Any()).SetArg(2, *util.NewGCPSecretCR(credentials, secretName))
 				})
 
 				It("returns without altering ProjectClaim", func() {
@@ -134,6 +206,224 @@ var _ = Describe("ProjectreferenceAdapter", func() {
 					Expect(err).NotTo(HaveOccurred())
 					Expect(adapter.ProjectClaim).To(Equal(oldClaim))
 				})
+
+				Context("When the credentials secret was deleted", func() {
+					BeforeEach(func() {
+						credentials = ""
+					})
+
+					It("recreates the credentials", func() {
+						mockGCPClient.EXPECT().GetServiceAccount(gomock.Any(), gomock.Any()).Return(&iam.ServiceAccount{Email: "foo"}, nil)
+						mockGCPClient.EXPECT().CreateServiceAccountKey(gomock.Any(), "foo").Return(&iam.ServiceAccountKey{Name: "projects/foo/serviceAccounts/foo/keys/2", PrivateKeyData: "YWRtaW4="}, nil)
+						mockKubeClient.EXPECT().Create(gomock.Any(), gomock.Any()).Return(nil)
+						mockKubeClient.EXPECT().Status().Return(mockStatusWriter)
+						mockStatusWriter.EXPECT().Update(gomock.Any(), gomock.Any()).Return(nil)
+						_, err := EnsureProjectClaimReady(adapter)
+						Expect(err).NotTo(HaveOccurred())
+						Expect(projectReference.Status.ServiceAccountKey.Name).To(Equal("projects/foo/serviceAccounts/foo/keys/2"))
+					})
+				})
+
+				Context("When drift checks are enabled", func() {
+					BeforeEach(func() {
+						configMap.ResyncInterval = time.Hour
+						projectReference.Spec.ServiceAccountName = "osd-managed-admin"
+					})
+
+					Context("When the last check is recent", func() {
+						BeforeEach(func() {
+							projectReference.Status.Conditions = []gcpv1alpha1.Condition{{
+								Type:          gcpv1alpha1.ConditionDrifted,
+								Status:        corev1.ConditionFalse,
+								LastProbeTime: metav1.NewTime(time.Now().Add(-time.Minute)),
+							}}
+						})
+
+						It("requeues when the next check is due", func() {
+							mockConditions.EXPECT().HasCondition(gomock.Any(), gcpv1alpha1.ConditionDrifted).Return(true)
+							mockConditions.EXPECT().FindCondition(gomock.Any(), gcpv1alpha1.ConditionDrifted).Return(&projectReference.Status.Conditions[0], true)
+							result, err := EnsureProjectClaimReady(adapter)
+							Expect(err).NotTo(HaveOccurred())
+							Expect(result.RequeueRequest).To(BeTrue())
+							Expect(result.RequeueDelay).To(BeNumerically("~", 59*time.Minute, time.Minute))
+						})
+					})
+
+					Context("When the project drifted", func() {
+						It("reports the drift without repairing it", func() {
+							mockConditions.EXPECT().HasCondition(gomock.Any(), gcpv1alpha1.ConditionDrifted).Return(false)
+							mockGCPClient.EXPECT().ListAPIs(gomock.Any(), gomock.Any()).Return(append([]string{"cloudbilling.googleapis.com"}, OSDRequiredAPIS[1:]...), nil)
+							mockGCPClient.EXPECT().GetBillingInfo(gomock.Any(), gomock.Any()).Return(&cloudbilling.ProjectBillingInfo{BillingAccountName: "billingAccounts/fake-account", BillingEnabled: true}, nil)
+							labels := projectLabels(projectClaim, projectReference)
+							delete(labels, naming.LegalEntityIDLabel)
+							mockGCPClient.EXPECT().GetProject(gomock.Any(), gomock.Any()).Return(&cloudresourcemanager.Project{Labels: labels}, nil)
+							mockGCPClient.EXPECT().GetServiceAccount(gomock.Any(), "osd-managed-admin").Return(&iam.ServiceAccount{Email: "foo"}, nil)
+							mockGCPClient.EXPECT().GetIamPolicy(gomock.Any(), gomock.Any()).Return(&cloudresourcemanager.Policy{Bindings: []*cloudresourcemanager.Binding{
+								{Role: OSDRequiredRoles[0], Members: []string{"serviceAccount:foo"}},
+							}}, nil)
+							mockConditions.EXPECT().SetCondition(gomock.Any(), gcpv1alpha1.ConditionDrifted, corev1.ConditionTrue, "DriftDetected", gomock.Any()).Do(
+								func(_ *[]gcpv1alpha1.Condition, _ gcpv1alpha1.ConditionType, _ corev1.ConditionStatus, _ string, message string) {
+									Expect(message).To(HavePrefix("API " + OSDRequiredAPIS[0] + " is not enabled; label legal_entity_id=fakelegalentityid is missing; serviceAccount:foo is missing role " + OSDRequiredRoles[1] + ";"))
+								})
+							mockKubeClient.EXPECT().Status().Return(mockStatusWriter)
+							mockStatusWriter.EXPECT().Update(gomock.Any(), gomock.Any()).Return(nil)
+							result, err := EnsureProjectClaimReady(adapter)
+							Expect(err).NotTo(HaveOccurred())
+							Expect(result.RequeueDelay).To(Equal(time.Hour))
+						})
+					})
+				})
+
+				Context("When workload identity federation is enabled", func() {
+					BeforeEach(func() {
+						configMap.ServiceAccountKeyMaxAge = 24 * time.Hour
+						projectClaim.Spec.CredentialMode = gcpv1alpha1.CredentialModeWorkloadIdentityFederation
+						projectReference.Status.WorkloadIdentityProvider = "projects/1/locations/global/workloadIdentityPools/osd-managed/providers/osd-managed-oidc"
+						credentials = `{"type":"external_account","audience":"//iam.googleapis.com/projects/1/locations/global/workloadIdentityPools/osd-managed/providers/osd-managed-oidc"}`
+					})
+
+					It("doesn't rotate keys", func() {
+						result, err := EnsureProjectClaimReady(adapter)
+						Expect(err).NotTo(HaveOccurred())
+						Expect(result).To(Equal(stopProcessingResult))
+					})
+
+					Context("When the credentials secret holds a service account key", func() {
+						BeforeEach(func() {
+							projectClaim.Spec.WorkloadIdentity = &gcpv1alpha1.WorkloadIdentityConfig{IssuerURI: "https://oidc.example.com/cluster"}
+							projectReference.Spec.ServiceAccountName = "osd-managed-admin"
+							projectReference.Status.ServiceAccountKey = &gcpv1alpha1.ServiceAccountKeyStatus{
+								Name:             "projects/foo/serviceAccounts/foo/keys/1",
+								PreviousKeyNames: []string{"projects/foo/serviceAccounts/foo/keys/0"},
+							}
+							credentials = `{"type":"service_account","client_email":"foo","private_key_id":"1"}`
+						})
+
+						It("replaces the key and deletes only the keys issued by the operator", func() {
+							mockGCPClient.EXPECT().GetServiceAccount(gomock.Any(), "osd-managed-admin").Return(&iam.ServiceAccount{Email: "foo"}, nil)
+							mockKubeClient.EXPECT().Update(gomock.Any(), gomock.Any()).Do(func(_ context.Context, obj *corev1.Secret, _ ...interface{}) {
+								Expect(string(obj.Data["osServiceAccount.json"])).To(ContainSubstring(`"type":"external_account"`))
+							}).Return(nil)
+							mockGCPClient.EXPECT().DeleteServiceAccountKey(gomock.Any(), "projects/foo/serviceAccounts/foo/keys/0").Return(nil)
+							mockGCPClient.EXPECT().DeleteServiceAccountKey(gomock.Any(), "projects/"+projectReference.Spec.GCPProjectID+"/serviceAccounts/foo/keys/1").Return(nil)
+							mockKubeClient.EXPECT().Status().Return(mockStatusWriter)
+							mockStatusWriter.EXPECT().Update(gomock.Any(), gomock.Any()).Return(nil)
+							_, err := EnsureProjectClaimReady(adapter)
+							Expect(err).NotTo(HaveOccurred())
+							Expect(projectReference.Status.ServiceAccountKey).To(BeNil())
+						})
+					})
+				})
+
+				Context("When key rotation is enabled", func() {
+					var secret *corev1.Secret
+
+					BeforeEach(func() {
+						configMap.ServiceAccountKeyMaxAge = 24 * time.Hour
+						projectReference.Spec.ServiceAccountName = "osd-managed-admin"
+						projectReference.Status.ServiceAccountKey = &gcpv1alpha1.ServiceAccountKeyStatus{
+							Name:         "projects/foo/serviceAccounts/foo/keys/1",
+							CreationTime: metav1.NewTime(time.Now().Add(-time.Hour)),
+						}
+						secret = util.NewGCPSecretCR(`{"client_email":"foo","private_key_id":"1"}`, types.NamespacedName{
+							Name:      projectClaim.Spec.GCPCredentialSecret.Name,
+							Namespace: projectClaim.Spec.GCPCredentialSecret.Namespace,
+						})
+					})
+
+					Context("When the key is recent", func() {
+						It("requeues when the key is due for rotation", func() {
+							mockKubeClient.EXPECT().Status().Return(mockStatusWriter)
+							mockStatusWriter.EXPECT().Update(gomock.Any(), gomock.Any()).Return(nil)
+							result, err := EnsureProjectClaimReady(adapter)
+							Expect(err).NotTo(HaveOccurred())
+							Expect(result.RequeueRequest).To(BeTrue())
+							Expect(result.RequeueDelay).To(BeNumerically("~", 23*time.Hour, time.Minute))
+							Expect(projectReference.Status.ServiceAccountKey.NextRotationTime.Time).To(BeTemporally("~", time.Now().Add(23*time.Hour), time.Minute))
+						})
+					})
+
+					Context("When the key is older than the max age", func() {
+						BeforeEach(func() {
+							projectReference.Status.ServiceAccountKey.CreationTime = metav1.NewTime(time.Now().Add(-25 * time.Hour))
+						})
+
+						It("replaces the key in the secret", func() {
+							mockKubeClient.EXPECT().Get(gomock.Any(), gomock.Any(), gomock.Any()).SetArg(2, *secret)
+							mockGCPClient.EXPECT().GetServiceAccount(gomock.Any(), "osd-managed-admin").Return(&iam.ServiceAccount{Email: "foo"}, nil)
+							mockGCPClient.EXPECT().CreateServiceAccountKey(gomock.Any(), "foo").Return(&iam.ServiceAccountKey{Name: "projects/foo/serviceAccounts/foo/keys/2", PrivateKeyData: "YWRtaW4="}, nil)
+							mockKubeClient.EXPECT().Update(gomock.Any(), gomock.Any()).Do(func(_ context.Context, obj *corev1.Secret, _ ...interface{}) {
+								Expect(obj.Data).To(HaveKeyWithValue("osServiceAccount.json", []byte("admin")))
+							}).Return(nil)
+							mockKubeClient.EXPECT().Status().Return(mockStatusWriter)
+							mockStatusWriter.EXPECT().Update(gomock.Any(), gomock.Any()).Return(nil)
+							result, err := EnsureProjectClaimReady(adapter)
+							Expect(err).NotTo(HaveOccurred())
+							Expect(result.RequeueDelay).To(BeNumerically("~", configmap.DefaultServiceAccountKeyGracePeriod, time.Minute))
+							key := projectReference.Status.ServiceAccountKey
+							Expect(key.Name).To(Equal("projects/foo/serviceAccounts/foo/keys/2"))
+							Expect(key.PreviousKeysExpirationTime.Time).To(BeTemporally("~", time.Now().Add(configmap.DefaultServiceAccountKeyGracePeriod), time.Minute))
+							Expect(key.PreviousKeyNames).To(ConsistOf("projects/" + projectReference.Spec.GCPProjectID + "/serviceAccounts/foo/keys/1"))
+						})
+
+						It("deletes the new key if the secret can't be updated", func() {
+							mockKubeClient.EXPECT().Get(gomock.Any(), gomock.Any(), gomock.Any()).SetArg(2, *secret)
+							mockGCPClient.EXPECT().GetServiceAccount(gomock.Any(), "osd-managed-admin").Return(&iam.ServiceAccount{Email: "foo"}, nil)
+							mockGCPClient.EXPECT().CreateServiceAccountKey(gomock.Any(), "foo").Return(&iam.ServiceAccountKey{Name: "projects/foo/serviceAccounts/foo/keys/2", PrivateKeyData: "YWRtaW4="}, nil)
+							mockKubeClient.EXPECT().Status().Return(mockStatusWriter).Times(2)
+							mockStatusWriter.EXPECT().Update(gomock.Any(), gomock.Any()).Return(nil).Times(2)
+							mockKubeClient.EXPECT().Update(gomock.Any(), gomock.Any()).Return(errMock)
+							mockGCPClient.EXPECT().DeleteServiceAccountKey(gomock.Any(), "projects/foo/serviceAccounts/foo/keys/2").Return(nil)
+							_, err := EnsureProjectClaimReady(adapter)
+							Expect(err).To(HaveOccurred())
+							Expect(projectReference.Status.ServiceAccountKey.Name).To(Equal("projects/foo/serviceAccounts/foo/keys/1"))
+						})
+
+						It("records the new key before writing it to the secret", func() {
+							mockKubeClient.EXPECT().Get(gomock.Any(), gomock.Any(), gomock.Any()).SetArg(2, *secret)
+							mockGCPClient.EXPECT().GetServiceAccount(gomock.Any(), "osd-managed-admin").Return(&iam.ServiceAccount{Email: "foo"}, nil)
+							mockGCPClient.EXPECT().CreateServiceAccountKey(gomock.Any(), "foo").Return(&iam.ServiceAccountKey{Name: "projects/foo/serviceAccounts/foo/keys/2", PrivateKeyData: "YWRtaW4="}, nil)
+							mockKubeClient.EXPECT().Status().Return(mockStatusWriter)
+							mockStatusWriter.EXPECT().Update(gomock.Any(), gomock.Any()).Return(errMock)
+							mockGCPClient.EXPECT().DeleteServiceAccountKey(gomock.Any(), "projects/foo/serviceAccounts/foo/keys/2").Return(nil)
+							_, err := EnsureProjectClaimReady(adapter)
+							Expect(err).To(HaveOccurred())
+							// the secret wasn't written
+							Expect(projectReference.Status.ServiceAccountKey.Name).To(Equal("projects/foo/serviceAccounts/foo/keys/1"))
+							Expect(projectReference.Status.ServiceAccountKey.PreviousKeyNames).To(BeEmpty())
+						})
+					})
+
+					Context("When the grace period of the replaced keys is over", func() {
+						BeforeEach(func() {
+							expiration := metav1.NewTime(time.Now().Add(-time.Minute))
+							projectReference.Status.ServiceAccountKey.PreviousKeysExpirationTime = &expiration
+							projectReference.Status.ServiceAccountKey.PreviousKeyNames = []string{"projects/foo/serviceAccounts/foo/keys/0"}
+						})
+
+						It("deletes only the replaced keys", func() {
+							mockKubeClient.EXPECT().Get(gomock.Any(), gomock.Any(), gomock.Any()).SetArg(2, *secret)
+							mockGCPClient.EXPECT().DeleteServiceAccountKey(gomock.Any(), "projects/foo/serviceAccounts/foo/keys/0").Return(nil)
+							mockKubeClient.EXPECT().Status().Return(mockStatusWriter)
+							mockStatusWriter.EXPECT().Update(gomock.Any(), gomock.Any()).Return(nil)
+							_, err := EnsureProjectClaimReady(adapter)
+							Expect(err).NotTo(HaveOccurred())
+							Expect(projectReference.Status.ServiceAccountKey.PreviousKeysExpirationTime).To(BeNil())
+							Expect(projectReference.Status.ServiceAccountKey.PreviousKeyNames).To(BeEmpty())
+						})
+
+						It("keeps a replaced key that the secret still holds", func() {
+							projectReference.Status.ServiceAccountKey.PreviousKeyNames = append(projectReference.Status.ServiceAccountKey.PreviousKeyNames,
+								"projects/"+projectReference.Spec.GCPProjectID+"/serviceAccounts/foo/keys/1")
+							mockKubeClient.EXPECT().Get(gomock.Any(), gomock.Any(), gomock.Any()).SetArg(2, *secret)
+							mockGCPClient.EXPECT().DeleteServiceAccountKey(gomock.Any(), "projects/foo/serviceAccounts/foo/keys/0").Return(nil)
+							mockKubeClient.EXPECT().Status().Return(mockStatusWriter)
+							mockStatusWriter.EXPECT().Update(gomock.Any(), gomock.Any()).Return(nil)
+							_, err := EnsureProjectClaimReady(adapter)
+							Expect(err).NotTo(HaveOccurred())
+						})
+					})
+				})
 			})
 
 			Context("When ProjectClaim is not in Ready state", func() {
@@ -147,7 +437,7 @@ var _ = Describe("ProjectreferenceAdapter", func() {
 
 					Context("When availability zones are not set", func() {
 						BeforeEach(func() {
-							mockGCPClient.EXPECT().ListAvailabilityZones(gomock.Any(), gomock.Any()).Return([]string{"zone1", "zone2", "zone3"}, nil)
+							mockGCPClient.EXPECT().ListAvailabilityZones(gomock.Any(), gomock.Any(), gomock.Any()).Return([]string{"zone1", "zone2", "zone3"}, nil)
 							mockKubeClient.EXPECT().Update(gomock.Any(), gomock.Any()).Times(1)
 							mockConditions.EXPECT().SetCondition(gomock.Any(), gcpv1alpha1.ConditionComputeApiReady, corev1.ConditionTrue, "QueryAvailabilityZonesSucceeded", "ComputeAPI ready, successfully queried availability zones").Times(1)
 						})
@@ -161,7 +451,7 @@ var _ = Describe("ProjectreferenceAdapter", func() {
 
 					Context("When availability zones are set but GCPProjectID are not", func() {
 						BeforeEach(func() {
-							mockGCPClient.EXPECT().ListAvailabilityZones(gomock.Any(), gomock.Any()).Return([]string{"zone1", "zone2", "zone3"}, nil)
+							mockGCPClient.EXPECT().ListAvailabilityZones(gomock.Any(), gomock.Any(), gomock.Any()).Return([]string{"zone1", "zone2", "zone3"}, nil)
 							mockKubeClient.EXPECT().Update(gomock.Any(), gomock.Any())
 							mockConditions.EXPECT().SetCondition(gomock.Any(), gcpv1alpha1.ConditionComputeApiReady, corev1.ConditionTrue, "QueryAvailabilityZonesSucceeded", "ComputeAPI ready, successfully queried availability zones").Times(1)
 						})
@@ -203,7 +493,7 @@ var _ = Describe("ProjectreferenceAdapter", func() {
 
 						conditionFound = false
 						mockConditions.EXPECT().SetCondition(gomock.Any(), gcpv1alpha1.ConditionComputeApiReady, corev1.ConditionFalse, "QueryAvailabilityZonesFailed", "ComputeAPI not yet ready, couldn't query availability zones").Times(1)
-						mockGCPClient.EXPECT().ListAvailabilityZones(gomock.Any(), gomock.Any()).Return([]string{}, errors.New("googleapi: Error 403: Access Not Configured. Compute Engine API has not been used in project"))
+						mockGCPClient.EXPECT().ListAvailabilityZones(gomock.Any(), gomock.Any(), gomock.Any()).Return([]string{}, serviceDisabledError("compute.googleapis.com"))
 						mockKubeClient.EXPECT().Status().Return(mockStatusWriter)
 						mockStatusWriter.EXPECT().Update(gomock.Any(), gomock.Any()).Return(nil)
 					})
@@ -290,6 +580,95 @@ var _ = Describe("ProjectreferenceAdapter", func() {
 		})
 	})
 
+	Context("EnsureProjectAdopted", func() {
+		var project *cloudresourcemanager.Project
+
+		BeforeEach(func() {
+			projectReference.Spec.GCPProjectID = "existing-project"
+			projectClaim.Spec.GCPProjectID = "existing-project"
+			projectClaim.Spec.AdoptProject = true
+			project = &cloudresourcemanager.Project{
+				ProjectId:      "existing-project",
+				LifecycleState: "ACTIVE",
+				Parent:         &cloudresourcemanager.ResourceId{Type: "folder", Id: configMap.ParentFolderID},
+				Labels:         map[string]string{"claim_name": naming.LabelValue(projectClaim.Name)},
+			}
+		})
+
+		Context("When the ProjectClaim doesn't adopt its project", func() {
+			BeforeEach(func() {
+				projectClaim.Spec.AdoptProject = false
+			})
+
+			It("continues processing", func() {
+				result, err := EnsureProjectAdopted(adapter)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(result).To(Equal(continueProcessingResult))
+			})
+		})
+
+		Context("When the project was adopted", func() {
+			BeforeEach(func() {
+				projectReference.Status.Adopted = true
+			})
+
+			It("doesn't verify it again", func() {
+				result, err := EnsureProjectAdopted(adapter)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(result).To(Equal(continueProcessingResult))
+			})
+		})
+
+		It("adopts a project billed to the configured billing account", func() {
+			mockGCPClient.EXPECT().GetProject(gomock.Any(), "existing-project").Return(project, nil)
+			mockGCPClient.EXPECT().ListAPIs(gomock.Any(), "existing-project").Return([]string{"cloudbilling.googleapis.com"}, nil)
+			mockGCPClient.EXPECT().GetBillingInfo(gomock.Any(), "existing-project").Return(&cloudbilling.ProjectBillingInfo{BillingAccountName: "billingAccounts/fake-account", BillingEnabled: true}, nil)
+			mockConditions.EXPECT().SetCondition(gomock.Any(), gcpv1alpha1.ConditionProjectCreated, corev1.ConditionTrue, "ProjectAdopted", "Adopted existing project existing-project")
+			mockKubeClient.EXPECT().Status().Return(mockStatusWriter)
+			mockStatusWriter.EXPECT().Update(gomock.Any(), gomock.Any()).Return(nil)
+			result, err := EnsureProjectAdopted(adapter)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result).To(Equal(continueProcessingResult))
+			Expect(adapter.ProjectReference.Status.Adopted).To(BeTrue())
+			Expect(recorder.Events).To(Receive(ContainSubstring(util.EventReasonProjectAdopted)))
+		})
+
+		It("rejects a project billed to another billing account", func() {
+			mockGCPClient.EXPECT().GetProject(gomock.Any(), "existing-project").Return(project, nil)
+			mockGCPClient.EXPECT().ListAPIs(gomock.Any(), "existing-project").Return([]string{"cloudbilling.googleapis.com"}, nil)
+			mockGCPClient.EXPECT().GetBillingInfo(gomock.Any(), "existing-project").Return(&cloudbilling.ProjectBillingInfo{BillingAccountName: "billingAccounts/other-account", BillingEnabled: true}, nil)
+			mockConditions.EXPECT().SetCondition(gomock.Any(), gcpv1alpha1.ConditionProjectCreated, corev1.ConditionFalse, "ProjectAdoptionRejected",
+				"project existing-project is billed to billingAccounts/other-account instead of billingAccounts/fake-account")
+			mockKubeClient.EXPECT().Status().Return(mockStatusWriter)
+			mockStatusWriter.EXPECT().Update(gomock.Any(), gomock.Any()).Return(nil)
+			result, err := EnsureProjectAdopted(adapter)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.RequeueRequest).To(BeTrue())
+			Expect(adapter.ProjectReference.Status.State).To(Equal(gcpv1alpha1.ProjectReferenceStatusError))
+			Expect(adapter.ProjectReference.Status.Adopted).To(BeFalse())
+			Expect(recorder.Events).To(Receive(ContainSubstring(util.EventReasonAdoptionRejected)))
+		})
+
+		It("rejects a project outside of the parent folder", func() {
+			project.Parent.Id = "other-folder"
+			mockGCPClient.EXPECT().GetProject(gomock.Any(), "existing-project").Return(project, nil)
+			mockConditions.EXPECT().SetCondition(gomock.Any(), gcpv1alpha1.ConditionProjectCreated, corev1.ConditionFalse, "ProjectAdoptionRejected",
+				"project existing-project isn't in the parent folder fake-folderID")
+			mockKubeClient.EXPECT().Status().Return(mockStatusWriter)
+			mockStatusWriter.EXPECT().Update(gomock.Any(), gomock.Any()).Return(nil)
+			result, err := EnsureProjectAdopted(adapter)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.RequeueRequest).To(BeTrue())
+		})
+
+		It("requeues with error if the project can't be verified", func() {
+			mockGCPClient.EXPECT().GetProject(gomock.Any(), "existing-project").Return(project, nil)
+			mockGCPClient.EXPECT().ListAPIs(gomock.Any(), "existing-project").Return(nil, errMock)
+			_, err := EnsureProjectAdopted(adapter)
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
 	Context("EnsureProjectCreated", func() {
 
 		Context("When CCS project", func() {
@@ -302,6 +681,28 @@ var _ = Describe("ProjectreferenceAdapter", func() {
 				Expect(err).NotTo(HaveOccurred())
 				Expect(result).To(Equal(continueProcessingResult))
 			})
+
+			Context("When the ProjectClaim restores deleted projects", func() {
+				BeforeEach(func() {
+					projectClaim.Spec.RestoreDeletedProject = true
+				})
+
+				It("restores the project if it is pending deletion", func() {
+					mockGCPClient.EXPECT().GetProject(gomock.Any(), projectReference.Spec.GCPProjectID).Return(&cloudresourcemanager.Project{LifecycleState: "DELETE_REQUESTED", ProjectId: projectReference.Spec.GCPProjectID}, nil)
+					mockGCPClient.EXPECT().UndeleteProject(gomock.Any(), projectReference.Spec.GCPProjectID).Return(nil)
+					result, err := EnsureProjectCreated(adapter)
+					Expect(err).NotTo(HaveOccurred())
+					Expect(result).To(Equal(continueProcessingResult))
+					Expect(recorder.Events).To(Receive(ContainSubstring(util.EventReasonProjectRestored)))
+				})
+
+				It("leaves active projects alone", func() {
+					mockGCPClient.EXPECT().GetProject(gomock.Any(), projectReference.Spec.GCPProjectID).Return(&cloudresourcemanager.Project{LifecycleState: "ACTIVE", ProjectId: projectReference.Spec.GCPProjectID}, nil)
+					result, err := EnsureProjectCreated(adapter)
+					Expect(err).NotTo(HaveOccurred())
+					Expect(result).To(Equal(continueProcessingResult))
+				})
+			})
 		})
 
 		Context("When non-CCS project", func() {
@@ -309,7 +710,7 @@ var _ = Describe("ProjectreferenceAdapter", func() {
 
 				Context("When it fails to get project", func() {
 					It("It requeues with error", func() {
-						mockGCPClient.EXPECT().ListProjects().Return(nil, errMock)
+						mockGCPClient.EXPECT().GetProject(gomock.Any(), projectReference.Spec.GCPProjectID).Return(nil, errMock)
 						_, err := EnsureProjectCreated(adapter)
 						Expect(err).To(HaveOccurred())
 					})
@@ -317,7 +718,7 @@ var _ = Describe("ProjectreferenceAdapter", func() {
 
 				Context("When the lifecycleStatus is LIFECYCLE_STATE_UNSPECIFIED", func() {
 					It("It requeues with error", func() {
-						mockGCPClient.EXPECT().ListProjects().Return([]*cloudresourcemanager.Project{{LifecycleState: "foo", ProjectId: projectReference.Spec.GCPProjectID}}, nil)
+						mockGCPClient.EXPECT().GetProject(gomock.Any(), projectReference.Spec.GCPProjectID).Return(&cloudresourcemanager.Project{LifecycleState: "foo", ProjectId: projectReference.Spec.GCPProjectID}, nil)
 						_, err := EnsureProjectCreated(adapter)
 						Expect(err).To(HaveOccurred())
 					})
@@ -325,7 +726,7 @@ var _ = Describe("ProjectreferenceAdapter", func() {
 
 				Context("When the lifecycleStatus is DELETE_REQUESTED and fails to update projectReference status", func() {
 					It("It requeues with error", func() {
-						mockGCPClient.EXPECT().ListProjects().Return([]*cloudresourcemanager.Project{{LifecycleState: "DELETE_REQUESTED", ProjectId: projectReference.Spec.GCPProjectID}}, nil)
+						mockGCPClient.EXPECT().GetProject(gomock.Any(), projectReference.Spec.GCPProjectID).Return(&cloudresourcemanager.Project{LifecycleState: "DELETE_REQUESTED", ProjectId: projectReference.Spec.GCPProjectID}, nil)
 						mockKubeClient.EXPECT().Status().Return(mockStatusWriter)
 						mockStatusWriter.EXPECT().Update(gomock.Any(), gomock.Any()).Return(errMock)
 						_, err := EnsureProjectCreated(adapter)
@@ -335,7 +736,7 @@ var _ = Describe("ProjectreferenceAdapter", func() {
 
 				Context("When the project is inactive and update projectReference status successfully", func() {
 					It("It requeues with error", func() {
-						mockGCPClient.EXPECT().ListProjects().Return([]*cloudresourcemanager.Project{{LifecycleState: "DELETE_REQUESTED", ProjectId: projectReference.Spec.GCPProjectID}}, nil)
+						mockGCPClient.EXPECT().GetProject(gomock.Any(), projectReference.Spec.GCPProjectID).Return(&cloudresourcemanager.Project{LifecycleState: "DELETE_REQUESTED", ProjectId: projectReference.Spec.GCPProjectID}, nil)
 						mockKubeClient.EXPECT().Status().Return(mockStatusWriter)
 						mockStatusWriter.EXPECT().Update(gomock.Any(), gomock.Any()).Return(nil)
 						result, err := EnsureProjectCreated(adapter)
@@ -344,12 +745,40 @@ var _ = Describe("ProjectreferenceAdapter", func() {
 					})
 				})
 
+				Context("When the project is pending deletion and the ProjectClaim restores deleted projects", func() {
+					BeforeEach(func() {
+						projectClaim.Spec.RestoreDeletedProject = true
+					})
+
+					It("It requeues with error if the project can't be restored", func() {
+						mockGCPClient.EXPECT().GetProject(gomock.Any(), projectReference.Spec.GCPProjectID).Return(&cloudresourcemanager.Project{LifecycleState: "DELETE_REQUESTED", ProjectId: projectReference.Spec.GCPProjectID}, nil)
+						mockGCPClient.EXPECT().UndeleteProject(gomock.Any(), projectReference.Spec.GCPProjectID).Return(errMock)
+						_, err := EnsureProjectCreated(adapter)
+						Expect(err).To(HaveOccurred())
+					})
+
+					It("It restores the project and configures its billing", func() {
+						mockGCPClient.EXPECT().GetProject(gomock.Any(), projectReference.Spec.GCPProjectID).Return(&cloudresourcemanager.Project{LifecycleState: "DELETE_REQUESTED", ProjectId: projectReference.Spec.GCPProjectID}, nil)
+						mockGCPClient.EXPECT().UndeleteProject(gomock.Any(), projectReference.Spec.GCPProjectID).Return(nil)
+						mockGCPClient.EXPECT().ListAPIs(gomock.Any(), projectReference.Spec.GCPProjectID).Return([]string{"cloudbilling.googleapis.com"}, nil)
+						mockGCPClient.EXPECT().CreateCloudBillingAccount(gomock.Any(), projectReference.Spec.GCPProjectID, configMap.BillingAccount).Return(nil)
+						result, err := EnsureProjectCreated(adapter)
+						Expect(err).NotTo(HaveOccurred())
+						Expect(result).To(Equal(continueProcessingResult))
+						Expect(recorder.Events).To(Receive(ContainSubstring(util.EventReasonProjectRestored)))
+					})
+				})
+
 				Context("When the project doesn't exist and fails to create one", func() {
 
 					Context("When fails to clear projectID", func() {
 						It("It requeues with error", func() {
-							mockGCPClient.EXPECT().ListProjects().Return([]*cloudresourcemanager.Project{{LifecycleState: "ACTIVE", ProjectId: "foo"}}, nil)
-							mockGCPClient.EXPECT().CreateProject(gomock.Any(), gomock.Any()).Return(nil, errMock)
+							mockGCPClient.EXPECT().GetProject(gomock.Any(), projectReference.Spec.GCPProjectID).Return(nil, &googleapi.Error{Code: http.StatusForbidden, Message: "The caller does not have permission"})
+							mockConditions.EXPECT().FindCondition(gomock.Any(), gcpv1alpha1.ConditionProjectCreated).Return(&gcpv1alpha1.Condition{}, false)
+							mockGCPClient.EXPECT().CreateProject(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, errMock)
+							mockConditions.EXPECT().SetCondition(gomock.Any(), gcpv1alpha1.ConditionProjectCreated, corev1.ConditionFalse, "ProjectCreationFailed", errMock.Error())
+							mockKubeClient.EXPECT().Status().Return(mockStatusWriter)
+							mockStatusWriter.EXPECT().Update(gomock.Any(), gomock.Any()).Return(nil)
 							mockKubeClient.EXPECT().Update(gomock.Any(), gomock.Any()).Return(errMock)
 							_, err := EnsureProjectCreated(adapter)
 							Expect(err).To(HaveOccurred())
@@ -359,8 +788,12 @@ var _ = Describe("ProjectreferenceAdapter", func() {
 
 					Context("When it clears projectID successfully", func() {
 						It("It requeues with error", func() {
-							mockGCPClient.EXPECT().ListProjects().Return([]*cloudresourcemanager.Project{{LifecycleState: "ACTIVE", ProjectId: "foo"}}, nil)
-							mockGCPClient.EXPECT().CreateProject(gomock.Any(), gomock.Any()).Return(nil, errMock)
+							mockGCPClient.EXPECT().GetProject(gomock.Any(), projectReference.Spec.GCPProjectID).Return(nil, &googleapi.Error{Code: http.StatusForbidden, Message: "The caller does not have permission"})
+							mockConditions.EXPECT().FindCondition(gomock.Any(), gcpv1alpha1.ConditionProjectCreated).Return(&gcpv1alpha1.Condition{}, false)
+							mockGCPClient.EXPECT().CreateProject(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, errMock)
+							mockConditions.EXPECT().SetCondition(gomock.Any(), gcpv1alpha1.ConditionProjectCreated, corev1.ConditionFalse, "ProjectCreationFailed", errMock.Error())
+							mockKubeClient.EXPECT().Status().Return(mockStatusWriter)
+							mockStatusWriter.EXPECT().Update(gomock.Any(), gomock.Any()).Return(nil)
 							mockKubeClient.EXPECT().Update(gomock.Any(), gomock.Any()).Return(nil)
 							_, err := EnsureProjectCreated(adapter)
 							Expect(err).To(HaveOccurred())
@@ -368,14 +801,172 @@ var _ = Describe("ProjectreferenceAdapter", func() {
 							Expect(strings.Contains(err.Error(), "could not create project. Parent Folder ID")).To(BeTrue())
 						})
 					})
+
+					Context("When the ProjectClaim pinned the projectID", func() {
+						BeforeEach(func() {
+							projectReference.Spec.GCPProjectID = "pinned-project"
+							projectClaim.Spec.GCPProjectID = "pinned-project"
+						})
+
+						It("It keeps the projectID and requeues with error", func() {
+							mockGCPClient.EXPECT().GetProject(gomock.Any(), projectReference.Spec.GCPProjectID).Return(nil, &googleapi.Error{Code: http.StatusForbidden, Message: "The caller does not have permission"})
+							mockConditions.EXPECT().FindCondition(gomock.Any(), gcpv1alpha1.ConditionProjectCreated).Return(&gcpv1alpha1.Condition{}, false)
+							mockGCPClient.EXPECT().CreateProject(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, errMock)
+							mockConditions.EXPECT().SetCondition(gomock.Any(), gcpv1alpha1.ConditionProjectCreated, corev1.ConditionFalse, "ProjectCreationFailed", errMock.Error())
+							mockKubeClient.EXPECT().Status().Return(mockStatusWriter)
+							mockStatusWriter.EXPECT().Update(gomock.Any(), gomock.Any()).Return(nil)
+							_, err := EnsureProjectCreated(adapter)
+							Expect(err).To(HaveOccurred())
+							Expect(adapter.ProjectReference.Spec.GCPProjectID).To(Equal(projectClaim.Spec.GCPProjectID))
+						})
+					})
+
+					Context("When GCP fails on its side", func() {
+						It("It keeps the projectID as the project may have been created", func() {
+							errUnavailable := &googleapi.Error{Code: http.StatusServiceUnavailable, Message: "Unavailable"}
+							mockGCPClient.EXPECT().GetProject(gomock.Any(), projectReference.Spec.GCPProjectID).Return(nil, &googleapi.Error{Code: http.StatusNotFound, Message: "Not found"})
+							mockConditions.EXPECT().FindCondition(gomock.Any(), gcpv1alpha1.ConditionProjectCreated).Return(&gcpv1alpha1.Condition{}, false)
+							mockGCPClient.EXPECT().CreateProject(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, errUnavailable)
+							mockConditions.EXPECT().SetCondition(gomock.Any(), gcpv1alpha1.ConditionProjectCreated, corev1.ConditionFalse, "ProjectCreationFailed", errUnavailable.Error())
+							mockKubeClient.EXPECT().Status().Return(mockStatusWriter)
+							mockStatusWriter.EXPECT().Update(gomock.Any(), gomock.Any()).Return(nil)
+							projectID := projectReference.Spec.GCPProjectID
+							_, err := EnsureProjectCreated(adapter)
+							Expect(err).To(HaveOccurred())
+							Expect(adapter.ProjectReference.Spec.GCPProjectID).To(Equal(projectID))
+						})
+					})
+
+					Context("When the projectID conflicts", func() {
+						var errConflict = &googleapi.Error{Code: http.StatusConflict, Message: "Requested entity already exists"}
+
+						It("It keeps the projectID while the creation may be pending", func() {
+							mockGCPClient.EXPECT().GetProject(gomock.Any(), projectReference.Spec.GCPProjectID).Return(nil, &googleapi.Error{Code: http.StatusNotFound, Message: "Not found"})
+							mockConditions.EXPECT().FindCondition(gomock.Any(), gcpv1alpha1.ConditionProjectCreated).Return(&gcpv1alpha1.Condition{}, false)
+							mockGCPClient.EXPECT().CreateProject(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, errConflict)
+							mockConditions.EXPECT().SetCondition(gomock.Any(), gcpv1alpha1.ConditionProjectCreated, corev1.ConditionFalse, "ProjectIDConflict", errConflict.Error())
+							mockKubeClient.EXPECT().Status().Return(mockStatusWriter)
+							mockStatusWriter.EXPECT().Update(gomock.Any(), gomock.Any()).Return(nil)
+							projectID := projectReference.Spec.GCPProjectID
+							_, err := EnsureProjectCreated(adapter)
+							Expect(err).To(HaveOccurred())
+							Expect(adapter.ProjectReference.Spec.GCPProjectID).To(Equal(projectID))
+						})
+
+						It("It clears the projectID once it has been conflicting for too long", func() {
+							mockGCPClient.EXPECT().GetProject(gomock.Any(), projectReference.Spec.GCPProjectID).Return(nil, &googleapi.Error{Code: http.StatusNotFound, Message: "Not found"})
+							mockConditions.EXPECT().FindCondition(gomock.Any(), gcpv1alpha1.ConditionProjectCreated).Return(&gcpv1alpha1.Condition{
+								Status:             corev1.ConditionFalse,
+								Reason:             "ProjectIDConflict",
+								LastTransitionTime: metav1.NewTime(time.Now().Add(-time.Hour)),
+							}, true)
+							mockGCPClient.EXPECT().CreateProject(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, errConflict)
+							mockConditions.EXPECT().SetCondition(gomock.Any(), gcpv1alpha1.ConditionProjectCreated, corev1.ConditionFalse, "ProjectIDConflict", errConflict.Error())
+							mockKubeClient.EXPECT().Status().Return(mockStatusWriter)
+							mockStatusWriter.EXPECT().Update(gomock.Any(), gomock.Any()).Return(nil)
+							mockKubeClient.EXPECT().Update(gomock.Any(), gomock.Any()).Return(nil)
+							_, err := EnsureProjectCreated(adapter)
+							Expect(err).To(HaveOccurred())
+							Expect(adapter.ProjectReference.Spec.GCPProjectID).To(BeEmpty())
+						})
+					})
+				})
+			})
+
+			Context("When the project doesn't exist and its creation starts", func() {
+				It("It persists the operation and requeues", func() {
+					mockGCPClient.EXPECT().GetProject(gomock.Any(), projectReference.Spec.GCPProjectID).Return(nil, &googleapi.Error{Code: http.StatusNotFound, Message: "Not found"})
+					mockConditions.EXPECT().FindCondition(gomock.Any(), gcpv1alpha1.ConditionProjectCreated).Return(&gcpv1alpha1.Condition{}, false)
+					mockGCPClient.EXPECT().CreateProject(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(&cloudresourcemanager.Operation{Name: "operations/cp.1"}, nil)
+					mockConditions.EXPECT().SetCondition(gomock.Any(), gcpv1alpha1.ConditionProjectCreated, corev1.ConditionFalse, "ProjectCreationInProgress", "Waiting for operation operations/cp.1")
+					mockKubeClient.EXPECT().Status().Return(mockStatusWriter)
+					mockStatusWriter.EXPECT().Update(gomock.Any(), gomock.Any()).Return(nil)
+					result, err := EnsureProjectCreated(adapter)
+					Expect(err).NotTo(HaveOccurred())
+					Expect(result.RequeueRequest).To(BeTrue())
+					Expect(result.RequeueDelay).To(BeNumerically(">", 0))
+					Expect(projectReference.Status.ProjectCreationOperation).To(Equal("operations/cp.1"))
+				})
+			})
+
+			Context("When the project was created but is not listed yet", func() {
+				It("It requeues with error without creating it again", func() {
+					mockGCPClient.EXPECT().GetProject(gomock.Any(), projectReference.Spec.GCPProjectID).Return(nil, &googleapi.Error{Code: http.StatusNotFound, Message: "Not found"})
+					mockConditions.EXPECT().FindCondition(gomock.Any(), gcpv1alpha1.ConditionProjectCreated).Return(&gcpv1alpha1.Condition{Status: corev1.ConditionTrue, LastTransitionTime: metav1.Now()}, true)
+					_, err := EnsureProjectCreated(adapter)
+					Expect(err).To(HaveOccurred())
+					Expect(strings.Contains(err.Error(), "not visible yet")).To(BeTrue())
+				})
+
+				It("It resets the condition once the project hasn't been visible for too long", func() {
+					mockGCPClient.EXPECT().GetProject(gomock.Any(), projectReference.Spec.GCPProjectID).Return(nil, &googleapi.Error{Code: http.StatusNotFound, Message: "Not found"})
+					mockConditions.EXPECT().FindCondition(gomock.Any(), gcpv1alpha1.ConditionProjectCreated).Return(&gcpv1alpha1.Condition{
+						Status:             corev1.ConditionTrue,
+						LastTransitionTime: metav1.NewTime(time.Now().Add(-time.Hour)),
+					}, true)
+					mockConditions.EXPECT().SetCondition(gomock.Any(), gcpv1alpha1.ConditionProjectCreated, corev1.ConditionFalse, "ProjectNotFound", gomock.Any())
+					mockKubeClient.EXPECT().Status().Return(mockStatusWriter)
+					mockStatusWriter.EXPECT().Update(gomock.Any(), gomock.Any()).Return(nil)
+					_, err := EnsureProjectCreated(adapter)
+					Expect(err).To(HaveOccurred())
+					Expect(strings.Contains(err.Error(), "not visible yet")).To(BeFalse())
+					Expect(recorder.Events).To(Receive(ContainSubstring(util.EventReasonProjectNotFound)))
+				})
+			})
+
+			Context("When a project creation operation is pending", func() {
+				JustBeforeEach(func() {
+					projectReference.Status.ProjectCreationOperation = "operations/cp.1"
+				})
+
+				It("It requeues while the operation isn't done", func() {
+					mockGCPClient.EXPECT().GetOperation(gomock.Any(), "operations/cp.1").Return(&cloudresourcemanager.Operation{Name: "operations/cp.1"}, nil)
+					result, err := EnsureProjectCreated(adapter)
+					Expect(err).NotTo(HaveOccurred())
+					Expect(result.RequeueRequest).To(BeTrue())
+					Expect(projectReference.Status.ProjectCreationOperation).To(Equal("operations/cp.1"))
+				})
+
+				It("It surfaces a failed operation and clears the projectID", func() {
+					mockGCPClient.EXPECT().GetOperation(gomock.Any(), "operations/cp.1").Return(&cloudresourcemanager.Operation{
+						Name:  "operations/cp.1",
+						Done:  true,
+						Error: &cloudresourcemanager.Status{Code: 9, Message: "quota exceeded"},
+					}, nil)
+					mockConditions.EXPECT().SetCondition(gomock.Any(), gcpv1alpha1.ConditionProjectCreated, corev1.ConditionFalse, "ProjectCreationFailed", "operation operations/cp.1 failed with code 9: quota exceeded")
+					mockKubeClient.EXPECT().Status().Return(mockStatusWriter)
+					mockStatusWriter.EXPECT().Update(gomock.Any(), gomock.Any()).Return(nil)
+					mockKubeClient.EXPECT().Update(gomock.Any(), gomock.Any()).Return(nil)
+					_, err := EnsureProjectCreated(adapter)
+					Expect(err).To(HaveOccurred())
+					Expect(projectReference.Status.ProjectCreationOperation).To(BeEmpty())
+					Expect(projectReference.Spec.GCPProjectID).To(BeEmpty())
+				})
+
+				It("It continues once the operation succeeded", func() {
+					mockGCPClient.EXPECT().GetOperation(gomock.Any(), "operations/cp.1").Return(&cloudresourcemanager.Operation{Name: "operations/cp.1", Done: true}, nil)
+					mockConditions.EXPECT().SetCondition(gomock.Any(), gcpv1alpha1.ConditionProjectCreated, corev1.ConditionTrue, "ProjectCreationSucceeded", "Operation operations/cp.1 done")
+					mockKubeClient.EXPECT().Status().Return(mockStatusWriter)
+					mockStatusWriter.EXPECT().Update(gomock.Any(), gomock.Any()).Return(nil)
+					mockGCPClient.EXPECT().GetProject(gomock.Any(), projectReference.Spec.GCPProjectID).Return(&cloudresourcemanager.Project{LifecycleState: "ACTIVE", ProjectId: projectReference.Spec.GCPProjectID}, nil)
+					mockGCPClient.EXPECT().ListAPIs(gomock.Any(), gomock.Any()).Return([]string{"cloudbilling.googleapis.com"}, nil)
+					mockGCPClient.EXPECT().CreateCloudBillingAccount(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil)
+					result, err := EnsureProjectCreated(adapter)
+					Expect(err).NotTo(HaveOccurred())
+					Expect(result).To(Equal(continueProcessingResult))
+					Expect(projectReference.Status.ProjectCreationOperation).To(BeEmpty())
+					// recorded on the ProjectReference and the ProjectClaim
+					Expect(recorder.Events).To(Receive(HavePrefix("Normal ProjectCreated")))
+					Expect(recorder.Events).To(Receive(HavePrefix("Normal ProjectCreated")))
+					Expect(recorder.Events).To(Receive(HavePrefix("Normal BillingLinked")))
 				})
 			})
 
 			Context("When it fails to configure Billing API", func() {
 				Context("When it fails to list APIs", func() {
 					It("It requeues with error", func() {
-						mockGCPClient.EXPECT().ListProjects().Return([]*cloudresourcemanager.Project{{LifecycleState: "ACTIVE", ProjectId: projectReference.Spec.GCPProjectID}}, nil)
-						mockGCPClient.EXPECT().ListAPIs(gomock.Any()).Return(nil, errMock)
+						mockGCPClient.EXPECT().GetProject(gomock.Any(), projectReference.Spec.GCPProjectID).Return(&cloudresourcemanager.Project{LifecycleState: "ACTIVE", ProjectId: projectReference.Spec.GCPProjectID}, nil)
+						mockGCPClient.EXPECT().ListAPIs(gomock.Any(), gomock.Any()).Return(nil, errMock)
 						_, err := EnsureProjectCreated(adapter)
 						Expect(err).To(HaveOccurred())
 					})
@@ -383,9 +974,9 @@ var _ = Describe("ProjectreferenceAdapter", func() {
 
 				Context("When it fails to enable Billing API", func() {
 					It("It requeues with error", func() {
-						mockGCPClient.EXPECT().ListProjects().Return([]*cloudresourcemanager.Project{{LifecycleState: "ACTIVE", ProjectId: projectReference.Spec.GCPProjectID}}, nil)
-						mockGCPClient.EXPECT().ListAPIs(gomock.Any()).Return([]string{"foo"}, nil)
-						mockGCPClient.EXPECT().EnableAPI(gomock.Any(), gomock.Any()).Return(errMock)
+						mockGCPClient.EXPECT().GetProject(gomock.Any(), projectReference.Spec.GCPProjectID).Return(&cloudresourcemanager.Project{LifecycleState: "ACTIVE", ProjectId: projectReference.Spec.GCPProjectID}, nil)
+						mockGCPClient.EXPECT().ListAPIs(gomock.Any(), gomock.Any()).Return([]string{"foo"}, nil)
+						mockGCPClient.EXPECT().EnableAPI(gomock.Any(), gomock.Any(), gomock.Any()).Return(errMock)
 						_, err := EnsureProjectCreated(adapter)
 						Expect(err).To(HaveOccurred())
 						Expect(strings.Contains(err.Error(), "Error enabling cloudbilling.googleapis.com api for project")).To(BeTrue())
@@ -394,9 +985,9 @@ var _ = Describe("ProjectreferenceAdapter", func() {
 
 				Context("When it fails to create Cloud Billing account", func() {
 					It("It requeues with error", func() {
-						mockGCPClient.EXPECT().ListProjects().Return([]*cloudresourcemanager.Project{{LifecycleState: "ACTIVE", ProjectId: projectReference.Spec.GCPProjectID}}, nil)
-						mockGCPClient.EXPECT().ListAPIs(gomock.Any()).Return([]string{"cloudbilling.googleapis.com"}, nil)
-						mockGCPClient.EXPECT().CreateCloudBillingAccount(gomock.Any(), gomock.Any()).Return(errMock)
+						mockGCPClient.EXPECT().GetProject(gomock.Any(), projectReference.Spec.GCPProjectID).Return(&cloudresourcemanager.Project{LifecycleState: "ACTIVE", ProjectId: projectReference.Spec.GCPProjectID}, nil)
+						mockGCPClient.EXPECT().ListAPIs(gomock.Any(), gomock.Any()).Return([]string{"cloudbilling.googleapis.com"}, nil)
+						mockGCPClient.EXPECT().CreateCloudBillingAccount(gomock.Any(), gomock.Any(), gomock.Any()).Return(errMock)
 						_, err := EnsureProjectCreated(adapter)
 						Expect(err).To(HaveOccurred())
 						Expect(strings.Contains(err.Error(), "error creating CloudBilling")).To(BeTrue())
@@ -412,35 +1003,112 @@ var _ = Describe("ProjectreferenceAdapter", func() {
 		JustBeforeEach(func() {
 			projectReference.Spec.GCPProjectID = "Some fake id"
 			projectReference.Status.State = gcpv1alpha1.ProjectReferenceStatusCreating
+			projectReference.Status.APIs = enabledAPIStatus(OSDRequiredAPIS)
+			projectReference.Status.GrantedRoles = []gcpv1alpha1.IAMBinding{{Member: "serviceAccount:foo", Roles: OSDRequiredRoles}}
+			mockGCPClient.EXPECT().GetProject(gomock.Any(), "Some fake id").Return(&cloudresourcemanager.Project{
+				ProjectId:     "Some fake id",
+				ProjectNumber: 123,
+				Labels:        projectLabels(projectClaim, projectReference),
+			}, nil).AnyTimes()
+		})
+
+		Context("When labels and tags are configured", func() {
+			BeforeEach(func() {
+				configMap.ProjectLabels = map[string]string{"team": "sre", "cost_center": "cc-1"}
+				configMap.ProjectTags = []string{"123456789012/environment/production", "123456789012/team/sre"}
+				projectClaim.Spec.CostCenter = "CC-42"
+			})
+
+			It("It adds the missing labels and binds the missing tags", func() {
+				mockGCPClient.EXPECT().UpdateProjectLabels(gomock.Any(), "Some fake id", map[string]string{"team": "sre", "cost_center": "cc-42"}, nil).Return(nil)
+				mockGCPClient.EXPECT().ListTagBindings(gomock.Any(), int64(123)).Return([]string{"tagValues/2"}, nil)
+				mockGCPClient.EXPECT().GetTagValue(gomock.Any(), "123456789012/environment/production").Return(
+					&cloudresourcemanagerv3.TagValue{Name: "tagValues/1", NamespacedName: "123456789012/environment/production"}, nil)
+				mockGCPClient.EXPECT().GetTagValue(gomock.Any(), "123456789012/team/sre").Return(
+					&cloudresourcemanagerv3.TagValue{Name: "tagValues/2", NamespacedName: "123456789012/team/sre"}, nil)
+				mockGCPClient.EXPECT().CreateTagBinding(gomock.Any(), int64(123), "tagValues/1").Return(nil)
+				mockGCPClient.EXPECT().ListAPIs(gomock.Any(), gomock.Any()).Return([]string{}, errMock)
+				_, err := EnsureProjectConfigured(adapter)
+				Expect(err).To(HaveOccurred())
+				Expect(recorder.Events).To(HaveLen(4))
+				Expect(recorder.Events).To(Receive(Equal("Normal ProjectLabeled Labeled GCP project Some fake id with cost_center, team")))
+				Expect(recorder.Events).To(Receive())
+				Expect(recorder.Events).To(Receive(Equal("Normal TagBound Bound tag 123456789012/environment/production to GCP project Some fake id")))
+			})
+
+			It("It requeues with error if the project can't be labeled", func() {
+				mockGCPClient.EXPECT().UpdateProjectLabels(gomock.Any(), "Some fake id", gomock.Any(), nil).Return(errMock)
+				_, err := EnsureProjectConfigured(adapter)
+				Expect(err).To(HaveOccurred())
+			})
+
+			It("It requeues with error if a tag doesn't exist", func() {
+				mockGCPClient.EXPECT().UpdateProjectLabels(gomock.Any(), "Some fake id", gomock.Any(), nil).Return(nil)
+				mockGCPClient.EXPECT().ListTagBindings(gomock.Any(), int64(123)).Return([]string{}, nil)
+				mockGCPClient.EXPECT().GetTagValue(gomock.Any(), "123456789012/environment/production").Return(nil, &googleapi.Error{Code: http.StatusForbidden})
+				_, err := EnsureProjectConfigured(adapter)
+				Expect(err).To(HaveOccurred())
+			})
 		})
 
 		Context("When it fails to configure APIS", func() {
 			Context("When it fails to list APIs", func() {
 				It("It requeues with error", func() {
-					mockGCPClient.EXPECT().ListAPIs(gomock.Any()).Return([]string{}, errMock)
+					mockGCPClient.EXPECT().ListAPIs(gomock.Any(), gomock.Any()).Return([]string{}, errMock)
 					_, err := EnsureProjectConfigured(adapter)
 					Expect(err).To(HaveOccurred())
 				})
 			})
 
 			Context("When it fails to enable APIs", func() {
-				It("It requeues with error", func() {
-					mockGCPClient.EXPECT().ListAPIs(gomock.Any()).Return([]string{}, nil)
-					mockGCPClient.EXPECT().EnableAPI(gomock.Any(), gomock.Any()).Return(errMock)
+				It("It records the failure and requeues with error", func() {
+					mockGCPClient.EXPECT().ListAPIs(gomock.Any(), gomock.Any()).Return(OSDRequiredAPIS[:1], nil)
+					mockGCPClient.EXPECT().EnableAPI(gomock.Any(), gomock.Any(), OSDRequiredAPIS[1]).Return(errMock)
+					mockKubeClient.EXPECT().Status().Return(mockStatusWriter)
+					mockStatusWriter.EXPECT().Update(gomock.Any(), gomock.Any()).Return(nil)
 					_, err := EnsureProjectConfigured(adapter)
 					Expect(err).To(HaveOccurred())
+					Expect(projectReference.Status.APIs).To(HaveLen(2))
+					Expect(projectReference.Status.APIs[0]).To(Equal(gcpv1alpha1.APIStatus{Name: OSDRequiredAPIS[0], Enabled: true}))
+					Expect(projectReference.Status.APIs[1].Name).To(Equal(OSDRequiredAPIS[1]))
+					Expect(projectReference.Status.APIs[1].Enabled).To(BeFalse())
+					Expect(projectReference.Status.APIs[1].Message).To(ContainSubstring(errMock.Error()))
 				})
 			})
 		})
 
+		Context("When APIs are configured in the operator config and the ProjectReference", func() {
+			BeforeEach(func() {
+				configMap.RequiredAPIs = []string{"serviceusage.googleapis.com", "compute.googleapis.com"}
+				projectReference.Spec.AdditionalAPIs = []string{"compute.googleapis.com", "secretmanager.googleapis.com"}
+			})
+
+			It("It enables the configured APIs instead of the default ones", func() {
+				mockGCPClient.EXPECT().ListAPIs(gomock.Any(), gomock.Any()).Return([]string{"compute.googleapis.com"}, nil)
+				gomock.InOrder(
+					mockGCPClient.EXPECT().EnableAPI(gomock.Any(), gomock.Any(), "serviceusage.googleapis.com").Return(nil),
+					mockGCPClient.EXPECT().EnableAPI(gomock.Any(), gomock.Any(), "secretmanager.googleapis.com").Return(nil),
+				)
+				mockKubeClient.EXPECT().Status().Return(mockStatusWriter)
+				mockStatusWriter.EXPECT().Update(gomock.Any(), gomock.Any()).Return(nil)
+				mockGCPClient.EXPECT().GetServiceAccount(gomock.Any(), gomock.Any()).Return(nil, errMock)
+				mockGCPClient.EXPECT().CreateServiceAccount(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, errMock)
+				_, err := EnsureProjectConfigured(adapter)
+				Expect(err).To(HaveOccurred())
+				Expect(projectReference.Status.APIs).To(Equal(enabledAPIStatus([]string{"serviceusage.googleapis.com", "compute.googleapis.com", "secretmanager.googleapis.com"})))
+				Expect(recorder.Events).To(HaveLen(4))
+				Expect(recorder.Events).To(Receive(Equal("Normal APIEnabled Enabled API serviceusage.googleapis.com")))
+			})
+		})
+
 		Context("When it fails to configure Service Accounts", func() {
 			Context("When it fails to get Service Accounts", func() {
 				Context("When it fails to create Service Account", func() {
 					Context("When it fails to create Service Account with fakeError", func() {
 						It("It requeues with error", func() {
-							mockGCPClient.EXPECT().ListAPIs(gomock.Any()).Return(OSDRequiredAPIS, nil)
-							mockGCPClient.EXPECT().GetServiceAccount(gomock.Any()).Return(nil, errMock)
-							mockGCPClient.EXPECT().CreateServiceAccount(gomock.Any(), gomock.Any()).Return(nil, errMock)
+							mockGCPClient.EXPECT().ListAPIs(gomock.Any(), gomock.Any()).Return(OSDRequiredAPIS, nil)
+							mockGCPClient.EXPECT().GetServiceAccount(gomock.Any(), gomock.Any()).Return(nil, errMock)
+							mockGCPClient.EXPECT().CreateServiceAccount(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, errMock)
 							_, err := EnsureProjectConfigured(adapter)
 							Expect(err).To(HaveOccurred())
 						})
@@ -448,9 +1116,9 @@ var _ = Describe("ProjectreferenceAdapter", func() {
 
 					Context("When it fails to create Service Account with matchesAlreadyExistsError", func() {
 						It("It requeues with delay", func() {
-							mockGCPClient.EXPECT().ListAPIs(gomock.Any()).Return(OSDRequiredAPIS, nil)
-							mockGCPClient.EXPECT().GetServiceAccount(gomock.Any()).Return(nil, errMock)
-							mockGCPClient.EXPECT().CreateServiceAccount(gomock.Any(), gomock.Any()).Return(nil, errors.New("googleapi: Error 409:foo"))
+							mockGCPClient.EXPECT().ListAPIs(gomock.Any(), gomock.Any()).Return(OSDRequiredAPIS, nil)
+							mockGCPClient.EXPECT().GetServiceAccount(gomock.Any(), gomock.Any()).Return(nil, errMock)
+							mockGCPClient.EXPECT().CreateServiceAccount(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, &googleapi.Error{Code: http.StatusConflict, Message: "foo"})
 							result, err := EnsureProjectConfigured(adapter)
 							Expect(err).ToNot(HaveOccurred())
 							Expect(result).To(Equal(util.OperationResult{
@@ -466,9 +1134,9 @@ var _ = Describe("ProjectreferenceAdapter", func() {
 			Context("When it fails to configure IAM policy", func() {
 				Context("When it fails to get IAM Policy", func() {
 					It("It requeues with error", func() {
-						mockGCPClient.EXPECT().ListAPIs(gomock.Any()).Return(OSDRequiredAPIS, nil)
-						mockGCPClient.EXPECT().GetServiceAccount(gomock.Any()).Return(&iam.ServiceAccount{Email: "foo"}, nil)
-						mockGCPClient.EXPECT().GetIamPolicy(gomock.Any()).Return(nil, errMock)
+						mockGCPClient.EXPECT().ListAPIs(gomock.Any(), gomock.Any()).Return(OSDRequiredAPIS, nil)
+						mockGCPClient.EXPECT().GetServiceAccount(gomock.Any(), gomock.Any()).Return(&iam.ServiceAccount{Email: "foo"}, nil)
+						mockGCPClient.EXPECT().GetIamPolicy(gomock.Any(), gomock.Any()).Return(nil, errMock)
 						_, err := EnsureProjectConfigured(adapter)
 						Expect(err).To(HaveOccurred())
 					})
@@ -476,10 +1144,10 @@ var _ = Describe("ProjectreferenceAdapter", func() {
 
 				Context("When it fails to set IAM Policy", func() {
 					It("It requeues with error", func() {
-						mockGCPClient.EXPECT().ListAPIs(gomock.Any()).Return(OSDRequiredAPIS, nil)
-						mockGCPClient.EXPECT().GetServiceAccount(gomock.Any()).Return(&iam.ServiceAccount{Email: "foo"}, nil)
-						mockGCPClient.EXPECT().GetIamPolicy(gomock.Any()).Return(&cloudresourcemanager.Policy{}, nil)
-						mockGCPClient.EXPECT().SetIamPolicy(gomock.Any()).Return(nil, errMock)
+						mockGCPClient.EXPECT().ListAPIs(gomock.Any(), gomock.Any()).Return(OSDRequiredAPIS, nil)
+						mockGCPClient.EXPECT().GetServiceAccount(gomock.Any(), gomock.Any()).Return(&iam.ServiceAccount{Email: "foo"}, nil)
+						mockGCPClient.EXPECT().GetIamPolicy(gomock.Any(), gomock.Any()).Return(&cloudresourcemanager.Policy{}, nil)
+						mockGCPClient.EXPECT().SetIamPolicy(gomock.Any(), gomock.Any()).Return(nil, errMock)
 						_, err := EnsureProjectConfigured(adapter)
 						Expect(err).To(HaveOccurred())
 					})
@@ -490,12 +1158,12 @@ var _ = Describe("ProjectreferenceAdapter", func() {
 		Context("When it fails to create credentials", func() {
 			Context("When it fails to get Service Account", func() {
 				It("It requeues with error", func() {
-					mockGCPClient.EXPECT().ListAPIs(gomock.Any()).Return(OSDRequiredAPIS, nil)
-					mockGCPClient.EXPECT().GetServiceAccount(gomock.Any()).Return(&iam.ServiceAccount{Email: "foo"}, nil)
-					mockGCPClient.EXPECT().GetIamPolicy(gomock.Any()).Return(&cloudresourcemanager.Policy{}, nil)
-					mockGCPClient.EXPECT().SetIamPolicy(gomock.Any()).Return(nil, nil)
+					mockGCPClient.EXPECT().ListAPIs(gomock.Any(), gomock.Any()).Return(OSDRequiredAPIS, nil)
+					mockGCPClient.EXPECT().GetServiceAccount(gomock.Any(), gomock.Any()).Return(&iam.ServiceAccount{Email: "foo"}, nil)
+					mockGCPClient.EXPECT().GetIamPolicy(gomock.Any(), gomock.Any()).Return(&cloudresourcemanager.Policy{}, nil)
+					mockGCPClient.EXPECT().SetIamPolicy(gomock.Any(), gomock.Any()).Return(nil, nil)
 					mockKubeClient.EXPECT().Get(gomock.Any(), gomock.Any(), gomock.Any()).Return(errMock)
-					mockGCPClient.EXPECT().GetServiceAccount(gomock.Any()).Return(nil, errMock)
+					mockGCPClient.EXPECT().GetServiceAccount(gomock.Any(), gomock.Any()).Return(nil, errMock)
 					_, err := EnsureProjectConfigured(adapter)
 					Expect(err).To(HaveOccurred())
 				})
@@ -503,13 +1171,13 @@ var _ = Describe("ProjectreferenceAdapter", func() {
 
 			Context("When it fails to create Service Account Key", func() {
 				It("It requeues with error", func() {
-					mockGCPClient.EXPECT().ListAPIs(gomock.Any()).Return(OSDRequiredAPIS, nil)
-					mockGCPClient.EXPECT().GetServiceAccount(gomock.Any()).Return(&iam.ServiceAccount{Email: "foo"}, nil)
-					mockGCPClient.EXPECT().GetIamPolicy(gomock.Any()).Return(&cloudresourcemanager.Policy{}, nil)
-					mockGCPClient.EXPECT().SetIamPolicy(gomock.Any()).Return(nil, nil)
+					mockGCPClient.EXPECT().ListAPIs(gomock.Any(), gomock.Any()).Return(OSDRequiredAPIS, nil)
+					mockGCPClient.EXPECT().GetServiceAccount(gomock.Any(), gomock.Any()).Return(&iam.ServiceAccount{Email: "foo"}, nil)
+					mockGCPClient.EXPECT().GetIamPolicy(gomock.Any(), gomock.Any()).Return(&cloudresourcemanager.Policy{}, nil)
+					mockGCPClient.EXPECT().SetIamPolicy(gomock.Any(), gomock.Any()).Return(nil, nil)
 					mockKubeClient.EXPECT().Get(gomock.Any(), gomock.Any(), gomock.Any()).Return(errMock)
-					mockGCPClient.EXPECT().GetServiceAccount(gomock.Any()).Return(&iam.ServiceAccount{Email: "foo"}, nil)
-					mockGCPClient.EXPECT().CreateServiceAccountKey(gomock.Any()).Return(nil, errMock)
+					mockGCPClient.EXPECT().GetServiceAccount(gomock.Any(), gomock.Any()).Return(&iam.ServiceAccount{Email: "foo"}, nil)
+					mockGCPClient.EXPECT().CreateServiceAccountKey(gomock.Any(), gomock.Any()).Return(nil, errMock)
 					_, err := EnsureProjectConfigured(adapter)
 					Expect(err).To(HaveOccurred())
 				})
@@ -517,13 +1185,13 @@ var _ = Describe("ProjectreferenceAdapter", func() {
 
 			Context("When it fails to create secret", func() {
 				It("It requeues with error", func() {
-					mockGCPClient.EXPECT().ListAPIs(gomock.Any()).Return(OSDRequiredAPIS, nil)
-					mockGCPClient.EXPECT().GetServiceAccount(gomock.Any()).Return(&iam.ServiceAccount{Email: "foo"}, nil)
-					mockGCPClient.EXPECT().GetIamPolicy(gomock.Any()).Return(&cloudresourcemanager.Policy{}, nil)
-					mockGCPClient.EXPECT().SetIamPolicy(gomock.Any()).Return(nil, nil)
+					mockGCPClient.EXPECT().ListAPIs(gomock.Any(), gomock.Any()).Return(OSDRequiredAPIS, nil)
+					mockGCPClient.EXPECT().GetServiceAccount(gomock.Any(), gomock.Any()).Return(&iam.ServiceAccount{Email: "foo"}, nil)
+					mockGCPClient.EXPECT().GetIamPolicy(gomock.Any(), gomock.Any()).Return(&cloudresourcemanager.Policy{}, nil)
+					mockGCPClient.EXPECT().SetIamPolicy(gomock.Any(), gomock.Any()).Return(nil, nil)
 					mockKubeClient.EXPECT().Get(gomock.Any(), gomock.Any(), gomock.Any()).Return(errMock)
-					mockGCPClient.EXPECT().GetServiceAccount(gomock.Any()).Return(&iam.ServiceAccount{Email: "foo"}, nil)
-					mockGCPClient.EXPECT().CreateServiceAccountKey(gomock.Any()).Return(&iam.ServiceAccountKey{PrivateKeyData: "YWRtaW4="}, nil)
+					mockGCPClient.EXPECT().GetServiceAccount(gomock.Any(), gomock.Any()).Return(&iam.ServiceAccount{Email: "foo"}, nil)
+					mockGCPClient.EXPECT().CreateServiceAccountKey(gomock.Any(), gomock.Any()).Return(&iam.ServiceAccountKey{PrivateKeyData: "YWRtaW4="}, nil)
 					mockKubeClient.EXPECT().Create(gomock.Any(), gomock.Any()).Return(errMock)
 					_, err := EnsureProjectConfigured(adapter)
 					Expect(err).To(HaveOccurred())
@@ -531,13 +1199,35 @@ var _ = Describe("ProjectreferenceAdapter", func() {
 			})
 		})
 
+		Context("When workload identity federation is enabled", func() {
+			BeforeEach(func() {
+				projectClaim.Spec.CredentialMode = gcpv1alpha1.CredentialModeWorkloadIdentityFederation
+				projectClaim.Spec.WorkloadIdentity = &gcpv1alpha1.WorkloadIdentityConfig{IssuerURI: "https://oidc.example.com"}
+			})
+
+			It("It creates the workload identity pool before the credentials", func() {
+				mockGCPClient.EXPECT().ListAPIs(gomock.Any(), gomock.Any()).Return(append(OSDRequiredAPIS, "sts.googleapis.com"), nil)
+				mockKubeClient.EXPECT().Status().Return(mockStatusWriter)
+				mockStatusWriter.EXPECT().Update(gomock.Any(), gomock.Any()).Return(nil)
+				mockGCPClient.EXPECT().GetServiceAccount(gomock.Any(), gomock.Any()).Return(&iam.ServiceAccount{Email: "foo"}, nil)
+				mockGCPClient.EXPECT().GetIamPolicy(gomock.Any(), gomock.Any()).Return(&cloudresourcemanager.Policy{}, nil)
+				mockGCPClient.EXPECT().SetIamPolicy(gomock.Any(), gomock.Any()).Return(nil, nil)
+				mockGCPClient.EXPECT().GetWorkloadIdentityPool(gomock.Any(), "Some fake id", "osd-managed").Return(nil, &googleapi.Error{Code: http.StatusNotFound, Message: "Requested entity was not found"})
+				mockGCPClient.EXPECT().CreateWorkloadIdentityPool(gomock.Any(), "Some fake id", "osd-managed", gomock.Any()).Return(nil)
+				result, err := EnsureProjectConfigured(adapter)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(result).To(Equal(util.OperationResult{RequeueDelay: 5 * time.Second, RequeueRequest: true}))
+				Expect(projectReference.Status.APIs).To(ContainElement(gcpv1alpha1.APIStatus{Name: "sts.googleapis.com", Enabled: true}))
+			})
+		})
+
 		Context("When it create credentials successfully", func() {
 			Context("Credential Secret already exists", func() {
 				It("Continue execute", func() {
-					mockGCPClient.EXPECT().ListAPIs(gomock.Any()).Return(OSDRequiredAPIS, nil)
-					mockGCPClient.EXPECT().GetServiceAccount(gomock.Any()).Return(&iam.ServiceAccount{Email: "foo"}, nil)
-					mockGCPClient.EXPECT().GetIamPolicy(gomock.Any()).Return(&cloudresourcemanager.Policy{}, nil)
-					mockGCPClient.EXPECT().SetIamPolicy(gomock.Any()).Return(nil, nil)
+					mockGCPClient.EXPECT().ListAPIs(gomock.Any(), gomock.Any()).Return(OSDRequiredAPIS, nil)
+					mockGCPClient.EXPECT().GetServiceAccount(gomock.Any(), gomock.Any()).Return(&iam.ServiceAccount{Email: "foo"}, nil)
+					mockGCPClient.EXPECT().GetIamPolicy(gomock.Any(), gomock.Any()).Return(&cloudresourcemanager.Policy{}, nil)
+					mockGCPClient.EXPECT().SetIamPolicy(gomock.Any(), gomock.Any()).Return(nil, nil)
 					mockKubeClient.EXPECT().Get(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil)
 					_, err := EnsureProjectConfigured(adapter)
 					Expect(err).ToNot(HaveOccurred())
@@ -546,30 +1236,38 @@ var _ = Describe("ProjectreferenceAdapter", func() {
 
 			Context("Create a secret successfully", func() {
 				It("Continue execute", func() {
-					mockGCPClient.EXPECT().ListAPIs(gomock.Any()).Return(OSDRequiredAPIS, nil)
-					mockGCPClient.EXPECT().GetServiceAccount(gomock.Any()).Return(&iam.ServiceAccount{Email: "foo"}, nil)
-					mockGCPClient.EXPECT().GetIamPolicy(gomock.Any()).Return(&cloudresourcemanager.Policy{}, nil)
-					mockGCPClient.EXPECT().SetIamPolicy(gomock.Any()).Return(nil, nil)
+					mockGCPClient.EXPECT().ListAPIs(gomock.Any(), gomock.Any()).Return(OSDRequiredAPIS, nil)
+					mockGCPClient.EXPECT().GetServiceAccount(gomock.Any(), gomock.Any()).Return(&iam.ServiceAccount{Email: "foo"}, nil)
+					mockGCPClient.EXPECT().GetIamPolicy(gomock.Any(), gomock.Any()).Return(&cloudresourcemanager.Policy{}, nil)
+					mockGCPClient.EXPECT().SetIamPolicy(gomock.Any(), gomock.Any()).Return(nil, nil)
 					mockKubeClient.EXPECT().Get(gomock.Any(), gomock.Any(), gomock.Any()).Return(errMock)
-					mockGCPClient.EXPECT().GetServiceAccount(gomock.Any()).Return(&iam.ServiceAccount{Email: "foo"}, nil)
-					mockGCPClient.EXPECT().CreateServiceAccountKey(gomock.Any()).Return(&iam.ServiceAccountKey{PrivateKeyData: "YWRtaW4="}, nil)
+					mockGCPClient.EXPECT().GetServiceAccount(gomock.Any(), gomock.Any()).Return(&iam.ServiceAccount{Email: "foo"}, nil)
+					mockGCPClient.EXPECT().CreateServiceAccountKey(gomock.Any(), gomock.Any()).Return(&iam.ServiceAccountKey{Name: "projects/foo/serviceAccounts/foo/keys/1", PrivateKeyData: "YWRtaW4="}, nil)
 					mockKubeClient.EXPECT().Create(gomock.Any(), gomock.Any()).Return(nil)
+					mockKubeClient.EXPECT().Status().Return(mockStatusWriter)
+					mockStatusWriter.EXPECT().Update(gomock.Any(), gomock.Any()).Return(nil)
 					_, err := EnsureProjectConfigured(adapter)
 					Expect(err).ToNot(HaveOccurred())
+					Expect(projectReference.Status.ServiceAccountKey).ToNot(BeNil())
+					Expect(projectReference.Status.ServiceAccountKey.Name).To(Equal("projects/foo/serviceAccounts/foo/keys/1"))
+					Expect(projectReference.Status.ServiceAccountKey.NextRotationTime).To(BeNil())
 				})
 			})
 		})
 
 		Context("When ccsConsoleAccess configured", func() {
 			JustBeforeEach(func() {
-				mockGCPClient.EXPECT().ListAPIs(gomock.Any()).Return(OSDRequiredAPIS, nil)
-				mockGCPClient.EXPECT().GetServiceAccount(gomock.Any()).Return(&iam.ServiceAccount{Email: "foo"}, nil)
-				mockGCPClient.EXPECT().GetIamPolicy(gomock.Any()).Return(&cloudresourcemanager.Policy{}, nil)
-				mockGCPClient.EXPECT().SetIamPolicy(gomock.Any()).Return(nil, nil)
+				mockGCPClient.EXPECT().ListAPIs(gomock.Any(), gomock.Any()).Return(OSDRequiredAPIS, nil)
+				mockGCPClient.EXPECT().GetServiceAccount(gomock.Any(), gomock.Any()).Return(&iam.ServiceAccount{Email: "foo"}, nil)
+				mockGCPClient.EXPECT().GetIamPolicy(gomock.Any(), gomock.Any()).Return(&cloudresourcemanager.Policy{}, nil)
+				mockGCPClient.EXPECT().SetIamPolicy(gomock.Any(), gomock.Any()).Return(nil, nil)
 				mockKubeClient.EXPECT().Get(gomock.Any(), gomock.Any(), gomock.Any()).Return(errMock)
-				mockGCPClient.EXPECT().GetServiceAccount(gomock.Any()).Return(&iam.ServiceAccount{Email: "foo"}, nil)
-				mockGCPClient.EXPECT().CreateServiceAccountKey(gomock.Any()).Return(&iam.ServiceAccountKey{PrivateKeyData: "YWRtaW4="}, nil)
+				mockGCPClient.EXPECT().GetServiceAccount(gomock.Any(), gomock.Any()).Return(&iam.ServiceAccount{Email: "foo"}, nil)
+				mockGCPClient.EXPECT().CreateServiceAccountKey(gomock.Any(), gomock.Any()).Return(&iam.ServiceAccountKey{PrivateKeyData: "YWRtaW4="}, nil)
 				mockKubeClient.EXPECT().Create(gomock.Any(), gomock.Any()).Return(nil)
+				// the key of the new secret is recorded
+				mockKubeClient.EXPECT().Status().Return(mockStatusWriter)
+				mockStatusWriter.EXPECT().Update(gomock.Any(), gomock.Any()).Return(nil)
 
 				adapter.OperatorConfig.CCSConsoleAccess = []string{"example-group@xxx.com"}
 			})
@@ -584,12 +1282,15 @@ var _ = Describe("ProjectreferenceAdapter", func() {
 			Context("When it is a CCS project", func() {
 				JustBeforeEach(func() {
 					projectReference.Spec.CCS = true
+					// the roles granted to the groups are recorded
+					mockKubeClient.EXPECT().Status().Return(mockStatusWriter).MinTimes(1)
+					mockStatusWriter.EXPECT().Update(gomock.Any(), gomock.Any()).Return(nil).MinTimes(1)
 				})
 
 				Context("When only one ccsConsoleAccessAccount are configured", func() {
 					It("It doesn't need to create a service account", func() {
-						mockGCPClient.EXPECT().GetIamPolicy(gomock.Any()).Return(&cloudresourcemanager.Policy{}, nil)
-						mockGCPClient.EXPECT().SetIamPolicy(gomock.Any())
+						mockGCPClient.EXPECT().GetIamPolicy(gomock.Any(), gomock.Any()).Return(&cloudresourcemanager.Policy{}, nil)
+						mockGCPClient.EXPECT().SetIamPolicy(gomock.Any(), gomock.Any())
 						_, err := EnsureProjectConfigured(adapter)
 						Expect(err).ToNot(HaveOccurred())
 					})
@@ -600,10 +1301,10 @@ var _ = Describe("ProjectreferenceAdapter", func() {
 						adapter.OperatorConfig.CCSConsoleAccess = []string{"foo", "bar"}
 					})
 					It("repeat the process", func() {
-						mockGCPClient.EXPECT().GetIamPolicy(gomock.Any()).Return(&cloudresourcemanager.Policy{}, nil)
-						mockGCPClient.EXPECT().SetIamPolicy(gomock.Any())
-						mockGCPClient.EXPECT().GetIamPolicy(gomock.Any()).Return(&cloudresourcemanager.Policy{}, nil)
-						mockGCPClient.EXPECT().SetIamPolicy(gomock.Any())
+						mockGCPClient.EXPECT().GetIamPolicy(gomock.Any(), gomock.Any()).Return(&cloudresourcemanager.Policy{}, nil)
+						mockGCPClient.EXPECT().SetIamPolicy(gomock.Any(), gomock.Any())
+						mockGCPClient.EXPECT().GetIamPolicy(gomock.Any(), gomock.Any()).Return(&cloudresourcemanager.Policy{}, nil)
+						mockGCPClient.EXPECT().SetIamPolicy(gomock.Any(), gomock.Any())
 						_, err := EnsureProjectConfigured(adapter)
 						Expect(err).ToNot(HaveOccurred())
 					})
@@ -613,14 +1314,17 @@ var _ = Describe("ProjectreferenceAdapter", func() {
 
 		Context("When ccsReadOnlyConsoleAccess configured", func() {
 			JustBeforeEach(func() {
-				mockGCPClient.EXPECT().ListAPIs(gomock.Any()).Return(OSDRequiredAPIS, nil)
-				mockGCPClient.EXPECT().GetServiceAccount(gomock.Any()).Return(&iam.ServiceAccount{Email: "foo"}, nil)
-				mockGCPClient.EXPECT().GetIamPolicy(gomock.Any()).Return(&cloudresourcemanager.Policy{}, nil)
-				mockGCPClient.EXPECT().SetIamPolicy(gomock.Any()).Return(nil, nil)
+				mockGCPClient.EXPECT().ListAPIs(gomock.Any(), gomock.Any()).Return(OSDRequiredAPIS, nil)
+				mockGCPClient.EXPECT().GetServiceAccount(gomock.Any(), gomock.Any()).Return(&iam.ServiceAccount{Email: "foo"}, nil)
+				mockGCPClient.EXPECT().GetIamPolicy(gomock.Any(), gomock.Any()).Return(&cloudresourcemanager.Policy{}, nil)
+				mockGCPClient.EXPECT().SetIamPolicy(gomock.Any(), gomock.Any()).Return(nil, nil)
 				mockKubeClient.EXPECT().Get(gomock.Any(), gomock.Any(), gomock.Any()).Return(errMock)
-				mockGCPClient.EXPECT().GetServiceAccount(gomock.Any()).Return(&iam.ServiceAccount{Email: "foo"}, nil)
-				mockGCPClient.EXPECT().CreateServiceAccountKey(gomock.Any()).Return(&iam.ServiceAccountKey{PrivateKeyData: "YWRtaW4="}, nil)
+				mockGCPClient.EXPECT().GetServiceAccount(gomock.Any(), gomock.Any()).Return(&iam.ServiceAccount{Email: "foo"}, nil)
+				mockGCPClient.EXPECT().CreateServiceAccountKey(gomock.Any(), gomock.Any()).Return(&iam.ServiceAccountKey{PrivateKeyData: "YWRtaW4="}, nil)
 				mockKubeClient.EXPECT().Create(gomock.Any(), gomock.Any()).Return(nil)
+				// the key of the new secret is recorded
+				mockKubeClient.EXPECT().Status().Return(mockStatusWriter)
+				mockStatusWriter.EXPECT().Update(gomock.Any(), gomock.Any()).Return(nil)
 
 				adapter.OperatorConfig.CCSReadOnlyConsoleAccess = []string{"example-group@xxx.com"}
 			})
@@ -635,12 +1339,15 @@ var _ = Describe("ProjectreferenceAdapter", func() {
 			Context("When it is a CCS project", func() {
 				JustBeforeEach(func() {
 					projectReference.Spec.CCS = true
+					// the roles granted to the groups are recorded
+					mockKubeClient.EXPECT().Status().Return(mockStatusWriter).MinTimes(1)
+					mockStatusWriter.EXPECT().Update(gomock.Any(), gomock.Any()).Return(nil).MinTimes(1)
 				})
 
 				Context("When only one ccsReadOnlyConsoleAccessAccount are configured", func() {
 					It("It doesn't need to create a service account", func() {
-						mockGCPClient.EXPECT().GetIamPolicy(gomock.Any()).Return(&cloudresourcemanager.Policy{}, nil)
-						mockGCPClient.EXPECT().SetIamPolicy(gomock.Any())
+						mockGCPClient.EXPECT().GetIamPolicy(gomock.Any(), gomock.Any()).Return(&cloudresourcemanager.Policy{}, nil)
+						mockGCPClient.EXPECT().SetIamPolicy(gomock.Any(), gomock.Any())
 						_, err := EnsureProjectConfigured(adapter)
 						Expect(err).ToNot(HaveOccurred())
 					})
@@ -651,10 +1358,10 @@ var _ = Describe("ProjectreferenceAdapter", func() {
 						adapter.OperatorConfig.CCSReadOnlyConsoleAccess = []string{"foo", "bar"}
 					})
 					It("repeat the process", func() {
-						mockGCPClient.EXPECT().GetIamPolicy(gomock.Any()).Return(&cloudresourcemanager.Policy{}, nil)
-						mockGCPClient.EXPECT().SetIamPolicy(gomock.Any())
-						mockGCPClient.EXPECT().GetIamPolicy(gomock.Any()).Return(&cloudresourcemanager.Policy{}, nil)
-						mockGCPClient.EXPECT().SetIamPolicy(gomock.Any())
+						mockGCPClient.EXPECT().GetIamPolicy(gomock.Any(), gomock.Any()).Return(&cloudresourcemanager.Policy{}, nil)
+						mockGCPClient.EXPECT().SetIamPolicy(gomock.Any(), gomock.Any())
+						mockGCPClient.EXPECT().GetIamPolicy(gomock.Any(), gomock.Any()).Return(&cloudresourcemanager.Policy{}, nil)
+						mockGCPClient.EXPECT().SetIamPolicy(gomock.Any(), gomock.Any())
 						_, err := EnsureProjectConfigured(adapter)
 						Expect(err).ToNot(HaveOccurred())
 					})
@@ -663,6 +1370,73 @@ var _ = Describe("ProjectreferenceAdapter", func() {
 		})
 	})
 
+	Context("SetIAMPolicy", func() {
+		var policy *cloudresourcemanager.Policy
+		BeforeEach(func() {
+			projectReference.Status.GrantedRoles = []gcpv1alpha1.IAMBinding{{Member: "group:sre", Roles: []string{"roles/editor", "roles/viewer"}}}
+			policy = &cloudresourcemanager.Policy{Bindings: []*cloudresourcemanager.Binding{
+				{Role: "roles/editor", Members: []string{"group:sre", "group:customer"}},
+				{Role: "roles/viewer", Members: []string{"group:sre"}},
+				{Role: "roles/owner", Members: []string{"group:sre"}},
+			}}
+		})
+
+		Context("When a granted role isn't required anymore", func() {
+			It("It revokes it and records the granted roles", func() {
+				mockGCPClient.EXPECT().GetIamPolicy(gomock.Any(), gomock.Any()).Return(policy, nil)
+				mockGCPClient.EXPECT().SetIamPolicy(gomock.Any(), gomock.Any()).DoAndReturn(
+					func(_ context.Context, request *cloudresourcemanager.SetIamPolicyRequest) (*cloudresourcemanager.Policy, error) {
+						Expect(request.Policy.Bindings).To(ConsistOf(
+							&cloudresourcemanager.Binding{Role: "roles/editor", Members: []string{"group:customer"}},
+							&cloudresourcemanager.Binding{Role: "roles/viewer", Members: []string{"group:sre"}},
+							&cloudresourcemanager.Binding{Role: "roles/owner", Members: []string{"group:sre"}},
+						))
+						return request.Policy, nil
+					})
+				mockKubeClient.EXPECT().Status().Return(mockStatusWriter)
+				mockStatusWriter.EXPECT().Update(gomock.Any(), gomock.Any()).Return(nil)
+				Expect(adapter.SetIAMPolicy("sre", []string{"roles/viewer"}, util.GoogleGroup)).To(Succeed())
+				Expect(projectReference.Status.GrantedRoles).To(Equal([]gcpv1alpha1.IAMBinding{{Member: "group:sre", Roles: []string{"roles/viewer"}}}))
+			})
+		})
+
+		Context("When the granted roles are still required", func() {
+			It("It leaves the policy and the status alone", func() {
+				mockGCPClient.EXPECT().GetIamPolicy(gomock.Any(), gomock.Any()).Return(policy, nil)
+				Expect(adapter.SetIAMPolicy("sre", []string{"roles/editor", "roles/viewer"}, util.GoogleGroup)).To(Succeed())
+			})
+		})
+	})
+
+	Context("When role sets are configured", func() {
+		BeforeEach(func() {
+			configMap.ServiceAccountRoles = []string{"roles/compute.admin"}
+			configMap.CCSConsoleAccessRoles = []string{"roles/editor"}
+			configMap.CCSReadOnlyConsoleAccessRoles = []string{"roles/viewer"}
+			configMap.CCSConsoleAccess = []string{"sre"}
+			configMap.CCSReadOnlyConsoleAccess = []string{"sre"}
+			projectReference.Spec.GCPProjectID = "Some fake id"
+			projectReference.Spec.CCS = true
+			projectReference.Status.APIs = enabledAPIStatus(OSDRequiredAPIS)
+		})
+
+		It("EnsureProjectConfigured grants them, once per member", func() {
+			mockGCPClient.EXPECT().ListAPIs(gomock.Any(), gomock.Any()).Return(OSDRequiredAPIS, nil)
+			mockGCPClient.EXPECT().GetServiceAccount(gomock.Any(), gomock.Any()).Return(&iam.ServiceAccount{Email: "foo"}, nil)
+			mockGCPClient.EXPECT().GetIamPolicy(gomock.Any(), gomock.Any()).Return(&cloudresourcemanager.Policy{}, nil).Times(2)
+			mockGCPClient.EXPECT().SetIamPolicy(gomock.Any(), gomock.Any()).Times(2)
+			mockKubeClient.EXPECT().Status().Return(mockStatusWriter).Times(2)
+			mockStatusWriter.EXPECT().Update(gomock.Any(), gomock.Any()).Return(nil).Times(2)
+			mockKubeClient.EXPECT().Get(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil)
+			_, err := EnsureProjectConfigured(adapter)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(projectReference.Status.GrantedRoles).To(Equal([]gcpv1alpha1.IAMBinding{
+				{Member: "serviceAccount:foo", Roles: []string{"roles/compute.admin"}},
+				{Member: "group:sre", Roles: []string{"roles/editor", "roles/viewer"}},
+			}))
+		})
+	})
+
 	Context("IsDeletionRequested", func() {
 		Context("If there is a deletionTimestamp", func() {
 			It("returns true", func() {
@@ -729,13 +1503,13 @@ var _ = Describe("ProjectreferenceAdapter", func() {
 			projectReference.Spec.GCPProjectID = "fake-id"
 			projectState = "ACTIVE"
 			email = "Some Email"
-			mockGCPClient.EXPECT().GetServiceAccount(gomock.Any()).Return(&iam.ServiceAccount{Email: email}, nil).Times(1)
-			mockGCPClient.EXPECT().GetIamPolicy(gomock.Any()).Return(&cloudresourcemanager.Policy{}, nil)
-			mockGCPClient.EXPECT().DeleteServiceAccount(gomock.Eq(email)).Return(nil).Times(1)
+			mockGCPClient.EXPECT().GetServiceAccount(gomock.Any(), gomock.Any()).Return(&iam.ServiceAccount{Email: email}, nil).Times(1)
+			mockGCPClient.EXPECT().GetIamPolicy(gomock.Any(), gomock.Any()).Return(&cloudresourcemanager.Policy{}, nil)
+			mockGCPClient.EXPECT().DeleteServiceAccount(gomock.Any(), gomock.Eq(email)).Return(nil).Times(1)
 		})
 		Context("When it's a non-CCS Project", func() {
 			JustBeforeEach(func() {
-				mockGCPClient.EXPECT().ListProjects().Return([]*cloudresourcemanager.Project{{LifecycleState: projectState, ProjectId: projectReference.Spec.GCPProjectID}}, nil)
+				mockGCPClient.EXPECT().GetProject(gomock.Any(), projectReference.Spec.GCPProjectID).Return(&cloudresourcemanager.Project{LifecycleState: projectState, ProjectId: projectReference.Spec.GCPProjectID}, nil)
 			})
 			Context("When the lifecycleStatus is unknown", func() {
 				BeforeEach(func() {
@@ -768,7 +1542,7 @@ var _ = Describe("ProjectreferenceAdapter", func() {
 			})
 			Context("When the lifecycleStatus is ACTIVE", func() {
 				It("deletes the project", func() {
-					mockGCPClient.EXPECT().DeleteProject(gomock.Any()).Times(1)
+					mockGCPClient.EXPECT().DeleteProject(gomock.Any(), gomock.Any()).Times(1)
 					mockKubeClient.EXPECT().Get(gomock.Any(), gomock.Any(), gomock.Any()).SetArg(2, corev1.Secret{}).Times(2)
 					mockKubeClient.EXPECT().Delete(gomock.Any(), gomock.Any()).Times(1)
 					err := adapter.EnsureProjectCleanedUp()
@@ -777,7 +1551,7 @@ var _ = Describe("ProjectreferenceAdapter", func() {
 			})
 			Context("When it cannot delete the project", func() {
 				It("returns an error", func() {
-					mockGCPClient.EXPECT().DeleteProject(gomock.Any()).Times(1)
+					mockGCPClient.EXPECT().DeleteProject(gomock.Any(), gomock.Any()).Times(1)
 					mockKubeClient.EXPECT().Get(gomock.Any(), gomock.Any(), gomock.Any()).SetArg(2, corev1.Secret{}).Times(2)
 					mockKubeClient.EXPECT().Delete(gomock.Any(), gomock.Any()).Return(errors.New("Cannot delete the project"))
 					err := adapter.EnsureProjectCleanedUp()
@@ -812,8 +1586,20 @@ var _ = Describe("ProjectreferenceAdapter", func() {
 					mockKubeClient.EXPECT().Get(gomock.Any(), gomock.Any(), gomock.Any()).SetArg(2, corev1.Secret{}).Times(2)
 					mockKubeClient.EXPECT().Delete(gomock.Any(), gomock.Any())
 					// delete CCSConsoleAccess and CCSReadOnlyConsoleAccess
-					mockGCPClient.EXPECT().GetIamPolicy(gomock.Any()).Return(&testPolicy, nil).Times(2)
-					mockGCPClient.EXPECT().SetIamPolicy(gomock.Any()).Times(2)
+					mockGCPClient.EXPECT().GetIamPolicy(gomock.Any(), gomock.Any()).Return(&testPolicy, nil).Times(2)
+					mockGCPClient.EXPECT().SetIamPolicy(gomock.Any(), gomock.Any()).Times(2)
+					err := adapter.EnsureProjectCleanedUp()
+					Expect(err).NotTo(HaveOccurred())
+				})
+			})
+			Context("When workload identity federation has been configured", func() {
+				BeforeEach(func() {
+					projectReference.Status.WorkloadIdentityProvider = "projects/1/locations/global/workloadIdentityPools/osd-managed/providers/osd-managed-oidc"
+				})
+				It("deletes the workload identity pool", func() {
+					mockKubeClient.EXPECT().Get(gomock.Any(), gomock.Any(), gomock.Any()).SetArg(2, corev1.Secret{}).Times(2)
+					mockKubeClient.EXPECT().Delete(gomock.Any(), gomock.Any())
+					mockGCPClient.EXPECT().DeleteWorkloadIdentityPool(gomock.Any(), "fake-id", "osd-managed").Return(nil)
 					err := adapter.EnsureProjectCleanedUp()
 					Expect(err).NotTo(HaveOccurred())
 				})
@@ -822,15 +1608,73 @@ var _ = Describe("ProjectreferenceAdapter", func() {
 	})
 
 	Context("UpdateProjectID", func() {
-		BeforeEach(func() {
-			mockKubeClient.EXPECT().Update(gomock.Any(), gomock.Any())
-		})
+		notFound := &googleapi.Error{Code: http.StatusForbidden}
 		It("Sets a new projectid", func() {
+			mockKubeClient.EXPECT().List(gomock.Any(), gomock.Any())
+			mockGCPClient.EXPECT().GetProject(gomock.Any(), gomock.Any()).Return(nil, notFound)
+			mockKubeClient.EXPECT().Update(gomock.Any(), gomock.Any())
 			projectIDBefore := projectReference.Spec.GCPProjectID
 			err := adapter.UpdateProjectID()
 			Expect(err).NotTo(HaveOccurred())
 			Expect(projectReference.Spec.GCPProjectID).NotTo(Equal(projectIDBefore))
 		})
 
+		Context("with a projectIDTemplate", func() {
+			BeforeEach(func() {
+				configMap.ProjectIDTemplate = "osd-{{.LegalEntityID | hash 6}}-{{rand 4}}"
+				projectReference.Spec.LegalEntity.ID = "1234"
+			})
+			It("sets the rendered project ID", func() {
+				mockKubeClient.EXPECT().List(gomock.Any(), gomock.Any())
+				mockGCPClient.EXPECT().GetProject(gomock.Any(), gomock.Any()).Return(nil, notFound)
+				mockKubeClient.EXPECT().Update(gomock.Any(), gomock.Any())
+				Expect(adapter.UpdateProjectID()).To(Succeed())
+				Expect(projectReference.Spec.GCPProjectID).To(MatchRegexp("^osd-03ac67-[0-9a-f]{4}$"))
+			})
+			It("generates another project ID if a project exists", func() {
+				mockKubeClient.EXPECT().List(gomock.Any(), gomock.Any()).Times(2)
+				mockGCPClient.EXPECT().GetProject(gomock.Any(), gomock.Any()).Return(&cloudresourcemanager.Project{}, nil)
+				mockGCPClient.EXPECT().GetProject(gomock.Any(), gomock.Any()).Return(nil, notFound)
+				mockKubeClient.EXPECT().Update(gomock.Any(), gomock.Any())
+				Expect(adapter.UpdateProjectID()).To(Succeed())
+			})
+			It("fails if the project IDs are in use by other ProjectReferences", func() {
+				adapter.OperatorConfig.ProjectIDTemplate = "osd-{{.LegalEntityID | hash 6}}"
+				other := gcpv1alpha1.ProjectReference{Spec: gcpv1alpha1.ProjectReferenceSpec{GCPProjectID: "osd-03ac67"}}
+				other.UID = "other"
+				mockKubeClient.EXPECT().List(gomock.Any(), gomock.Any()).SetArg(1, gcpv1alpha1.ProjectReferenceList{Items: []gcpv1alpha1.ProjectReference{other}}).Times(5)
+				projectIDBefore := projectReference.Spec.GCPProjectID
+				Expect(adapter.UpdateProjectID()).To(MatchError(ContainSubstring("in use")))
+				Expect(projectReference.Spec.GCPProjectID).To(Equal(projectIDBefore))
+			})
+			It("fails if the project ID is invalid", func() {
+				adapter.OperatorConfig.ProjectIDTemplate = "{{.LegalEntityID}}"
+				Expect(adapter.UpdateProjectID()).To(MatchError(ContainSubstring("must be 6 to 30")))
+			})
+		})
 	})
 })
+
+// enabledAPIStatus returns the status of apis once they are all enabled
+func enabledAPIStatus(apis []string) []gcpv1alpha1.APIStatus {
+	status := []gcpv1alpha1.APIStatus{}
+	for _, api := range apis {
+		status = append(status, gcpv1alpha1.APIStatus{Name: api, Enabled: true})
+	}
+	return status
+}
+
+// serviceDisabledError is the error GCP returns for calls to the API of service before it is enabled
+func serviceDisabledError(service string) error {
+	return &googleapi.Error{
+		Code:    http.StatusForbidden,
+		Message: service + " has not been used in project fake-gcp-project before or it is disabled.",
+		Details: []interface{}{
+			map[string]interface{}{
+				"@type":    "type.googleapis.com/google.rpc.ErrorInfo",
+				"reason":   "SERVICE_DISABLED",
+				"metadata": map[string]interface{}{"service": service},
+			},
+		},
+	}
+}
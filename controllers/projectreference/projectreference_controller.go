@@ -19,23 +19,30 @@ package projectreference
 import (
 	"context"
 	"fmt"
-	"reflect"
-	"strings"
-
-	goruntime "runtime"
 
 	"github.com/go-logr/logr"
 	"github.com/openshift/gcp-project-operator/pkg/configmap"
 	"github.com/openshift/gcp-project-operator/pkg/gcpclient"
+	"github.com/openshift/gcp-project-operator/pkg/tracing"
 	"github.com/openshift/gcp-project-operator/pkg/util"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+	"golang.org/x/oauth2/google"
 	"k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/events"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	gcpv1alpha1 "github.com/openshift/gcp-project-operator/api/v1alpha1"
 	"github.com/openshift/gcp-project-operator/pkg/condition"
 	operrors "github.com/openshift/gcp-project-operator/pkg/util/errors"
+	corev1 "k8s.io/api/core/v1"
 	ctrl "sigs.k8s.io/controller-runtime"
 )
 
@@ -52,6 +59,9 @@ type ProjectReferenceReconciler struct {
 	client.Client
 	Scheme           *runtime.Scheme
 	GcpClientBuilder func(projectName string, authJSON []byte) (gcpclient.Client, error)
+	Recorder         events.EventRecorder
+	// clients caches the clients built with GcpClientBuilder per credentials secret
+	clients clientCache
 }
 
 // Reconcile is part of the main kubernetes reconciliation loop which aims to
@@ -65,9 +75,14 @@ type ProjectReferenceReconciler struct {
 // - https://pkg.go.dev/sigs.k8s.io/controller-runtime@v0.11.0/pkg/reconcile
 func (r *ProjectReferenceReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
 	reqLogger := log.FromContext(ctx)
+	ctx, span := tracing.Tracer().Start(ctx, "ProjectReferenceReconciler.Reconcile", trace.WithAttributes(
+		tracing.NamespaceKey.String(req.Namespace),
+		tracing.NameKey.String(req.Name),
+	))
+	defer span.End()
 
 	projectReference := &gcpv1alpha1.ProjectReference{}
-	err := r.Get(context.TODO(), req.NamespacedName, projectReference)
+	err := r.Get(ctx, req.NamespacedName, projectReference)
 	if err != nil {
 		if errors.IsNotFound(err) {
 			// Request object not found, could have been deleted after reconcile request.
@@ -94,26 +109,34 @@ func (r *ProjectReferenceReconciler) Reconcile(ctx context.Context, req ctrl.Req
 		}
 	}
 
-	gcpClient, err := r.getGcpClient(projectReference, reqLogger)
+	gcpClient, err := r.getGcpClient(ctx, projectReference)
 	if err != nil {
 		return ctrl.Result{}, err
 	}
 
-	cm, err := r.getConfigMap()
+	cm, err := r.getConfigMap(ctx)
 	if err != nil {
 		return ctrl.Result{}, err
 	}
 
 	conditionManager := condition.NewConditionManager()
-	adapter, err := NewReferenceAdapter(projectReference, reqLogger, r.Client, gcpClient, conditionManager, cm)
+	adapter, err := NewReferenceAdapter(ctx, projectReference, reqLogger, r.Client, gcpClient, conditionManager, cm, r.Recorder)
 	if err != nil {
 		err = operrors.Wrap(err, "could not create ReferenceAdapter")
 		return ctrl.Result{}, err
 	}
 
+	if projectReference.Spec.GCPProjectID != "" {
+		span.SetAttributes(tracing.ProjectIDKey.String(projectReference.Spec.GCPProjectID))
+	}
 	result, err := r.ReconcileHandler(adapter, reqLogger)
 	reason := "ReconcileError"
 	_ = adapter.SetProjectReferenceCondition(reason, err)
+	if err != nil {
+		adapter.recordGCPError(err)
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
 
 	reqLogger.V(1).Info(fmt.Sprintf("Finished Reconcile. Error occurred: %t, Requeing: %t, Delay: %d", err != nil, result.Requeue, result.RequeueAfter))
 	return result, err
@@ -123,6 +146,7 @@ type ReferenceReconcileOperation func(*ReferenceAdapter) (util.OperationResult,
 
 // ReconcileHandler reads that state of the cluster for a ProjectReference object and makes changes based on the state read
 // and what is in the ProjectReference.Spec
+// Every operation is traced in a child span of the context of adapter.
 func (r *ProjectReferenceReconciler) ReconcileHandler(adapter *ReferenceAdapter, reqLogger logr.Logger) (ctrl.Result, error) {
 	operations := []ReferenceReconcileOperation{
 		EnsureProjectReferenceInitialized, //Set conditions
@@ -132,16 +156,22 @@ func (r *ProjectReferenceReconciler) ReconcileHandler(adapter *ReferenceAdapter,
 		EnsureProjectReferenceStatusCreating,
 		EnsureProjectID,
 		EnsureServiceAccountName,
+		EnsureProjectAdopted,
 		EnsureFinalizerAdded,
 		EnsureProjectCreated,
 		EnsureProjectConfigured,
 		EnsureStateReady,
 	}
+	ctx := adapter.ctx
+	defer func() { adapter.ctx = ctx }()
 	for _, operation := range operations {
-		if log.Log.V(3).Enabled() {
-			log.Log.V(3).Info("func", "name", strings.Split(goruntime.FuncForPC(reflect.ValueOf(operation).Pointer()).Name(), ".")[2])
-		}
+		name := tracing.OperationName(operation)
+		log.Log.V(3).Info("func", "name", name)
+		var span trace.Span
+		// the GCP calls of the operation use the context of adapter
+		adapter.ctx, span = tracing.Tracer().Start(ctx, name)
 		result, err := operation(adapter)
+		tracing.End(span, err)
 		if err != nil || result.RequeueRequest {
 			return ctrl.Result{RequeueAfter: result.RequeueDelay}, err
 		}
@@ -152,8 +182,9 @@ func (r *ProjectReferenceReconciler) ReconcileHandler(adapter *ReferenceAdapter,
 	return ctrl.Result{}, nil
 }
 
-// Returns a gcpClient, that uses the access credential Secret in the CCS project namespace or the operator namespace
-func (r *ProjectReferenceReconciler) getGcpClient(projectReference *gcpv1alpha1.ProjectReference, logger logr.Logger) (gcpclient.Client, error) {
+// getGcpClient returns the client of the project of projectReference, which is reused
+// by the following reconciles until its credentials secret changes.
+func (r *ProjectReferenceReconciler) getGcpClient(ctx context.Context, projectReference *gcpv1alpha1.ProjectReference) (gcpclient.Client, error) {
 	credSecretNamespace := operatorNamespace
 	credSecretName := orgGcpSecretName
 	if projectReference.Spec.CCS {
@@ -161,23 +192,40 @@ func (r *ProjectReferenceReconciler) getGcpClient(projectReference *gcpv1alpha1.
 		credSecretName = projectReference.Spec.CCSSecretRef.Name
 	}
 	// Get org creds from secret
-	creds, err := util.GetGCPCredentialsFromSecret(r.Client, credSecretNamespace, credSecretName)
+	secret := &corev1.Secret{}
+	if err := r.Get(ctx, types.NamespacedName{Namespace: credSecretNamespace, Name: credSecretName}, secret); err != nil {
+		return nil, operrors.Wrap(err, fmt.Sprintf("could not get Creds from secret: %s, for namespace %s", credSecretName, credSecretNamespace))
+	}
+	creds, err := util.GCPCredentialsFromSecret(secret)
 	if err != nil {
 		err = operrors.Wrap(err, fmt.Sprintf("could not get Creds from secret: %s, for namespace %s", credSecretName, credSecretNamespace))
 		return nil, err
 	}
+	if projectReference.Spec.CCS {
+		// external_account configs make the operator read local files and send them to the configured URLs,
+		// customers may only provide service account keys
+		credType, err := gcpclient.CredentialsType(creds)
+		if err != nil {
+			return nil, operrors.Wrap(err, fmt.Sprintf("could not parse Creds from secret: %s, for namespace %s", credSecretName, credSecretNamespace))
+		}
+		if credType != google.ServiceAccount {
+			return nil, fmt.Errorf("unsupported credentials type %q in secret: %s, for namespace %s, expected %q", credType, credSecretName, credSecretNamespace, google.ServiceAccount)
+		}
+	}
 
-	// Get gcpclient with creds
-	gcpClient, err := r.GcpClientBuilder(projectReference.Spec.GCPProjectID, creds)
+	// Get gcpclient with creds, the client of the secret is shared by all its projects
+	gcpClient, err := r.clients.get(secret, func() (gcpclient.Client, error) {
+		return r.GcpClientBuilder("", creds)
+	})
 	if err != nil {
 		return nil, operrors.Wrap(err, fmt.Sprintf("could not get gcp client with secret: %s, for namespace %s", credSecretName, credSecretNamespace))
 	}
 
-	return gcpClient, nil
+	return gcpClient.ForProject(projectReference.Spec.GCPProjectID), nil
 }
 
-func (r *ProjectReferenceReconciler) getConfigMap() (configmap.OperatorConfigMap, error) {
-	operatorConfigMap, err := configmap.GetOperatorConfigMap(r.Client)
+func (r *ProjectReferenceReconciler) getConfigMap(ctx context.Context) (configmap.OperatorConfigMap, error) {
+	operatorConfigMap, err := configmap.GetOperatorConfigMap(ctx, r.Client)
 	if err != nil {
 		return operatorConfigMap, operrors.Wrap(err, "could not find the OperatorConfigMap")
 	}
@@ -190,8 +238,56 @@ func (r *ProjectReferenceReconciler) getConfigMap() (configmap.OperatorConfigMap
 }
 
 // SetupWithManager sets up the controller with the Manager.
+// Besides ProjectReferences it watches their ProjectClaims, the metadata of the secrets the ProjectClaims reference and the operator ConfigMap,
+// so their changes are reconciled right away.
 func (r *ProjectReferenceReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	return ctrl.NewControllerManagedBy(mgr).
 		For(&gcpv1alpha1.ProjectReference{}).
+		Watches(&gcpv1alpha1.ProjectClaim{}, handler.EnqueueRequestsFromMapFunc(RequestsForProjectClaim)).
+		Watches(&corev1.Secret{}, handler.EnqueueRequestsFromMapFunc(r.RequestsForSecret), builder.OnlyMetadata).
+		Watches(&corev1.ConfigMap{}, handler.EnqueueRequestsFromMapFunc(r.RequestsForOperatorConfigMap),
+			builder.WithPredicates(predicate.NewPredicateFuncs(configmap.IsOperatorConfigMap))).
 		Complete(r)
 }
+
+// RequestsForProjectClaim returns the request of the ProjectReference of the ProjectClaim claim
+func RequestsForProjectClaim(_ context.Context, claim client.Object) []reconcile.Request {
+	projectClaim, ok := claim.(*gcpv1alpha1.ProjectClaim)
+	if !ok {
+		return nil
+	}
+	link := projectClaim.ProjectReferenceLink()
+	return []reconcile.Request{{NamespacedName: types.NamespacedName{Namespace: link.Namespace, Name: link.Name}}}
+}
+
+// RequestsForSecret returns the requests of the ProjectReferences whose ProjectClaims reference secret,
+// e.g. to recreate the GCP credentials secret once it was deleted
+func (r *ProjectReferenceReconciler) RequestsForSecret(ctx context.Context, secret client.Object) []reconcile.Request {
+	// ProjectClaims may only reference secrets in their own namespace
+	claims := &gcpv1alpha1.ProjectClaimList{}
+	if err := r.List(ctx, claims, client.InNamespace(secret.GetNamespace())); err != nil {
+		log.FromContext(ctx).Error(err, "could not list the ProjectClaims referencing a secret", "secret", client.ObjectKeyFromObject(secret))
+		return nil
+	}
+	requests := []reconcile.Request{}
+	for i := range claims.Items {
+		if claims.Items[i].ReferencesSecret(secret.GetNamespace(), secret.GetName()) {
+			requests = append(requests, RequestsForProjectClaim(ctx, &claims.Items[i])...)
+		}
+	}
+	return requests
+}
+
+// RequestsForOperatorConfigMap returns the requests of all ProjectReferences, which are all configured by the operator ConfigMap
+func (r *ProjectReferenceReconciler) RequestsForOperatorConfigMap(ctx context.Context, _ client.Object) []reconcile.Request {
+	references := &gcpv1alpha1.ProjectReferenceList{}
+	if err := r.List(ctx, references); err != nil {
+		log.FromContext(ctx).Error(err, "could not list the ProjectReferences")
+		return nil
+	}
+	requests := make([]reconcile.Request, 0, len(references.Items))
+	for _, reference := range references.Items {
+		requests = append(requests, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(&reference)})
+	}
+	return requests
+}
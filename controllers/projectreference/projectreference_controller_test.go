@@ -0,0 +1,697 @@
+package projectreference_test
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"google.golang.org/api/cloudresourcemanager/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/events"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	fakekubeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	api "github.com/openshift/gcp-project-operator/api/v1alpha1"
+	"github.com/openshift/gcp-project-operator/controllers/projectclaim"
+	. "github.com/openshift/gcp-project-operator/controllers/projectreference"
+	"github.com/openshift/gcp-project-operator/pkg/configmap"
+	"github.com/openshift/gcp-project-operator/pkg/gcpclient"
+	"github.com/openshift/gcp-project-operator/pkg/gcpclient/fake"
+	"github.com/openshift/gcp-project-operator/pkg/naming"
+	"github.com/openshift/gcp-project-operator/pkg/util"
+	testStructs "github.com/openshift/gcp-project-operator/pkg/util/mocks/structs"
+)
+
+const maxReconcileRounds = 30
+
+var _ = Describe("ProjectReferenceController against the fake GCP backend", func() {
+	var (
+		kubeClient          client.Client
+		backend             *fake.Backend
+		claimReconciler     *projectclaim.ProjectClaimReconciler
+		referenceReconciler *ProjectReferenceReconciler
+		claimName           types.NamespacedName
+		referenceName       types.NamespacedName
+		recorder            *events.FakeRecorder
+		// tick is called before every reconcile round
+		tick func()
+	)
+
+	// reconcileUntil alternates between both reconcilers, like the manager would, until done returns true.
+	reconcileUntil := func(done func() bool) {
+		for i := 0; i < maxReconcileRounds && !done(); i++ {
+			tick()
+			_, _ = claimReconciler.Reconcile(context.TODO(), reconcile.Request{NamespacedName: claimName})
+			_, _ = referenceReconciler.Reconcile(context.TODO(), reconcile.Request{NamespacedName: referenceName})
+		}
+		Expect(done()).To(BeTrue())
+	}
+
+	// recordedReasons drains the events recorded so far and returns their reasons
+	recordedReasons := func() []string {
+		reasons := []string{}
+		for len(recorder.Events) > 0 {
+			reasons = append(reasons, strings.Fields(<-recorder.Events)[1])
+		}
+		return reasons
+	}
+
+	// reconcileUntilReady reconciles until the ProjectClaim is Ready and returns it
+	reconcileUntilReady := func() *api.ProjectClaim {
+		claim := &api.ProjectClaim{}
+		reconcileUntil(func() bool {
+			Expect(kubeClient.Get(context.TODO(), claimName, claim)).To(Succeed())
+			return claim.Status.State == api.ClaimStatusReady
+		})
+		return claim
+	}
+
+	// updateOperatorConfig appends config to the operator config of the OperatorConfigMap
+	updateOperatorConfig := func(config string) {
+		operatorConfig := &corev1.ConfigMap{}
+		Expect(kubeClient.Get(context.TODO(), types.NamespacedName{Name: configmap.OperatorConfigMapName, Namespace: configmap.OperatorConfigMapNamespace}, operatorConfig)).To(Succeed())
+		operatorConfig.Data[configmap.OperatorConfigMapKey] += config
+		Expect(kubeClient.Update(context.TODO(), operatorConfig)).To(Succeed())
+	}
+
+	claimDeleted := func() bool {
+		return errors.IsNotFound(kubeClient.Get(context.TODO(), claimName, &api.ProjectClaim{}))
+	}
+	credentialsSecret := func() error {
+		return kubeClient.Get(context.TODO(), types.NamespacedName{Name: "gcp-secret", Namespace: claimName.Namespace}, &corev1.Secret{})
+	}
+
+	BeforeEach(func() {
+		s := runtime.NewScheme()
+		Expect(clientgoscheme.AddToScheme(s)).To(Succeed())
+		Expect(api.AddToScheme(s)).To(Succeed())
+
+		claim := testStructs.NewProjectClaimBuilder().GetProjectClaim()
+		claim.Spec.GCPCredentialSecret = api.NamespacedName{Name: "gcp-secret", Namespace: claim.Namespace}
+		claim.Spec.AdditionalAPIs = []string{"secretmanager.googleapis.com"}
+		claimName = types.NamespacedName{Name: claim.Name, Namespace: claim.Namespace}
+		referenceName = types.NamespacedName{Name: claim.Namespace + "-" + claim.Name, Namespace: "gcp-project-operator"}
+
+		orgCredentials := testStructs.NewTestSecretBuilder("gcp-project-operator-credentials", "gcp-project-operator", "{}").GetTestSecret()
+		orgCredentials.ResourceVersion = ""
+		operatorConfig := &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: configmap.OperatorConfigMapName, Namespace: configmap.OperatorConfigMapNamespace},
+			Data: map[string]string{
+				configmap.OperatorConfigMapKey: "billingAccount: ABCDEF-123456\nparentFolderID: \"123456789\"\n",
+			},
+		}
+
+		kubeClient = fakekubeclient.NewClientBuilder().
+			WithScheme(s).
+			WithObjects(claim, orgCredentials, operatorConfig).
+			WithStatusSubresource(&api.ProjectClaim{}, &api.ProjectReference{}).
+			Build()
+		backend = fake.NewBackend()
+		tick = func() {}
+		recorder = events.NewFakeRecorder(1000)
+		claimReconciler = &projectclaim.ProjectClaimReconciler{Client: kubeClient, Scheme: s, Recorder: recorder}
+		referenceReconciler = &ProjectReferenceReconciler{Client: kubeClient, Scheme: s, GcpClientBuilder: backend.ClientBuilder(), Recorder: recorder}
+	})
+
+	// adoptProject makes the ProjectClaim adopt projectID
+	adoptProject := func(projectID string) {
+		claim := &api.ProjectClaim{}
+		Expect(kubeClient.Get(context.TODO(), claimName, claim)).To(Succeed())
+		claim.Spec.GCPProjectID = projectID
+		claim.Spec.AdoptProject = true
+		Expect(kubeClient.Update(context.TODO(), claim)).To(Succeed())
+	}
+
+	Context("When the ProjectClaim adopts a project of another claim", func() {
+		BeforeEach(func() {
+			backend.AddProject("existing-project", "123456789", map[string]string{"claim_name": "other-claim"})
+			adoptProject("existing-project")
+		})
+
+		It("Leaves the project alone, also on deletion", func() {
+			reconcileUntil(func() bool {
+				reference := &api.ProjectReference{}
+				return kubeClient.Get(context.TODO(), referenceName, reference) == nil && reference.Status.State == api.ProjectReferenceStatusError
+			})
+			Expect(recordedReasons()).To(ContainElement(util.EventReasonAdoptionRejected))
+			Expect(backend.ServiceAccounts("existing-project")).To(BeEmpty())
+
+			claim := &api.ProjectClaim{}
+			Expect(kubeClient.Get(context.TODO(), claimName, claim)).To(Succeed())
+			Expect(kubeClient.Delete(context.TODO(), claim)).To(Succeed())
+			reconcileUntil(claimDeleted)
+			project, _ := backend.Project("existing-project")
+			Expect(project.LifecycleState).To(Equal(fake.LifecycleStateActive))
+		})
+	})
+
+	It("Waits for the project creation operation before configuring the project", func() {
+		now := time.Now()
+		backend.Now = func() time.Time { return now }
+		backend.SetPropagationDelay(time.Minute)
+
+		reference := &api.ProjectReference{}
+		reconcileUntil(func() bool {
+			Expect(client.IgnoreNotFound(kubeClient.Get(context.TODO(), referenceName, reference))).To(Succeed())
+			return reference.Status.ProjectCreationOperation != ""
+		})
+		projectID := reference.Spec.GCPProjectID
+		for i := 0; i < 3; i++ {
+			_, _ = referenceReconciler.Reconcile(context.TODO(), reconcile.Request{NamespacedName: referenceName})
+		}
+		Expect(kubeClient.Get(context.TODO(), referenceName, reference)).To(Succeed())
+		Expect(reference.Status.ProjectCreationOperation).NotTo(BeEmpty())
+		Expect(backend.EnabledServices(projectID)).To(BeEmpty())
+
+		tick = func() { now = now.Add(time.Minute) }
+		claim := &api.ProjectClaim{}
+		reconcileUntil(func() bool {
+			Expect(kubeClient.Get(context.TODO(), claimName, claim)).To(Succeed())
+			return claim.Status.State == api.ClaimStatusReady
+		})
+		Expect(claim.Spec.GCPProjectID).To(Equal(projectID))
+		Expect(kubeClient.Get(context.TODO(), referenceName, reference)).To(Succeed())
+		Expect(reference.Status.ProjectCreationOperation).To(BeEmpty())
+	})
+
+	Context("When the ProjectClaim is Ready", func() {
+		var (
+			claim     *api.ProjectClaim
+			projectID string
+		)
+
+		JustBeforeEach(func() {
+			claim = reconcileUntilReady()
+			projectID = claim.Spec.GCPProjectID
+		})
+
+		It("Configures its project and cleans it up on deletion", func() {
+			Expect(projectID).NotTo(BeEmpty())
+			Expect(claim.Spec.AvailabilityZones).To(Equal(fake.DefaultZones["us-east1"]))
+
+			project, ok := backend.Project(projectID)
+			Expect(ok).To(BeTrue())
+			Expect(project.LifecycleState).To(Equal(fake.LifecycleStateActive))
+			Expect(project.Parent.Id).To(Equal("123456789"))
+			billing, _ := backend.BillingInfo(projectID)
+			Expect(billing.BillingAccountName).To(Equal("billingAccounts/ABCDEF-123456"))
+			Expect(backend.ServiceAccounts(projectID)).To(HaveLen(1))
+			Expect(credentialsSecret()).To(Succeed())
+			Expect(recordedReasons()).To(ContainElements(
+				util.EventReasonProjectCreated,
+				util.EventReasonBillingLinked,
+				util.EventReasonAPIEnabled,
+				util.EventReasonServiceAccountCreated,
+				util.EventReasonServiceAccountKeyCreated,
+				util.EventReasonCredentialsWritten,
+			))
+
+			Expect(kubeClient.Delete(context.TODO(), claim)).To(Succeed())
+			reconcileUntil(claimDeleted)
+			Expect(recordedReasons()).To(ContainElements(util.EventReasonDeletionStarted, util.EventReasonDeletionCompleted))
+
+			project, _ = backend.Project(projectID)
+			Expect(project.LifecycleState).To(Equal(fake.LifecycleStateDeleteRequested))
+			Expect(errors.IsNotFound(kubeClient.Get(context.TODO(), referenceName, &api.ProjectReference{}))).To(BeTrue())
+		})
+
+		Context("When GCP API calls fail", func() {
+			BeforeEach(func() {
+				backend.InjectError("CreateProject", http.StatusTooManyRequests, 1)
+			})
+
+			It("Records the errors as Warning events", func() {
+				Expect(recordedReasons()).To(ContainElement(util.EventReasonGCPError))
+			})
+		})
+
+		Context("When the GCP clients are counted", func() {
+			var builds int
+
+			BeforeEach(func() {
+				builds = 0
+				build := backend.ClientBuilder()
+				referenceReconciler.GcpClientBuilder = func(projectName string, authJSON []byte) (gcpclient.Client, error) {
+					builds++
+					return build(projectName, authJSON)
+				}
+			})
+
+			It("Reuses the GCP client of the credentials secret until it changes", func() {
+				Expect(builds).To(Equal(1))
+
+				orgCredentials := &corev1.Secret{}
+				Expect(kubeClient.Get(context.TODO(), types.NamespacedName{Name: "gcp-project-operator-credentials", Namespace: "gcp-project-operator"}, orgCredentials)).To(Succeed())
+				orgCredentials.Data["osServiceAccount.json"] = []byte(`{"type": "service_account"}`)
+				Expect(kubeClient.Update(context.TODO(), orgCredentials)).To(Succeed())
+				_, err := referenceReconciler.Reconcile(context.TODO(), reconcile.Request{NamespacedName: referenceName})
+				Expect(err).NotTo(HaveOccurred())
+				_, err = referenceReconciler.Reconcile(context.TODO(), reconcile.Request{NamespacedName: referenceName})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(builds).To(Equal(2))
+			})
+		})
+
+		It("Maps the watched resources to the requests of the claim and reference they concern", func() {
+			reference := &api.ProjectReference{}
+			Expect(kubeClient.Get(context.TODO(), referenceName, reference)).To(Succeed())
+			claimRequests := []reconcile.Request{{NamespacedName: claimName}}
+			referenceRequests := []reconcile.Request{{NamespacedName: referenceName}}
+
+			Expect(RequestsForProjectClaim(context.TODO(), claim)).To(Equal(referenceRequests))
+			Expect(projectclaim.RequestsForProjectReference(context.TODO(), reference)).To(Equal(claimRequests))
+
+			secret := &corev1.Secret{}
+			Expect(kubeClient.Get(context.TODO(), types.NamespacedName{Name: "gcp-secret", Namespace: claimName.Namespace}, secret)).To(Succeed())
+			// the secrets are watched by their metadata only
+			secretMetadata := &metav1.PartialObjectMetadata{ObjectMeta: secret.ObjectMeta}
+			Expect(referenceReconciler.RequestsForSecret(context.TODO(), secretMetadata)).To(Equal(referenceRequests))
+			Expect(claimReconciler.RequestsForSecret(context.TODO(), secretMetadata)).To(Equal(claimRequests))
+			other := &metav1.PartialObjectMetadata{ObjectMeta: metav1.ObjectMeta{Name: "other", Namespace: claimName.Namespace}}
+			Expect(referenceReconciler.RequestsForSecret(context.TODO(), other)).To(BeEmpty())
+			Expect(claimReconciler.RequestsForSecret(context.TODO(), other)).To(BeEmpty())
+
+			operatorConfig := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: configmap.OperatorConfigMapName, Namespace: configmap.OperatorConfigMapNamespace}}
+			Expect(configmap.IsOperatorConfigMap(operatorConfig)).To(BeTrue())
+			Expect(configmap.IsOperatorConfigMap(&corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "other", Namespace: configmap.OperatorConfigMapNamespace}})).To(BeFalse())
+			Expect(referenceReconciler.RequestsForOperatorConfigMap(context.TODO(), operatorConfig)).To(Equal(referenceRequests))
+			Expect(claimReconciler.RequestsForOperatorConfigMap(context.TODO(), operatorConfig)).To(Equal(claimRequests))
+		})
+
+		It("Recreates the credentials secret once it is deleted", func() {
+			secret := &corev1.Secret{}
+			Expect(kubeClient.Get(context.TODO(), types.NamespacedName{Name: "gcp-secret", Namespace: claimName.Namespace}, secret)).To(Succeed())
+			Expect(kubeClient.Delete(context.TODO(), secret)).To(Succeed())
+
+			// the deletion of the secret is mapped to the ProjectReference, which recreates it
+			requests := referenceReconciler.RequestsForSecret(context.TODO(), secret)
+			Expect(requests).To(HaveLen(1))
+			_, err := referenceReconciler.Reconcile(context.TODO(), requests[0])
+			Expect(err).NotTo(HaveOccurred())
+			Expect(credentialsSecret()).To(Succeed())
+			Expect(recordedReasons()).To(ContainElement(util.EventReasonCredentialsWritten))
+		})
+
+		It("Converges the IAM policy of the project when the role sets change", func() {
+			member := "serviceAccount:" + backend.ServiceAccounts(projectID)[0]
+			updateOperatorConfig("serviceAccountRoles:\n- roles/compute.admin\n- roles/viewer\n")
+
+			reference := &api.ProjectReference{}
+			reconcileUntil(func() bool {
+				Expect(kubeClient.Get(context.TODO(), referenceName, reference)).To(Succeed())
+				return len(reference.Status.GrantedRoles) == 1 && len(reference.Status.GrantedRoles[0].Roles) == 2
+			})
+			Expect(reference.Status.GrantedRoles[0].Member).To(Equal(member))
+
+			policy, _ := backend.Policy(projectID)
+			roles := []string{}
+			for _, binding := range policy.Bindings {
+				for _, m := range binding.Members {
+					if m == member {
+						roles = append(roles, binding.Role)
+					}
+				}
+			}
+			Expect(roles).To(ConsistOf("roles/compute.admin", "roles/viewer"))
+		})
+
+		It("Records and converges the roles of a project configured before they were recorded", func() {
+			member := "serviceAccount:" + backend.ServiceAccounts(projectID)[0]
+			reference := &api.ProjectReference{}
+			Expect(kubeClient.Get(context.TODO(), referenceName, reference)).To(Succeed())
+			reference.Status.GrantedRoles = nil
+			Expect(kubeClient.Status().Update(context.TODO(), reference)).To(Succeed())
+			// the role sets configured before included roles/editor
+			gcp := backend.NewClient(projectID)
+			policy, err := gcp.GetIamPolicy(context.TODO(), projectID)
+			Expect(err).NotTo(HaveOccurred())
+			policy.Bindings = append(policy.Bindings, &cloudresourcemanager.Binding{Role: "roles/editor", Members: []string{member}})
+			_, err = gcp.SetIamPolicy(context.TODO(), &cloudresourcemanager.SetIamPolicyRequest{Policy: policy})
+			Expect(err).NotTo(HaveOccurred())
+
+			reconcileUntil(func() bool {
+				Expect(kubeClient.Get(context.TODO(), referenceName, reference)).To(Succeed())
+				return len(reference.Status.GrantedRoles) == 1
+			})
+			Expect(reference.Status.GrantedRoles[0].Member).To(Equal(member))
+			Expect(reference.Status.GrantedRoles[0].Roles).To(ConsistOf(OSDRequiredRoles))
+			policy, _ = backend.Policy(projectID)
+			for _, binding := range policy.Bindings {
+				if binding.Role == "roles/editor" {
+					Expect(binding.Members).NotTo(ContainElement(member))
+				}
+			}
+		})
+
+		Context("When drift checks are enabled", func() {
+			BeforeEach(func() {
+				updateOperatorConfig("resyncInterval: 1h\n")
+			})
+
+			It("Reports the drift of the project and repairs it once configured to", func() {
+				reference := &api.ProjectReference{}
+				drifted := func() *api.Condition {
+					Expect(kubeClient.Get(context.TODO(), referenceName, reference)).To(Succeed())
+					for i := range reference.Status.Conditions {
+						if reference.Status.Conditions[i].Type == api.ConditionDrifted {
+							return &reference.Status.Conditions[i]
+						}
+					}
+					return nil
+				}
+				reconcileUntil(func() bool { return drifted() != nil })
+				Expect(drifted().Reason).To(Equal("NoDriftDetected"))
+
+				serviceAccount := backend.ServiceAccounts(projectID)[0]
+				backend.DisableService(projectID, "compute.googleapis.com")
+				backend.DeleteServiceAccount(projectID, serviceAccount)
+				backend.SetProjectLabels(projectID, map[string]string{"team": "other"})
+				// the next drift check is due
+				drifted().LastProbeTime = metav1.NewTime(time.Now().Add(-2 * time.Hour))
+				Expect(kubeClient.Status().Update(context.TODO(), reference)).To(Succeed())
+
+				reconcileUntil(func() bool { return drifted().Status == corev1.ConditionTrue })
+				Expect(drifted().Reason).To(Equal("DriftDetected"))
+				Expect(drifted().Message).To(ContainSubstring("API compute.googleapis.com is not enabled"))
+				Expect(drifted().Message).To(ContainSubstring("service account " + reference.Spec.ServiceAccountName + " is missing"))
+				Expect(drifted().Message).To(ContainSubstring("label claim_name=" + naming.LabelValue(claimName.Name) + " is missing"))
+				Expect(backend.ServiceAccounts(projectID)).To(BeEmpty())
+
+				updateOperatorConfig("repairDrift: true\n")
+				reconcileUntil(func() bool { return drifted().Reason == "DriftRepaired" })
+				Expect(backend.EnabledServices(projectID)).To(ContainElement("compute.googleapis.com"))
+				Expect(backend.ServiceAccounts(projectID)).To(HaveLen(1))
+				Expect(backend.ServiceAccountKeys(projectID, backend.ServiceAccounts(projectID)[0])).To(HaveLen(1))
+				// the labels of others are kept
+				project, _ := backend.Project(projectID)
+				Expect(project.Labels).To(HaveKeyWithValue("claim_name", naming.LabelValue(claimName.Name)))
+				Expect(project.Labels).To(HaveKeyWithValue("team", "other"))
+				Expect(credentialsSecret()).To(Succeed())
+			})
+		})
+
+		It("Enables the required APIs and the additional APIs of the ProjectClaim", func() {
+			Expect(backend.EnabledServices(projectID)).To(ContainElements(OSDRequiredAPIS))
+			Expect(backend.EnabledServices(projectID)).To(ContainElement("secretmanager.googleapis.com"))
+			reference := &api.ProjectReference{}
+			Expect(kubeClient.Get(context.TODO(), referenceName, reference)).To(Succeed())
+			Expect(reference.Status.APIs).To(HaveLen(len(OSDRequiredAPIS) + 1))
+			for _, status := range reference.Status.APIs {
+				Expect(status.Enabled).To(BeTrue())
+			}
+		})
+
+		Context("When service account keys are rotated", func() {
+			BeforeEach(func() {
+				updateOperatorConfig("serviceAccountKeyMaxAge: 24h\nserviceAccountKeyGracePeriod: 2h\n")
+			})
+
+			It("Rotates the key of the credentials secret and deletes the replaced key after the grace period", func() {
+				reference := &api.ProjectReference{}
+				getReference := func() *api.ProjectReference {
+					Expect(kubeClient.Get(context.TODO(), referenceName, reference)).To(Succeed())
+					return reference
+				}
+				originalKey := getReference().Status.ServiceAccountKey.Name
+				Expect(reference.Status.ServiceAccountKey.NextRotationTime.Time).To(BeTemporally("~", time.Now().Add(24*time.Hour), time.Minute))
+
+				serviceAccount := backend.ServiceAccounts(projectID)[0]
+				secretName := types.NamespacedName{Name: "gcp-secret", Namespace: claimName.Namespace}
+				secret := &corev1.Secret{}
+				Expect(kubeClient.Get(context.TODO(), secretName, secret)).To(Succeed())
+				originalCredentials := secret.Data["osServiceAccount.json"]
+
+				// the key is due for rotation
+				reference.Status.ServiceAccountKey.CreationTime = metav1.NewTime(time.Now().Add(-25 * time.Hour))
+				Expect(kubeClient.Status().Update(context.TODO(), reference)).To(Succeed())
+				reconcileUntil(func() bool { return getReference().Status.ServiceAccountKey.Name != originalKey })
+				Expect(reference.Status.ServiceAccountKey.PreviousKeysExpirationTime.Time).To(BeTemporally("~", time.Now().Add(2*time.Hour), time.Minute))
+				Expect(backend.ServiceAccountKeys(projectID, serviceAccount)).To(ConsistOf(originalKey, reference.Status.ServiceAccountKey.Name))
+				Expect(kubeClient.Get(context.TODO(), secretName, secret)).To(Succeed())
+				Expect(secret.Data["osServiceAccount.json"]).NotTo(Equal(originalCredentials))
+
+				// keys created by someone else aren't deleted
+				foreignKey, err := backend.NewClient(projectID).CreateServiceAccountKey(context.TODO(), serviceAccount)
+				Expect(err).NotTo(HaveOccurred())
+
+				// the grace period is over
+				expired := metav1.NewTime(time.Now().Add(-time.Minute))
+				reference.Status.ServiceAccountKey.PreviousKeysExpirationTime = &expired
+				Expect(kubeClient.Status().Update(context.TODO(), reference)).To(Succeed())
+				reconcileUntil(func() bool { return getReference().Status.ServiceAccountKey.PreviousKeysExpirationTime == nil })
+				Expect(backend.ServiceAccountKeys(projectID, serviceAccount)).To(ConsistOf(reference.Status.ServiceAccountKey.Name, foreignKey.Name))
+			})
+		})
+
+		Context("When the ProjectClaim uses workload identity federation", func() {
+			BeforeEach(func() {
+				claim := &api.ProjectClaim{}
+				Expect(kubeClient.Get(context.TODO(), claimName, claim)).To(Succeed())
+				claim.Spec.CredentialMode = api.CredentialModeWorkloadIdentityFederation
+				claim.Spec.WorkloadIdentity = &api.WorkloadIdentityConfig{
+					IssuerURI: "https://oidc.example.com/cluster",
+					Subjects:  []string{"system:serviceaccount:openshift-machine-api:machine-api-controllers"},
+				}
+				Expect(kubeClient.Update(context.TODO(), claim)).To(Succeed())
+			})
+
+			It("Issues workload identity federation credentials and replaces them when the credential mode changes", func() {
+				serviceAccount := backend.ServiceAccounts(projectID)[0]
+				Expect(backend.ServiceAccountKeys(projectID, serviceAccount)).To(BeEmpty())
+				Expect(backend.EnabledServices(projectID)).To(ContainElement("sts.googleapis.com"))
+
+				provider, ok := backend.WorkloadIdentityPoolProvider(projectID, "osd-managed", "osd-managed-oidc")
+				Expect(ok).To(BeTrue())
+				Expect(provider.Oidc.IssuerUri).To(Equal("https://oidc.example.com/cluster"))
+				reference := &api.ProjectReference{}
+				Expect(kubeClient.Get(context.TODO(), referenceName, reference)).To(Succeed())
+				Expect(reference.Status.WorkloadIdentityProvider).To(HaveSuffix("/workloadIdentityPools/osd-managed/providers/osd-managed-oidc"))
+				Expect(reference.Status.ServiceAccountKey).To(BeNil())
+
+				policy, _ := backend.ServiceAccountPolicy(projectID, serviceAccount)
+				Expect(policy.Bindings).To(HaveLen(1))
+				Expect(policy.Bindings[0].Role).To(Equal("roles/iam.workloadIdentityUser"))
+				Expect(policy.Bindings[0].Members).To(ConsistOf(
+					"principal://iam.googleapis.com/" + strings.TrimSuffix(reference.Status.WorkloadIdentityProvider, "/providers/osd-managed-oidc") +
+						"/subject/system:serviceaccount:openshift-machine-api:machine-api-controllers"))
+
+				secretName := types.NamespacedName{Name: "gcp-secret", Namespace: claimName.Namespace}
+				secret := &corev1.Secret{}
+				Expect(kubeClient.Get(context.TODO(), secretName, secret)).To(Succeed())
+				Expect(string(secret.Data["osServiceAccount.json"])).To(ContainSubstring(`"type":"external_account"`))
+				Expect(string(secret.Data["osServiceAccount.json"])).To(ContainSubstring(reference.Status.WorkloadIdentityProvider))
+
+				Expect(kubeClient.Get(context.TODO(), claimName, claim)).To(Succeed())
+				claim.Spec.CredentialMode = api.CredentialModeServiceAccountKey
+				Expect(kubeClient.Update(context.TODO(), claim)).To(Succeed())
+				reconcileUntil(func() bool {
+					Expect(kubeClient.Get(context.TODO(), referenceName, reference)).To(Succeed())
+					return reference.Status.ServiceAccountKey != nil
+				})
+				Expect(backend.ServiceAccountKeys(projectID, serviceAccount)).To(ConsistOf(reference.Status.ServiceAccountKey.Name))
+				Expect(kubeClient.Get(context.TODO(), secretName, secret)).To(Succeed())
+				Expect(string(secret.Data["osServiceAccount.json"])).To(ContainSubstring(`"type":"service_account"`))
+
+				// switching back deletes the key of the secret, but not the keys created by someone else
+				foreignKey, err := backend.NewClient(projectID).CreateServiceAccountKey(context.TODO(), serviceAccount)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(kubeClient.Get(context.TODO(), claimName, claim)).To(Succeed())
+				claim.Spec.CredentialMode = api.CredentialModeWorkloadIdentityFederation
+				Expect(kubeClient.Update(context.TODO(), claim)).To(Succeed())
+				reconcileUntil(func() bool {
+					Expect(kubeClient.Get(context.TODO(), referenceName, reference)).To(Succeed())
+					return reference.Status.ServiceAccountKey == nil
+				})
+				Expect(backend.ServiceAccountKeys(projectID, serviceAccount)).To(ConsistOf(foreignKey.Name))
+			})
+		})
+
+		Context("When the ProjectClaim is deleted", func() {
+			var (
+				deletionPolicy api.DeletionPolicy
+				annotations    map[string]string
+			)
+
+			BeforeEach(func() {
+				deletionPolicy = ""
+				annotations = nil
+			})
+
+			JustBeforeEach(func() {
+				claim.Spec.DeletionPolicy = deletionPolicy
+				claim.Annotations = annotations
+				Expect(kubeClient.Update(context.TODO(), claim)).To(Succeed())
+				Expect(kubeClient.Delete(context.TODO(), claim)).To(Succeed())
+			})
+
+			Context("When its deletion policy is Retain", func() {
+				BeforeEach(func() {
+					deletionPolicy = api.DeletionPolicyRetain
+				})
+
+				It("Retains the project without the access of the operator", func() {
+					reconcileUntil(claimDeleted)
+					Expect(recordedReasons()).To(ContainElement(util.EventReasonProjectRetained))
+
+					project, _ := backend.Project(projectID)
+					Expect(project.LifecycleState).To(Equal(fake.LifecycleStateActive))
+					Expect(project.Labels).To(HaveKeyWithValue("orphaned", "true"))
+					Expect(backend.ServiceAccounts(projectID)).To(BeEmpty())
+					policy, _ := backend.Policy(projectID)
+					for _, binding := range policy.Bindings {
+						for _, member := range binding.Members {
+							Expect(member).NotTo(HavePrefix("serviceAccount:"))
+						}
+					}
+					Expect(errors.IsNotFound(credentialsSecret())).To(BeTrue())
+					Expect(errors.IsNotFound(kubeClient.Get(context.TODO(), referenceName, &api.ProjectReference{}))).To(BeTrue())
+				})
+			})
+
+			Context("When its deletion policy is Orphan", func() {
+				BeforeEach(func() {
+					deletionPolicy = api.DeletionPolicyOrphan
+				})
+
+				It("Leaves the project untouched", func() {
+					reconcileUntil(claimDeleted)
+
+					project, _ := backend.Project(projectID)
+					Expect(project.LifecycleState).To(Equal(fake.LifecycleStateActive))
+					Expect(project.Labels).NotTo(HaveKey("orphaned"))
+					Expect(backend.ServiceAccounts(projectID)).To(HaveLen(1))
+					Expect(credentialsSecret()).To(Succeed())
+					Expect(errors.IsNotFound(kubeClient.Get(context.TODO(), referenceName, &api.ProjectReference{}))).To(BeTrue())
+				})
+			})
+
+			Context("When its deletion policy is Delete", func() {
+				BeforeEach(func() {
+					deletionPolicy = api.DeletionPolicyDelete
+				})
+
+				It("Restores the project pending deletion once a recreated ProjectClaim opts in", func() {
+					reconcileUntil(claimDeleted)
+					project, _ := backend.Project(projectID)
+					Expect(project.LifecycleState).To(Equal(fake.LifecycleStateDeleteRequested))
+
+					recreated := testStructs.NewProjectClaimBuilder().GetProjectClaim()
+					recreated.ResourceVersion = ""
+					recreated.Spec.GCPCredentialSecret = api.NamespacedName{Name: "gcp-secret", Namespace: recreated.Namespace}
+					recreated.Spec.GCPProjectID = projectID
+					recreated.Spec.RestoreDeletedProject = true
+					Expect(kubeClient.Create(context.TODO(), recreated)).To(Succeed())
+					Expect(reconcileUntilReady().Spec.GCPProjectID).To(Equal(projectID))
+					Expect(recordedReasons()).To(ContainElement(util.EventReasonProjectRestored))
+
+					project, _ = backend.Project(projectID)
+					Expect(project.LifecycleState).To(Equal(fake.LifecycleStateActive))
+					Expect(backend.ServiceAccounts(projectID)).To(HaveLen(1))
+					Expect(credentialsSecret()).To(Succeed())
+				})
+			})
+
+			Context("When it is protected from deletion", func() {
+				BeforeEach(func() {
+					annotations = map[string]string{api.DeletionProtectionAnnotation: "true"}
+				})
+
+				It("Blocks the deletion until the protection is removed", func() {
+					for i := 0; i < 3; i++ {
+						_, _ = claimReconciler.Reconcile(context.TODO(), reconcile.Request{NamespacedName: claimName})
+						_, _ = referenceReconciler.Reconcile(context.TODO(), reconcile.Request{NamespacedName: referenceName})
+					}
+					Expect(recordedReasons()).To(ContainElement(util.EventReasonDeletionBlocked))
+					project, _ := backend.Project(projectID)
+					Expect(project.LifecycleState).To(Equal(fake.LifecycleStateActive))
+					Expect(kubeClient.Get(context.TODO(), referenceName, &api.ProjectReference{})).To(Succeed())
+
+					Expect(kubeClient.Get(context.TODO(), claimName, claim)).To(Succeed())
+					delete(claim.Annotations, api.DeletionProtectionAnnotation)
+					Expect(kubeClient.Update(context.TODO(), claim)).To(Succeed())
+					reconcileUntil(claimDeleted)
+					project, _ = backend.Project(projectID)
+					Expect(project.LifecycleState).To(Equal(fake.LifecycleStateDeleteRequested))
+				})
+			})
+		})
+
+		Context("When the ProjectClaim adopts a project labeled with its name", func() {
+			BeforeEach(func() {
+				backend.AddProject("existing-project", "123456789", map[string]string{"claim_name": naming.LabelValue(claimName.Name), "orphaned": "true"})
+				adoptProject("existing-project")
+			})
+
+			It("Configures the adopted project instead of creating one", func() {
+				reasons := recordedReasons()
+				Expect(reasons).To(ContainElements(util.EventReasonProjectAdopted, util.EventReasonBillingLinked))
+				Expect(reasons).NotTo(ContainElement(util.EventReasonProjectCreated))
+
+				Expect(projectID).To(Equal("existing-project"))
+				reference := &api.ProjectReference{}
+				Expect(kubeClient.Get(context.TODO(), referenceName, reference)).To(Succeed())
+				Expect(reference.Status.Adopted).To(BeTrue())
+				project, _ := backend.Project(projectID)
+				Expect(project.Labels).To(HaveKeyWithValue("claim_name", naming.LabelValue(claimName.Name)))
+				Expect(project.Labels).To(HaveKey("legal_entity_id"))
+				Expect(project.Labels).NotTo(HaveKey("orphaned"))
+				billing, _ := backend.BillingInfo(projectID)
+				Expect(billing.BillingAccountName).To(Equal("billingAccounts/ABCDEF-123456"))
+				Expect(backend.ServiceAccounts(projectID)).To(HaveLen(1))
+				Expect(credentialsSecret()).To(Succeed())
+			})
+		})
+
+		Context("When the OperatorConfigMap has project templates", func() {
+			BeforeEach(func() {
+				updateOperatorConfig("projectIDTemplate: \"{{.LegalEntityName | slug | trunc 12}}-{{rand 4}}\"\n" +
+					"projectNameTemplate: \"{{.LegalEntityName}} {{.ClaimName}}\"\n")
+			})
+
+			It("Names the project with the templates", func() {
+				Expect(projectID).To(MatchRegexp("^fakelegalent-[0-9a-f]{4}$"))
+				project, ok := backend.Project(projectID)
+				Expect(ok).To(BeTrue())
+				Expect(project.Name).To(Equal("fakeLegalEntityName fakeProjec"))
+			})
+		})
+
+		Context("When the OperatorConfigMap has project labels and tags", func() {
+			var production string
+
+			BeforeEach(func() {
+				production = backend.AddTagValue("123456789012/environment/production")
+				updateOperatorConfig("projectLabels:\n  team: sre\n  cost_center: cc-1\n" +
+					"projectTags:\n- 123456789012/environment/production\n")
+				claim := &api.ProjectClaim{}
+				Expect(kubeClient.Get(context.TODO(), claimName, claim)).To(Succeed())
+				claim.Spec.ClusterID = "1a2b3c4d-cluster"
+				claim.Spec.CostCenter = "CC-42"
+				Expect(kubeClient.Update(context.TODO(), claim)).To(Succeed())
+			})
+
+			It("Labels the project from its ProjectClaim and binds the tags", func() {
+				Expect(recordedReasons()).To(ContainElement(util.EventReasonTagBound))
+				project, _ := backend.Project(projectID)
+				Expect(project.Labels).To(Equal(map[string]string{
+					"claim_name":      naming.LabelValue(claimName.Name),
+					"claim_namespace": naming.LabelValue(claimName.Namespace),
+					"legal_entity_id": "fakelegalentityid",
+					"cluster_id":      "1a2b3c4d-cluster",
+					"cost_center":     "cc-42",
+					"team":            "sre",
+				}))
+				Expect(backend.TagBindings(projectID)).To(Equal([]string{production}))
+			})
+		})
+	})
+})
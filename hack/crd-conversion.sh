@@ -0,0 +1,45 @@
+#!/usr/bin/env bash
+# Adds the conversion webhook of the operator to the CRDs, which controller-gen only generates with kustomize.
+# Run by "make generate" between op-generate and sync-pko-crds. With --check, fails if a generated CRD
+# lacks the conversion webhook instead.
+set -euo pipefail
+
+REPO_ROOT="$(cd "$(dirname "${BASH_SOURCE[0]}")/.." && pwd)"
+cd "$REPO_ROOT"
+
+CRDS=(deploy/crds/gcp.managed.openshift.io_projectclaims.yaml deploy/crds/gcp.managed.openshift.io_projectreferences.yaml)
+PKO_CRDS=(deploy_pko/CustomResourceDefinition-projectclaims.gcp.managed.openshift.io.yaml deploy_pko/CustomResourceDefinition-projectreferences.gcp.managed.openshift.io.yaml)
+
+if [ "${1:-}" = "--check" ]; then
+  failed=0
+  for crd in "${CRDS[@]}" "${PKO_CRDS[@]}"; do
+    if ! grep -q '^  conversion:$' "$crd" || ! grep -q '^    strategy: Webhook$' "$crd"; then
+      echo "$crd has no spec.conversion webhook, run make generate" >&2
+      failed=1
+    fi
+    if ! grep -q "^    service.beta.openshift.io/inject-cabundle: [\"']true[\"']$" "$crd"; then
+      echo "$crd has no service.beta.openshift.io/inject-cabundle annotation, run make generate" >&2
+      failed=1
+    fi
+  done
+  exit "$failed"
+fi
+
+for crd in "${CRDS[@]}"; do
+  grep -q '^  conversion:$' "$crd" && continue
+  # the service CA operator injects its CA into the webhook client config
+  sed -i '/^    controller-gen.kubebuilder.io\/version:/a\    service.beta.openshift.io/inject-cabundle: "true"' "$crd"
+  cat >>"$crd" <<CONVERSION
+  conversion:
+    strategy: Webhook
+    webhook:
+      clientConfig:
+        service:
+          name: gcp-project-operator-webhook
+          namespace: gcp-project-operator
+          path: /convert
+          port: 443
+      conversionReviewVersions:
+      - v1
+CONVERSION
+done
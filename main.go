@@ -17,30 +17,41 @@ limitations under the License.
 package main
 
 import (
+	"context"
 	"flag"
 	"fmt"
 	"net/http"
 	"os"
+	"time"
 
 	// Import all Kubernetes client auth plugins (e.g. Azure, GCP, OIDC, etc.)
 	// to ensure that exec-entrypoint and run can make use of them.
 	_ "k8s.io/client-go/plugin/pkg/client/auth"
 	"k8s.io/client-go/rest"
 
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/meta"
 	"k8s.io/apimachinery/pkg/runtime"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
 	"sigs.k8s.io/controller-runtime/pkg/healthz"
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+	ctrlmetrics "sigs.k8s.io/controller-runtime/pkg/metrics"
 	"sigs.k8s.io/controller-runtime/pkg/metrics/server"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
 
 	gcpv1alpha1 "github.com/openshift/gcp-project-operator/api/v1alpha1"
+	gcpv1beta1 "github.com/openshift/gcp-project-operator/api/v1beta1"
 	"github.com/openshift/gcp-project-operator/controllers/projectclaim"
 	"github.com/openshift/gcp-project-operator/controllers/projectreference"
 	"github.com/openshift/gcp-project-operator/pkg/gcpclient"
+	"github.com/openshift/gcp-project-operator/pkg/metrics"
+	"github.com/openshift/gcp-project-operator/pkg/tracing"
+	"github.com/openshift/gcp-project-operator/pkg/util"
+	"github.com/openshift/gcp-project-operator/webhooks"
 	//+kubebuilder:scaffold:imports
 )
 
@@ -50,12 +61,19 @@ var (
 
 	metricsHost       = "0.0.0.0"
 	metricsPort int32 = 8383
+
+	webhookPort = 9443
+	// webhookCertDir is where the serving certificate issued by the OpenShift service CA is mounted
+	webhookCertDir = "/tmp/k8s-webhook-server/serving-certs"
+
+	tracingShutdownTimeout = 10 * time.Second
 )
 
 func init() {
 	utilruntime.Must(clientgoscheme.AddToScheme(scheme))
 
 	utilruntime.Must(gcpv1alpha1.AddToScheme(scheme))
+	utilruntime.Must(gcpv1beta1.AddToScheme(scheme))
 	//+kubebuilder:scaffold:scheme
 }
 
@@ -63,11 +81,25 @@ func main() {
 	var metricsAddr string
 	var enableLeaderElection bool
 	var probeAddr string
+	var tracingConfig tracing.Config
+	var familyRateLimits string
+	rateLimits := gcpclient.DefaultRateLimits
 	flag.StringVar(&metricsAddr, "metrics-bind-address", ":8383", "The address the metric endpoint binds to.")
 	flag.StringVar(&probeAddr, "health-probe-bind-address", ":8081", "The address the probe endpoint binds to.")
 	flag.BoolVar(&enableLeaderElection, "leader-elect", false,
 		"Enable leader election for controller manager. "+
 			"Enabling this will ensure there is only one active controller manager.")
+	flag.StringVar(&tracingConfig.Exporter, "tracing-exporter", tracing.ExporterNone,
+		"Where to export the traces of the reconciles to: none, otlp, stdout or file. "+
+			"otlp is configured with the OTEL_EXPORTER_OTLP_* environment variables.")
+	flag.StringVar(&tracingConfig.File, "tracing-file", "", "The file the file exporter writes the traces to.")
+	flag.Float64Var(&rateLimits.Default.QPS, "gcp-api-qps", rateLimits.Default.QPS,
+		"The requests per second to each GCP API with the same credentials, 0 disables the rate limiting.")
+	flag.IntVar(&rateLimits.Default.Burst, "gcp-api-burst", rateLimits.Default.Burst,
+		"The burst of the requests to each GCP API with the same credentials.")
+	flag.StringVar(&familyRateLimits, "gcp-api-rate-limits", "",
+		"The rate limits of single GCP APIs overriding --gcp-api-qps and --gcp-api-burst as api=qps:burst, e.g. compute=5:10,iam=20:40. "+
+			"The APIs are cloudresourcemanager, iam, serviceusage, cloudbilling and compute.")
 	opts := zap.Options{
 		Development: true,
 	}
@@ -76,6 +108,21 @@ func main() {
 
 	ctrl.SetLogger(zap.New(zap.UseFlagOptions(&opts)))
 
+	ctx := ctrl.SetupSignalHandler()
+	shutdownTracing, err := tracing.Setup(ctx, tracingConfig)
+	if err != nil {
+		log.Error(err, "unable to set up tracing")
+		os.Exit(1)
+	}
+
+	rateLimits.Families, err = gcpclient.ParseRateLimits(familyRateLimits)
+	if err != nil {
+		log.Error(err, "invalid --gcp-api-rate-limits")
+		os.Exit(1)
+	}
+	// the GCP clients are cached per credentials secret by the ProjectReference controller, they share the rate limits
+	rateLimiter := gcpclient.NewRateLimiter(rateLimits)
+
 	mgr, err := ctrl.NewManager(ctrl.GetConfigOrDie(), ctrl.Options{
 		Metrics: server.Options{
 			BindAddress: fmt.Sprintf("%s:%d", metricsHost, metricsPort),
@@ -84,6 +131,15 @@ func main() {
 		MapperProvider: func(cfg *rest.Config, httpClient *http.Client) (meta.RESTMapper, error) {
 			return apiutil.NewDynamicRESTMapper(cfg, httpClient)
 		},
+		WebhookServer: webhook.NewServer(webhook.Options{
+			Port:    webhookPort,
+			CertDir: webhookCertDir,
+		}),
+		// the secrets the operator reads are spread across the namespaces of the ProjectClaims,
+		// so they are read from the API server instead of caching every secret of the cluster
+		Client: client.Options{
+			Cache: &client.CacheOptions{DisableFor: []client.Object{&corev1.Secret{}}},
+		},
 		LeaderElection:   enableLeaderElection,
 		LeaderElectionID: "gcp-project-operator.openshift.io",
 	})
@@ -100,8 +156,9 @@ func main() {
 
 	log.V(2).Info("Add controllers to Manager")
 	if err = (&projectclaim.ProjectClaimReconciler{
-		Client: mgr.GetClient(),
-		Scheme: mgr.GetScheme(),
+		Client:   mgr.GetClient(),
+		Scheme:   mgr.GetScheme(),
+		Recorder: mgr.GetEventRecorder(util.EventReportingController),
 	}).SetupWithManager(mgr); err != nil {
 		log.Error(err, "unable to create controller", "controller", "ProjectClaim")
 		os.Exit(1)
@@ -110,12 +167,37 @@ func main() {
 		Client: mgr.GetClient(),
 		Scheme: mgr.GetScheme(),
 		GcpClientBuilder: func(projectName string, authJSON []byte) (gcpclient.Client, error) {
-			return gcpclient.NewClient(projectName, authJSON)
+			return gcpclient.NewClient(projectName, authJSON, gcpclient.WithRateLimiter(rateLimiter))
 		},
+		Recorder: mgr.GetEventRecorder(util.EventReportingController),
 	}).SetupWithManager(mgr); err != nil {
 		log.Error(err, "unable to create controller", "controller", "ProjectReference")
 		os.Exit(1)
 	}
+	if err = ctrlmetrics.Registry.Register(metrics.NewProjectClaimCollector(mgr.GetClient())); err != nil {
+		log.Error(err, "unable to register metrics", "collector", "ProjectClaim")
+		os.Exit(1)
+	}
+	// webhooks need a serving certificate, they can be disabled to run the operator locally
+	if os.Getenv("ENABLE_WEBHOOKS") != "false" {
+		log.V(2).Info("Add webhooks to Manager")
+		if err = (&webhooks.ProjectClaimDefaulter{}).SetupWithManager(mgr); err != nil {
+			log.Error(err, "unable to create webhook", "webhook", "ProjectClaim")
+			os.Exit(1)
+		}
+		if err = (&webhooks.ProjectClaimValidator{Client: mgr.GetClient()}).SetupWithManager(mgr); err != nil {
+			log.Error(err, "unable to create webhook", "webhook", "ProjectClaim")
+			os.Exit(1)
+		}
+		if err = (&webhooks.ProjectReferenceValidator{}).SetupWithManager(mgr); err != nil {
+			log.Error(err, "unable to create webhook", "webhook", "ProjectReference")
+			os.Exit(1)
+		}
+		if err = webhooks.SetupConversionWithManager(mgr); err != nil {
+			log.Error(err, "unable to create webhook", "webhook", "conversion")
+			os.Exit(1)
+		}
+	}
 	//+kubebuilder:scaffold:builder
 
 	if err := mgr.AddHealthzCheck("healthz", healthz.Ping); err != nil {
@@ -128,7 +210,14 @@ func main() {
 	}
 
 	log.V(2).Info("starting manager")
-	if err := mgr.Start(ctrl.SetupSignalHandler()); err != nil {
+	err = mgr.Start(ctx)
+	// flush the spans of the last reconciles
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), tracingShutdownTimeout)
+	defer cancel()
+	if err := shutdownTracing(shutdownCtx); err != nil {
+		log.Error(err, "unable to shut down tracing")
+	}
+	if err != nil {
 		log.Error(err, "problem running manager")
 		os.Exit(1)
 	}
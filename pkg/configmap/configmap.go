@@ -3,12 +3,17 @@ package configmap
 import (
 	"context"
 	"fmt"
+	"regexp"
+	"slices"
+	"time"
 
 	"gopkg.in/yaml.v2"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	corev1 "k8s.io/api/core/v1"
 	kubetypes "k8s.io/apimachinery/pkg/types"
+
+	"github.com/openshift/gcp-project-operator/pkg/naming"
 )
 
 // OperatorConfigMapName holds the name of configmap
@@ -25,8 +30,54 @@ type OperatorConfigMap struct {
 	CCSConsoleAccess         []string `yaml:"ccsConsoleAccess,omitempty"`
 	CCSReadOnlyConsoleAccess []string `yaml:"ccsReadOnlyConsoleAccess,omitempty"`
 	DisabledRegions          []string `yaml:"disabledRegions,omitempty"`
+	// RequiredAPIs replaces the default list of APIs enabled on every project, in order
+	RequiredAPIs []string `yaml:"requiredAPIs,omitempty"`
+	// The role sets replace the default project roles granted to the managed service account
+	// and to the CCS console access groups
+	ServiceAccountRoles           []string `yaml:"serviceAccountRoles,omitempty"`
+	SharedVPCRoles                []string `yaml:"sharedVPCRoles,omitempty"`
+	CCSConsoleAccessRoles         []string `yaml:"ccsConsoleAccessRoles,omitempty"`
+	CCSReadOnlyConsoleAccessRoles []string `yaml:"ccsReadOnlyConsoleAccessRoles,omitempty"`
+	// ResyncInterval is how often Ready projects are checked for drift, drift isn't checked if it is 0
+	ResyncInterval time.Duration `yaml:"resyncInterval,omitempty"`
+	// RepairDrift makes the operator reconfigure the projects that drifted
+	RepairDrift bool `yaml:"repairDrift,omitempty"`
+	// ServiceAccountKeyMaxAge is how long the key of a credentials secret is used before it is rotated,
+	// keys aren't rotated if it is 0
+	ServiceAccountKeyMaxAge time.Duration `yaml:"serviceAccountKeyMaxAge,omitempty"`
+	// ServiceAccountKeyGracePeriod is how long the replaced keys stay valid after a rotation,
+	// DefaultServiceAccountKeyGracePeriod if it is 0
+	ServiceAccountKeyGracePeriod time.Duration `yaml:"serviceAccountKeyGracePeriod,omitempty"`
+	// ProjectIDTemplate generates the IDs of the projects the operator creates, see naming.Data and naming.ProjectID,
+	// naming.DefaultProjectIDTemplate if it is empty
+	ProjectIDTemplate string `yaml:"projectIDTemplate,omitempty"`
+	// ProjectNameTemplate generates the display names of the projects the operator creates, see naming.DisplayName,
+	// the display name is the project ID if it is empty
+	ProjectNameTemplate string `yaml:"projectNameTemplate,omitempty"`
+	// ProjectLabels are set on every non-CCS project the operator manages on top of the labels derived from its ProjectClaim,
+	// see naming.ReservedLabels
+	ProjectLabels map[string]string `yaml:"projectLabels,omitempty"`
+	// ProjectTags are the Resource Manager tag values bound to every non-CCS project the operator manages,
+	// as namespaced names, e.g. 123456789012/environment/production
+	ProjectTags []string `yaml:"projectTags,omitempty"`
 }
 
+const (
+	// minResyncInterval is the shortest ResyncInterval, to keep drift checks from exhausting the GCP API quotas
+	minResyncInterval = time.Minute
+	// minServiceAccountKeyMaxAge is the shortest ServiceAccountKeyMaxAge, consumers need time to pick up new keys
+	minServiceAccountKeyMaxAge = time.Hour
+	// DefaultServiceAccountKeyGracePeriod is the ServiceAccountKeyGracePeriod used if none is configured
+	DefaultServiceAccountKeyGracePeriod = time.Hour
+)
+
+var (
+	// roleName matches predefined and custom IAM role names
+	roleName = regexp.MustCompile(`^(roles/[a-zA-Z0-9_.]+|(projects|organizations)/[a-zA-Z0-9_.-]+/roles/[a-zA-Z0-9_.]+)$`)
+	// tagValueName matches the namespaced names of tag values, {parent ID}/{tag key short name}/{tag value short name}
+	tagValueName = regexp.MustCompile(`^[^/\s]+/[^/\s]+/[^/\s]+$`)
+)
+
 // ValidateOperatorConfigMap checks if OperatorConfigMap filled properly
 func ValidateOperatorConfigMap(configmap OperatorConfigMap) error {
 	if configmap.BillingAccount == "" {
@@ -37,14 +88,75 @@ func ValidateOperatorConfigMap(configmap OperatorConfigMap) error {
 		return fmt.Errorf("missing configmap key: parentFolderID")
 	}
 
+	if configmap.ResyncInterval != 0 && configmap.ResyncInterval < minResyncInterval {
+		return fmt.Errorf("invalid configmap key: resyncInterval must be 0 or at least %s", minResyncInterval)
+	}
+
+	if configmap.ServiceAccountKeyMaxAge != 0 && configmap.ServiceAccountKeyMaxAge < minServiceAccountKeyMaxAge {
+		return fmt.Errorf("invalid configmap key: serviceAccountKeyMaxAge must be 0 or at least %s", minServiceAccountKeyMaxAge)
+	}
+
+	if configmap.ServiceAccountKeyGracePeriod < 0 {
+		return fmt.Errorf("invalid configmap key: serviceAccountKeyGracePeriod must not be negative")
+	}
+
+	if configmap.ServiceAccountKeyMaxAge != 0 && configmap.ServiceAccountKeyGracePeriod >= configmap.ServiceAccountKeyMaxAge {
+		return fmt.Errorf("invalid configmap key: serviceAccountKeyGracePeriod must be shorter than serviceAccountKeyMaxAge")
+	}
+
+	if err := naming.ValidateProjectIDTemplate(configmap.ProjectIDTemplate); err != nil {
+		return fmt.Errorf("invalid configmap key: projectIDTemplate: %v", err)
+	}
+
+	if err := naming.ValidateDisplayNameTemplate(configmap.ProjectNameTemplate); err != nil {
+		return fmt.Errorf("invalid configmap key: projectNameTemplate: %v", err)
+	}
+
+	for key, value := range configmap.ProjectLabels {
+		if slices.Contains(naming.ReservedLabels, key) {
+			return fmt.Errorf("invalid configmap key: projectLabels: label %s is set by the operator", key)
+		}
+		if err := naming.ValidateLabel(key, value); err != nil {
+			return fmt.Errorf("invalid configmap key: projectLabels: %v", err)
+		}
+	}
+
+	for _, tag := range configmap.ProjectTags {
+		if !tagValueName.MatchString(tag) {
+			return fmt.Errorf("invalid tag value %q in configmap key: projectTags, expected parentID/key/value", tag)
+		}
+	}
+
+	roleSets := []struct {
+		key   string
+		roles []string
+	}{
+		{"serviceAccountRoles", configmap.ServiceAccountRoles},
+		{"sharedVPCRoles", configmap.SharedVPCRoles},
+		{"ccsConsoleAccessRoles", configmap.CCSConsoleAccessRoles},
+		{"ccsReadOnlyConsoleAccessRoles", configmap.CCSReadOnlyConsoleAccessRoles},
+	}
+	for _, roleSet := range roleSets {
+		for _, role := range roleSet.roles {
+			if !roleName.MatchString(role) {
+				return fmt.Errorf("invalid role %q in configmap key: %s", role, roleSet.key)
+			}
+		}
+	}
+
 	return nil
 }
 
+// IsOperatorConfigMap returns true if obj is the ConfigMap holding the configuration of the operator
+func IsOperatorConfigMap(obj client.Object) bool {
+	return obj.GetNamespace() == OperatorConfigMapNamespace && obj.GetName() == OperatorConfigMapName
+}
+
 // GetOperatorConfigMap returns a configmap defined in requested namespace and name
-func GetOperatorConfigMap(kubeClient client.Client) (OperatorConfigMap, error) {
+func GetOperatorConfigMap(ctx context.Context, kubeClient client.Client) (OperatorConfigMap, error) {
 	var operatorConfigMap OperatorConfigMap
 	configmap := &corev1.ConfigMap{}
-	if err := kubeClient.Get(context.TODO(), kubetypes.NamespacedName{Name: OperatorConfigMapName, Namespace: OperatorConfigMapNamespace}, configmap); err != nil {
+	if err := kubeClient.Get(ctx, kubetypes.NamespacedName{Name: OperatorConfigMapName, Namespace: OperatorConfigMapNamespace}, configmap); err != nil {
 		return operatorConfigMap, fmt.Errorf("unable to get configmap: %v", err)
 	}
 
@@ -1,8 +1,10 @@
 package configmap
 
 import (
+	"context"
 	"errors"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"k8s.io/apimachinery/pkg/runtime"
@@ -27,6 +29,53 @@ func TestValidateOperatorConfigMap(t *testing.T) {
 		t.Errorf("no err expected since OperatorConfigMap filled properly")
 	}
 
+	sut.ServiceAccountRoles = []string{"roles/compute.admin", "organizations/1234567/roles/osdAdmin"}
+	sut.CCSReadOnlyConsoleAccessRoles = []string{"projects/my-project/roles/viewer"}
+	assert.NoError(t, ValidateOperatorConfigMap(sut))
+
+	sut.SharedVPCRoles = []string{"compute.networkAdmin"}
+	err = ValidateOperatorConfigMap(sut)
+	assert.EqualError(t, err, `invalid role "compute.networkAdmin" in configmap key: sharedVPCRoles`)
+	sut.SharedVPCRoles = nil
+
+	sut.ResyncInterval = time.Second
+	assert.Error(t, ValidateOperatorConfigMap(sut))
+	sut.ResyncInterval = time.Hour
+	assert.NoError(t, ValidateOperatorConfigMap(sut))
+
+	sut.ServiceAccountKeyMaxAge = time.Minute
+	assert.Error(t, ValidateOperatorConfigMap(sut))
+	sut.ServiceAccountKeyMaxAge = 24 * time.Hour
+	assert.NoError(t, ValidateOperatorConfigMap(sut))
+	sut.ServiceAccountKeyGracePeriod = 24 * time.Hour
+	assert.Error(t, ValidateOperatorConfigMap(sut))
+	sut.ServiceAccountKeyGracePeriod = -time.Hour
+	assert.Error(t, ValidateOperatorConfigMap(sut))
+	sut.ServiceAccountKeyGracePeriod = 2 * time.Hour
+	assert.NoError(t, ValidateOperatorConfigMap(sut))
+
+	sut.ProjectIDTemplate = "{{.LegalEntityName}}"
+	assert.ErrorContains(t, ValidateOperatorConfigMap(sut), "projectIDTemplate")
+	sut.ProjectIDTemplate = "osd-{{.LegalEntityID | hash 8}}-{{rand 4}}"
+	assert.NoError(t, ValidateOperatorConfigMap(sut))
+	sut.ProjectNameTemplate = "{{.Cluster}}"
+	assert.ErrorContains(t, ValidateOperatorConfigMap(sut), "projectNameTemplate")
+	sut.ProjectNameTemplate = "{{.LegalEntityName}} {{.ClaimName}}"
+	assert.NoError(t, ValidateOperatorConfigMap(sut))
+
+	sut.ProjectLabels = map[string]string{"cost_center": "cc-42", "team": ""}
+	assert.NoError(t, ValidateOperatorConfigMap(sut))
+	sut.ProjectLabels = map[string]string{"claim_name": "claim"}
+	assert.ErrorContains(t, ValidateOperatorConfigMap(sut), "label claim_name is set by the operator")
+	sut.ProjectLabels = map[string]string{"team": "Platform SRE"}
+	assert.ErrorContains(t, ValidateOperatorConfigMap(sut), "projectLabels")
+	sut.ProjectLabels = nil
+
+	sut.ProjectTags = []string{"123456789012/environment/production"}
+	assert.NoError(t, ValidateOperatorConfigMap(sut))
+	sut.ProjectTags = []string{"tagValues/123"}
+	assert.ErrorContains(t, ValidateOperatorConfigMap(sut), "projectTags")
+
 }
 
 func TestGetOperatorConfigMap(t *testing.T) {
@@ -189,7 +238,7 @@ func TestGetOperatorConfigMap(t *testing.T) {
 		t.Run(test.name, func(t *testing.T) {
 			mocks := builders.SetupDefaultMocks(t, test.localObjects)
 
-			operatorConfigMap, err := GetOperatorConfigMap(mocks.FakeKubeClient)
+			operatorConfigMap, err := GetOperatorConfigMap(context.TODO(), mocks.FakeKubeClient)
 
 			if test.expectedErr != nil {
 				assert.Error(t, err)
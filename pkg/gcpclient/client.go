@@ -5,20 +5,27 @@ package gcpclient
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"maps"
 	"net/http"
 	"strings"
 	"time"
 
+	"github.com/openshift/gcp-project-operator/pkg/metrics"
+	"github.com/openshift/gcp-project-operator/pkg/tracing"
+	"github.com/openshift/gcp-project-operator/pkg/util"
+	operrors "github.com/openshift/gcp-project-operator/pkg/util/errors"
 	"golang.org/x/oauth2/google"
-	"google.golang.org/api/googleapi"
 	"google.golang.org/api/option"
 	"google.golang.org/api/serviceusage/v1"
+	htransport "google.golang.org/api/transport/http"
 
 	backoff "github.com/cenkalti/backoff/v4"
 	cloudbilling "google.golang.org/api/cloudbilling/v1"
 	cloudresourcemanager "google.golang.org/api/cloudresourcemanager/v1"
+	cloudresourcemanagerv3 "google.golang.org/api/cloudresourcemanager/v3"
 	compute "google.golang.org/api/compute/v1"
 	iam "google.golang.org/api/iam/v1"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
@@ -28,99 +35,244 @@ var log = logf.Log.WithName("gcpclient")
 
 const gcpAPIRetriesCount = 3
 
+// gcpAPICallTimeout bounds every Client call, including its retries,
+// so a hung GCP request can't hold a reconcile worker indefinitely.
+const gcpAPICallTimeout = 2 * time.Minute
+
 // Client is a wrapper object for actual GCP libraries to allow for easier mocking/testing.
+// Every call is bound to ctx, and at most lasts gcpAPICallTimeout.
 type Client interface {
 	// IAM
-	GetServiceAccount(accountName string) (*iam.ServiceAccount, error)
-	CreateServiceAccount(name, displayName string) (*iam.ServiceAccount, error)
-	DeleteServiceAccount(accountEmail string) error
-	CreateServiceAccountKey(serviceAccountEmail string) (*iam.ServiceAccountKey, error)
-	DeleteServiceAccountKeys(serviceAccountEmail string) error
+	GetServiceAccount(ctx context.Context, accountName string) (*iam.ServiceAccount, error)
+	CreateServiceAccount(ctx context.Context, name, displayName string) (*iam.ServiceAccount, error)
+	DeleteServiceAccount(ctx context.Context, accountEmail string) error
+	CreateServiceAccountKey(ctx context.Context, serviceAccountEmail string) (*iam.ServiceAccountKey, error)
+	DeleteServiceAccountKeys(ctx context.Context, serviceAccountEmail string) error
+	ListServiceAccountKeys(ctx context.Context, serviceAccountEmail string) ([]*iam.ServiceAccountKey, error)
+	DeleteServiceAccountKey(ctx context.Context, keyName string) error
+	GetServiceAccountIamPolicy(ctx context.Context, serviceAccountEmail string) (*iam.Policy, error)
+	SetServiceAccountIamPolicy(ctx context.Context, serviceAccountEmail string, policy *iam.Policy) (*iam.Policy, error)
+	GetWorkloadIdentityPool(ctx context.Context, projectID, poolID string) (*iam.WorkloadIdentityPool, error)
+	CreateWorkloadIdentityPool(ctx context.Context, projectID, poolID string, pool *iam.WorkloadIdentityPool) error
+	DeleteWorkloadIdentityPool(ctx context.Context, projectID, poolID string) error
+	UndeleteWorkloadIdentityPool(ctx context.Context, projectID, poolID string) error
+	GetWorkloadIdentityPoolProvider(ctx context.Context, projectID, poolID, providerID string) (*iam.WorkloadIdentityPoolProvider, error)
+	CreateWorkloadIdentityPoolProvider(ctx context.Context, projectID, poolID, providerID string, provider *iam.WorkloadIdentityPoolProvider) error
+	UpdateWorkloadIdentityPoolProvider(ctx context.Context, projectID, poolID, providerID string, provider *iam.WorkloadIdentityPoolProvider, updateMask string) error
 	// Cloudresourcemanager
-	GetIamPolicy(projectName string) (*cloudresourcemanager.Policy, error)
-	SetIamPolicy(setIamPolicyRequest *cloudresourcemanager.SetIamPolicyRequest) (*cloudresourcemanager.Policy, error)
-	ListProjects() ([]*cloudresourcemanager.Project, error)
-	CreateProject(parentFolder string, claimName string) (*cloudresourcemanager.Operation, error)
-	CreateProjectLabels(project *cloudresourcemanager.Project, labels map[string]string) error
-	DeleteProject(parentFolder string) (*cloudresourcemanager.Empty, error)
-	GetProject(projectID string) (*cloudresourcemanager.Project, error)
+	GetIamPolicy(ctx context.Context, projectName string) (*cloudresourcemanager.Policy, error)
+	SetIamPolicy(ctx context.Context, setIamPolicyRequest *cloudresourcemanager.SetIamPolicyRequest) (*cloudresourcemanager.Policy, error)
+	ListProjects(ctx context.Context, parentFolderID string) ([]*cloudresourcemanager.Project, error)
+	CreateProject(ctx context.Context, parentFolder string, displayName string, labels map[string]string) (*cloudresourcemanager.Operation, error)
+	UpdateProjectLabels(ctx context.Context, projectID string, labels map[string]string, remove []string) error
+	DeleteProject(ctx context.Context, parentFolder string) (*cloudresourcemanager.Empty, error)
+	UndeleteProject(ctx context.Context, projectID string) error
+	GetProject(ctx context.Context, projectID string) (*cloudresourcemanager.Project, error)
+	GetOperation(ctx context.Context, name string) (*cloudresourcemanager.Operation, error)
+	ListTagBindings(ctx context.Context, projectNumber int64) ([]string, error)
+	GetTagValue(ctx context.Context, namespacedName string) (*cloudresourcemanagerv3.TagValue, error)
+	CreateTagBinding(ctx context.Context, projectNumber int64, tagValue string) error
 	// ServiceManagement
-	EnableAPI(projectID, api string) error
-	ListAPIs(projectID string) ([]string, error)
+	EnableAPI(ctx context.Context, projectID, api string) error
+	ListAPIs(ctx context.Context, projectID string) ([]string, error)
 	// CloudBilling
-	CreateCloudBillingAccount(projectID, billingAccount string) error
+	CreateCloudBillingAccount(ctx context.Context, projectID, billingAccount string) error
+	GetBillingInfo(ctx context.Context, projectID string) (*cloudbilling.ProjectBillingInfo, error)
 	//Compute
-	ListAvailabilityZones(projectID, region string) ([]string, error)
+	ListAvailabilityZones(ctx context.Context, projectID, region string) ([]string, error)
+	// ForProject returns a client of projectName sharing the credentials and services of the client
+	ForProject(projectName string) Client
 }
 
 type gcpClient struct {
 	projectName                string
 	creds                      *google.Credentials
 	cloudResourceManagerClient *cloudresourcemanager.Service
-	iamClient                  *iam.Service
-	serviceUsageClient         *serviceusage.Service
-	cloudBillingClient         *cloudbilling.APIService
-	computeClient              *compute.Service
+	// tags are only served by version 3 of the Cloud Resource Manager API
+	cloudResourceManagerV3Client *cloudresourcemanagerv3.Service
+	iamClient                    *iam.Service
+	serviceUsageClient           *serviceusage.Service
+	cloudBillingClient           *cloudbilling.APIService
+	computeClient                *compute.Service
 	// Some actions requires new individual client to be
 	// initiated. we try to re-use clients, but we store
 	// credentials for these methods
 	credentials *google.Credentials
 }
 
+// Endpoints overrides the base URL of each Google API used by the client,
+// e.g. to point it at a local emulator. Empty fields keep the default Google endpoint.
+type Endpoints struct {
+	CloudResourceManager string
+	IAM                  string
+	ServiceUsage         string
+	CloudBilling         string
+	Compute              string
+}
+
+// Option customizes the client built by NewClient.
+type Option func(*options)
+
+type options struct {
+	endpoints   Endpoints
+	rateLimiter *RateLimiter
+}
+
+// WithEndpoints makes the client talk to endpoints instead of the Google APIs.
+func WithEndpoints(endpoints Endpoints) Option {
+	return func(o *options) {
+		o.endpoints = endpoints
+	}
+}
+
+// WithRateLimiter limits the requests of the client with limiter, which should be shared by all clients.
+// The clients built without it share a limiter enforcing DefaultRateLimits.
+func WithRateLimiter(limiter *RateLimiter) Option {
+	return func(o *options) {
+		o.rateLimiter = limiter
+	}
+}
+
+// serviceOptions returns the options to build a single service, reaching endpoint if set.
+func serviceOptions(httpClient *http.Client, endpoint string) []option.ClientOption {
+	opts := []option.ClientOption{option.WithHTTPClient(httpClient)}
+	if endpoint != "" {
+		opts = append(opts, option.WithEndpoint(endpoint))
+	}
+	return opts
+}
+
+// newHTTPClient returns the authenticated client of the requests to the API family,
+// which are rate limited by limiter and whose metrics are recorded.
+func newHTTPClient(ctx context.Context, creds *google.Credentials, limiter *RateLimiter, credential, family string) (*http.Client, error) {
+	base := limiter.transport(&instrumentedTransport{base: http.DefaultTransport}, credential, family)
+	transport, err := htransport.NewTransport(ctx, base, option.WithCredentials(creds))
+	if err != nil {
+		return nil, err
+	}
+	return &http.Client{Transport: transport}, nil
+}
+
+// CredentialsType returns the type of the JSON credentials authJSON, e.g. service_account for a key
+// or external_account for a workload identity federation credential config.
+func CredentialsType(authJSON []byte) (google.CredentialsType, error) {
+	var f struct {
+		Type string `json:"type"`
+	}
+	if err := json.Unmarshal(authJSON, &f); err != nil {
+		return "", err
+	}
+	return google.CredentialsType(f.Type), nil
+}
+
 // NewClient creates our client wrapper object for interacting with GCP.
-func NewClient(projectName string, authJSON []byte) (Client, error) {
-	ctx := context.TODO()
+// The credentials outlive any single reconcile, so they are not bound to a request context.
+func NewClient(projectName string, authJSON []byte, opts ...Option) (Client, error) {
+	ctx := context.Background()
+
+	o := &options{rateLimiter: defaultRateLimiter}
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	credType, err := CredentialsType(authJSON)
+	if err != nil {
+		return nil, fmt.Errorf("gcpclient.NewClient.CredentialsType %v", err)
+	}
+	if credType != google.ServiceAccount && credType != google.ExternalAccount {
+		return nil, fmt.Errorf("gcpclient.NewClient: unsupported credentials type %q, expected %q or %q", credType, google.ServiceAccount, google.ExternalAccount)
+	}
 
 	// since we're using a single creds var, we should specify all the required scopes when initializing
-	creds, err := google.CredentialsFromJSON(ctx, authJSON, "https://www.googleapis.com/auth/cloud-platform")
+	creds, err := google.CredentialsFromJSONWithType(ctx, authJSON, credType, "https://www.googleapis.com/auth/cloud-platform")
 	if err != nil {
-		return nil, fmt.Errorf("gcpclient.NewClient.google.CredentialsFromJSON %v", err)
+		return nil, fmt.Errorf("gcpclient.NewClient.google.CredentialsFromJSONWithType %v", err)
 	}
 
-	cloudResourceManagerClient, err := cloudresourcemanager.NewService(ctx, option.WithCredentials(creds))
+	// every API family has its own rate limit
+	credential := credentialID(authJSON)
+	httpClients := map[string]*http.Client{}
+	for _, family := range Families {
+		if httpClients[family], err = newHTTPClient(ctx, creds, o.rateLimiter, credential, family); err != nil {
+			return nil, fmt.Errorf("gcpclient.NewClient.htransport.NewTransport %v", err)
+		}
+	}
+
+	cloudResourceManagerClient, err := cloudresourcemanager.NewService(ctx, serviceOptions(httpClients[FamilyCloudResourceManager], o.endpoints.CloudResourceManager)...)
 	if err != nil {
 		return nil, fmt.Errorf("gcpclient.NewClient.cloudresourcemanager.NewService %v", err)
 	}
 
-	iamClient, err := iam.NewService(ctx, option.WithCredentials(creds))
+	cloudResourceManagerV3Client, err := cloudresourcemanagerv3.NewService(ctx, serviceOptions(httpClients[FamilyCloudResourceManager], o.endpoints.CloudResourceManager)...)
+	if err != nil {
+		return nil, fmt.Errorf("gcpclient.NewClient.cloudresourcemanagerv3.NewService %v", err)
+	}
+
+	iamClient, err := iam.NewService(ctx, serviceOptions(httpClients[FamilyIAM], o.endpoints.IAM)...)
 	if err != nil {
 		return nil, fmt.Errorf("gcpclient.iam.NewService %v", err)
 	}
 
-	serviceUsageClient, err := serviceusage.NewService(ctx, option.WithCredentials(creds))
+	serviceUsageClient, err := serviceusage.NewService(ctx, serviceOptions(httpClients[FamilyServiceUsage], o.endpoints.ServiceUsage)...)
 	if err != nil {
 		return nil, fmt.Errorf("gcpclient.serviceUsageClient.NewService %v", err)
 	}
 
-	cloudBillingClient, err := cloudbilling.NewService(ctx, option.WithCredentials(creds))
+	cloudBillingClient, err := cloudbilling.NewService(ctx, serviceOptions(httpClients[FamilyCloudBilling], o.endpoints.CloudBilling)...)
 	if err != nil {
 		return nil, fmt.Errorf("gcpclient.cloudBillingClient.NewService %v", err)
 	}
 
-	computeService, err := compute.NewService(ctx, option.WithCredentials(creds))
+	computeService, err := compute.NewService(ctx, serviceOptions(httpClients[FamilyCompute], o.endpoints.Compute)...)
 	if err != nil {
 		return nil, fmt.Errorf("gcpclient.compute.NewService %v", err)
 	}
 
 	return &gcpClient{
-		projectName:                projectName,
-		creds:                      creds,
-		cloudResourceManagerClient: cloudResourceManagerClient,
-		iamClient:                  iamClient,
-		serviceUsageClient:         serviceUsageClient,
-		cloudBillingClient:         cloudBillingClient,
-		computeClient:              computeService,
-		credentials:                creds,
+		projectName:                  projectName,
+		creds:                        creds,
+		cloudResourceManagerClient:   cloudResourceManagerClient,
+		cloudResourceManagerV3Client: cloudResourceManagerV3Client,
+		iamClient:                    iamClient,
+		serviceUsageClient:           serviceUsageClient,
+		cloudBillingClient:           cloudBillingClient,
+		computeClient:                computeService,
+		credentials:                  creds,
 	}, nil
 }
 
+// ForProject returns a copy of c scoped to projectName. Building the services and the OAuth token source is
+// the expensive part of NewClient, so the clients of every project using the same credentials share them.
+func (c *gcpClient) ForProject(projectName string) Client {
+	scoped := *c
+	scoped.projectName = projectName
+	return &scoped
+}
+
+// callContext derives the context of a single call of the Client method from ctx,
+// which is traced in a span until the returned function is called.
+func (c *gcpClient) callContext(ctx context.Context, method string) (context.Context, context.CancelFunc) {
+	ctx, span := tracing.Tracer().Start(ctx, "gcpclient."+method)
+	if c.projectName != "" {
+		span.SetAttributes(tracing.ProjectIDKey.String(c.projectName))
+	}
+	call := &call{method: method, span: span}
+	ctx, cancel := context.WithTimeout(withCall(ctx, call), gcpAPICallTimeout)
+	return ctx, func() {
+		cancel()
+		call.end()
+	}
+}
+
 // ListAvailabilityZones returns a map of all availability zones a project has access to
 // where the key is the region and the values is a list of zones
-func (c *gcpClient) ListAvailabilityZones(projectID, region string) ([]string, error) {
+func (c *gcpClient) ListAvailabilityZones(ctx context.Context, projectID, region string) ([]string, error) {
+	ctx, cancel := c.callContext(ctx, "ListAvailabilityZones")
+	defer cancel()
 
 	zones := []string{}
 	req := c.computeClient.Zones.List(projectID)
-	err := req.Pages(context.Background(), func(page *compute.ZoneList) error {
+	err := req.Pages(ctx, func(page *compute.ZoneList) error {
 		for _, zone := range page.Items {
 			if strings.Contains(zone.Region, region) {
 				zones = append(zones, zone.Name)
@@ -135,82 +287,193 @@ func (c *gcpClient) ListAvailabilityZones(projectID, region string) ([]string, e
 	return zones, nil
 }
 
-// ListProjects returns a list of all projects
-func (c *gcpClient) ListProjects() ([]*cloudresourcemanager.Project, error) {
-	resp, err := c.cloudResourceManagerClient.Projects.List().Do()
+// ListProjects returns all projects in parentFolderID, or all projects visible to the client if parentFolderID is empty.
+// It pages through every result, use GetProject to look up a single project.
+func (c *gcpClient) ListProjects(ctx context.Context, parentFolderID string) ([]*cloudresourcemanager.Project, error) {
+	ctx, cancel := c.callContext(ctx, "ListProjects")
+	defer cancel()
+
+	call := c.cloudResourceManagerClient.Projects.List()
+	if parentFolderID != "" {
+		call = call.Filter(fmt.Sprintf("parent.type:folder parent.id:%s", parentFolderID))
+	}
+
+	projects := []*cloudresourcemanager.Project{}
+	err := call.Pages(ctx, func(resp *cloudresourcemanager.ListProjectsResponse) error {
+		projects = append(projects, resp.Projects...)
+		return nil
+	})
 	if err != nil {
 		return []*cloudresourcemanager.Project{}, err
 	}
-	return resp.Projects, nil
+	return projects, nil
 }
 
 // GetProject returns a project
-func (c *gcpClient) GetProject(projectID string) (*cloudresourcemanager.Project, error) {
-	project, err := c.cloudResourceManagerClient.Projects.Get(projectID).Do()
+func (c *gcpClient) GetProject(ctx context.Context, projectID string) (*cloudresourcemanager.Project, error) {
+	ctx, cancel := c.callContext(ctx, "GetProject")
+	defer cancel()
+
+	project, err := c.cloudResourceManagerClient.Projects.Get(projectID).Context(ctx).Do()
 	if err != nil {
 		return nil, err
 	}
 	return project, nil
 }
 
-// CreateProjectLabels creates the claimName label on a project
-func (c *gcpClient) CreateProjectLabels(project *cloudresourcemanager.Project, labels map[string]string) error {
-	log.V(2).Info("Started gcpClient.CreateProjectLabels")
+// UpdateProjectLabels sets labels on the project projectID and removes the labels named in remove.
+// The other labels of the project are kept, the project isn't updated if its labels already match.
+func (c *gcpClient) UpdateProjectLabels(ctx context.Context, projectID string, labels map[string]string, remove []string) error {
+	log.V(2).Info("Started gcpClient.UpdateProjectLabels")
+	ctx, cancel := c.callContext(ctx, "UpdateProjectLabels")
+	defer cancel()
 
-	project.Labels = labels
-
-	_, err := c.cloudResourceManagerClient.Projects.Update(project.ProjectId, project).Do()
+	project, err := c.cloudResourceManagerClient.Projects.Get(projectID).Context(ctx).Do()
 	if err != nil {
-		return fmt.Errorf("gcpclient.CreateProject.Projects.Update %v", err)
+		return fmt.Errorf("gcpclient.UpdateProjectLabels.Projects.Get %w", err)
+	}
+	merged := maps.Clone(project.Labels)
+	if merged == nil {
+		merged = map[string]string{}
+	}
+	maps.Copy(merged, labels)
+	for _, key := range remove {
+		delete(merged, key)
+	}
+	if maps.Equal(merged, project.Labels) {
+		return nil
 	}
-	time.Sleep(3 * time.Second) //Wait 3 seconds to make it more probable the project is updated after returning
 
+	project.Labels = merged
+	if _, err := c.cloudResourceManagerClient.Projects.Update(projectID, project).Context(ctx).Do(); err != nil {
+		return fmt.Errorf("gcpclient.UpdateProjectLabels.Projects.Update %w", err)
+	}
 	return nil
 }
 
-// CreateProject creates a project in a given folder.
-func (c *gcpClient) CreateProject(parentFolderID string, claimName string) (*cloudresourcemanager.Operation, error) {
+// CreateProject starts the creation of a project in a given folder, named displayName or its ID if displayName is empty.
+// The project exists once the returned operation is done, see GetOperation.
+func (c *gcpClient) CreateProject(ctx context.Context, parentFolderID string, displayName string, labels map[string]string) (*cloudresourcemanager.Operation, error) {
 	log.V(2).Info("Started gcpClient.CreateProject")
+	ctx, cancel := c.callContext(ctx, "CreateProject")
+	defer cancel()
 
-	labelsMap := make(map[string]string)
-	labelsMap["claim_name"] = claimName
-
+	if displayName == "" {
+		displayName = c.projectName
+	}
 	project := cloudresourcemanager.Project{
-		Labels: labelsMap,
-		Name:   c.projectName,
+		Labels: labels,
+		Name:   displayName,
 		Parent: &cloudresourcemanager.ResourceId{
 			Id:   parentFolderID,
 			Type: "folder",
 		},
 		ProjectId: c.projectName,
 	}
-	operation, err := c.cloudResourceManagerClient.Projects.Create(&project).Do()
+	operation, err := c.cloudResourceManagerClient.Projects.Create(&project).Context(ctx).Do()
 	if err != nil {
-		ae, ok := err.(*googleapi.Error)
-		// google uses 409 for "already exists"
-		// we continue as it was created
-		if ok && ae.Code == http.StatusConflict {
-			return &cloudresourcemanager.Operation{}, nil
-		}
-		return &cloudresourcemanager.Operation{}, fmt.Errorf("gcpclient.CreateProject.Projects.Create %v", err)
+		return &cloudresourcemanager.Operation{}, fmt.Errorf("gcpclient.CreateProject.Projects.Create %w", err)
 	}
-	time.Sleep(3 * time.Second) //Wait 3 seconds to make it more probable the project is created after returning
 	return operation, nil
 }
 
+// GetOperation returns the long-running operation with name, e.g. the one returned by CreateProject
+func (c *gcpClient) GetOperation(ctx context.Context, name string) (*cloudresourcemanager.Operation, error) {
+	ctx, cancel := c.callContext(ctx, "GetOperation")
+	defer cancel()
+
+	operation, err := c.cloudResourceManagerClient.Operations.Get(name).Context(ctx).Do()
+	if err != nil {
+		return nil, fmt.Errorf("gcpclient.GetOperation.Operations.Get %w", err)
+	}
+	return operation, nil
+}
+
+// projectResourceName is the full resource name of the project projectNumber, which tags are bound to
+func projectResourceName(projectNumber int64) string {
+	return fmt.Sprintf("//cloudresourcemanager.googleapis.com/projects/%d", projectNumber)
+}
+
+// ListTagBindings returns the tag values bound directly to the project projectNumber, e.g. tagValues/456.
+// The tags the project inherits from its folder and organization aren't included.
+func (c *gcpClient) ListTagBindings(ctx context.Context, projectNumber int64) ([]string, error) {
+	ctx, cancel := c.callContext(ctx, "ListTagBindings")
+	defer cancel()
+
+	tagValues := []string{}
+	call := c.cloudResourceManagerV3Client.TagBindings.List().Parent(projectResourceName(projectNumber))
+	err := call.Pages(ctx, func(resp *cloudresourcemanagerv3.ListTagBindingsResponse) error {
+		for _, binding := range resp.TagBindings {
+			tagValues = append(tagValues, binding.TagValue)
+		}
+		return nil
+	})
+	if err != nil {
+		return []string{}, fmt.Errorf("gcpclient.ListTagBindings.TagBindings.List %w", err)
+	}
+	return tagValues, nil
+}
+
+// GetTagValue returns the tag value with namespacedName, e.g. 123456789012/environment/production
+func (c *gcpClient) GetTagValue(ctx context.Context, namespacedName string) (*cloudresourcemanagerv3.TagValue, error) {
+	ctx, cancel := c.callContext(ctx, "GetTagValue")
+	defer cancel()
+
+	tagValue, err := c.cloudResourceManagerV3Client.TagValues.GetNamespaced().Name(namespacedName).Context(ctx).Do()
+	if err != nil {
+		return nil, fmt.Errorf("gcpclient.GetTagValue.TagValues.GetNamespaced %w", err)
+	}
+	return tagValue, nil
+}
+
+// CreateTagBinding binds tagValue, e.g. tagValues/456, to the project projectNumber.
+// The binding is created asynchronously, and listed by ListTagBindings once it is done. Existing bindings are ignored.
+func (c *gcpClient) CreateTagBinding(ctx context.Context, projectNumber int64, tagValue string) error {
+	ctx, cancel := c.callContext(ctx, "CreateTagBinding")
+	defer cancel()
+
+	binding := &cloudresourcemanagerv3.TagBinding{
+		Parent:   projectResourceName(projectNumber),
+		TagValue: tagValue,
+	}
+	_, err := c.cloudResourceManagerV3Client.TagBindings.Create(binding).Context(ctx).Do()
+	if err != nil && !operrors.IsConflict(err) {
+		return fmt.Errorf("gcpclient.CreateTagBinding.TagBindings.Create %w", err)
+	}
+	return nil
+}
+
 // DeleteProject deletes a project from a given folder.
-func (c *gcpClient) DeleteProject(parentFolder string) (*cloudresourcemanager.Empty, error) {
-	empty, err := c.cloudResourceManagerClient.Projects.Delete(c.projectName).Do()
+func (c *gcpClient) DeleteProject(ctx context.Context, parentFolder string) (*cloudresourcemanager.Empty, error) {
+	ctx, cancel := c.callContext(ctx, "DeleteProject")
+	defer cancel()
+
+	empty, err := c.cloudResourceManagerClient.Projects.Delete(c.projectName).Context(ctx).Do()
 	if err != nil {
-		return &cloudresourcemanager.Empty{}, fmt.Errorf("gcpclient.DeleteProject.Projects.Delete %v", err)
+		return &cloudresourcemanager.Empty{}, fmt.Errorf("gcpclient.DeleteProject.Projects.Delete %w", err)
 	}
 	return empty, nil
 }
 
+// UndeleteProject restores a project pending deletion, which GCP keeps for 30 days before purging it
+func (c *gcpClient) UndeleteProject(ctx context.Context, projectID string) error {
+	ctx, cancel := c.callContext(ctx, "UndeleteProject")
+	defer cancel()
+
+	_, err := c.cloudResourceManagerClient.Projects.Undelete(projectID, &cloudresourcemanager.UndeleteProjectRequest{}).Context(ctx).Do()
+	if err != nil {
+		return fmt.Errorf("gcpclient.UndeleteProject.Projects.Undelete %w", err)
+	}
+	return nil
+}
+
 // GetServiceAccount returns a service account if it exists
-func (c *gcpClient) GetServiceAccount(accountName string) (*iam.ServiceAccount, error) {
+func (c *gcpClient) GetServiceAccount(ctx context.Context, accountName string) (*iam.ServiceAccount, error) {
+	ctx, cancel := c.callContext(ctx, "GetServiceAccount")
+	defer cancel()
+
 	resource := fmt.Sprintf("projects/%s/serviceAccounts/%s@%s.iam.gserviceaccount.com", c.projectName, accountName, c.projectName)
-	sa, err := c.iamClient.Projects.ServiceAccounts.Get(resource).Do()
+	sa, err := c.iamClient.Projects.ServiceAccounts.Get(resource).Context(ctx).Do()
 	if err != nil {
 		return &iam.ServiceAccount{}, err
 	}
@@ -219,7 +482,10 @@ func (c *gcpClient) GetServiceAccount(accountName string) (*iam.ServiceAccount,
 }
 
 // CreateServiceAccount creates a service account with required roles.
-func (c *gcpClient) CreateServiceAccount(name, displayName string) (*iam.ServiceAccount, error) {
+func (c *gcpClient) CreateServiceAccount(ctx context.Context, name, displayName string) (*iam.ServiceAccount, error) {
+	ctx, cancel := c.callContext(ctx, "CreateServiceAccount")
+	defer cancel()
+
 	CreateServiceAccountRequest := &iam.CreateServiceAccountRequest{
 		AccountId: name,
 		ServiceAccount: &iam.ServiceAccount{
@@ -227,7 +493,7 @@ func (c *gcpClient) CreateServiceAccount(name, displayName string) (*iam.Service
 		},
 	}
 
-	serviceAccount, err := c.iamClient.Projects.ServiceAccounts.Create(fmt.Sprintf("projects/%s", c.projectName), CreateServiceAccountRequest).Do()
+	serviceAccount, err := c.iamClient.Projects.ServiceAccounts.Create(fmt.Sprintf("projects/%s", c.projectName), CreateServiceAccountRequest).Context(ctx).Do()
 	if err != nil {
 		return &iam.ServiceAccount{}, err
 	}
@@ -235,27 +501,35 @@ func (c *gcpClient) CreateServiceAccount(name, displayName string) (*iam.Service
 	return serviceAccount, nil
 }
 
-func (c *gcpClient) DeleteServiceAccount(accountEmail string) error {
-	_, err := c.iamClient.Projects.ServiceAccounts.Delete(fmt.Sprintf("projects/%s/serviceAccounts/%s", c.projectName, accountEmail)).Do()
+func (c *gcpClient) DeleteServiceAccount(ctx context.Context, accountEmail string) error {
+	ctx, cancel := c.callContext(ctx, "DeleteServiceAccount")
+	defer cancel()
+
+	_, err := c.iamClient.Projects.ServiceAccounts.Delete(fmt.Sprintf("projects/%s/serviceAccounts/%s", c.projectName, accountEmail)).Context(ctx).Do()
 	if err != nil {
-		return fmt.Errorf("gcpclient.DeleteServiceAccount.Projects.ServiceAccounts.Delete: %v", err)
+		return fmt.Errorf("gcpclient.DeleteServiceAccount.Projects.ServiceAccounts.Delete: %w", err)
 	}
 
 	return nil
 }
 
-func (c *gcpClient) CreateServiceAccountKey(serviceAccountEmail string) (*iam.ServiceAccountKey, error) {
-	key, err := c.iamClient.Projects.ServiceAccounts.Keys.Create(fmt.Sprintf("projects/%s/serviceAccounts/%s", c.projectName, serviceAccountEmail), &iam.CreateServiceAccountKeyRequest{}).Do()
+func (c *gcpClient) CreateServiceAccountKey(ctx context.Context, serviceAccountEmail string) (*iam.ServiceAccountKey, error) {
+	ctx, cancel := c.callContext(ctx, "CreateServiceAccountKey")
+	defer cancel()
+
+	key, err := c.iamClient.Projects.ServiceAccounts.Keys.Create(fmt.Sprintf("projects/%s/serviceAccounts/%s", c.projectName, serviceAccountEmail), &iam.CreateServiceAccountKeyRequest{}).Context(ctx).Do()
 	if err != nil {
-		return &iam.ServiceAccountKey{}, fmt.Errorf("gcpclient.CreateServiceAccountKey.Projects.ServiceAccounts.Keys.Create: %v", err)
+		return &iam.ServiceAccountKey{}, fmt.Errorf("gcpclient.CreateServiceAccountKey.Projects.ServiceAccounts.Keys.Create: %w", err)
 	}
 
 	exp := backoff.NewExponentialBackOff()
 	for i := 0; i <= 3; i++ {
-		if _, err = c.iamClient.Projects.ServiceAccounts.Keys.Get(key.Name).Do(); err != nil {
+		if _, err = c.iamClient.Projects.ServiceAccounts.Keys.Get(key.Name).Context(ctx).Do(); err != nil {
 			duration := exp.NextBackOff()
 			log.V(2).Info("error getting the serviceaccount key, sleeping for %v", duration)
-			time.Sleep(duration)
+			if err := util.Sleep(ctx, duration); err != nil {
+				return key, err
+			}
 		} else {
 			return key, nil
 		}
@@ -264,11 +538,14 @@ func (c *gcpClient) CreateServiceAccountKey(serviceAccountEmail string) (*iam.Se
 }
 
 // DeleteServiceAccountKeys deletes all keys associated with the service account
-func (c *gcpClient) DeleteServiceAccountKeys(serviceAccountEmail string) error {
+func (c *gcpClient) DeleteServiceAccountKeys(ctx context.Context, serviceAccountEmail string) error {
+	ctx, cancel := c.callContext(ctx, "DeleteServiceAccountKeys")
+	defer cancel()
+
 	resource := fmt.Sprintf("projects/%s/serviceAccounts/%s", c.projectName, serviceAccountEmail)
-	response, err := c.iamClient.Projects.ServiceAccounts.Keys.List(resource).Do()
+	response, err := c.iamClient.Projects.ServiceAccounts.Keys.List(resource).Context(ctx).Do()
 	if err != nil {
-		return fmt.Errorf("gcpclient.DeleteServiceAccountKeys.Projects.ServiceAccounts.Keys.List: %v", err)
+		return fmt.Errorf("gcpclient.DeleteServiceAccountKeys.Projects.ServiceAccounts.Keys.List: %w", err)
 	}
 
 	if len(response.Keys) <= 1 {
@@ -276,13 +553,13 @@ func (c *gcpClient) DeleteServiceAccountKeys(serviceAccountEmail string) error {
 	}
 
 	for _, key := range response.Keys {
-		_, _ = c.iamClient.Projects.ServiceAccounts.Keys.Delete(key.Name).Do()
+		_, _ = c.iamClient.Projects.ServiceAccounts.Keys.Delete(key.Name).Context(ctx).Do()
 	}
 
 	// ensure only one key exits
-	newResponse, err := c.iamClient.Projects.ServiceAccounts.Keys.List(resource).Do()
+	newResponse, err := c.iamClient.Projects.ServiceAccounts.Keys.List(resource).Context(ctx).Do()
 	if err != nil {
-		return fmt.Errorf("gcpclient.DeleteServiceAccountKeys.Projects.ServiceAccounts.Keys.List: %v", err)
+		return fmt.Errorf("gcpclient.DeleteServiceAccountKeys.Projects.ServiceAccounts.Keys.List: %w", err)
 	}
 
 	if len(newResponse.Keys) > 1 {
@@ -292,39 +569,162 @@ func (c *gcpClient) DeleteServiceAccountKeys(serviceAccountEmail string) error {
 	return nil
 }
 
-func (c *gcpClient) GetIamPolicy(projectName string) (*cloudresourcemanager.Policy, error) {
-	policy, err := c.cloudResourceManagerClient.Projects.GetIamPolicy(projectName, &cloudresourcemanager.GetIamPolicyRequest{}).Do()
+// ListServiceAccountKeys returns the user managed keys of the service account
+func (c *gcpClient) ListServiceAccountKeys(ctx context.Context, serviceAccountEmail string) ([]*iam.ServiceAccountKey, error) {
+	ctx, cancel := c.callContext(ctx, "ListServiceAccountKeys")
+	defer cancel()
+
+	resource := fmt.Sprintf("projects/%s/serviceAccounts/%s", c.projectName, serviceAccountEmail)
+	response, err := c.iamClient.Projects.ServiceAccounts.Keys.List(resource).KeyTypes("USER_MANAGED").Context(ctx).Do()
 	if err != nil {
-		return nil, fmt.Errorf("gcpclient.GetIamPolicy.Projects.ServiceAccounts.GetIamPolicy %v", err)
+		return nil, fmt.Errorf("gcpclient.ListServiceAccountKeys.Projects.ServiceAccounts.Keys.List: %w", err)
+	}
+	return response.Keys, nil
+}
+
+// DeleteServiceAccountKey deletes the key keyName, e.g. projects/{project}/serviceAccounts/{email}/keys/{key}
+func (c *gcpClient) DeleteServiceAccountKey(ctx context.Context, keyName string) error {
+	ctx, cancel := c.callContext(ctx, "DeleteServiceAccountKey")
+	defer cancel()
+
+	_, err := c.iamClient.Projects.ServiceAccounts.Keys.Delete(keyName).Context(ctx).Do()
+	return err
+}
+
+// GetServiceAccountIamPolicy returns the IAM policy of the service account, which controls who can act as it
+func (c *gcpClient) GetServiceAccountIamPolicy(ctx context.Context, serviceAccountEmail string) (*iam.Policy, error) {
+	ctx, cancel := c.callContext(ctx, "GetServiceAccountIamPolicy")
+	defer cancel()
+
+	resource := fmt.Sprintf("projects/%s/serviceAccounts/%s", c.projectName, serviceAccountEmail)
+	return c.iamClient.Projects.ServiceAccounts.GetIamPolicy(resource).Context(ctx).Do()
+}
+
+// SetServiceAccountIamPolicy replaces the IAM policy of the service account
+func (c *gcpClient) SetServiceAccountIamPolicy(ctx context.Context, serviceAccountEmail string, policy *iam.Policy) (*iam.Policy, error) {
+	ctx, cancel := c.callContext(ctx, "SetServiceAccountIamPolicy")
+	defer cancel()
+
+	resource := fmt.Sprintf("projects/%s/serviceAccounts/%s", c.projectName, serviceAccountEmail)
+	return c.iamClient.Projects.ServiceAccounts.SetIamPolicy(resource, &iam.SetIamPolicyRequest{Policy: policy}).Context(ctx).Do()
+}
+
+func workloadIdentityPoolName(projectID, poolID string) string {
+	return fmt.Sprintf("projects/%s/locations/global/workloadIdentityPools/%s", projectID, poolID)
+}
+
+// GetWorkloadIdentityPool returns the workload identity pool poolID of the project
+func (c *gcpClient) GetWorkloadIdentityPool(ctx context.Context, projectID, poolID string) (*iam.WorkloadIdentityPool, error) {
+	ctx, cancel := c.callContext(ctx, "GetWorkloadIdentityPool")
+	defer cancel()
+
+	return c.iamClient.Projects.Locations.WorkloadIdentityPools.Get(workloadIdentityPoolName(projectID, poolID)).Context(ctx).Do()
+}
+
+// CreateWorkloadIdentityPool starts the creation of the workload identity pool poolID in the project.
+// The pool can be used once GetWorkloadIdentityPool returns it.
+func (c *gcpClient) CreateWorkloadIdentityPool(ctx context.Context, projectID, poolID string, pool *iam.WorkloadIdentityPool) error {
+	ctx, cancel := c.callContext(ctx, "CreateWorkloadIdentityPool")
+	defer cancel()
+
+	parent := fmt.Sprintf("projects/%s/locations/global", projectID)
+	_, err := c.iamClient.Projects.Locations.WorkloadIdentityPools.Create(parent, pool).WorkloadIdentityPoolId(poolID).Context(ctx).Do()
+	return err
+}
+
+// DeleteWorkloadIdentityPool deletes the workload identity pool poolID and its providers
+func (c *gcpClient) DeleteWorkloadIdentityPool(ctx context.Context, projectID, poolID string) error {
+	ctx, cancel := c.callContext(ctx, "DeleteWorkloadIdentityPool")
+	defer cancel()
+
+	_, err := c.iamClient.Projects.Locations.WorkloadIdentityPools.Delete(workloadIdentityPoolName(projectID, poolID)).Context(ctx).Do()
+	return err
+}
+
+// UndeleteWorkloadIdentityPool restores the deleted workload identity pool poolID, pool IDs can't be reused until they are purged
+func (c *gcpClient) UndeleteWorkloadIdentityPool(ctx context.Context, projectID, poolID string) error {
+	ctx, cancel := c.callContext(ctx, "UndeleteWorkloadIdentityPool")
+	defer cancel()
+
+	_, err := c.iamClient.Projects.Locations.WorkloadIdentityPools.Undelete(workloadIdentityPoolName(projectID, poolID), &iam.UndeleteWorkloadIdentityPoolRequest{}).Context(ctx).Do()
+	return err
+}
+
+// GetWorkloadIdentityPoolProvider returns the provider providerID of the workload identity pool poolID
+func (c *gcpClient) GetWorkloadIdentityPoolProvider(ctx context.Context, projectID, poolID, providerID string) (*iam.WorkloadIdentityPoolProvider, error) {
+	ctx, cancel := c.callContext(ctx, "GetWorkloadIdentityPoolProvider")
+	defer cancel()
+
+	name := fmt.Sprintf("%s/providers/%s", workloadIdentityPoolName(projectID, poolID), providerID)
+	return c.iamClient.Projects.Locations.WorkloadIdentityPools.Providers.Get(name).Context(ctx).Do()
+}
+
+// CreateWorkloadIdentityPoolProvider starts the creation of the provider providerID in the workload identity pool poolID.
+// The provider can be used once GetWorkloadIdentityPoolProvider returns it.
+func (c *gcpClient) CreateWorkloadIdentityPoolProvider(ctx context.Context, projectID, poolID, providerID string, provider *iam.WorkloadIdentityPoolProvider) error {
+	ctx, cancel := c.callContext(ctx, "CreateWorkloadIdentityPoolProvider")
+	defer cancel()
+
+	_, err := c.iamClient.Projects.Locations.WorkloadIdentityPools.Providers.Create(workloadIdentityPoolName(projectID, poolID), provider).
+		WorkloadIdentityPoolProviderId(providerID).Context(ctx).Do()
+	return err
+}
+
+// UpdateWorkloadIdentityPoolProvider updates the fields of the provider providerID listed in updateMask, e.g. "oidc,attributeCondition"
+func (c *gcpClient) UpdateWorkloadIdentityPoolProvider(ctx context.Context, projectID, poolID, providerID string, provider *iam.WorkloadIdentityPoolProvider, updateMask string) error {
+	ctx, cancel := c.callContext(ctx, "UpdateWorkloadIdentityPoolProvider")
+	defer cancel()
+
+	name := fmt.Sprintf("%s/providers/%s", workloadIdentityPoolName(projectID, poolID), providerID)
+	_, err := c.iamClient.Projects.Locations.WorkloadIdentityPools.Providers.Patch(name, provider).UpdateMask(updateMask).Context(ctx).Do()
+	return err
+}
+
+func (c *gcpClient) GetIamPolicy(ctx context.Context, projectName string) (*cloudresourcemanager.Policy, error) {
+	ctx, cancel := c.callContext(ctx, "GetIamPolicy")
+	defer cancel()
+
+	policy, err := c.cloudResourceManagerClient.Projects.GetIamPolicy(projectName, &cloudresourcemanager.GetIamPolicyRequest{}).Context(ctx).Do()
+	if err != nil {
+		return nil, fmt.Errorf("gcpclient.GetIamPolicy.Projects.ServiceAccounts.GetIamPolicy %w", err)
 	}
 
 	return policy, nil
 }
 
-func (c *gcpClient) SetIamPolicy(setIamPolicyRequest *cloudresourcemanager.SetIamPolicyRequest) (*cloudresourcemanager.Policy, error) {
-	policy, err := c.cloudResourceManagerClient.Projects.SetIamPolicy(c.projectName, setIamPolicyRequest).Do()
+func (c *gcpClient) SetIamPolicy(ctx context.Context, setIamPolicyRequest *cloudresourcemanager.SetIamPolicyRequest) (*cloudresourcemanager.Policy, error) {
+	ctx, cancel := c.callContext(ctx, "SetIamPolicy")
+	defer cancel()
+
+	policy, err := c.cloudResourceManagerClient.Projects.SetIamPolicy(c.projectName, setIamPolicyRequest).Context(ctx).Do()
 	if err != nil {
 		return &cloudresourcemanager.Policy{}, err
 	}
 	return policy, nil
 }
 
-func (c *gcpClient) ListAPIs(projectID string) ([]string, error) {
+func (c *gcpClient) ListAPIs(ctx context.Context, projectID string) ([]string, error) {
+	ctx, cancel := c.callContext(ctx, "ListAPIs")
+	defer cancel()
+
 	enabledAPIs := []string{}
-	response, err := c.serviceUsageClient.Services.List(fmt.Sprintf("projects/%s", projectID)).Do()
-	if err != nil {
-		return enabledAPIs, err
-	}
-	for _, svc := range response.Services {
-		if svc.State == "ENABLED" {
+	call := c.serviceUsageClient.Services.List(fmt.Sprintf("projects/%s", projectID)).Filter("state:ENABLED")
+	err := call.Pages(ctx, func(resp *serviceusage.ListServicesResponse) error {
+		for _, svc := range resp.Services {
 			enabledAPIs = append(enabledAPIs, svc.Config.Name)
 		}
+		return nil
+	})
+	if err != nil {
+		return []string{}, err
 	}
-	return enabledAPIs, err
+	return enabledAPIs, nil
 }
 
-func (c *gcpClient) EnableAPI(projectID, api string) error {
+func (c *gcpClient) EnableAPI(ctx context.Context, projectID, api string) error {
 	log.V(1).Info(fmt.Sprintf("enable %s api", api))
+	ctx, cancel := c.callContext(ctx, "EnableAPI")
+	defer cancel()
 
 	fullAPIName := fmt.Sprintf("projects/%s/services/%s", projectID, api)
 	req := c.serviceUsageClient.Services.Enable(fullAPIName, &serviceusage.EnableServiceRequest{})
@@ -332,18 +732,20 @@ func (c *gcpClient) EnableAPI(projectID, api string) error {
 	var retry int
 	for {
 		retry++
-		time.Sleep(time.Second)
+		if err := util.Sleep(ctx, time.Second); err != nil {
+			return err
+		}
 
-		_, err := req.Do()
+		_, err := req.Context(ctx).Do()
 		if err != nil {
-			ae, ok := err.(*googleapi.Error)
 			// Retry rules below:
 
 			// sometimes we get 403 - Permission denied when even project
 			// creation is completed and marked as Done.
 			// Something is not propagating in the backend.
-			if ok && ae.Code == http.StatusForbidden && retry <= gcpAPIRetriesCount {
+			if operrors.IsPermissionDenied(err) && retry <= gcpAPIRetriesCount {
 				log.V(2).Info(fmt.Sprintf("retry %d for enable %s api", retry, api))
+				metrics.EnableAPIRetries.WithLabelValues(api).Inc()
 				continue
 			}
 			return err
@@ -354,10 +756,13 @@ func (c *gcpClient) EnableAPI(projectID, api string) error {
 
 // CreateCloudBillingAccount associates cloud billing account with project
 // TODO: This needs unit testing. Sensitive place
-func (c *gcpClient) CreateCloudBillingAccount(projectID, billingAccountID string) error {
+func (c *gcpClient) CreateCloudBillingAccount(ctx context.Context, projectID, billingAccountID string) error {
+	ctx, cancel := c.callContext(ctx, "CreateCloudBillingAccount")
+	defer cancel()
+
 	project := fmt.Sprintf("projects/%s", projectID)
 	billingAccount := fmt.Sprintf("billingAccounts/%s", strings.TrimSuffix(billingAccountID, "\n"))
-	info, err := c.cloudBillingClient.Projects.GetBillingInfo(project).Do()
+	info, err := c.cloudBillingClient.Projects.GetBillingInfo(project).Context(ctx).Do()
 	if err != nil {
 		return err
 	}
@@ -367,7 +772,7 @@ func (c *gcpClient) CreateCloudBillingAccount(projectID, billingAccountID string
 		info.BillingAccountName = billingAccount
 		info.BillingEnabled = true
 		log.V(1).Info("Linking Cloud Billing Account")
-		_, err := c.cloudBillingClient.Projects.UpdateBillingInfo(project, info).Do()
+		_, err := c.cloudBillingClient.Projects.UpdateBillingInfo(project, info).Context(ctx).Do()
 		if err != nil {
 			return err
 		}
@@ -380,12 +785,12 @@ func (c *gcpClient) CreateCloudBillingAccount(projectID, billingAccountID string
 			BillingAccountName: "",
 			BillingEnabled:     false,
 		}
-		_, err := c.cloudBillingClient.Projects.UpdateBillingInfo(project, projectBillingDisable).Do()
+		_, err := c.cloudBillingClient.Projects.UpdateBillingInfo(project, projectBillingDisable).Context(ctx).Do()
 		if err != nil {
 			return err
 		}
 		log.V(2).Info("Relinking part")
-		_, err = c.cloudBillingClient.Projects.UpdateBillingInfo(project, info).Do()
+		_, err = c.cloudBillingClient.Projects.UpdateBillingInfo(project, info).Context(ctx).Do()
 		if err != nil {
 			return err
 		}
@@ -393,3 +798,11 @@ func (c *gcpClient) CreateCloudBillingAccount(projectID, billingAccountID string
 
 	return nil
 }
+
+// GetBillingInfo returns the billing information of projectID
+func (c *gcpClient) GetBillingInfo(ctx context.Context, projectID string) (*cloudbilling.ProjectBillingInfo, error) {
+	ctx, cancel := c.callContext(ctx, "GetBillingInfo")
+	defer cancel()
+
+	return c.cloudBillingClient.Projects.GetBillingInfo(fmt.Sprintf("projects/%s", projectID)).Context(ctx).Do()
+}
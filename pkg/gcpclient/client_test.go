@@ -0,0 +1,461 @@
+package gcpclient_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace/noop"
+	"golang.org/x/oauth2/google"
+	"google.golang.org/api/googleapi"
+
+	"github.com/openshift/gcp-project-operator/pkg/gcpclient"
+	"github.com/openshift/gcp-project-operator/pkg/gcpclient/fake"
+	"github.com/openshift/gcp-project-operator/pkg/metrics"
+	"github.com/openshift/gcp-project-operator/pkg/tracing"
+	operrors "github.com/openshift/gcp-project-operator/pkg/util/errors"
+
+	cloudresourcemanager "google.golang.org/api/cloudresourcemanager/v1"
+	iam "google.golang.org/api/iam/v1"
+)
+
+const testProjectID = "o-12345678"
+
+func newEmulatedClient(t *testing.T, projectName string) (gcpclient.Client, *fake.Backend) {
+	t.Helper()
+	backend := fake.NewBackend()
+	emulator, err := fake.NewEmulator(backend)
+	require.NoError(t, err)
+	t.Cleanup(emulator.Close)
+
+	client, err := gcpclient.NewClient(projectName, emulator.Credentials(), gcpclient.WithEndpoints(emulator.Endpoints()))
+	require.NoError(t, err)
+	return client, backend
+}
+
+func TestCreateProject(t *testing.T) {
+	client, backend := newEmulatedClient(t, testProjectID)
+
+	operation, err := client.CreateProject(context.TODO(), "folder", "Claim Project", map[string]string{"claim_name": "claim", "orphaned": "true"})
+	require.NoError(t, err)
+	operation, err = client.GetOperation(context.TODO(), operation.Name)
+	assert.NoError(t, err)
+	assert.True(t, operation.Done)
+	project, ok := backend.Project(testProjectID)
+	assert.True(t, ok)
+	assert.Equal(t, map[string]string{"claim_name": "claim", "orphaned": "true"}, project.Labels)
+	assert.Equal(t, "Claim Project", project.Name)
+
+	// google uses 409 for "already exists"
+	_, err = client.CreateProject(context.TODO(), "folder", "", nil)
+	assertErrorCode(t, http.StatusConflict, err)
+
+	// the labels are merged into the labels of the project
+	assert.NoError(t, client.UpdateProjectLabels(context.TODO(), testProjectID, map[string]string{"claim_name": "other", "team": "sre"}, []string{"orphaned"}))
+	project, err = client.GetProject(context.TODO(), testProjectID)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{"claim_name": "other", "team": "sre"}, project.Labels)
+	assert.NoError(t, client.UpdateProjectLabels(context.TODO(), testProjectID, map[string]string{"team": "sre"}, []string{"unknown"}))
+	assertErrorCode(t, http.StatusForbidden, client.UpdateProjectLabels(context.TODO(), "o-87654321", map[string]string{"team": "sre"}, nil))
+
+	projects, err := client.ListProjects(context.TODO(), "folder")
+	assert.NoError(t, err)
+	assert.Len(t, projects, 1)
+
+	_, err = client.DeleteProject(context.TODO(), "folder")
+	assert.NoError(t, err)
+	project, _ = backend.Project(testProjectID)
+	assert.Equal(t, fake.LifecycleStateDeleteRequested, project.LifecycleState)
+
+	assert.NoError(t, client.UndeleteProject(context.TODO(), testProjectID))
+	project, _ = backend.Project(testProjectID)
+	assert.Equal(t, fake.LifecycleStateActive, project.LifecycleState)
+	assertErrorCode(t, http.StatusBadRequest, client.UndeleteProject(context.TODO(), testProjectID))
+}
+
+func TestTagBindings(t *testing.T) {
+	client, backend := newEmulatedClient(t, testProjectID)
+	backend.AddProject(testProjectID, "folder", nil)
+	production := backend.AddTagValue("123456789012/environment/production")
+	project, err := client.GetProject(context.TODO(), testProjectID)
+	require.NoError(t, err)
+
+	tagValue, err := client.GetTagValue(context.TODO(), "123456789012/environment/production")
+	require.NoError(t, err)
+	assert.Equal(t, production, tagValue.Name)
+	assert.Equal(t, "production", tagValue.ShortName)
+	_, err = client.GetTagValue(context.TODO(), "123456789012/environment/staging")
+	assertErrorCode(t, http.StatusForbidden, err)
+
+	bindings, err := client.ListTagBindings(context.TODO(), project.ProjectNumber)
+	assert.NoError(t, err)
+	assert.Empty(t, bindings)
+	assert.NoError(t, client.CreateTagBinding(context.TODO(), project.ProjectNumber, production))
+	// binding a tag value again isn't an error
+	assert.NoError(t, client.CreateTagBinding(context.TODO(), project.ProjectNumber, production))
+	bindings, err = client.ListTagBindings(context.TODO(), project.ProjectNumber)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{production}, bindings)
+	assert.Equal(t, []string{production}, backend.TagBindings(testProjectID))
+
+	assertErrorCode(t, http.StatusForbidden, client.CreateTagBinding(context.TODO(), project.ProjectNumber, "tagValues/unknown"))
+}
+
+func TestListProjects(t *testing.T) {
+	client, backend := newEmulatedClient(t, testProjectID)
+	// more than two pages of projects
+	for i := 0; i < 1200; i++ {
+		backend.AddProject(fmt.Sprintf("o-%08d", i), "folder", nil)
+	}
+	backend.AddProject("ccs-project", "other-folder", nil)
+
+	projects, err := client.ListProjects(context.TODO(), "folder")
+	assert.NoError(t, err)
+	assert.Len(t, projects, 1200)
+	assert.Equal(t, "o-00001199", projects[1199].ProjectId)
+
+	projects, err = client.ListProjects(context.TODO(), "other-folder")
+	assert.NoError(t, err)
+	assert.Len(t, projects, 1)
+
+	projects, err = client.ListProjects(context.TODO(), "")
+	assert.NoError(t, err)
+	assert.Len(t, projects, 1201)
+}
+
+func TestEnableAPI(t *testing.T) {
+	tests := []struct {
+		name            string
+		injectedCode    int
+		injectedTimes   int
+		expectedCode    int
+		expectedRetries float64
+	}{
+		{
+			name:            "retries 403 until the project has propagated",
+			injectedCode:    http.StatusForbidden,
+			injectedTimes:   2,
+			expectedRetries: 2,
+		},
+		{
+			name:          "doesn't retry other errors",
+			injectedCode:  http.StatusBadRequest,
+			injectedTimes: 1,
+			expectedCode:  http.StatusBadRequest,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			client, backend := newEmulatedClient(t, testProjectID)
+			backend.AddProject(testProjectID, "folder", nil)
+			backend.InjectError("serviceusage.services.enable", test.injectedCode, test.injectedTimes)
+			retries := metrics.EnableAPIRetries.WithLabelValues("compute.googleapis.com")
+			retriesBefore := testutil.ToFloat64(retries)
+
+			err := client.EnableAPI(context.TODO(), testProjectID, "compute.googleapis.com")
+			assert.Equal(t, test.expectedRetries, testutil.ToFloat64(retries)-retriesBefore)
+			if test.expectedCode != 0 {
+				assertErrorCode(t, test.expectedCode, err)
+				return
+			}
+			assert.NoError(t, err)
+			apis, err := client.ListAPIs(context.TODO(), testProjectID)
+			assert.NoError(t, err)
+			assert.Equal(t, []string{"compute.googleapis.com"}, apis)
+		})
+	}
+}
+
+func TestListAPIs(t *testing.T) {
+	client, backend := newEmulatedClient(t, testProjectID)
+	backend.AddProject(testProjectID, "folder", nil)
+	// more than two pages of services
+	for i := 0; i < 120; i++ {
+		backend.EnableService(testProjectID, fmt.Sprintf("api-%03d.googleapis.com", i))
+	}
+	backend.DisableService(testProjectID, "api-042.googleapis.com")
+
+	apis, err := client.ListAPIs(context.TODO(), testProjectID)
+	assert.NoError(t, err)
+	assert.Len(t, apis, 119)
+	assert.Equal(t, "api-119.googleapis.com", apis[118])
+	assert.NotContains(t, apis, "api-042.googleapis.com")
+}
+
+func TestQuotaExceededRetries(t *testing.T) {
+	backend := fake.NewBackend()
+	emulator, err := fake.NewEmulator(backend)
+	require.NoError(t, err)
+	t.Cleanup(emulator.Close)
+	limiter := gcpclient.NewRateLimiter(gcpclient.RateLimits{MaxRetries: 2, BaseDelay: time.Millisecond, MaxDelay: time.Millisecond})
+	client, err := gcpclient.NewClient(testProjectID, emulator.Credentials(), gcpclient.WithEndpoints(emulator.Endpoints()), gcpclient.WithRateLimiter(limiter))
+	require.NoError(t, err)
+	backend.AddProject(testProjectID, "folder", nil)
+	throttled := metrics.GCPAPIRequests.WithLabelValues("GetProject", "429")
+	throttledBefore := testutil.ToFloat64(throttled)
+
+	backend.InjectError("cloudresourcemanager.projects.get", http.StatusTooManyRequests, 2)
+	project, err := client.GetProject(context.TODO(), testProjectID)
+	assert.NoError(t, err)
+	assert.Equal(t, testProjectID, project.ProjectId)
+	assert.Equal(t, float64(2), testutil.ToFloat64(throttled)-throttledBefore)
+
+	backend.InjectError("cloudresourcemanager.projects.get", http.StatusTooManyRequests, 3)
+	_, err = client.GetProject(context.TODO(), testProjectID)
+	assertErrorCode(t, http.StatusTooManyRequests, err)
+}
+
+func TestRequestMetrics(t *testing.T) {
+	client, backend := newEmulatedClient(t, testProjectID)
+	backend.AddProject(testProjectID, "folder", nil)
+	found := metrics.GCPAPIRequests.WithLabelValues("GetProject", "200")
+	// like GCP, the emulator denies access to missing projects
+	denied := metrics.GCPAPIRequests.WithLabelValues("GetProject", "403")
+	foundBefore, deniedBefore := testutil.ToFloat64(found), testutil.ToFloat64(denied)
+
+	_, err := client.GetProject(context.TODO(), testProjectID)
+	assert.NoError(t, err)
+	_, err = client.GetProject(context.TODO(), "missing-project")
+	assertErrorCode(t, http.StatusForbidden, err)
+
+	assert.Equal(t, float64(1), testutil.ToFloat64(found)-foundBefore)
+	assert.Equal(t, float64(1), testutil.ToFloat64(denied)-deniedBefore)
+	assert.NotZero(t, testutil.CollectAndCount(metrics.GCPAPIRequestDuration, "gcp_project_operator_gcp_api_request_duration_seconds"))
+}
+
+func TestTracing(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	t.Cleanup(func() { otel.SetTracerProvider(noop.NewTracerProvider()) })
+
+	client, backend := newEmulatedClient(t, testProjectID)
+	backend.AddProject(testProjectID, "folder", nil)
+	backend.InjectError("serviceusage.services.enable", http.StatusForbidden, 1)
+
+	_, err := client.GetProject(context.TODO(), "missing-project")
+	assertErrorCode(t, http.StatusForbidden, err)
+	assert.NoError(t, client.EnableAPI(context.TODO(), testProjectID, "compute.googleapis.com"))
+
+	var spans []sdktrace.ReadOnlySpan
+	for _, span := range recorder.Ended() {
+		if strings.HasPrefix(span.Name(), "gcpclient.") {
+			spans = append(spans, span)
+		}
+	}
+	require.Len(t, spans, 2)
+
+	assert.Equal(t, "gcpclient.GetProject", spans[0].Name())
+	assert.Contains(t, spans[0].Attributes(), tracing.ProjectIDKey.String(testProjectID))
+	assert.Contains(t, spans[0].Attributes(), tracing.StatusCodeKey.Int(http.StatusForbidden))
+	assert.Equal(t, codes.Error, spans[0].Status().Code)
+
+	// the retry after the injected 403 succeeded
+	assert.Equal(t, "gcpclient.EnableAPI", spans[1].Name())
+	assert.Contains(t, spans[1].Attributes(), tracing.StatusCodeKey.Int(http.StatusOK))
+	assert.Equal(t, codes.Unset, spans[1].Status().Code)
+}
+
+func TestCancelledContext(t *testing.T) {
+	client, backend := newEmulatedClient(t, testProjectID)
+	backend.AddProject(testProjectID, "folder", nil)
+	backend.InjectError("serviceusage.services.enable", http.StatusForbidden, 1)
+
+	ctx, cancel := context.WithTimeout(context.TODO(), 1500*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	err := client.EnableAPI(ctx, testProjectID, "compute.googleapis.com")
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Less(t, time.Since(start), 2*time.Second)
+	assert.Empty(t, backend.EnabledServices(testProjectID))
+}
+
+func TestCreateCloudBillingAccount(t *testing.T) {
+	client, backend := newEmulatedClient(t, testProjectID)
+	backend.AddProject(testProjectID, "folder", nil)
+	backend.EnableService(testProjectID, "cloudbilling.googleapis.com")
+
+	assert.NoError(t, client.CreateCloudBillingAccount(context.TODO(), testProjectID, "ABCDEF-123456\n"))
+	info, _ := backend.BillingInfo(testProjectID)
+	assert.Equal(t, "billingAccounts/ABCDEF-123456", info.BillingAccountName)
+
+	assert.NoError(t, client.CreateCloudBillingAccount(context.TODO(), testProjectID, "GHIJKL-789012"))
+	info, err := client.GetBillingInfo(context.TODO(), testProjectID)
+	assert.NoError(t, err)
+	assert.Equal(t, "billingAccounts/GHIJKL-789012", info.BillingAccountName)
+	assert.True(t, info.BillingEnabled)
+}
+
+func TestListAvailabilityZones(t *testing.T) {
+	client, backend := newEmulatedClient(t, testProjectID)
+	backend.AddProject(testProjectID, "folder", nil)
+
+	_, err := client.ListAvailabilityZones(context.TODO(), testProjectID, "us-east1")
+	assertErrorCode(t, http.StatusForbidden, err)
+	service, disabled := operrors.IsServiceDisabled(err)
+	assert.True(t, disabled)
+	assert.Equal(t, "compute.googleapis.com", service)
+
+	backend.EnableService(testProjectID, "compute.googleapis.com")
+	zones, err := client.ListAvailabilityZones(context.TODO(), testProjectID, "us-east1")
+	assert.NoError(t, err)
+	assert.Equal(t, fake.DefaultZones["us-east1"], zones)
+}
+
+func TestServiceAccounts(t *testing.T) {
+	client, backend := newEmulatedClient(t, testProjectID)
+	backend.AddProject(testProjectID, "folder", nil)
+
+	_, err := client.GetServiceAccount(context.TODO(), "osd-managed-admin")
+	assertErrorCode(t, http.StatusNotFound, err)
+
+	sa, err := client.CreateServiceAccount(context.TODO(), "osd-managed-admin", "osd-managed-admin")
+	require.NoError(t, err)
+	_, err = client.GetServiceAccount(context.TODO(), "osd-managed-admin")
+	assert.NoError(t, err)
+
+	keyNames := []string{}
+	for i := 0; i < 3; i++ {
+		key, err := client.CreateServiceAccountKey(context.TODO(), sa.Email)
+		require.NoError(t, err)
+		assert.NotEmpty(t, key.PrivateKeyData)
+		keyNames = append(keyNames, key.Name)
+	}
+	assert.NoError(t, client.DeleteServiceAccountKey(context.TODO(), keyNames[0]))
+	assertErrorCode(t, http.StatusNotFound, client.DeleteServiceAccountKey(context.TODO(), keyNames[0]))
+	keys, err := client.ListServiceAccountKeys(context.TODO(), sa.Email)
+	require.NoError(t, err)
+	listed := []string{}
+	for _, key := range keys {
+		assert.Empty(t, key.PrivateKeyData)
+		listed = append(listed, key.Name)
+	}
+	assert.ElementsMatch(t, keyNames[1:], listed)
+
+	assert.NoError(t, client.DeleteServiceAccountKeys(context.TODO(), sa.Email))
+	assert.Empty(t, backend.ServiceAccountKeys(testProjectID, sa.Email))
+
+	assert.NoError(t, client.DeleteServiceAccount(context.TODO(), sa.Email))
+	assert.Empty(t, backend.ServiceAccounts(testProjectID))
+
+	err = client.DeleteServiceAccount(context.TODO(), sa.Email)
+	assertErrorCode(t, http.StatusNotFound, err)
+	assert.True(t, operrors.IsNotFound(err))
+}
+
+func TestIamPolicy(t *testing.T) {
+	client, backend := newEmulatedClient(t, testProjectID)
+	backend.AddProject(testProjectID, "folder", nil)
+
+	policy, err := client.GetIamPolicy(context.TODO(), testProjectID)
+	require.NoError(t, err)
+	policy.Bindings = append(policy.Bindings, &cloudresourcemanager.Binding{Role: "roles/viewer", Members: []string{"group:sre@example.com"}})
+	_, err = client.SetIamPolicy(context.TODO(), &cloudresourcemanager.SetIamPolicyRequest{Policy: policy})
+	assert.NoError(t, err)
+
+	// the etag of policy is stale now
+	_, err = client.SetIamPolicy(context.TODO(), &cloudresourcemanager.SetIamPolicyRequest{Policy: policy})
+	assertErrorCode(t, http.StatusConflict, err)
+
+	stored, _ := backend.Policy(testProjectID)
+	assert.Len(t, stored.Bindings, 1)
+}
+
+func TestWorkloadIdentityPools(t *testing.T) {
+	client, backend := newEmulatedClient(t, testProjectID)
+	backend.AddProject(testProjectID, "folder", nil)
+
+	_, err := client.GetWorkloadIdentityPool(context.TODO(), testProjectID, "pool")
+	assertErrorCode(t, http.StatusNotFound, err)
+	require.NoError(t, client.CreateWorkloadIdentityPool(context.TODO(), testProjectID, "pool", &iam.WorkloadIdentityPool{DisplayName: "pool"}))
+	assertErrorCode(t, http.StatusConflict, client.CreateWorkloadIdentityPool(context.TODO(), testProjectID, "pool", &iam.WorkloadIdentityPool{}))
+	pool, err := client.GetWorkloadIdentityPool(context.TODO(), testProjectID, "pool")
+	require.NoError(t, err)
+	assert.Equal(t, "ACTIVE", pool.State)
+
+	provider := &iam.WorkloadIdentityPoolProvider{
+		AttributeMapping: map[string]string{"google.subject": "assertion.sub"},
+		Oidc:             &iam.Oidc{IssuerUri: "https://issuer.example.com"},
+	}
+	require.NoError(t, client.CreateWorkloadIdentityPoolProvider(context.TODO(), testProjectID, "pool", "oidc", provider))
+	provider.Oidc = &iam.Oidc{IssuerUri: "https://other.example.com", AllowedAudiences: []string{"openshift"}}
+	require.NoError(t, client.UpdateWorkloadIdentityPoolProvider(context.TODO(), testProjectID, "pool", "oidc", provider, "oidc"))
+	provider, err = client.GetWorkloadIdentityPoolProvider(context.TODO(), testProjectID, "pool", "oidc")
+	require.NoError(t, err)
+	assert.Equal(t, "https://other.example.com", provider.Oidc.IssuerUri)
+	assert.Equal(t, []string{"openshift"}, provider.Oidc.AllowedAudiences)
+	assert.Equal(t, "assertion.sub", provider.AttributeMapping["google.subject"])
+
+	require.NoError(t, client.DeleteWorkloadIdentityPool(context.TODO(), testProjectID, "pool"))
+	pool, _ = backend.WorkloadIdentityPool(testProjectID, "pool")
+	assert.Equal(t, "DELETED", pool.State)
+	// deleted pool IDs aren't available until they are purged
+	assertErrorCode(t, http.StatusConflict, client.CreateWorkloadIdentityPool(context.TODO(), testProjectID, "pool", &iam.WorkloadIdentityPool{}))
+	require.NoError(t, client.UndeleteWorkloadIdentityPool(context.TODO(), testProjectID, "pool"))
+	pool, _ = backend.WorkloadIdentityPool(testProjectID, "pool")
+	assert.Equal(t, "ACTIVE", pool.State)
+}
+
+func TestServiceAccountIamPolicy(t *testing.T) {
+	client, backend := newEmulatedClient(t, testProjectID)
+	backend.AddProject(testProjectID, "folder", nil)
+	sa, err := client.CreateServiceAccount(context.TODO(), "osd-managed-admin", "osd-managed-admin")
+	require.NoError(t, err)
+
+	policy, err := client.GetServiceAccountIamPolicy(context.TODO(), sa.Email)
+	require.NoError(t, err)
+	policy.Bindings = append(policy.Bindings, &iam.Binding{Role: "roles/iam.workloadIdentityUser", Members: []string{"principalSet://iam.googleapis.com/pool/*"}})
+	_, err = client.SetServiceAccountIamPolicy(context.TODO(), sa.Email, policy)
+	require.NoError(t, err)
+	// the etag of the policy is stale now
+	_, err = client.SetServiceAccountIamPolicy(context.TODO(), sa.Email, policy)
+	assertErrorCode(t, http.StatusConflict, err)
+
+	stored, _ := backend.ServiceAccountPolicy(testProjectID, sa.Email)
+	assert.Len(t, stored.Bindings, 1)
+}
+
+func TestExternalAccountCredentials(t *testing.T) {
+	backend := fake.NewBackend()
+	backend.AddProject(testProjectID, "folder", nil)
+	emulator, err := fake.NewEmulator(backend)
+	require.NoError(t, err)
+	t.Cleanup(emulator.Close)
+	sa, err := backend.NewClient(testProjectID).CreateServiceAccount(context.TODO(), "operator", "operator")
+	require.NoError(t, err)
+
+	tokenFile := filepath.Join(t.TempDir(), "token")
+	require.NoError(t, os.WriteFile(tokenFile, []byte("oidc-token"), 0600))
+	credentials := emulator.ExternalAccountCredentials(tokenFile, sa.Email)
+	credType, err := gcpclient.CredentialsType(credentials)
+	require.NoError(t, err)
+	assert.Equal(t, google.ExternalAccount, credType)
+
+	client, err := gcpclient.NewClient(testProjectID, credentials, gcpclient.WithEndpoints(emulator.Endpoints()))
+	require.NoError(t, err)
+	_, err = client.GetProject(context.TODO(), testProjectID)
+	assert.NoError(t, err)
+
+	_, err = gcpclient.NewClient(testProjectID, []byte(`{"type":"authorized_user"}`))
+	assert.ErrorContains(t, err, `unsupported credentials type "authorized_user"`)
+}
+
+func assertErrorCode(t *testing.T, code int, err error) {
+	t.Helper()
+	var ae *googleapi.Error
+	if assert.True(t, errors.As(err, &ae), "expected a googleapi.Error, got %v", err) {
+		assert.Equal(t, code, ae.Code)
+	}
+}
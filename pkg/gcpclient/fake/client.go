@@ -0,0 +1,556 @@
+package fake
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"google.golang.org/api/googleapi"
+
+	"github.com/openshift/gcp-project-operator/pkg/gcpclient"
+
+	cloudbilling "google.golang.org/api/cloudbilling/v1"
+	cloudresourcemanager "google.golang.org/api/cloudresourcemanager/v1"
+	cloudresourcemanagerv3 "google.golang.org/api/cloudresourcemanager/v3"
+	iam "google.golang.org/api/iam/v1"
+)
+
+// client is a gcpclient.Client scoped to a single project, backed by a shared Backend.
+type client struct {
+	backend     *Backend
+	projectName string
+}
+
+var _ gcpclient.Client = &client{}
+
+// ForProject returns a client of projectName backed by the same Backend
+func (c *client) ForProject(projectName string) gcpclient.Client {
+	return c.backend.NewClient(projectName)
+}
+
+// ListAvailabilityZones returns the zones of region, once compute.googleapis.com is enabled on projectID
+func (c *client) ListAvailabilityZones(ctx context.Context, projectID, region string) ([]string, error) {
+	b := c.backend
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if err := c.takeError(ctx, "ListAvailabilityZones"); err != nil {
+		return []string{}, err
+	}
+	p, err := b.activeProject(projectID)
+	if err != nil {
+		return []string{}, err
+	}
+	if !p.services["compute.googleapis.com"] {
+		return []string{}, newServiceDisabledError(projectID, "Compute Engine API", "compute.googleapis.com")
+	}
+	return append([]string{}, b.zones[region]...), nil
+}
+
+// ListProjects returns every project in parentFolderID, including the ones pending deletion
+func (c *client) ListProjects(ctx context.Context, parentFolderID string) ([]*cloudresourcemanager.Project, error) {
+	b := c.backend
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if err := c.takeError(ctx, "ListProjects"); err != nil {
+		return []*cloudresourcemanager.Project{}, err
+	}
+	projects := []*cloudresourcemanager.Project{}
+	for _, id := range b.listProjects(parentFolderID) {
+		projects = append(projects, copyProject(b.projects[id].project))
+	}
+	return projects, nil
+}
+
+// GetProject returns a project
+func (c *client) GetProject(ctx context.Context, projectID string) (*cloudresourcemanager.Project, error) {
+	b := c.backend
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if err := c.takeError(ctx, "GetProject"); err != nil {
+		return nil, err
+	}
+	p, ok := b.projects[projectID]
+	if !ok || !b.visible(p.createdAt) {
+		return nil, newError(http.StatusForbidden, fmt.Sprintf("The caller does not have permission on project %s", projectID))
+	}
+	return copyProject(p.project), nil
+}
+
+// UpdateProjectLabels sets labels on projectID and removes the labels named in remove, keeping its other labels
+func (c *client) UpdateProjectLabels(ctx context.Context, projectID string, labels map[string]string, remove []string) error {
+	b := c.backend
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if err := c.takeError(ctx, "UpdateProjectLabels"); err != nil {
+		return err
+	}
+	return b.updateProjectLabels(projectID, labels, remove)
+}
+
+// CreateProject starts the creation of the project of the client in parentFolderID.
+// The returned operation is done, and the project visible, after the propagation delay.
+func (c *client) CreateProject(ctx context.Context, parentFolderID string, displayName string, labels map[string]string) (*cloudresourcemanager.Operation, error) {
+	b := c.backend
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if err := c.takeError(ctx, "CreateProject"); err != nil {
+		return &cloudresourcemanager.Operation{}, err
+	}
+	op, err := b.createProject(c.projectName, displayName, parentFolderID, labels)
+	if err != nil {
+		return &cloudresourcemanager.Operation{}, err
+	}
+	return op, nil
+}
+
+// GetOperation returns the project creation operation with name
+func (c *client) GetOperation(ctx context.Context, name string) (*cloudresourcemanager.Operation, error) {
+	b := c.backend
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if err := c.takeError(ctx, "GetOperation"); err != nil {
+		return nil, err
+	}
+	return b.operation(name)
+}
+
+// ListTagBindings returns the tag values bound to the project projectNumber
+func (c *client) ListTagBindings(ctx context.Context, projectNumber int64) ([]string, error) {
+	b := c.backend
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if err := c.takeError(ctx, "ListTagBindings"); err != nil {
+		return []string{}, err
+	}
+	p, err := b.taggedProject(projectNumber)
+	if err != nil {
+		return []string{}, err
+	}
+	return sortedKeys(p.tagBindings), nil
+}
+
+// GetTagValue returns the tag value seeded with AddTagValue as namespacedName
+func (c *client) GetTagValue(ctx context.Context, namespacedName string) (*cloudresourcemanagerv3.TagValue, error) {
+	b := c.backend
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if err := c.takeError(ctx, "GetTagValue"); err != nil {
+		return nil, err
+	}
+	return b.tagValue(namespacedName)
+}
+
+// CreateTagBinding binds tagValue to the project projectNumber, ignoring existing bindings like gcpclient does
+func (c *client) CreateTagBinding(ctx context.Context, projectNumber int64, tagValue string) error {
+	b := c.backend
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if err := c.takeError(ctx, "CreateTagBinding"); err != nil {
+		return err
+	}
+	err := b.bindTag(projectNumber, tagValue)
+	var gcpErr *googleapi.Error
+	if errors.As(err, &gcpErr) && gcpErr.Code == http.StatusConflict {
+		return nil
+	}
+	return err
+}
+
+// DeleteProject marks the project of the client as DELETE_REQUESTED
+func (c *client) DeleteProject(ctx context.Context, parentFolder string) (*cloudresourcemanager.Empty, error) {
+	b := c.backend
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if err := c.takeError(ctx, "DeleteProject"); err != nil {
+		return &cloudresourcemanager.Empty{}, err
+	}
+	p, err := b.activeProject(c.projectName)
+	if err != nil {
+		return &cloudresourcemanager.Empty{}, err
+	}
+	p.project.LifecycleState = LifecycleStateDeleteRequested
+	return &cloudresourcemanager.Empty{}, nil
+}
+
+// UndeleteProject restores the project projectID, which must be DELETE_REQUESTED
+func (c *client) UndeleteProject(ctx context.Context, projectID string) error {
+	b := c.backend
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if err := c.takeError(ctx, "UndeleteProject"); err != nil {
+		return err
+	}
+	return b.undeleteProject(projectID)
+}
+
+// GetServiceAccount returns a service account if it exists
+func (c *client) GetServiceAccount(ctx context.Context, accountName string) (*iam.ServiceAccount, error) {
+	b := c.backend
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if err := c.takeError(ctx, "GetServiceAccount"); err != nil {
+		return &iam.ServiceAccount{}, err
+	}
+	sa, err := c.serviceAccount(serviceAccountEmail(accountName, c.projectName))
+	if err != nil {
+		return &iam.ServiceAccount{}, err
+	}
+	account := *sa.account
+	return &account, nil
+}
+
+// CreateServiceAccount creates a service account in the project of the client
+func (c *client) CreateServiceAccount(ctx context.Context, name, displayName string) (*iam.ServiceAccount, error) {
+	b := c.backend
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if err := c.takeError(ctx, "CreateServiceAccount"); err != nil {
+		return &iam.ServiceAccount{}, err
+	}
+	p, err := b.activeProject(c.projectName)
+	if err != nil {
+		return &iam.ServiceAccount{}, err
+	}
+	sa, err := b.addServiceAccount(p, c.projectName, name, displayName)
+	if err != nil {
+		return &iam.ServiceAccount{}, err
+	}
+	return sa, nil
+}
+
+// DeleteServiceAccount deletes a service account and its keys
+func (c *client) DeleteServiceAccount(ctx context.Context, accountEmail string) error {
+	b := c.backend
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if err := c.takeError(ctx, "DeleteServiceAccount"); err != nil {
+		return err
+	}
+	p, err := b.activeProject(c.projectName)
+	if err != nil {
+		return err
+	}
+	if _, ok := p.serviceAccounts[accountEmail]; !ok {
+		return newError(http.StatusNotFound, fmt.Sprintf("Service account projects/%s/serviceAccounts/%s does not exist.", c.projectName, accountEmail))
+	}
+	delete(p.serviceAccounts, accountEmail)
+	return nil
+}
+
+// CreateServiceAccountKey mints a new key for the service account
+func (c *client) CreateServiceAccountKey(ctx context.Context, serviceAccountEmail string) (*iam.ServiceAccountKey, error) {
+	b := c.backend
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if err := c.takeError(ctx, "CreateServiceAccountKey"); err != nil {
+		return &iam.ServiceAccountKey{}, err
+	}
+	sa, err := c.serviceAccount(serviceAccountEmail)
+	if err != nil {
+		return &iam.ServiceAccountKey{}, err
+	}
+	return b.addServiceAccountKey(sa, func(keyID string) string {
+		return fmt.Sprintf(`{"type":"service_account","project_id":%q,"private_key_id":%q,"client_email":%q}`, c.projectName, keyID, serviceAccountEmail)
+	}), nil
+}
+
+// DeleteServiceAccountKeys deletes all keys of the service account when it holds more than one
+func (c *client) DeleteServiceAccountKeys(ctx context.Context, serviceAccountEmail string) error {
+	b := c.backend
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if err := c.takeError(ctx, "DeleteServiceAccountKeys"); err != nil {
+		return err
+	}
+	sa, err := c.serviceAccount(serviceAccountEmail)
+	if err != nil {
+		return err
+	}
+	if len(sa.keys) <= 1 {
+		return nil
+	}
+	sa.keys = map[string]*iam.ServiceAccountKey{}
+	return nil
+}
+
+// ListServiceAccountKeys returns the keys of the service account
+func (c *client) ListServiceAccountKeys(ctx context.Context, serviceAccountEmail string) ([]*iam.ServiceAccountKey, error) {
+	b := c.backend
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if err := c.takeError(ctx, "ListServiceAccountKeys"); err != nil {
+		return nil, err
+	}
+	sa, err := c.serviceAccount(serviceAccountEmail)
+	if err != nil {
+		return nil, err
+	}
+	keys := []*iam.ServiceAccountKey{}
+	for _, name := range sortedKeys(sa.keys) {
+		key := *sa.keys[name]
+		key.PrivateKeyData = ""
+		keys = append(keys, &key)
+	}
+	return keys, nil
+}
+
+// DeleteServiceAccountKey deletes the key keyName
+func (c *client) DeleteServiceAccountKey(ctx context.Context, keyName string) error {
+	b := c.backend
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if err := c.takeError(ctx, "DeleteServiceAccountKey"); err != nil {
+		return err
+	}
+	email, _, _ := strings.Cut(strings.TrimPrefix(keyName, fmt.Sprintf("projects/%s/serviceAccounts/", c.projectName)), "/keys/")
+	sa, err := c.serviceAccount(email)
+	if err != nil {
+		return err
+	}
+	if _, ok := sa.keys[keyName]; !ok {
+		return newError(http.StatusNotFound, fmt.Sprintf("Service account key %s does not exist.", keyName))
+	}
+	delete(sa.keys, keyName)
+	return nil
+}
+
+// GetServiceAccountIamPolicy returns the IAM policy of the service account
+func (c *client) GetServiceAccountIamPolicy(ctx context.Context, serviceAccountEmail string) (*iam.Policy, error) {
+	b := c.backend
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if err := c.takeError(ctx, "GetServiceAccountIamPolicy"); err != nil {
+		return nil, err
+	}
+	sa, err := c.serviceAccount(serviceAccountEmail)
+	if err != nil {
+		return nil, err
+	}
+	return clone(sa.policy), nil
+}
+
+// SetServiceAccountIamPolicy replaces the IAM policy of the service account
+func (c *client) SetServiceAccountIamPolicy(ctx context.Context, serviceAccountEmail string, policy *iam.Policy) (*iam.Policy, error) {
+	b := c.backend
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if err := c.takeError(ctx, "SetServiceAccountIamPolicy"); err != nil {
+		return nil, err
+	}
+	sa, err := c.serviceAccount(serviceAccountEmail)
+	if err != nil {
+		return nil, err
+	}
+	return b.setServiceAccountPolicy(sa, policy)
+}
+
+// GetWorkloadIdentityPool returns the workload identity pool poolID
+func (c *client) GetWorkloadIdentityPool(ctx context.Context, projectID, poolID string) (*iam.WorkloadIdentityPool, error) {
+	b := c.backend
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if err := c.takeError(ctx, "GetWorkloadIdentityPool"); err != nil {
+		return nil, err
+	}
+	pool, err := b.workloadIdentityPool(projectID, poolID)
+	if err != nil {
+		return nil, err
+	}
+	return clone(pool.pool), nil
+}
+
+// CreateWorkloadIdentityPool creates the workload identity pool poolID
+func (c *client) CreateWorkloadIdentityPool(ctx context.Context, projectID, poolID string, pool *iam.WorkloadIdentityPool) error {
+	b := c.backend
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if err := c.takeError(ctx, "CreateWorkloadIdentityPool"); err != nil {
+		return err
+	}
+	return b.addWorkloadIdentityPool(projectID, poolID, pool)
+}
+
+// DeleteWorkloadIdentityPool soft deletes the workload identity pool poolID
+func (c *client) DeleteWorkloadIdentityPool(ctx context.Context, projectID, poolID string) error {
+	b := c.backend
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if err := c.takeError(ctx, "DeleteWorkloadIdentityPool"); err != nil {
+		return err
+	}
+	pool, err := b.workloadIdentityPool(projectID, poolID)
+	if err != nil {
+		return err
+	}
+	pool.pool.State = "DELETED"
+	return nil
+}
+
+// UndeleteWorkloadIdentityPool restores the deleted workload identity pool poolID
+func (c *client) UndeleteWorkloadIdentityPool(ctx context.Context, projectID, poolID string) error {
+	b := c.backend
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if err := c.takeError(ctx, "UndeleteWorkloadIdentityPool"); err != nil {
+		return err
+	}
+	return b.undeleteWorkloadIdentityPool(projectID, poolID)
+}
+
+// GetWorkloadIdentityPoolProvider returns the provider providerID of the workload identity pool poolID
+func (c *client) GetWorkloadIdentityPoolProvider(ctx context.Context, projectID, poolID, providerID string) (*iam.WorkloadIdentityPoolProvider, error) {
+	b := c.backend
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if err := c.takeError(ctx, "GetWorkloadIdentityPoolProvider"); err != nil {
+		return nil, err
+	}
+	provider, err := b.workloadIdentityPoolProvider(projectID, poolID, providerID)
+	if err != nil {
+		return nil, err
+	}
+	return clone(provider), nil
+}
+
+// CreateWorkloadIdentityPoolProvider creates the provider providerID in the workload identity pool poolID
+func (c *client) CreateWorkloadIdentityPoolProvider(ctx context.Context, projectID, poolID, providerID string, provider *iam.WorkloadIdentityPoolProvider) error {
+	b := c.backend
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if err := c.takeError(ctx, "CreateWorkloadIdentityPoolProvider"); err != nil {
+		return err
+	}
+	return b.addWorkloadIdentityPoolProvider(projectID, poolID, providerID, provider)
+}
+
+// UpdateWorkloadIdentityPoolProvider updates the fields of the provider providerID listed in updateMask
+func (c *client) UpdateWorkloadIdentityPoolProvider(ctx context.Context, projectID, poolID, providerID string, provider *iam.WorkloadIdentityPoolProvider, updateMask string) error {
+	b := c.backend
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if err := c.takeError(ctx, "UpdateWorkloadIdentityPoolProvider"); err != nil {
+		return err
+	}
+	return b.updateWorkloadIdentityPoolProvider(projectID, poolID, providerID, provider, updateMask)
+}
+
+// GetIamPolicy returns the IAM policy of projectName
+func (c *client) GetIamPolicy(ctx context.Context, projectName string) (*cloudresourcemanager.Policy, error) {
+	b := c.backend
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if err := c.takeError(ctx, "GetIamPolicy"); err != nil {
+		return nil, err
+	}
+	p, err := b.activeProject(projectName)
+	if err != nil {
+		return nil, err
+	}
+	return copyPolicy(p.policy), nil
+}
+
+// SetIamPolicy replaces the IAM policy of the project of the client.
+// A request carrying a stale etag fails with 409, like concurrent writes do in GCP.
+func (c *client) SetIamPolicy(ctx context.Context, setIamPolicyRequest *cloudresourcemanager.SetIamPolicyRequest) (*cloudresourcemanager.Policy, error) {
+	b := c.backend
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if err := c.takeError(ctx, "SetIamPolicy"); err != nil {
+		return &cloudresourcemanager.Policy{}, err
+	}
+	p, err := b.activeProject(c.projectName)
+	if err != nil {
+		return &cloudresourcemanager.Policy{}, err
+	}
+	policy, err := b.setPolicy(p, setIamPolicyRequest.Policy)
+	if err != nil {
+		return &cloudresourcemanager.Policy{}, err
+	}
+	return policy, nil
+}
+
+// ListAPIs returns the services enabled on projectID
+func (c *client) ListAPIs(ctx context.Context, projectID string) ([]string, error) {
+	b := c.backend
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if err := c.takeError(ctx, "ListAPIs"); err != nil {
+		return []string{}, err
+	}
+	p, err := b.activeProject(projectID)
+	if err != nil {
+		return []string{}, err
+	}
+	return p.enabledServices(), nil
+}
+
+// EnableAPI enables api on projectID
+func (c *client) EnableAPI(ctx context.Context, projectID, api string) error {
+	b := c.backend
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if err := c.takeError(ctx, "EnableAPI"); err != nil {
+		return err
+	}
+	p, err := b.activeProject(projectID)
+	if err != nil {
+		return err
+	}
+	if !strings.HasSuffix(api, ".googleapis.com") {
+		return newError(http.StatusBadRequest, fmt.Sprintf("Service %s is not a valid service name.", api))
+	}
+	p.services[api] = true
+	return nil
+}
+
+// CreateCloudBillingAccount links projectID to billingAccountID
+func (c *client) CreateCloudBillingAccount(ctx context.Context, projectID, billingAccountID string) error {
+	b := c.backend
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if err := c.takeError(ctx, "CreateCloudBillingAccount"); err != nil {
+		return err
+	}
+	p, err := b.billingProject(projectID)
+	if err != nil {
+		return err
+	}
+	p.billing.BillingAccountName = fmt.Sprintf("billingAccounts/%s", strings.TrimSuffix(billingAccountID, "\n"))
+	p.billing.BillingEnabled = true
+	return nil
+}
+
+// GetBillingInfo returns the billing information of projectID
+func (c *client) GetBillingInfo(ctx context.Context, projectID string) (*cloudbilling.ProjectBillingInfo, error) {
+	b := c.backend
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if err := c.takeError(ctx, "GetBillingInfo"); err != nil {
+		return nil, err
+	}
+	p, err := b.billingProject(projectID)
+	if err != nil {
+		return nil, err
+	}
+	info := *p.billing
+	return &info, nil
+}
+
+// takeError fails calls whose context is done, otherwise it pops the next injected error for method. Callers must hold the backend lock.
+func (c *client) takeError(ctx context.Context, method string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	return c.backend.takeError(method)
+}
+
+// serviceAccount returns the propagated service account with email in the project of the client. Callers must hold the backend lock.
+func (c *client) serviceAccount(email string) (*serviceAccount, error) {
+	return c.backend.serviceAccount(c.projectName, email)
+}
+
+func serviceAccountEmail(accountName, projectName string) string {
+	return fmt.Sprintf("%s@%s.iam.gserviceaccount.com", accountName, projectName)
+}
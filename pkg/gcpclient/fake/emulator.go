@@ -0,0 +1,762 @@
+package fake
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"google.golang.org/api/googleapi"
+	"google.golang.org/api/serviceusage/v1"
+
+	"github.com/openshift/gcp-project-operator/pkg/gcpclient"
+
+	cloudbilling "google.golang.org/api/cloudbilling/v1"
+	cloudresourcemanager "google.golang.org/api/cloudresourcemanager/v1"
+	cloudresourcemanagerv3 "google.golang.org/api/cloudresourcemanager/v3"
+	compute "google.golang.org/api/compute/v1"
+	iam "google.golang.org/api/iam/v1"
+)
+
+const (
+	// defaultPageSize is the number of projects returned per page by the emulated Projects.List
+	defaultPageSize = 500
+	// defaultServicesPageSize is the number of services returned per page by the emulated Services.List
+	defaultServicesPageSize = 50
+)
+
+// Emulator serves the subset of the Google REST APIs used by gcpclient.NewClient on a local
+// httptest.Server, backed by the state of a Backend. Unlike the in-process client, it lets the
+// real gcpClient code run, including its retry loops and its 409 handling.
+//
+// Errors injected with Backend.InjectError are keyed by REST method id for the emulator,
+// e.g. "cloudresourcemanager.projects.create" or "serviceusage.services.enable".
+type Emulator struct {
+	*httptest.Server
+
+	backend     *Backend
+	credentials []byte
+	tokens      int
+}
+
+// NewEmulator starts an Emulator serving b. Callers must Close it.
+func NewEmulator(b *Backend) (*Emulator, error) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		return nil, fmt.Errorf("fake.NewEmulator.rsa.GenerateKey %v", err)
+	}
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		return nil, fmt.Errorf("fake.NewEmulator.x509.MarshalPKCS8PrivateKey %v", err)
+	}
+
+	e := &Emulator{backend: b}
+	e.Server = httptest.NewServer(e.handler())
+	e.credentials, err = json.Marshal(map[string]string{
+		"type":           "service_account",
+		"project_id":     "emulator",
+		"private_key_id": "emulator",
+		"private_key":    string(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der})),
+		"client_email":   "emulator@emulator.iam.gserviceaccount.com",
+		"client_id":      "1",
+		"token_uri":      e.URL + "/token",
+	})
+	if err != nil {
+		e.Close()
+		return nil, fmt.Errorf("fake.NewEmulator.json.Marshal %v", err)
+	}
+	return e, nil
+}
+
+// Credentials returns service account JSON credentials whose tokens are minted by the emulator.
+func (e *Emulator) Credentials() []byte {
+	return append([]byte{}, e.credentials...)
+}
+
+// ExternalAccountCredentials returns external_account JSON credentials reading the subject token from tokenFile,
+// exchanging it with the emulator and impersonating serviceAccountEmail.
+func (e *Emulator) ExternalAccountCredentials(tokenFile, serviceAccountEmail string) []byte {
+	credentials, _ := json.Marshal(map[string]interface{}{
+		"type":                              "external_account",
+		"audience":                          "//iam.googleapis.com/projects/1/locations/global/workloadIdentityPools/emulator/providers/emulator",
+		"subject_token_type":                "urn:ietf:params:oauth:token-type:jwt",
+		"token_url":                         e.URL + "/sts/v1/token",
+		"service_account_impersonation_url": fmt.Sprintf("%s/iamcredentials/v1/projects/-/serviceAccounts/%s:generateAccessToken", e.URL, serviceAccountEmail),
+		"credential_source":                 map[string]interface{}{"file": tokenFile, "format": map[string]string{"type": "text"}},
+	})
+	return credentials
+}
+
+// Endpoints returns the endpoints to pass to gcpclient.WithEndpoints to reach the emulator.
+func (e *Emulator) Endpoints() gcpclient.Endpoints {
+	return gcpclient.Endpoints{
+		CloudResourceManager: e.URL + "/cloudresourcemanager/",
+		IAM:                  e.URL + "/iam/",
+		ServiceUsage:         e.URL + "/serviceusage/",
+		CloudBilling:         e.URL + "/cloudbilling/",
+		Compute:              e.URL + "/compute/v1/",
+	}
+}
+
+// ClientBuilder returns a function matching ProjectReferenceReconciler.GcpClientBuilder,
+// building real clients that talk to the emulator.
+func (e *Emulator) ClientBuilder() func(projectName string, authJSON []byte) (gcpclient.Client, error) {
+	return func(projectName string, authJSON []byte) (gcpclient.Client, error) {
+		return gcpclient.NewClient(projectName, authJSON, gcpclient.WithEndpoints(e.Endpoints()))
+	}
+}
+
+func (e *Emulator) handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /token", e.token)
+	mux.HandleFunc("POST /sts/v1/token", e.exchangeToken)
+
+	wrap := func(method string, h func(r *http.Request) (interface{}, error)) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			if !strings.HasPrefix(r.Header.Get("Authorization"), "Bearer ") {
+				writeError(w, newError(http.StatusUnauthorized, "Request is missing required authentication credential."))
+				return
+			}
+			body, err := e.serve(method, h, r)
+			if err != nil {
+				writeError(w, err)
+				return
+			}
+			writeJSON(w, http.StatusOK, body)
+		}
+	}
+	handle := func(pattern, method string, h func(r *http.Request) (interface{}, error)) {
+		mux.HandleFunc(pattern, wrap(method, h))
+	}
+
+	handle("POST /cloudresourcemanager/v1/projects", "cloudresourcemanager.projects.create", e.createProject)
+	handle("GET /cloudresourcemanager/v1/projects", "cloudresourcemanager.projects.list", e.listProjects)
+	handle("GET /cloudresourcemanager/v1/projects/{project}", "cloudresourcemanager.projects.get", e.getProject)
+	handle("PUT /cloudresourcemanager/v1/projects/{project}", "cloudresourcemanager.projects.update", e.updateProject)
+	handle("DELETE /cloudresourcemanager/v1/projects/{project}", "cloudresourcemanager.projects.delete", e.deleteProject)
+	// projects/{project}:undelete and the IAM policy methods share a path
+	undeleteProject := wrap("cloudresourcemanager.projects.undelete", e.undeleteProject)
+	projectIamPolicy := wrap("cloudresourcemanager.projects.iamPolicy", e.projectIamPolicy)
+	mux.HandleFunc("POST /cloudresourcemanager/v1/projects/{resource}", func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasSuffix(r.PathValue("resource"), ":undelete") {
+			undeleteProject(w, r)
+			return
+		}
+		projectIamPolicy(w, r)
+	})
+	handle("GET /cloudresourcemanager/v1/operations/{operation}", "cloudresourcemanager.operations.get", e.getOperation)
+	handle("GET /cloudresourcemanager/v3/tagBindings", "cloudresourcemanager.tagBindings.list", e.listTagBindings)
+	handle("POST /cloudresourcemanager/v3/tagBindings", "cloudresourcemanager.tagBindings.create", e.createTagBinding)
+	handle("GET /cloudresourcemanager/v3/tagValues/namespaced", "cloudresourcemanager.tagValues.getNamespaced", e.getNamespacedTagValue)
+
+	handle("GET /iam/v1/projects/{project}/serviceAccounts/{account}", "iam.projects.serviceAccounts.get", e.getServiceAccount)
+	handle("POST /iam/v1/projects/{project}/serviceAccounts", "iam.projects.serviceAccounts.create", e.createServiceAccount)
+	handle("DELETE /iam/v1/projects/{project}/serviceAccounts/{account}", "iam.projects.serviceAccounts.delete", e.deleteServiceAccount)
+	handle("POST /iam/v1/projects/{project}/serviceAccounts/{account}/keys", "iam.projects.serviceAccounts.keys.create", e.createServiceAccountKey)
+	handle("GET /iam/v1/projects/{project}/serviceAccounts/{account}/keys", "iam.projects.serviceAccounts.keys.list", e.listServiceAccountKeys)
+	handle("GET /iam/v1/projects/{project}/serviceAccounts/{account}/keys/{key}", "iam.projects.serviceAccounts.keys.get", e.getServiceAccountKey)
+	handle("DELETE /iam/v1/projects/{project}/serviceAccounts/{account}/keys/{key}", "iam.projects.serviceAccounts.keys.delete", e.deleteServiceAccountKey)
+	handle("POST /iam/v1/projects/{project}/serviceAccounts/{account}", "iam.projects.serviceAccounts.iamPolicy", e.serviceAccountIamPolicy)
+	handle("POST /iam/v1/projects/{project}/locations/global/workloadIdentityPools", "iam.projects.locations.workloadIdentityPools.create", e.createWorkloadIdentityPool)
+	handle("GET /iam/v1/projects/{project}/locations/global/workloadIdentityPools/{pool}", "iam.projects.locations.workloadIdentityPools.get", e.getWorkloadIdentityPool)
+	handle("DELETE /iam/v1/projects/{project}/locations/global/workloadIdentityPools/{pool}", "iam.projects.locations.workloadIdentityPools.delete", e.deleteWorkloadIdentityPool)
+	handle("POST /iam/v1/projects/{project}/locations/global/workloadIdentityPools/{pool}", "iam.projects.locations.workloadIdentityPools.undelete", e.undeleteWorkloadIdentityPool)
+	handle("POST /iam/v1/projects/{project}/locations/global/workloadIdentityPools/{pool}/providers", "iam.projects.locations.workloadIdentityPools.providers.create", e.createWorkloadIdentityPoolProvider)
+	handle("GET /iam/v1/projects/{project}/locations/global/workloadIdentityPools/{pool}/providers/{provider}", "iam.projects.locations.workloadIdentityPools.providers.get", e.getWorkloadIdentityPoolProvider)
+	handle("PATCH /iam/v1/projects/{project}/locations/global/workloadIdentityPools/{pool}/providers/{provider}", "iam.projects.locations.workloadIdentityPools.providers.patch", e.patchWorkloadIdentityPoolProvider)
+
+	handle("POST /iamcredentials/v1/projects/-/serviceAccounts/{account}", "iamcredentials.serviceAccounts.generateAccessToken", e.generateAccessToken)
+
+	handle("POST /serviceusage/v1/projects/{project}/services/{service}", "serviceusage.services.enable", e.enableService)
+	handle("GET /serviceusage/v1/projects/{project}/services", "serviceusage.services.list", e.listServices)
+
+	handle("GET /cloudbilling/v1/projects/{project}/billingInfo", "cloudbilling.projects.getBillingInfo", e.getBillingInfo)
+	handle("PUT /cloudbilling/v1/projects/{project}/billingInfo", "cloudbilling.projects.updateBillingInfo", e.updateBillingInfo)
+
+	handle("GET /compute/v1/projects/{project}/zones", "compute.zones.list", e.listZones)
+
+	return mux
+}
+
+// serve runs h under the backend lock, unless an error was injected for method.
+func (e *Emulator) serve(method string, h func(r *http.Request) (interface{}, error), r *http.Request) (interface{}, error) {
+	e.backend.mu.Lock()
+	defer e.backend.mu.Unlock()
+	if err := e.backend.takeError(method); err != nil {
+		return nil, err
+	}
+	return h(r)
+}
+
+// token implements the OAuth2 token endpoint referenced by Credentials.
+// Any JWT assertion is accepted, the emulator doesn't authorize callers.
+func (e *Emulator) token(w http.ResponseWriter, r *http.Request) {
+	e.backend.mu.Lock()
+	e.tokens++
+	token := fmt.Sprintf("emulator-token-%d", e.tokens)
+	e.backend.mu.Unlock()
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"access_token": token,
+		"token_type":   "Bearer",
+		"expires_in":   3600,
+	})
+}
+
+// exchangeToken implements the Security Token Service endpoint used by external_account credentials.
+// Any subject token is accepted, the emulator doesn't check it against the workload identity providers.
+func (e *Emulator) exchangeToken(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil || r.Form.Get("subject_token") == "" || r.Form.Get("audience") == "" {
+		writeError(w, newError(http.StatusBadRequest, "Request is missing the subject token or the audience."))
+		return
+	}
+	e.backend.mu.Lock()
+	e.tokens++
+	token := fmt.Sprintf("emulator-sts-token-%d", e.tokens)
+	e.backend.mu.Unlock()
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"access_token":      token,
+		"issued_token_type": "urn:ietf:params:oauth:token-type:access_token",
+		"token_type":        "Bearer",
+		"expires_in":        3600,
+	})
+}
+
+// generateAccessToken serves projects/-/serviceAccounts/{account}:generateAccessToken,
+// impersonating any service account known to the backend
+func (e *Emulator) generateAccessToken(r *http.Request) (interface{}, error) {
+	email, method, _ := strings.Cut(r.PathValue("account"), ":")
+	if method != "generateAccessToken" {
+		return nil, newError(http.StatusNotFound, fmt.Sprintf("Method %s not found.", method))
+	}
+	for _, p := range e.backend.projects {
+		if _, ok := p.serviceAccounts[email]; ok {
+			e.tokens++
+			return map[string]string{
+				"accessToken": fmt.Sprintf("emulator-impersonated-token-%d", e.tokens),
+				"expireTime":  e.backend.Now().Add(time.Hour).UTC().Format(time.RFC3339),
+			}, nil
+		}
+	}
+	return nil, newError(http.StatusNotFound, fmt.Sprintf("Service account %s does not exist.", email))
+}
+
+func (e *Emulator) createProject(r *http.Request) (interface{}, error) {
+	b := e.backend
+	project := &cloudresourcemanager.Project{}
+	if err := decode(r, project); err != nil {
+		return nil, err
+	}
+	parentID := ""
+	if project.Parent != nil {
+		parentID = project.Parent.Id
+	}
+	return b.createProject(project.ProjectId, project.Name, parentID, project.Labels)
+}
+
+func (e *Emulator) getOperation(r *http.Request) (interface{}, error) {
+	return e.backend.operation("operations/" + r.PathValue("operation"))
+}
+
+func (e *Emulator) listProjects(r *http.Request) (interface{}, error) {
+	b := e.backend
+	parentFolderID := ""
+	// only the filter used by gcpclient is supported
+	for _, term := range strings.Fields(r.URL.Query().Get("filter")) {
+		if id, ok := strings.CutPrefix(term, "parent.id:"); ok {
+			parentFolderID = id
+		}
+	}
+	ids := b.listProjects(parentFolderID)
+
+	start, end, next := page(r, len(ids), defaultPageSize)
+	resp := &cloudresourcemanager.ListProjectsResponse{NextPageToken: next}
+	for _, id := range ids[start:end] {
+		resp.Projects = append(resp.Projects, copyProject(b.projects[id].project))
+	}
+	return resp, nil
+}
+
+// page returns the bounds of the page of n items requested by the pageToken and pageSize
+// parameters of r, and the token of the next page
+func page(r *http.Request, n, defaultSize int) (start, end int, next string) {
+	start, _ = strconv.Atoi(r.URL.Query().Get("pageToken"))
+	size, err := strconv.Atoi(r.URL.Query().Get("pageSize"))
+	if err != nil || size <= 0 {
+		size = defaultSize
+	}
+	start = min(max(start, 0), n)
+	end = min(start+size, n)
+	if end < n {
+		next = strconv.Itoa(end)
+	}
+	return start, end, next
+}
+
+func (e *Emulator) getProject(r *http.Request) (interface{}, error) {
+	b := e.backend
+	projectID := r.PathValue("project")
+	p, ok := b.projects[projectID]
+	if !ok || !b.visible(p.createdAt) {
+		return nil, newError(http.StatusForbidden, fmt.Sprintf("The caller does not have permission on project %s", projectID))
+	}
+	return copyProject(p.project), nil
+}
+
+func (e *Emulator) updateProject(r *http.Request) (interface{}, error) {
+	project := &cloudresourcemanager.Project{}
+	if err := decode(r, project); err != nil {
+		return nil, err
+	}
+	p, err := e.backend.activeProject(r.PathValue("project"))
+	if err != nil {
+		return nil, err
+	}
+	p.project.Labels = map[string]string{}
+	for k, v := range project.Labels {
+		p.project.Labels[k] = v
+	}
+	return copyProject(p.project), nil
+}
+
+func (e *Emulator) deleteProject(r *http.Request) (interface{}, error) {
+	p, err := e.backend.activeProject(r.PathValue("project"))
+	if err != nil {
+		return nil, err
+	}
+	p.project.LifecycleState = LifecycleStateDeleteRequested
+	return &cloudresourcemanager.Empty{}, nil
+}
+
+// undeleteProject serves projects/{project}:undelete
+func (e *Emulator) undeleteProject(r *http.Request) (interface{}, error) {
+	projectID := strings.TrimSuffix(r.PathValue("resource"), ":undelete")
+	if err := e.backend.undeleteProject(projectID); err != nil {
+		return nil, err
+	}
+	return &cloudresourcemanager.Empty{}, nil
+}
+
+// projectIamPolicy serves both projects/{resource}:getIamPolicy and projects/{resource}:setIamPolicy
+func (e *Emulator) projectIamPolicy(r *http.Request) (interface{}, error) {
+	projectID, method, _ := strings.Cut(r.PathValue("resource"), ":")
+	switch method {
+	case "getIamPolicy":
+		p, err := e.backend.activeProject(projectID)
+		if err != nil {
+			return nil, err
+		}
+		return copyPolicy(p.policy), nil
+	case "setIamPolicy":
+		req := &cloudresourcemanager.SetIamPolicyRequest{}
+		if err := decode(r, req); err != nil {
+			return nil, err
+		}
+		p, err := e.backend.activeProject(projectID)
+		if err != nil {
+			return nil, err
+		}
+		if req.Policy == nil {
+			return nil, newError(http.StatusBadRequest, "Request contains an invalid argument.")
+		}
+		return e.backend.setPolicy(p, req.Policy)
+	}
+	return nil, newError(http.StatusNotFound, fmt.Sprintf("Method %s not found.", method))
+}
+
+func (e *Emulator) listTagBindings(r *http.Request) (interface{}, error) {
+	projectNumber, err := projectNumber(r.URL.Query().Get("parent"))
+	if err != nil {
+		return nil, err
+	}
+	p, err := e.backend.taggedProject(projectNumber)
+	if err != nil {
+		return nil, err
+	}
+	resp := &cloudresourcemanagerv3.ListTagBindingsResponse{}
+	for _, tagValue := range sortedKeys(p.tagBindings) {
+		resp.TagBindings = append(resp.TagBindings, &cloudresourcemanagerv3.TagBinding{
+			Name:     fmt.Sprintf("tagBindings/%s/%s", url.PathEscape(r.URL.Query().Get("parent")), tagValue),
+			Parent:   r.URL.Query().Get("parent"),
+			TagValue: tagValue,
+		})
+	}
+	return resp, nil
+}
+
+func (e *Emulator) createTagBinding(r *http.Request) (interface{}, error) {
+	binding := &cloudresourcemanagerv3.TagBinding{}
+	if err := decode(r, binding); err != nil {
+		return nil, err
+	}
+	projectNumber, err := projectNumber(binding.Parent)
+	if err != nil {
+		return nil, err
+	}
+	if err := e.backend.bindTag(projectNumber, binding.TagValue); err != nil {
+		return nil, err
+	}
+	return &cloudresourcemanagerv3.Operation{Name: "operations/rctb." + e.backend.nextID(), Done: true}, nil
+}
+
+func (e *Emulator) getNamespacedTagValue(r *http.Request) (interface{}, error) {
+	return e.backend.tagValue(r.URL.Query().Get("name"))
+}
+
+// projectNumber parses the full resource name of a project, e.g. //cloudresourcemanager.googleapis.com/projects/123
+func projectNumber(resourceName string) (int64, error) {
+	number, ok := strings.CutPrefix(resourceName, "//cloudresourcemanager.googleapis.com/projects/")
+	projectNumber, err := strconv.ParseInt(number, 10, 64)
+	if !ok || err != nil {
+		return 0, newError(http.StatusBadRequest, fmt.Sprintf("Invalid resource name %q.", resourceName))
+	}
+	return projectNumber, nil
+}
+
+func (e *Emulator) getServiceAccount(r *http.Request) (interface{}, error) {
+	sa, err := e.backend.serviceAccount(r.PathValue("project"), r.PathValue("account"))
+	if err != nil {
+		return nil, err
+	}
+	account := *sa.account
+	return &account, nil
+}
+
+func (e *Emulator) createServiceAccount(r *http.Request) (interface{}, error) {
+	req := &iam.CreateServiceAccountRequest{}
+	if err := decode(r, req); err != nil {
+		return nil, err
+	}
+	projectID := r.PathValue("project")
+	p, err := e.backend.activeProject(projectID)
+	if err != nil {
+		return nil, err
+	}
+	displayName := ""
+	if req.ServiceAccount != nil {
+		displayName = req.ServiceAccount.DisplayName
+	}
+	return e.backend.addServiceAccount(p, projectID, req.AccountId, displayName)
+}
+
+func (e *Emulator) deleteServiceAccount(r *http.Request) (interface{}, error) {
+	projectID, email := r.PathValue("project"), r.PathValue("account")
+	if _, err := e.backend.serviceAccount(projectID, email); err != nil {
+		return nil, err
+	}
+	delete(e.backend.projects[projectID].serviceAccounts, email)
+	return &iam.Empty{}, nil
+}
+
+func (e *Emulator) createServiceAccountKey(r *http.Request) (interface{}, error) {
+	projectID, email := r.PathValue("project"), r.PathValue("account")
+	sa, err := e.backend.serviceAccount(projectID, email)
+	if err != nil {
+		return nil, err
+	}
+	return e.backend.addServiceAccountKey(sa, func(keyID string) string {
+		credentials := map[string]string{}
+		_ = json.Unmarshal(e.credentials, &credentials)
+		credentials["project_id"] = projectID
+		credentials["private_key_id"] = keyID
+		credentials["client_email"] = email
+		data, _ := json.Marshal(credentials)
+		return string(data)
+	}), nil
+}
+
+func (e *Emulator) listServiceAccountKeys(r *http.Request) (interface{}, error) {
+	sa, err := e.backend.serviceAccount(r.PathValue("project"), r.PathValue("account"))
+	if err != nil {
+		return nil, err
+	}
+	resp := &iam.ListServiceAccountKeysResponse{}
+	for _, name := range sortedKeys(sa.keys) {
+		key := *sa.keys[name]
+		key.PrivateKeyData = ""
+		resp.Keys = append(resp.Keys, &key)
+	}
+	return resp, nil
+}
+
+func (e *Emulator) getServiceAccountKey(r *http.Request) (interface{}, error) {
+	key, _, err := e.serviceAccountKey(r)
+	if err != nil {
+		return nil, err
+	}
+	k := *key
+	k.PrivateKeyData = ""
+	return &k, nil
+}
+
+func (e *Emulator) deleteServiceAccountKey(r *http.Request) (interface{}, error) {
+	key, sa, err := e.serviceAccountKey(r)
+	if err != nil {
+		return nil, err
+	}
+	delete(sa.keys, key.Name)
+	return &iam.Empty{}, nil
+}
+
+func (e *Emulator) serviceAccountKey(r *http.Request) (*iam.ServiceAccountKey, *serviceAccount, error) {
+	sa, err := e.backend.serviceAccount(r.PathValue("project"), r.PathValue("account"))
+	if err != nil {
+		return nil, nil, err
+	}
+	name := fmt.Sprintf("%s/keys/%s", sa.account.Name, r.PathValue("key"))
+	key, ok := sa.keys[name]
+	if !ok {
+		return nil, nil, newError(http.StatusNotFound, fmt.Sprintf("Service account key %s does not exist.", name))
+	}
+	return key, sa, nil
+}
+
+// serviceAccountIamPolicy serves both serviceAccounts/{account}:getIamPolicy and serviceAccounts/{account}:setIamPolicy
+func (e *Emulator) serviceAccountIamPolicy(r *http.Request) (interface{}, error) {
+	email, method, _ := strings.Cut(r.PathValue("account"), ":")
+	sa, err := e.backend.serviceAccount(r.PathValue("project"), email)
+	if err != nil {
+		return nil, err
+	}
+	switch method {
+	case "getIamPolicy":
+		return clone(sa.policy), nil
+	case "setIamPolicy":
+		req := &iam.SetIamPolicyRequest{}
+		if err := decode(r, req); err != nil {
+			return nil, err
+		}
+		if req.Policy == nil {
+			return nil, newError(http.StatusBadRequest, "Request contains an invalid argument.")
+		}
+		return e.backend.setServiceAccountPolicy(sa, req.Policy)
+	}
+	return nil, newError(http.StatusNotFound, fmt.Sprintf("Method %s not found.", method))
+}
+
+func (e *Emulator) createWorkloadIdentityPool(r *http.Request) (interface{}, error) {
+	pool := &iam.WorkloadIdentityPool{}
+	if err := decode(r, pool); err != nil {
+		return nil, err
+	}
+	projectID, poolID := r.PathValue("project"), r.URL.Query().Get("workloadIdentityPoolId")
+	if err := e.backend.addWorkloadIdentityPool(projectID, poolID, pool); err != nil {
+		return nil, err
+	}
+	return &iam.Operation{Name: fmt.Sprintf("%s/operations/%s", workloadIdentityPoolName(projectID, poolID), e.backend.nextID())}, nil
+}
+
+func (e *Emulator) getWorkloadIdentityPool(r *http.Request) (interface{}, error) {
+	pool, err := e.backend.workloadIdentityPool(r.PathValue("project"), r.PathValue("pool"))
+	if err != nil {
+		return nil, err
+	}
+	return clone(pool.pool), nil
+}
+
+func (e *Emulator) deleteWorkloadIdentityPool(r *http.Request) (interface{}, error) {
+	pool, err := e.backend.workloadIdentityPool(r.PathValue("project"), r.PathValue("pool"))
+	if err != nil {
+		return nil, err
+	}
+	pool.pool.State = "DELETED"
+	return &iam.Operation{Name: fmt.Sprintf("%s/operations/%s", pool.pool.Name, e.backend.nextID()), Done: true}, nil
+}
+
+// undeleteWorkloadIdentityPool serves workloadIdentityPools/{pool}:undelete
+func (e *Emulator) undeleteWorkloadIdentityPool(r *http.Request) (interface{}, error) {
+	poolID, method, _ := strings.Cut(r.PathValue("pool"), ":")
+	if method != "undelete" {
+		return nil, newError(http.StatusNotFound, fmt.Sprintf("Method %s not found.", method))
+	}
+	projectID := r.PathValue("project")
+	if err := e.backend.undeleteWorkloadIdentityPool(projectID, poolID); err != nil {
+		return nil, err
+	}
+	return &iam.Operation{Name: fmt.Sprintf("%s/operations/%s", workloadIdentityPoolName(projectID, poolID), e.backend.nextID()), Done: true}, nil
+}
+
+func (e *Emulator) createWorkloadIdentityPoolProvider(r *http.Request) (interface{}, error) {
+	provider := &iam.WorkloadIdentityPoolProvider{}
+	if err := decode(r, provider); err != nil {
+		return nil, err
+	}
+	projectID, poolID, providerID := r.PathValue("project"), r.PathValue("pool"), r.URL.Query().Get("workloadIdentityPoolProviderId")
+	if err := e.backend.addWorkloadIdentityPoolProvider(projectID, poolID, providerID, provider); err != nil {
+		return nil, err
+	}
+	return &iam.Operation{Name: fmt.Sprintf("%s/providers/%s/operations/%s", workloadIdentityPoolName(projectID, poolID), providerID, e.backend.nextID())}, nil
+}
+
+func (e *Emulator) getWorkloadIdentityPoolProvider(r *http.Request) (interface{}, error) {
+	provider, err := e.backend.workloadIdentityPoolProvider(r.PathValue("project"), r.PathValue("pool"), r.PathValue("provider"))
+	if err != nil {
+		return nil, err
+	}
+	return clone(provider), nil
+}
+
+func (e *Emulator) patchWorkloadIdentityPoolProvider(r *http.Request) (interface{}, error) {
+	provider := &iam.WorkloadIdentityPoolProvider{}
+	if err := decode(r, provider); err != nil {
+		return nil, err
+	}
+	projectID, poolID, providerID := r.PathValue("project"), r.PathValue("pool"), r.PathValue("provider")
+	if err := e.backend.updateWorkloadIdentityPoolProvider(projectID, poolID, providerID, provider, r.URL.Query().Get("updateMask")); err != nil {
+		return nil, err
+	}
+	return &iam.Operation{Name: fmt.Sprintf("%s/providers/%s/operations/%s", workloadIdentityPoolName(projectID, poolID), providerID, e.backend.nextID())}, nil
+}
+
+// enableService serves projects/{project}/services/{service}:enable
+func (e *Emulator) enableService(r *http.Request) (interface{}, error) {
+	service, method, _ := strings.Cut(r.PathValue("service"), ":")
+	if method != "enable" {
+		return nil, newError(http.StatusNotFound, fmt.Sprintf("Method %s not found.", method))
+	}
+	p, err := e.backend.activeProject(r.PathValue("project"))
+	if err != nil {
+		return nil, err
+	}
+	if !strings.HasSuffix(service, ".googleapis.com") {
+		return nil, newError(http.StatusBadRequest, fmt.Sprintf("Service %s is not a valid service name.", service))
+	}
+	p.services[service] = true
+	return &serviceusage.Operation{
+		Done: true,
+		Name: fmt.Sprintf("operations/acf.%s", e.backend.nextID()),
+	}, nil
+}
+
+// listServices serves projects/{project}/services, which also lists the disabled services
+// unless filtered by state
+func (e *Emulator) listServices(r *http.Request) (interface{}, error) {
+	p, err := e.backend.activeProject(r.PathValue("project"))
+	if err != nil {
+		return nil, err
+	}
+	state := ""
+	switch filter := r.URL.Query().Get("filter"); filter {
+	case "":
+	case "state:ENABLED", "state:DISABLED":
+		state = strings.TrimPrefix(filter, "state:")
+	default:
+		return nil, newError(http.StatusBadRequest, fmt.Sprintf("Invalid filter %q.", filter))
+	}
+	services := []*serviceusage.GoogleApiServiceusageV1Service{}
+	for _, name := range p.listServices() {
+		service := &serviceusage.GoogleApiServiceusageV1Service{
+			Config: &serviceusage.GoogleApiServiceusageV1ServiceConfig{Name: name},
+			Name:   fmt.Sprintf("projects/%d/services/%s", p.project.ProjectNumber, name),
+			Parent: fmt.Sprintf("projects/%d", p.project.ProjectNumber),
+			State:  "DISABLED",
+		}
+		if p.services[name] {
+			service.State = "ENABLED"
+		}
+		if state == "" || service.State == state {
+			services = append(services, service)
+		}
+	}
+
+	start, end, next := page(r, len(services), defaultServicesPageSize)
+	return &serviceusage.ListServicesResponse{Services: services[start:end], NextPageToken: next}, nil
+}
+
+func (e *Emulator) getBillingInfo(r *http.Request) (interface{}, error) {
+	p, err := e.backend.billingProject(r.PathValue("project"))
+	if err != nil {
+		return nil, err
+	}
+	info := *p.billing
+	return &info, nil
+}
+
+func (e *Emulator) updateBillingInfo(r *http.Request) (interface{}, error) {
+	req := &cloudbilling.ProjectBillingInfo{}
+	if err := decode(r, req); err != nil {
+		return nil, err
+	}
+	p, err := e.backend.billingProject(r.PathValue("project"))
+	if err != nil {
+		return nil, err
+	}
+	p.billing.BillingAccountName = req.BillingAccountName
+	p.billing.BillingEnabled = req.BillingAccountName != ""
+	info := *p.billing
+	return &info, nil
+}
+
+func (e *Emulator) listZones(r *http.Request) (interface{}, error) {
+	projectID := r.PathValue("project")
+	p, err := e.backend.activeProject(projectID)
+	if err != nil {
+		return nil, err
+	}
+	if !p.services["compute.googleapis.com"] {
+		return nil, newServiceDisabledError(projectID, "Compute Engine API", "compute.googleapis.com")
+	}
+	resp := &compute.ZoneList{}
+	for _, region := range sortedKeys(e.backend.zones) {
+		for _, zone := range e.backend.zones[region] {
+			resp.Items = append(resp.Items, &compute.Zone{
+				Name:   zone,
+				Region: fmt.Sprintf("%sprojects/%s/regions/%s", e.Endpoints().Compute, projectID, region),
+				Status: "UP",
+			})
+		}
+	}
+	return resp, nil
+}
+
+func decode(r *http.Request, v interface{}) error {
+	if err := json.NewDecoder(r.Body).Decode(v); err != nil {
+		return newError(http.StatusBadRequest, fmt.Sprintf("Invalid JSON payload received. %v", err))
+	}
+	return nil
+}
+
+func writeJSON(w http.ResponseWriter, code int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+// writeError writes err in the error format of the Google APIs, so it is decoded into a *googleapi.Error
+func writeError(w http.ResponseWriter, err error) {
+	code, message := http.StatusInternalServerError, err.Error()
+	var details []interface{}
+	var ae *googleapi.Error
+	if errors.As(err, &ae) {
+		code, message, details = ae.Code, ae.Message, ae.Details
+	}
+	body := map[string]interface{}{
+		"code":    code,
+		"message": message,
+		"errors": []map[string]string{
+			{"message": message, "domain": "global", "reason": http.StatusText(code)},
+		},
+	}
+	if len(details) > 0 {
+		body["details"] = details
+	}
+	writeJSON(w, code, map[string]interface{}{"error": body})
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,808 @@
+// Package fake provides a stateful, in-memory implementation of gcpclient.Client.
+// It lets the ProjectReference pipeline run end to end without GCP credentials or network access.
+package fake
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"google.golang.org/api/googleapi"
+
+	"github.com/openshift/gcp-project-operator/pkg/gcpclient"
+	cloudbilling "google.golang.org/api/cloudbilling/v1"
+	cloudresourcemanager "google.golang.org/api/cloudresourcemanager/v1"
+	cloudresourcemanagerv3 "google.golang.org/api/cloudresourcemanager/v3"
+	iam "google.golang.org/api/iam/v1"
+)
+
+// Lifecycle states reported for projects held by the Backend
+const (
+	LifecycleStateActive          = "ACTIVE"
+	LifecycleStateDeleteRequested = "DELETE_REQUESTED"
+)
+
+// DefaultZones is the region to zone mapping the Backend serves unless overridden with SetZones.
+var DefaultZones = map[string][]string{
+	"us-east1":     {"us-east1-b", "us-east1-c", "us-east1-d"},
+	"us-central1":  {"us-central1-a", "us-central1-b", "us-central1-c", "us-central1-f"},
+	"europe-west1": {"europe-west1-b", "europe-west1-c", "europe-west1-d"},
+}
+
+// Backend holds the state of every project created through its clients.
+// A single Backend is meant to be shared by all clients handed out by ClientBuilder,
+// the same way all real clients share one GCP organization.
+type Backend struct {
+	mu sync.Mutex
+
+	projects        map[string]*project
+	operations      map[string]*operation
+	zones           map[string][]string
+	injected        map[string][]int
+	operationErrors []*cloudresourcemanager.Status
+	// tagValues maps the namespaced names of the tag values to their names, e.g. tagValues/456
+	tagValues        map[string]string
+	propagationDelay time.Duration
+	sequence         int
+
+	// Now returns the current time. It can be replaced to control propagation delays in tests.
+	Now func() time.Time
+}
+
+type project struct {
+	project   *cloudresourcemanager.Project
+	createdAt time.Time
+	policy    *cloudresourcemanager.Policy
+	// services maps the services enabled at some point to whether they still are
+	services        map[string]bool
+	serviceAccounts map[string]*serviceAccount
+	billing         *cloudbilling.ProjectBillingInfo
+	pools           map[string]*workloadIdentityPool
+	// tagBindings are the names of the tag values bound to the project
+	tagBindings map[string]bool
+}
+
+// workloadIdentityPool is a workload identity pool and its providers, keyed by provider ID
+type workloadIdentityPool struct {
+	pool      *iam.WorkloadIdentityPool
+	createdAt time.Time
+	providers map[string]*iam.WorkloadIdentityPoolProvider
+}
+
+// operation is a project creation, done once the project has propagated
+type operation struct {
+	createdAt time.Time
+	err       *cloudresourcemanager.Status
+}
+
+type serviceAccount struct {
+	account   *iam.ServiceAccount
+	createdAt time.Time
+	keys      map[string]*iam.ServiceAccountKey
+	policy    *iam.Policy
+}
+
+// NewBackend returns an empty Backend serving DefaultZones.
+func NewBackend() *Backend {
+	zones := make(map[string][]string, len(DefaultZones))
+	for region, z := range DefaultZones {
+		zones[region] = append([]string{}, z...)
+	}
+	return &Backend{
+		projects:   make(map[string]*project),
+		operations: make(map[string]*operation),
+		zones:      zones,
+		injected:   make(map[string][]int),
+		tagValues:  make(map[string]string),
+		Now:        time.Now,
+	}
+}
+
+// NewClient returns a gcpclient.Client scoped to projectName, backed by b.
+func (b *Backend) NewClient(projectName string) gcpclient.Client {
+	return &client{backend: b, projectName: projectName}
+}
+
+// ClientBuilder returns a function matching ProjectReferenceReconciler.GcpClientBuilder.
+// The credentials are ignored, every client shares the state of b.
+func (b *Backend) ClientBuilder() func(projectName string, authJSON []byte) (gcpclient.Client, error) {
+	return func(projectName string, authJSON []byte) (gcpclient.Client, error) {
+		return b.NewClient(projectName), nil
+	}
+}
+
+// InjectError makes the next times calls of the named gcpclient.Client method
+// fail with a *googleapi.Error carrying code, e.g. InjectError("SetIamPolicy", http.StatusConflict, 2).
+func (b *Backend) InjectError(method string, code int, times int) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for i := 0; i < times; i++ {
+		b.injected[method] = append(b.injected[method], code)
+	}
+}
+
+// InjectOperationError makes the operation of the next project creation fail with code and message.
+// The project is not created, like when GCP rejects it asynchronously.
+func (b *Backend) InjectOperationError(code int, message string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.operationErrors = append(b.operationErrors, &cloudresourcemanager.Status{Code: int64(code), Message: message})
+}
+
+// SetPropagationDelay hides newly created projects and service accounts for d, keeps project creation operations pending for d,
+// mimicking the eventual consistency of the GCP control plane.
+func (b *Backend) SetPropagationDelay(d time.Duration) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.propagationDelay = d
+}
+
+// SetZones replaces the zones served for region.
+func (b *Backend) SetZones(region string, zones []string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.zones[region] = append([]string{}, zones...)
+}
+
+// AddProject seeds an ACTIVE project, e.g. one owned by a CCS customer.
+func (b *Backend) AddProject(projectID, parentFolderID string, labels map[string]string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.addProject(projectID, parentFolderID, labels, time.Time{})
+}
+
+// Project returns a copy of the project with projectID.
+func (b *Backend) Project(projectID string) (*cloudresourcemanager.Project, bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	p, ok := b.projects[projectID]
+	if !ok {
+		return nil, false
+	}
+	return copyProject(p.project), true
+}
+
+// EnabledServices returns the sorted list of services enabled on projectID.
+func (b *Backend) EnabledServices(projectID string) []string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	p, ok := b.projects[projectID]
+	if !ok {
+		return nil
+	}
+	return p.enabledServices()
+}
+
+// EnableService enables api on projectID out-of-band.
+func (b *Backend) EnableService(projectID, api string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if p, ok := b.projects[projectID]; ok {
+		p.services[api] = true
+	}
+}
+
+// DisableService disables api on projectID.
+func (b *Backend) DisableService(projectID, api string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if p, ok := b.projects[projectID]; ok && p.services[api] {
+		p.services[api] = false
+	}
+}
+
+// SetProjectLabels replaces the labels of projectID out-of-band.
+func (b *Backend) SetProjectLabels(projectID string, labels map[string]string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if p, ok := b.projects[projectID]; ok {
+		p.project.Labels = maps.Clone(labels)
+	}
+}
+
+// Policy returns a copy of the IAM policy of projectID.
+func (b *Backend) Policy(projectID string) (*cloudresourcemanager.Policy, bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	p, ok := b.projects[projectID]
+	if !ok {
+		return nil, false
+	}
+	return copyPolicy(p.policy), true
+}
+
+// BillingInfo returns a copy of the billing info of projectID.
+func (b *Backend) BillingInfo(projectID string) (*cloudbilling.ProjectBillingInfo, bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	p, ok := b.projects[projectID]
+	if !ok {
+		return nil, false
+	}
+	info := *p.billing
+	return &info, true
+}
+
+// ServiceAccounts returns the sorted emails of the service accounts in projectID.
+func (b *Backend) ServiceAccounts(projectID string) []string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	p, ok := b.projects[projectID]
+	if !ok {
+		return nil
+	}
+	emails := []string{}
+	for email := range p.serviceAccounts {
+		emails = append(emails, email)
+	}
+	sort.Strings(emails)
+	return emails
+}
+
+// ServiceAccountKeys returns the key names of the service account with email in projectID.
+func (b *Backend) ServiceAccountKeys(projectID, email string) []string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	p, ok := b.projects[projectID]
+	if !ok {
+		return nil
+	}
+	sa, ok := p.serviceAccounts[email]
+	if !ok {
+		return nil
+	}
+	names := []string{}
+	for name := range sa.keys {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// ServiceAccountPolicy returns a copy of the IAM policy of the service account with email in projectID.
+func (b *Backend) ServiceAccountPolicy(projectID, email string) (*iam.Policy, bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	p, ok := b.projects[projectID]
+	if !ok {
+		return nil, false
+	}
+	sa, ok := p.serviceAccounts[email]
+	if !ok {
+		return nil, false
+	}
+	return clone(sa.policy), true
+}
+
+// WorkloadIdentityPool returns a copy of the workload identity pool poolID of projectID.
+func (b *Backend) WorkloadIdentityPool(projectID, poolID string) (*iam.WorkloadIdentityPool, bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	p, ok := b.projects[projectID]
+	if !ok || p.pools[poolID] == nil {
+		return nil, false
+	}
+	return clone(p.pools[poolID].pool), true
+}
+
+// WorkloadIdentityPoolProvider returns a copy of the provider providerID of the workload identity pool poolID of projectID.
+func (b *Backend) WorkloadIdentityPoolProvider(projectID, poolID, providerID string) (*iam.WorkloadIdentityPoolProvider, bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	p, ok := b.projects[projectID]
+	if !ok || p.pools[poolID] == nil || p.pools[poolID].providers[providerID] == nil {
+		return nil, false
+	}
+	return clone(p.pools[poolID].providers[providerID]), true
+}
+
+// AddTagValue seeds the tag value with namespacedName, e.g. 123456789012/environment/production, and returns its name.
+func (b *Backend) AddTagValue(namespacedName string) string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if name, ok := b.tagValues[namespacedName]; ok {
+		return name
+	}
+	name := "tagValues/" + b.nextID()
+	b.tagValues[namespacedName] = name
+	return name
+}
+
+// TagBindings returns the sorted names of the tag values bound to projectID.
+func (b *Backend) TagBindings(projectID string) []string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	p, ok := b.projects[projectID]
+	if !ok {
+		return nil
+	}
+	return sortedKeys(p.tagBindings)
+}
+
+// DeleteServiceAccount removes the service account with email from projectID out-of-band.
+func (b *Backend) DeleteServiceAccount(projectID, email string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if p, ok := b.projects[projectID]; ok {
+		delete(p.serviceAccounts, email)
+	}
+}
+
+// takeError pops the next injected error for method, if any. Callers must hold b.mu.
+func (b *Backend) takeError(method string) error {
+	codes := b.injected[method]
+	if len(codes) == 0 {
+		return nil
+	}
+	b.injected[method] = codes[1:]
+	return newError(codes[0], fmt.Sprintf("injected error for %s", method))
+}
+
+// visible reports whether an object created at createdAt has propagated. Callers must hold b.mu.
+func (b *Backend) visible(createdAt time.Time) bool {
+	return !b.Now().Before(createdAt.Add(b.propagationDelay))
+}
+
+// listProjects returns the sorted IDs of the visible projects in parentFolderID, or of every visible project
+// if parentFolderID is empty. Callers must hold b.mu.
+func (b *Backend) listProjects(parentFolderID string) []string {
+	ids := []string{}
+	for id, p := range b.projects {
+		if !b.visible(p.createdAt) {
+			continue
+		}
+		if parentFolderID != "" && (p.project.Parent == nil || p.project.Parent.Id != parentFolderID) {
+			continue
+		}
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// billingProject returns projectID if it is active and has the Cloud Billing API enabled. Callers must hold b.mu.
+func (b *Backend) billingProject(projectID string) (*project, error) {
+	p, err := b.activeProject(projectID)
+	if err != nil {
+		return nil, err
+	}
+	if !p.services["cloudbilling.googleapis.com"] {
+		return nil, newServiceDisabledError(projectID, "Cloud Billing API", "cloudbilling.googleapis.com")
+	}
+	return p, nil
+}
+
+// activeProject returns projectID if it exists, has propagated and is ACTIVE. Callers must hold b.mu.
+func (b *Backend) activeProject(projectID string) (*project, error) {
+	p, ok := b.projects[projectID]
+	if !ok || !b.visible(p.createdAt) || p.project.LifecycleState != LifecycleStateActive {
+		// GCP doesn't disclose whether a project exists to callers without access
+		return nil, newError(http.StatusForbidden, fmt.Sprintf("The caller does not have permission on project %s", projectID))
+	}
+	return p, nil
+}
+
+// updateProjectLabels sets labels on projectID and removes the labels named in remove. Callers must hold b.mu.
+func (b *Backend) updateProjectLabels(projectID string, labels map[string]string, remove []string) error {
+	p, err := b.activeProject(projectID)
+	if err != nil {
+		return err
+	}
+	for k, v := range labels {
+		p.project.Labels[k] = v
+	}
+	for _, k := range remove {
+		delete(p.project.Labels, k)
+	}
+	return nil
+}
+
+// taggedProject returns the active project with projectNumber. Callers must hold b.mu.
+func (b *Backend) taggedProject(projectNumber int64) (*project, error) {
+	for id, p := range b.projects {
+		if p.project.ProjectNumber == projectNumber {
+			return b.activeProject(id)
+		}
+	}
+	return nil, newError(http.StatusForbidden, fmt.Sprintf("The caller does not have permission on project %d", projectNumber))
+}
+
+// tagValue returns the tag value with namespacedName. Callers must hold b.mu.
+func (b *Backend) tagValue(namespacedName string) (*cloudresourcemanagerv3.TagValue, error) {
+	name, ok := b.tagValues[namespacedName]
+	if !ok {
+		return nil, newError(http.StatusForbidden, fmt.Sprintf("Permission denied on resource '%s' (or it may not exist).", namespacedName))
+	}
+	parent, shortName, _ := strings.Cut(namespacedName[strings.Index(namespacedName, "/")+1:], "/")
+	return &cloudresourcemanagerv3.TagValue{
+		Name:           name,
+		NamespacedName: namespacedName,
+		Parent:         "tagKeys/" + parent,
+		ShortName:      shortName,
+	}, nil
+}
+
+// bindTag binds the tag value tagValue to the project projectNumber. Callers must hold b.mu.
+func (b *Backend) bindTag(projectNumber int64, tagValue string) error {
+	p, err := b.taggedProject(projectNumber)
+	if err != nil {
+		return err
+	}
+	known := false
+	for _, name := range b.tagValues {
+		known = known || name == tagValue
+	}
+	if !known {
+		return newError(http.StatusForbidden, fmt.Sprintf("Permission denied on resource '%s' (or it may not exist).", tagValue))
+	}
+	if p.tagBindings[tagValue] {
+		return newError(http.StatusConflict, "A binding already exists between the given resource and TagValue.")
+	}
+	p.tagBindings[tagValue] = true
+	return nil
+}
+
+// undeleteProject restores projectID if it is DELETE_REQUESTED. Callers must hold b.mu.
+func (b *Backend) undeleteProject(projectID string) error {
+	p, ok := b.projects[projectID]
+	if !ok || !b.visible(p.createdAt) {
+		return newError(http.StatusForbidden, fmt.Sprintf("The caller does not have permission on project %s", projectID))
+	}
+	if p.project.LifecycleState != LifecycleStateDeleteRequested {
+		return newError(http.StatusBadRequest, fmt.Sprintf("Project %s is not pending deletion.", projectID))
+	}
+	p.project.LifecycleState = LifecycleStateActive
+	return nil
+}
+
+// addServiceAccount creates the service account name in p, which is projectID. Callers must hold b.mu.
+func (b *Backend) addServiceAccount(p *project, projectID, name, displayName string) (*iam.ServiceAccount, error) {
+	email := serviceAccountEmail(name, projectID)
+	if _, ok := p.serviceAccounts[email]; ok {
+		return nil, newError(http.StatusConflict, fmt.Sprintf("Service account %s already exists within project %s.", name, projectID))
+	}
+	sa := &serviceAccount{
+		account: &iam.ServiceAccount{
+			DisplayName: displayName,
+			Email:       email,
+			Name:        fmt.Sprintf("projects/%s/serviceAccounts/%s", projectID, email),
+			ProjectId:   projectID,
+			UniqueId:    b.nextID(),
+		},
+		createdAt: b.Now(),
+		keys:      map[string]*iam.ServiceAccountKey{},
+		policy:    &iam.Policy{Etag: nextEtag(""), Version: 1},
+	}
+	p.serviceAccounts[email] = sa
+	account := *sa.account
+	return &account, nil
+}
+
+// setPolicy replaces the IAM policy of p.
+// A policy carrying a stale etag fails with 409, like concurrent writes do in GCP. Callers must hold b.mu.
+func (b *Backend) setPolicy(p *project, policy *cloudresourcemanager.Policy) (*cloudresourcemanager.Policy, error) {
+	if policy.Etag != "" && policy.Etag != p.policy.Etag {
+		return nil, newError(http.StatusConflict, "There were concurrent policy changes. Please retry the whole read-modify-write with exponential backoff.")
+	}
+	updated := copyPolicy(policy)
+	bindings := updated.Bindings[:0]
+	for _, binding := range updated.Bindings {
+		if len(binding.Members) > 0 {
+			bindings = append(bindings, binding)
+		}
+	}
+	updated.Bindings = bindings
+	updated.Etag = nextEtag(p.policy.Etag)
+	p.policy = updated
+	return copyPolicy(updated), nil
+}
+
+// createProject starts the creation of projectID, named displayName or projectID if it is empty. Callers must hold b.mu.
+func (b *Backend) createProject(projectID, displayName, parentFolderID string, labels map[string]string) (*cloudresourcemanager.Operation, error) {
+	if _, ok := b.projects[projectID]; ok {
+		return nil, newError(http.StatusConflict, "Requested entity already exists")
+	}
+	op := &operation{createdAt: b.Now()}
+	if len(b.operationErrors) > 0 {
+		op.err, b.operationErrors = b.operationErrors[0], b.operationErrors[1:]
+	} else {
+		p := b.addProject(projectID, parentFolderID, labels, op.createdAt)
+		if displayName != "" {
+			p.project.Name = displayName
+		}
+	}
+	name := fmt.Sprintf("operations/cp.%s", b.nextID())
+	b.operations[name] = op
+	return op.toOperation(name, b.visible(op.createdAt)), nil
+}
+
+// operation returns the project creation operation with name. Callers must hold b.mu.
+func (b *Backend) operation(name string) (*cloudresourcemanager.Operation, error) {
+	op, ok := b.operations[name]
+	if !ok {
+		return nil, newError(http.StatusNotFound, fmt.Sprintf("Operation %s not found.", name))
+	}
+	return op.toOperation(name, b.visible(op.createdAt)), nil
+}
+
+func (op *operation) toOperation(name string, done bool) *cloudresourcemanager.Operation {
+	o := &cloudresourcemanager.Operation{Name: name, Done: done}
+	if done && op.err != nil {
+		status := *op.err
+		o.Error = &status
+	}
+	return o
+}
+
+// serviceAccount returns the propagated service account with email in projectID. Callers must hold b.mu.
+func (b *Backend) serviceAccount(projectID, email string) (*serviceAccount, error) {
+	p, err := b.activeProject(projectID)
+	if err != nil {
+		return nil, err
+	}
+	sa, ok := p.serviceAccounts[email]
+	if !ok || !b.visible(sa.createdAt) {
+		return nil, newError(http.StatusNotFound, fmt.Sprintf("Service account projects/%s/serviceAccounts/%s does not exist.", projectID, email))
+	}
+	return sa, nil
+}
+
+// setServiceAccountPolicy replaces the IAM policy of sa. A policy carrying a stale etag fails with 409. Callers must hold b.mu.
+func (b *Backend) setServiceAccountPolicy(sa *serviceAccount, policy *iam.Policy) (*iam.Policy, error) {
+	if policy.Etag != "" && policy.Etag != sa.policy.Etag {
+		return nil, newError(http.StatusConflict, "There were concurrent policy changes. Please retry the whole read-modify-write with exponential backoff.")
+	}
+	updated := clone(policy)
+	updated.Etag = nextEtag(sa.policy.Etag)
+	sa.policy = updated
+	return clone(updated), nil
+}
+
+// workloadIdentityPool returns the propagated pool poolID of projectID. Callers must hold b.mu.
+func (b *Backend) workloadIdentityPool(projectID, poolID string) (*workloadIdentityPool, error) {
+	p, err := b.activeProject(projectID)
+	if err != nil {
+		return nil, err
+	}
+	pool, ok := p.pools[poolID]
+	if !ok || !b.visible(pool.createdAt) {
+		return nil, newError(http.StatusNotFound, fmt.Sprintf("Requested entity was not found: %s", workloadIdentityPoolName(projectID, poolID)))
+	}
+	return pool, nil
+}
+
+// addWorkloadIdentityPool creates the pool poolID in projectID. Pool IDs can't be reused, not even after the pool was deleted.
+// Callers must hold b.mu.
+func (b *Backend) addWorkloadIdentityPool(projectID, poolID string, pool *iam.WorkloadIdentityPool) error {
+	p, err := b.activeProject(projectID)
+	if err != nil {
+		return err
+	}
+	if _, ok := p.pools[poolID]; ok {
+		return newError(http.StatusConflict, "Requested entity already exists")
+	}
+	created := clone(pool)
+	created.Name = workloadIdentityPoolName(projectID, poolID)
+	created.State = "ACTIVE"
+	p.pools[poolID] = &workloadIdentityPool{pool: created, createdAt: b.Now(), providers: map[string]*iam.WorkloadIdentityPoolProvider{}}
+	return nil
+}
+
+// undeleteWorkloadIdentityPool restores the deleted pool poolID. Callers must hold b.mu.
+func (b *Backend) undeleteWorkloadIdentityPool(projectID, poolID string) error {
+	pool, err := b.workloadIdentityPool(projectID, poolID)
+	if err != nil {
+		return err
+	}
+	if pool.pool.State != "DELETED" {
+		return newError(http.StatusBadRequest, fmt.Sprintf("Pool %s is not deleted.", pool.pool.Name))
+	}
+	pool.pool.State = "ACTIVE"
+	return nil
+}
+
+// workloadIdentityPoolProvider returns the provider providerID of the pool poolID. Callers must hold b.mu.
+func (b *Backend) workloadIdentityPoolProvider(projectID, poolID, providerID string) (*iam.WorkloadIdentityPoolProvider, error) {
+	pool, err := b.workloadIdentityPool(projectID, poolID)
+	if err != nil {
+		return nil, err
+	}
+	provider, ok := pool.providers[providerID]
+	if !ok {
+		return nil, newError(http.StatusNotFound, fmt.Sprintf("Requested entity was not found: %s/providers/%s", pool.pool.Name, providerID))
+	}
+	return provider, nil
+}
+
+// addWorkloadIdentityPoolProvider creates the provider providerID in the active pool poolID. Callers must hold b.mu.
+func (b *Backend) addWorkloadIdentityPoolProvider(projectID, poolID, providerID string, provider *iam.WorkloadIdentityPoolProvider) error {
+	pool, err := b.workloadIdentityPool(projectID, poolID)
+	if err != nil {
+		return err
+	}
+	if pool.pool.State != "ACTIVE" {
+		return newError(http.StatusBadRequest, fmt.Sprintf("Pool %s is not active.", pool.pool.Name))
+	}
+	if _, ok := pool.providers[providerID]; ok {
+		return newError(http.StatusConflict, "Requested entity already exists")
+	}
+	created := clone(provider)
+	created.Name = fmt.Sprintf("%s/providers/%s", pool.pool.Name, providerID)
+	created.State = "ACTIVE"
+	pool.providers[providerID] = created
+	return nil
+}
+
+// updateWorkloadIdentityPoolProvider copies the fields in updateMask from provider. Callers must hold b.mu.
+func (b *Backend) updateWorkloadIdentityPoolProvider(projectID, poolID, providerID string, provider *iam.WorkloadIdentityPoolProvider, updateMask string) error {
+	existing, err := b.workloadIdentityPoolProvider(projectID, poolID, providerID)
+	if err != nil {
+		return err
+	}
+	update := clone(provider)
+	for _, field := range strings.Split(updateMask, ",") {
+		switch field {
+		case "displayName":
+			existing.DisplayName = update.DisplayName
+		case "disabled":
+			existing.Disabled = update.Disabled
+		case "attributeMapping":
+			existing.AttributeMapping = update.AttributeMapping
+		case "attributeCondition":
+			existing.AttributeCondition = update.AttributeCondition
+		case "oidc":
+			existing.Oidc = update.Oidc
+		default:
+			return newError(http.StatusBadRequest, fmt.Sprintf("Invalid update mask field %q.", field))
+		}
+	}
+	return nil
+}
+
+func (b *Backend) nextID() string {
+	b.sequence++
+	return strconv.Itoa(b.sequence)
+}
+
+func (b *Backend) addProject(projectID, parentFolderID string, labels map[string]string, createdAt time.Time) *project {
+	l := map[string]string{}
+	for k, v := range labels {
+		l[k] = v
+	}
+	p := &project{
+		project: &cloudresourcemanager.Project{
+			Labels:         l,
+			LifecycleState: LifecycleStateActive,
+			Name:           projectID,
+			Parent: &cloudresourcemanager.ResourceId{
+				Id:   parentFolderID,
+				Type: "folder",
+			},
+			ProjectId:     projectID,
+			ProjectNumber: int64(100000 + len(b.projects)),
+		},
+		createdAt:       createdAt,
+		policy:          &cloudresourcemanager.Policy{Etag: nextEtag(""), Version: 1},
+		services:        map[string]bool{},
+		serviceAccounts: map[string]*serviceAccount{},
+		pools:           map[string]*workloadIdentityPool{},
+		tagBindings:     map[string]bool{},
+		billing: &cloudbilling.ProjectBillingInfo{
+			Name:      fmt.Sprintf("projects/%s/billingInfo", projectID),
+			ProjectId: projectID,
+		},
+	}
+	b.projects[projectID] = p
+	return p
+}
+
+// addServiceAccountKey mints a key for sa, whose private key data holds the credentials returned by credentials. Callers must hold b.mu.
+func (b *Backend) addServiceAccountKey(sa *serviceAccount, credentials func(keyID string) string) *iam.ServiceAccountKey {
+	keyID := b.nextID()
+	key := &iam.ServiceAccountKey{
+		Name:           fmt.Sprintf("%s/keys/%s", sa.account.Name, keyID),
+		PrivateKeyData: base64.StdEncoding.EncodeToString([]byte(credentials(keyID))),
+		PrivateKeyType: "TYPE_GOOGLE_CREDENTIALS_FILE",
+		ValidAfterTime: b.Now().UTC().Format(time.RFC3339),
+	}
+	sa.keys[key.Name] = key
+	created := *key
+	return &created
+}
+
+// listServices returns the sorted list of services enabled on p at some point, disabled or not
+func (p *project) listServices() []string {
+	services := []string{}
+	for name := range p.services {
+		services = append(services, name)
+	}
+	sort.Strings(services)
+	return services
+}
+
+func (p *project) enabledServices() []string {
+	services := []string{}
+	for name, enabled := range p.services {
+		if enabled {
+			services = append(services, name)
+		}
+	}
+	sort.Strings(services)
+	return services
+}
+
+func newError(code int, message string) *googleapi.Error {
+	return &googleapi.Error{
+		Code:    code,
+		Message: message,
+		Body:    message,
+	}
+}
+
+// newServiceDisabledError is the error of calling the API of service before it is enabled on projectID
+func newServiceDisabledError(projectID, title, service string) *googleapi.Error {
+	err := newError(http.StatusForbidden, fmt.Sprintf("%s has not been used in project %s before or it is disabled.", title, projectID))
+	err.Details = []interface{}{
+		map[string]interface{}{
+			"@type":  "type.googleapis.com/google.rpc.ErrorInfo",
+			"reason": "SERVICE_DISABLED",
+			"domain": "googleapis.com",
+			"metadata": map[string]interface{}{
+				"consumer": "projects/" + projectID,
+				"service":  service,
+			},
+		},
+	}
+	return err
+}
+
+func workloadIdentityPoolName(projectID, poolID string) string {
+	return fmt.Sprintf("projects/%s/locations/global/workloadIdentityPools/%s", projectID, poolID)
+}
+
+// clone deep copies v through its JSON form, which is all the Google API types carry
+func clone[T any](v *T) *T {
+	data, _ := json.Marshal(v)
+	c := new(T)
+	_ = json.Unmarshal(data, c)
+	return c
+}
+
+func copyProject(p *cloudresourcemanager.Project) *cloudresourcemanager.Project {
+	c := *p
+	c.Labels = map[string]string{}
+	for k, v := range p.Labels {
+		c.Labels[k] = v
+	}
+	if p.Parent != nil {
+		parent := *p.Parent
+		c.Parent = &parent
+	}
+	return &c
+}
+
+func copyPolicy(p *cloudresourcemanager.Policy) *cloudresourcemanager.Policy {
+	c := *p
+	c.Bindings = make([]*cloudresourcemanager.Binding, 0, len(p.Bindings))
+	for _, binding := range p.Bindings {
+		c.Bindings = append(c.Bindings, &cloudresourcemanager.Binding{
+			Role:    binding.Role,
+			Members: append([]string{}, binding.Members...),
+		})
+	}
+	return &c
+}
+
+// nextEtag derives a new opaque etag, so stale read-modify-write cycles are detected.
+func nextEtag(etag string) string {
+	raw, _ := base64.StdEncoding.DecodeString(etag)
+	n, _ := strconv.Atoi(strings.TrimPrefix(string(raw), "etag-"))
+	return base64.StdEncoding.EncodeToString([]byte(fmt.Sprintf("etag-%d", n+1)))
+}
@@ -0,0 +1,216 @@
+package fake
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/api/googleapi"
+
+	operrors "github.com/openshift/gcp-project-operator/pkg/util/errors"
+
+	cloudresourcemanager "google.golang.org/api/cloudresourcemanager/v1"
+)
+
+func TestProjectLifecycle(t *testing.T) {
+	backend := NewBackend()
+	client := backend.NewClient("o-12345678")
+
+	_, err := client.CreateProject(context.TODO(), "folder", "", map[string]string{"claim_name": "claim"})
+	assert.NoError(t, err)
+	_, err = client.CreateProject(context.TODO(), "folder", "", map[string]string{"claim_name": "claim"})
+	assertErrorCode(t, http.StatusConflict, err)
+
+	project, err := client.GetProject(context.TODO(), "o-12345678")
+	assert.NoError(t, err)
+	assert.Equal(t, LifecycleStateActive, project.LifecycleState)
+	projects, err := client.ListProjects(context.TODO(), "folder")
+	assert.NoError(t, err)
+	assert.Len(t, projects, 1)
+	projects, err = client.ListProjects(context.TODO(), "other-folder")
+	assert.NoError(t, err)
+	assert.Empty(t, projects)
+	assert.Equal(t, map[string]string{"claim_name": "claim"}, project.Labels)
+	assert.Equal(t, "folder", project.Parent.Id)
+	// the display name defaults to the project ID
+	assert.Equal(t, "o-12345678", project.Name)
+
+	_, err = client.DeleteProject(context.TODO(), "folder")
+	assert.NoError(t, err)
+
+	project, _ = backend.Project("o-12345678")
+	assert.Equal(t, LifecycleStateDeleteRequested, project.LifecycleState)
+
+	_, err = client.GetIamPolicy(context.TODO(), "o-12345678")
+	assertErrorCode(t, http.StatusForbidden, err)
+
+	assert.NoError(t, client.UndeleteProject(context.TODO(), "o-12345678"))
+	project, _ = backend.Project("o-12345678")
+	assert.Equal(t, LifecycleStateActive, project.LifecycleState)
+	// only projects pending deletion can be restored
+	assertErrorCode(t, http.StatusBadRequest, client.UndeleteProject(context.TODO(), "o-12345678"))
+	assertErrorCode(t, http.StatusForbidden, client.UndeleteProject(context.TODO(), "o-87654321"))
+}
+
+func TestServiceAccounts(t *testing.T) {
+	backend := NewBackend()
+	backend.AddProject("ccs-project", "folder", nil)
+	client := backend.NewClient("ccs-project")
+
+	_, err := client.GetServiceAccount(context.TODO(), "osd-managed-admin")
+	assertErrorCode(t, http.StatusNotFound, err)
+
+	sa, err := client.CreateServiceAccount(context.TODO(), "osd-managed-admin", "osd-managed-admin")
+	assert.NoError(t, err)
+	assert.Equal(t, "osd-managed-admin@ccs-project.iam.gserviceaccount.com", sa.Email)
+
+	_, err = client.CreateServiceAccount(context.TODO(), "osd-managed-admin", "osd-managed-admin")
+	assertErrorCode(t, http.StatusConflict, err)
+
+	key, err := client.CreateServiceAccountKey(context.TODO(), sa.Email)
+	assert.NoError(t, err)
+	assert.NotEmpty(t, key.PrivateKeyData)
+	assert.Equal(t, []string{key.Name}, backend.ServiceAccountKeys("ccs-project", sa.Email))
+
+	assert.NoError(t, client.DeleteServiceAccount(context.TODO(), sa.Email))
+	assert.Empty(t, backend.ServiceAccounts("ccs-project"))
+}
+
+func TestLabelsAndTags(t *testing.T) {
+	backend := NewBackend()
+	backend.AddProject("o-12345678", "folder", map[string]string{"claim_name": "claim", "orphaned": "true"})
+	production := backend.AddTagValue("123456789012/environment/production")
+	assert.Equal(t, production, backend.AddTagValue("123456789012/environment/production"))
+	client := backend.NewClient("o-12345678")
+
+	assert.NoError(t, client.UpdateProjectLabels(context.TODO(), "o-12345678", map[string]string{"team": "sre"}, []string{"orphaned"}))
+	project, err := client.GetProject(context.TODO(), "o-12345678")
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{"claim_name": "claim", "team": "sre"}, project.Labels)
+
+	tagValue, err := client.GetTagValue(context.TODO(), "123456789012/environment/production")
+	assert.NoError(t, err)
+	assert.Equal(t, production, tagValue.Name)
+	assert.Equal(t, "tagKeys/environment", tagValue.Parent)
+	assert.NoError(t, client.CreateTagBinding(context.TODO(), project.ProjectNumber, production))
+	assert.NoError(t, client.CreateTagBinding(context.TODO(), project.ProjectNumber, production))
+	bindings, err := client.ListTagBindings(context.TODO(), project.ProjectNumber)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{production}, bindings)
+
+	_, err = client.GetTagValue(context.TODO(), "123456789012/environment/staging")
+	assertErrorCode(t, http.StatusForbidden, err)
+	_, err = client.ListTagBindings(context.TODO(), 42)
+	assertErrorCode(t, http.StatusForbidden, err)
+}
+
+func TestIamPolicyEtag(t *testing.T) {
+	backend := NewBackend()
+	backend.AddProject("ccs-project", "folder", nil)
+	client := backend.NewClient("ccs-project")
+
+	policy, err := client.GetIamPolicy(context.TODO(), "ccs-project")
+	assert.NoError(t, err)
+	stale := policy.Etag
+
+	policy.Bindings = []*cloudresourcemanager.Binding{{Role: "roles/viewer", Members: []string{"group:sre@example.com"}}}
+	updated, err := client.SetIamPolicy(context.TODO(), &cloudresourcemanager.SetIamPolicyRequest{Policy: policy})
+	assert.NoError(t, err)
+	assert.NotEqual(t, stale, updated.Etag)
+
+	policy.Etag = stale
+	_, err = client.SetIamPolicy(context.TODO(), &cloudresourcemanager.SetIamPolicyRequest{Policy: policy})
+	assertErrorCode(t, http.StatusConflict, err)
+}
+
+func TestServicesBillingAndZones(t *testing.T) {
+	backend := NewBackend()
+	backend.AddProject("ccs-project", "folder", nil)
+	client := backend.NewClient("ccs-project")
+
+	_, err := client.ListAvailabilityZones(context.TODO(), "ccs-project", "us-east1")
+	assertErrorCode(t, http.StatusForbidden, err)
+	assert.ErrorContains(t, err, "googleapi: Error 403: Compute Engine API has not been used in project")
+	service, disabled := operrors.IsServiceDisabled(err)
+	assert.True(t, disabled)
+	assert.Equal(t, "compute.googleapis.com", service)
+
+	assert.NoError(t, client.EnableAPI(context.TODO(), "ccs-project", "compute.googleapis.com"))
+	zones, err := client.ListAvailabilityZones(context.TODO(), "ccs-project", "us-east1")
+	assert.NoError(t, err)
+	assert.Equal(t, DefaultZones["us-east1"], zones)
+
+	err = client.CreateCloudBillingAccount(context.TODO(), "ccs-project", "ABCDEF-123456")
+	assertErrorCode(t, http.StatusForbidden, err)
+
+	assert.NoError(t, client.EnableAPI(context.TODO(), "ccs-project", "cloudbilling.googleapis.com"))
+	assert.NoError(t, client.CreateCloudBillingAccount(context.TODO(), "ccs-project", "ABCDEF-123456"))
+	info, _ := backend.BillingInfo("ccs-project")
+	assert.Equal(t, "billingAccounts/ABCDEF-123456", info.BillingAccountName)
+	assert.True(t, info.BillingEnabled)
+
+	apis, err := client.ListAPIs(context.TODO(), "ccs-project")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"cloudbilling.googleapis.com", "compute.googleapis.com"}, apis)
+}
+
+func TestInjectedErrors(t *testing.T) {
+	backend := NewBackend()
+	backend.AddProject("ccs-project", "folder", nil)
+	client := backend.NewClient("ccs-project")
+	backend.InjectError("EnableAPI", http.StatusForbidden, 2)
+
+	assertErrorCode(t, http.StatusForbidden, client.EnableAPI(context.TODO(), "ccs-project", "iam.googleapis.com"))
+	assertErrorCode(t, http.StatusForbidden, client.EnableAPI(context.TODO(), "ccs-project", "iam.googleapis.com"))
+	assert.NoError(t, client.EnableAPI(context.TODO(), "ccs-project", "iam.googleapis.com"))
+}
+
+func TestPropagationDelay(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	backend := NewBackend()
+	backend.Now = func() time.Time { return now }
+	backend.SetPropagationDelay(time.Minute)
+	client := backend.NewClient("o-12345678")
+
+	operation, err := client.CreateProject(context.TODO(), "folder", "", nil)
+	assert.NoError(t, err)
+	assert.False(t, operation.Done)
+	_, err = client.GetProject(context.TODO(), "o-12345678")
+	assertErrorCode(t, http.StatusForbidden, err)
+
+	now = now.Add(time.Minute)
+	operation, err = client.GetOperation(context.TODO(), operation.Name)
+	assert.NoError(t, err)
+	assert.True(t, operation.Done)
+	_, err = client.GetProject(context.TODO(), "o-12345678")
+	assert.NoError(t, err)
+}
+
+func TestOperationError(t *testing.T) {
+	backend := NewBackend()
+	backend.InjectOperationError(8, "The project quota has been exceeded.")
+	client := backend.NewClient("o-12345678")
+
+	operation, err := client.CreateProject(context.TODO(), "folder", "", nil)
+	assert.NoError(t, err)
+	operation, err = client.GetOperation(context.TODO(), operation.Name)
+	assert.NoError(t, err)
+	assert.True(t, operation.Done)
+	assert.Equal(t, "The project quota has been exceeded.", operation.Error.Message)
+	_, ok := backend.Project("o-12345678")
+	assert.False(t, ok)
+
+	_, err = client.GetOperation(context.TODO(), "operations/unknown")
+	assertErrorCode(t, http.StatusNotFound, err)
+}
+
+func assertErrorCode(t *testing.T, code int, err error) {
+	t.Helper()
+	var ae *googleapi.Error
+	if assert.True(t, errors.As(err, &ae), "expected a googleapi.Error, got %v", err) {
+		assert.Equal(t, code, ae.Code)
+	}
+}
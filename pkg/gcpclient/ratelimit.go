@@ -0,0 +1,299 @@
+package gcpclient
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+	"google.golang.org/api/googleapi"
+
+	"github.com/openshift/gcp-project-operator/pkg/util"
+	operrors "github.com/openshift/gcp-project-operator/pkg/util/errors"
+)
+
+// API families of the GCP requests, each is rate limited separately per credential
+const (
+	FamilyCloudResourceManager = "cloudresourcemanager"
+	FamilyIAM                  = "iam"
+	FamilyServiceUsage         = "serviceusage"
+	FamilyCloudBilling         = "cloudbilling"
+	FamilyCompute              = "compute"
+)
+
+// Families are the API families called by the client
+var Families = []string{FamilyCloudResourceManager, FamilyIAM, FamilyServiceUsage, FamilyCloudBilling, FamilyCompute}
+
+// RateLimit is the sustained rate and the burst of the requests to an API family with a single credential
+type RateLimit struct {
+	// QPS is the number of requests per second, 0 doesn't limit the requests
+	QPS   float64
+	Burst int
+}
+
+// RateLimits configures a RateLimiter
+type RateLimits struct {
+	// Default applies to the API families without a limit of their own
+	Default RateLimit
+	// Families overrides Default per API family
+	Families map[string]RateLimit
+	// MaxRetries bounds how often a request rejected for exceeding a quota is retried
+	MaxRetries int
+	// BaseDelay is the backoff of the first retry if GCP didn't send a Retry-After,
+	// it doubles with every further retry up to MaxDelay
+	BaseDelay time.Duration
+	MaxDelay  time.Duration
+}
+
+// DefaultRateLimits stays well below the default per-project quotas of the GCP APIs
+var DefaultRateLimits = RateLimits{
+	Default:    RateLimit{QPS: 10, Burst: 20},
+	MaxRetries: 5,
+	BaseDelay:  time.Second,
+	MaxDelay:   32 * time.Second,
+}
+
+// defaultRateLimiter is shared by the clients built without WithRateLimiter
+var defaultRateLimiter = NewRateLimiter(DefaultRateLimits)
+
+// ParseRateLimits parses per API family limits, e.g. "compute=5:10,iam=20:40" limits compute
+// to 5 requests per second with a burst of 10, and IAM to 20 requests per second with a burst of 40.
+func ParseRateLimits(s string) (map[string]RateLimit, error) {
+	limits := map[string]RateLimit{}
+	if s == "" {
+		return limits, nil
+	}
+	for _, entry := range strings.Split(s, ",") {
+		family, limit, ok := strings.Cut(strings.TrimSpace(entry), "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid rate limit %q, expected family=qps:burst", entry)
+		}
+		if !slices.Contains(Families, family) {
+			return nil, fmt.Errorf("unknown API family %q, expected one of %s", family, strings.Join(Families, ", "))
+		}
+		qps, burst, ok := strings.Cut(limit, ":")
+		if !ok {
+			return nil, fmt.Errorf("invalid rate limit %q, expected family=qps:burst", entry)
+		}
+		var rateLimit RateLimit
+		var err error
+		if rateLimit.QPS, err = strconv.ParseFloat(qps, 64); err != nil || rateLimit.QPS < 0 {
+			return nil, fmt.Errorf("invalid QPS %q of API family %s", qps, family)
+		}
+		if rateLimit.Burst, err = strconv.Atoi(burst); err != nil || rateLimit.Burst < 0 {
+			return nil, fmt.Errorf("invalid burst %q of API family %s", burst, family)
+		}
+		limits[family] = rateLimit
+	}
+	return limits, nil
+}
+
+// RateLimiter limits the GCP requests of all the clients it is passed to, per credential and API family.
+// The clients are rebuilt whenever their credentials change, so it must outlive them to be of any use.
+type RateLimiter struct {
+	limits RateLimits
+
+	mu      sync.Mutex
+	buckets map[bucketKey]*bucket
+}
+
+// NewRateLimiter returns a RateLimiter enforcing limits
+func NewRateLimiter(limits RateLimits) *RateLimiter {
+	return &RateLimiter{
+		limits:  limits,
+		buckets: map[bucketKey]*bucket{},
+	}
+}
+
+type bucketKey struct {
+	credential string
+	family     string
+}
+
+// bucket returns the bucket of the requests to family with credential
+func (l *RateLimiter) bucket(credential, family string) *bucket {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	key := bucketKey{credential: credential, family: family}
+	b, ok := l.buckets[key]
+	if !ok {
+		limit, ok := l.limits.Families[family]
+		if !ok {
+			limit = l.limits.Default
+		}
+		b = newBucket(limit)
+		l.buckets[key] = b
+	}
+	return b
+}
+
+// transport returns base limited to the rate of the requests to family with credential
+func (l *RateLimiter) transport(base http.RoundTripper, credential, family string) http.RoundTripper {
+	return &rateLimitedTransport{
+		base:   base,
+		bucket: l.bucket(credential, family),
+		limits: l.limits,
+		family: family,
+	}
+}
+
+// bucket is a token bucket, which additionally holds back all requests while GCP asks to back off
+type bucket struct {
+	limiter *rate.Limiter
+
+	mu          sync.Mutex
+	pausedUntil time.Time
+}
+
+func newBucket(limit RateLimit) *bucket {
+	if limit.QPS == 0 {
+		return &bucket{limiter: rate.NewLimiter(rate.Inf, 0)}
+	}
+	return &bucket{limiter: rate.NewLimiter(rate.Limit(limit.QPS), max(limit.Burst, 1))}
+}
+
+// wait blocks until a request may be sent, or ctx is done
+func (b *bucket) wait(ctx context.Context) error {
+	b.mu.Lock()
+	paused := time.Until(b.pausedUntil)
+	b.mu.Unlock()
+	if paused > 0 {
+		if err := util.Sleep(ctx, paused); err != nil {
+			return err
+		}
+	}
+	return b.limiter.Wait(ctx)
+}
+
+// pause holds back the requests for d
+func (b *bucket) pause(d time.Duration) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if until := time.Now().Add(d); until.After(b.pausedUntil) {
+		b.pausedUntil = until
+	}
+}
+
+// rateLimitedTransport waits for the rate limit of its bucket before every request, and retries
+// the requests GCP rejected for exceeding a quota, after the Retry-After or a jittered exponential backoff.
+// The retries pause the whole bucket, as the other requests would exceed the quota as well.
+type rateLimitedTransport struct {
+	base   http.RoundTripper
+	bucket *bucket
+	limits RateLimits
+	family string
+}
+
+// RoundTrip implements http.RoundTripper
+func (t *rateLimitedTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	ctx := req.Context()
+	for retry := 0; ; retry++ {
+		if err := t.bucket.wait(ctx); err != nil {
+			return nil, err
+		}
+		resp, err := t.base.RoundTrip(req)
+		if err != nil || retry >= t.limits.MaxRetries || !rewindable(req) {
+			return resp, err
+		}
+		exceeded, err := quotaExceeded(resp)
+		if err != nil || !exceeded {
+			return resp, err
+		}
+
+		delay, ok := retryAfter(resp)
+		if !ok {
+			delay = t.backoff(retry)
+		}
+		_, _ = io.Copy(io.Discard, resp.Body)
+		_ = resp.Body.Close()
+		log.V(1).Info("GCP quota exceeded, retrying", "family", t.family, "url", req.URL.Redacted(), "code", resp.StatusCode, "delay", delay.String())
+		t.bucket.pause(delay)
+
+		req = req.Clone(ctx)
+		if req.GetBody != nil {
+			if req.Body, err = req.GetBody(); err != nil {
+				return nil, err
+			}
+		}
+	}
+}
+
+// backoff returns the jittered delay of retry, between half and all of the exponential backoff
+func (t *rateLimitedTransport) backoff(retry int) time.Duration {
+	delay := t.limits.BaseDelay << retry
+	if delay > t.limits.MaxDelay || delay <= 0 {
+		delay = t.limits.MaxDelay
+	}
+	if delay < 2 {
+		return delay
+	}
+	return delay/2 + rand.N(delay/2)
+}
+
+// rewindable returns true if the body of req can be sent again
+func rewindable(req *http.Request) bool {
+	return req.Body == nil || req.Body == http.NoBody || req.GetBody != nil
+}
+
+// quotaExceeded returns true if GCP rejected the request for exceeding a quota or rate limit,
+// i.e. with 429 RESOURCE_EXHAUSTED, or with a 403 some APIs return for rate limits.
+// The body of resp is left for the caller to read.
+func quotaExceeded(resp *http.Response) (bool, error) {
+	switch resp.StatusCode {
+	case http.StatusTooManyRequests:
+		return true, nil
+	case http.StatusForbidden:
+	default:
+		return false, nil
+	}
+	body, err := io.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+	if err != nil {
+		return false, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+	return operrors.IsQuotaExceeded(googleapi.CheckResponseWithBody(resp, body)), nil
+}
+
+// retryAfter returns the delay of the Retry-After header of resp, which is either seconds or an HTTP date
+func retryAfter(resp *http.Response) (time.Duration, bool) {
+	value := resp.Header.Get("Retry-After")
+	if value == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(value); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second, true
+	}
+	if date, err := http.ParseTime(value); err == nil {
+		return max(time.Until(date), 0), true
+	}
+	return 0, false
+}
+
+// credentialID identifies the principal of the JSON credentials authJSON,
+// which the quotas of GCP are accounted to.
+func credentialID(authJSON []byte) string {
+	var f struct {
+		ClientEmail                    string `json:"client_email"`
+		ServiceAccountImpersonationURL string `json:"service_account_impersonation_url"`
+		Audience                       string `json:"audience"`
+	}
+	if err := json.Unmarshal(authJSON, &f); err != nil {
+		return ""
+	}
+	for _, id := range []string{f.ClientEmail, f.ServiceAccountImpersonationURL, f.Audience} {
+		if id != "" {
+			return id
+		}
+	}
+	return ""
+}
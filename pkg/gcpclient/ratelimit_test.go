@@ -0,0 +1,172 @@
+package gcpclient
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testRateLimits = RateLimits{
+	MaxRetries: 2,
+	BaseDelay:  time.Millisecond,
+	MaxDelay:   10 * time.Millisecond,
+}
+
+// rejectingServer rejects the first rejections requests with code, body and header, and counts all requests
+func rejectingServer(t *testing.T, rejections int32, code int, body string, header http.Header) (*httptest.Server, *atomic.Int32) {
+	t.Helper()
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		payload, _ := io.ReadAll(r.Body)
+		assert.Equal(t, "payload", string(payload))
+		if requests.Add(1) <= rejections {
+			for key, values := range header {
+				w.Header()[key] = values
+			}
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(code)
+			_, _ = io.WriteString(w, body)
+			return
+		}
+		_, _ = io.WriteString(w, "{}")
+	}))
+	t.Cleanup(server.Close)
+	return server, &requests
+}
+
+func post(t *testing.T, transport http.RoundTripper, url string) *http.Response {
+	t.Helper()
+	req, err := http.NewRequestWithContext(context.TODO(), http.MethodPost, url, strings.NewReader("payload"))
+	require.NoError(t, err)
+	resp, err := transport.RoundTrip(req)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = resp.Body.Close() })
+	return resp
+}
+
+func TestRateLimitedTransportRetries(t *testing.T) {
+	const (
+		resourceExhausted = `{"error": {"code": 429, "message": "Quota exceeded", "status": "RESOURCE_EXHAUSTED"}}`
+		rateLimitExceeded = `{"error": {"code": 403, "message": "Rate Limit Exceeded", "errors": [{"reason": "rateLimitExceeded"}]}}`
+		permissionDenied  = `{"error": {"code": 403, "message": "The caller does not have permission", "errors": [{"reason": "forbidden"}]}}`
+	)
+	tests := []struct {
+		name             string
+		rejections       int32
+		code             int
+		body             string
+		expectedCode     int
+		expectedRequests int32
+	}{
+		{name: "resource exhausted", rejections: 2, code: http.StatusTooManyRequests, body: resourceExhausted, expectedCode: http.StatusOK, expectedRequests: 3},
+		{name: "rate limit exceeded", rejections: 1, code: http.StatusForbidden, body: rateLimitExceeded, expectedCode: http.StatusOK, expectedRequests: 2},
+		{name: "too many retries", rejections: 5, code: http.StatusTooManyRequests, body: resourceExhausted, expectedCode: http.StatusTooManyRequests, expectedRequests: 3},
+		{name: "permission denied", rejections: 1, code: http.StatusForbidden, body: permissionDenied, expectedCode: http.StatusForbidden, expectedRequests: 1},
+		{name: "not found", rejections: 1, code: http.StatusNotFound, body: `{"error": {"code": 404}}`, expectedCode: http.StatusNotFound, expectedRequests: 1},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			server, requests := rejectingServer(t, test.rejections, test.code, test.body, nil)
+			transport := NewRateLimiter(testRateLimits).transport(http.DefaultTransport, "sa@project.iam.gserviceaccount.com", FamilyIAM)
+
+			resp := post(t, transport, server.URL)
+			assert.Equal(t, test.expectedCode, resp.StatusCode)
+			assert.Equal(t, test.expectedRequests, requests.Load())
+			// the body of the final response is intact
+			body, err := io.ReadAll(resp.Body)
+			assert.NoError(t, err)
+			if test.expectedCode == http.StatusOK {
+				assert.Equal(t, "{}", string(body))
+			} else {
+				assert.Equal(t, test.body, string(body))
+			}
+		})
+	}
+}
+
+func TestRateLimitedTransportRetryAfter(t *testing.T) {
+	server, requests := rejectingServer(t, 1, http.StatusTooManyRequests, "{}", http.Header{"Retry-After": {"1"}})
+	limiter := NewRateLimiter(testRateLimits)
+	transport := limiter.transport(http.DefaultTransport, "sa@project.iam.gserviceaccount.com", FamilyCompute)
+
+	start := time.Now()
+	resp := post(t, transport, server.URL)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, int32(2), requests.Load())
+	assert.GreaterOrEqual(t, time.Since(start), time.Second)
+
+	// the other requests of the credential to the API family were held back as well
+	assert.False(t, limiter.bucket("sa@project.iam.gserviceaccount.com", FamilyCompute).pausedUntil.IsZero())
+	assert.True(t, limiter.bucket("sa@project.iam.gserviceaccount.com", FamilyIAM).pausedUntil.IsZero())
+	assert.True(t, limiter.bucket("other@project.iam.gserviceaccount.com", FamilyCompute).pausedUntil.IsZero())
+}
+
+func TestRateLimiterBuckets(t *testing.T) {
+	limiter := NewRateLimiter(RateLimits{
+		Default:  RateLimit{QPS: 10, Burst: 20},
+		Families: map[string]RateLimit{FamilyCompute: {QPS: 1, Burst: 2}},
+	})
+
+	compute := limiter.bucket("sa", FamilyCompute)
+	assert.Same(t, compute, limiter.bucket("sa", FamilyCompute))
+	assert.NotSame(t, compute, limiter.bucket("other", FamilyCompute))
+	assert.Equal(t, 2, compute.limiter.Burst())
+	assert.Equal(t, 20, limiter.bucket("sa", FamilyIAM).limiter.Burst())
+
+	// the burst is spent without waiting, the next request waits for the rate
+	ctx, cancel := context.WithTimeout(context.TODO(), 100*time.Millisecond)
+	defer cancel()
+	assert.NoError(t, compute.wait(ctx))
+	assert.NoError(t, compute.wait(ctx))
+	assert.Error(t, compute.wait(ctx))
+}
+
+func TestParseRateLimits(t *testing.T) {
+	tests := []struct {
+		name        string
+		limits      string
+		expected    map[string]RateLimit
+		expectedErr bool
+	}{
+		{name: "empty", limits: "", expected: map[string]RateLimit{}},
+		{name: "single family", limits: "compute=5:10", expected: map[string]RateLimit{FamilyCompute: {QPS: 5, Burst: 10}}},
+		{
+			name:     "several families",
+			limits:   "iam=0.5:1, cloudresourcemanager=0:0",
+			expected: map[string]RateLimit{FamilyIAM: {QPS: 0.5, Burst: 1}, FamilyCloudResourceManager: {}},
+		},
+		{name: "unknown family", limits: "storage=5:10", expectedErr: true},
+		{name: "missing burst", limits: "compute=5", expectedErr: true},
+		{name: "missing limit", limits: "compute", expectedErr: true},
+		{name: "negative QPS", limits: "compute=-1:10", expectedErr: true},
+		{name: "invalid burst", limits: "compute=5:many", expectedErr: true},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			limits, err := ParseRateLimits(test.limits)
+			if test.expectedErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, test.expected, limits)
+		})
+	}
+}
+
+func TestCredentialID(t *testing.T) {
+	assert.Equal(t, "sa@project.iam.gserviceaccount.com",
+		credentialID([]byte(`{"type": "service_account", "client_email": "sa@project.iam.gserviceaccount.com"}`)))
+	assert.Equal(t, "https://iamcredentials.googleapis.com/v1/projects/-/serviceAccounts/sa@project.iam.gserviceaccount.com:generateAccessToken",
+		credentialID([]byte(`{"type": "external_account", "audience": "//iam.googleapis.com/pool", "service_account_impersonation_url": "https://iamcredentials.googleapis.com/v1/projects/-/serviceAccounts/sa@project.iam.gserviceaccount.com:generateAccessToken"}`)))
+	assert.Equal(t, "//iam.googleapis.com/pool", credentialID([]byte(`{"type": "external_account", "audience": "//iam.googleapis.com/pool"}`)))
+	assert.Equal(t, "", credentialID([]byte(`not json`)))
+}
@@ -0,0 +1,70 @@
+package gcpclient
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/openshift/gcp-project-operator/pkg/metrics"
+	"github.com/openshift/gcp-project-operator/pkg/tracing"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+type callKey struct{}
+
+// call is a single call of a Client method, which may send several requests
+type call struct {
+	method string
+	// span is the span of the call, the requests themselves may be traced in child spans
+	span trace.Span
+	// failure describes why the last request failed, it is empty if it succeeded
+	failure string
+}
+
+// withCall returns a context labelling the requests made with it as requests of c
+func withCall(ctx context.Context, c *call) context.Context {
+	return context.WithValue(ctx, callKey{}, c)
+}
+
+// end ends the span of c, which failed if its last request did
+func (c *call) end() {
+	if c.failure != "" {
+		c.span.SetStatus(codes.Error, c.failure)
+	}
+	c.span.End()
+}
+
+// instrumentedTransport records the count and latency of the requests of every Client method,
+// and their status on the span of the method
+type instrumentedTransport struct {
+	base http.RoundTripper
+}
+
+// RoundTrip implements http.RoundTripper
+func (t *instrumentedTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	c, ok := req.Context().Value(callKey{}).(*call)
+	if !ok {
+		c = &call{span: trace.SpanFromContext(req.Context())}
+	}
+
+	start := time.Now()
+	resp, err := t.base.RoundTrip(req)
+	code := metrics.RequestCodeError
+	if err != nil {
+		c.span.RecordError(err)
+		c.failure = err.Error()
+	} else {
+		code = strconv.Itoa(resp.StatusCode)
+		c.span.SetAttributes(tracing.StatusCodeKey.Int(resp.StatusCode))
+		c.failure = ""
+		if resp.StatusCode >= http.StatusBadRequest {
+			c.failure = http.StatusText(resp.StatusCode)
+		}
+	}
+
+	metrics.GCPAPIRequests.WithLabelValues(c.method, code).Inc()
+	metrics.GCPAPIRequestDuration.WithLabelValues(c.method, code).Observe(time.Since(start).Seconds())
+	return resp, err
+}
@@ -0,0 +1,137 @@
+// Package metrics defines the Prometheus metrics of the operator, which are served by the metrics endpoint of the Manager
+package metrics
+
+import (
+	"context"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	ctrlmetrics "sigs.k8s.io/controller-runtime/pkg/metrics"
+
+	gcpv1alpha1 "github.com/openshift/gcp-project-operator/api/v1alpha1"
+)
+
+var log = logf.Log.WithName("metrics")
+
+// listTimeout bounds listing the ProjectClaims of a scrape
+const listTimeout = 10 * time.Second
+
+var (
+	// ProjectClaimReadySeconds observes the time from the creation of a ProjectClaim until it is Ready
+	ProjectClaimReadySeconds = prometheus.NewHistogram(prometheus.HistogramOpts{
+		Name:    "gcp_project_operator_projectclaim_ready_seconds",
+		Help:    "Time from the creation of a ProjectClaim until it is Ready.",
+		Buckets: []float64{30, 60, 120, 300, 600, 900, 1800, 3600, 7200},
+	})
+	// ProjectClaimDeletionSeconds observes the time from the deletion of a ProjectClaim until its finalizer is removed
+	ProjectClaimDeletionSeconds = prometheus.NewHistogram(prometheus.HistogramOpts{
+		Name:    "gcp_project_operator_projectclaim_deletion_seconds",
+		Help:    "Time from the deletion of a ProjectClaim until its finalizer is removed.",
+		Buckets: []float64{5, 15, 30, 60, 120, 300, 600, 1800, 3600},
+	})
+	// GCPAPIRequests counts the HTTP requests to the GCP APIs per gcpclient.Client method and HTTP status code
+	GCPAPIRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "gcp_project_operator_gcp_api_requests_total",
+		Help: "Number of HTTP requests to the GCP APIs, by client method and HTTP status code.",
+	}, []string{"method", "code"})
+	// GCPAPIRequestDuration observes the latency of the HTTP requests to the GCP APIs per gcpclient.Client method and HTTP status code
+	GCPAPIRequestDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "gcp_project_operator_gcp_api_request_duration_seconds",
+		Help:    "Latency of the HTTP requests to the GCP APIs, by client method and HTTP status code.",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"method", "code"})
+	// EnableAPIRetries counts the retries of enabling a GCP service API on a project
+	EnableAPIRetries = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "gcp_project_operator_enable_api_retries_total",
+		Help: "Number of retries enabling a GCP service API on a project, by API.",
+	}, []string{"api"})
+	// IAMPolicyConflicts counts the IAM policy updates that failed with 409, because the policy was changed concurrently
+	IAMPolicyConflicts = prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "gcp_project_operator_iam_policy_conflicts_total",
+		Help: "Number of IAM policy updates that conflicted with a concurrent change.",
+	})
+)
+
+// RequestCodeError is the code label of GCP API requests without an HTTP response, e.g. because they timed out
+const RequestCodeError = "error"
+
+func init() {
+	ctrlmetrics.Registry.MustRegister(
+		ProjectClaimReadySeconds,
+		ProjectClaimDeletionSeconds,
+		GCPAPIRequests,
+		GCPAPIRequestDuration,
+		EnableAPIRetries,
+		IAMPolicyConflicts,
+	)
+}
+
+// ObserveProjectClaimReady records the time claim took to become Ready
+func ObserveProjectClaimReady(claim *gcpv1alpha1.ProjectClaim) {
+	ProjectClaimReadySeconds.Observe(time.Since(claim.CreationTimestamp.Time).Seconds())
+}
+
+// ObserveProjectClaimDeleted records the time the deletion of claim took
+func ObserveProjectClaimDeleted(claim *gcpv1alpha1.ProjectClaim) {
+	if claim.DeletionTimestamp == nil {
+		return
+	}
+	ProjectClaimDeletionSeconds.Observe(time.Since(claim.DeletionTimestamp.Time).Seconds())
+}
+
+var projectClaimsDesc = prometheus.NewDesc(
+	"gcp_project_operator_projectclaims",
+	"Number of ProjectClaims, by state.",
+	[]string{"state"}, nil,
+)
+
+// claimStates are reported even if no ProjectClaim is in them, so alerts don't depend on the series existing
+var claimStates = []gcpv1alpha1.ClaimStatus{
+	gcpv1alpha1.ClaimStatusPending,
+	gcpv1alpha1.ClaimStatusPendingProject,
+	gcpv1alpha1.ClaimStatusReady,
+	gcpv1alpha1.ClaimStatusError,
+	gcpv1alpha1.ClaimStatusVerification,
+}
+
+// projectClaimCollector counts the ProjectClaims per state at scrape time
+type projectClaimCollector struct {
+	reader client.Reader
+}
+
+// NewProjectClaimCollector returns a collector of the number of ProjectClaims per state,
+// which lists them with reader, e.g. the cache of the Manager.
+func NewProjectClaimCollector(reader client.Reader) prometheus.Collector {
+	return &projectClaimCollector{reader: reader}
+}
+
+// Describe implements prometheus.Collector
+func (c *projectClaimCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- projectClaimsDesc
+}
+
+// Collect implements prometheus.Collector
+func (c *projectClaimCollector) Collect(ch chan<- prometheus.Metric) {
+	ctx, cancel := context.WithTimeout(context.Background(), listTimeout)
+	defer cancel()
+
+	claims := &gcpv1alpha1.ProjectClaimList{}
+	if err := c.reader.List(ctx, claims); err != nil {
+		log.Error(err, "could not list ProjectClaims")
+		ch <- prometheus.NewInvalidMetric(projectClaimsDesc, err)
+		return
+	}
+
+	counts := map[gcpv1alpha1.ClaimStatus]int{}
+	for _, state := range claimStates {
+		counts[state] = 0
+	}
+	for _, claim := range claims.Items {
+		counts[claim.Status.State]++
+	}
+	for state, count := range counts {
+		ch <- prometheus.MustNewConstMetric(projectClaimsDesc, prometheus.GaugeValue, float64(count), string(state))
+	}
+}
@@ -0,0 +1,74 @@
+package metrics
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	fakekubeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	gcpv1alpha1 "github.com/openshift/gcp-project-operator/api/v1alpha1"
+)
+
+func newProjectClaim(name string, state gcpv1alpha1.ClaimStatus) *gcpv1alpha1.ProjectClaim {
+	return &gcpv1alpha1.ProjectClaim{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "tenant"},
+		Status:     gcpv1alpha1.ProjectClaimStatus{State: state},
+	}
+}
+
+func TestProjectClaimCollector(t *testing.T) {
+	scheme := runtime.NewScheme()
+	assert.NoError(t, gcpv1alpha1.AddToScheme(scheme))
+	reader := fakekubeclient.NewClientBuilder().WithScheme(scheme).WithObjects(
+		newProjectClaim("new", ""),
+		newProjectClaim("pending", gcpv1alpha1.ClaimStatusPendingProject),
+		newProjectClaim("ready", gcpv1alpha1.ClaimStatusReady),
+		newProjectClaim("other-ready", gcpv1alpha1.ClaimStatusReady),
+	).Build()
+
+	expected := `
+# HELP gcp_project_operator_projectclaims Number of ProjectClaims, by state.
+# TYPE gcp_project_operator_projectclaims gauge
+gcp_project_operator_projectclaims{state=""} 1
+gcp_project_operator_projectclaims{state="Error"} 0
+gcp_project_operator_projectclaims{state="Pending"} 0
+gcp_project_operator_projectclaims{state="PendingProject"} 1
+gcp_project_operator_projectclaims{state="Ready"} 2
+gcp_project_operator_projectclaims{state="Verification"} 0
+`
+	assert.NoError(t, testutil.CollectAndCompare(NewProjectClaimCollector(reader), strings.NewReader(expected)))
+}
+
+func TestProjectClaimCollectorListError(t *testing.T) {
+	// the scheme doesn't know ProjectClaims
+	reader := fakekubeclient.NewClientBuilder().WithScheme(runtime.NewScheme()).Build()
+	_, err := testutil.CollectAndLint(NewProjectClaimCollector(reader))
+	assert.Error(t, err)
+}
+
+func sampleCount(t *testing.T, histogram prometheus.Histogram) uint64 {
+	t.Helper()
+	metric := &dto.Metric{}
+	assert.NoError(t, histogram.Write(metric))
+	return metric.GetHistogram().GetSampleCount()
+}
+
+func TestObserveProjectClaimDeleted(t *testing.T) {
+	claim := newProjectClaim("claim", gcpv1alpha1.ClaimStatusReady)
+	before := sampleCount(t, ProjectClaimDeletionSeconds)
+
+	ObserveProjectClaimDeleted(claim)
+	assert.Equal(t, before, sampleCount(t, ProjectClaimDeletionSeconds))
+
+	deletion := metav1.NewTime(time.Now().Add(-time.Minute))
+	claim.DeletionTimestamp = &deletion
+	ObserveProjectClaimDeleted(claim)
+	assert.Equal(t, before+1, sampleCount(t, ProjectClaimDeletionSeconds))
+}
@@ -0,0 +1,187 @@
+// Package naming generates the IDs and display names of the GCP projects from the templates of the OperatorConfigMap,
+// and sanitizes the labels of the projects
+package naming
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"fmt"
+	"math/rand/v2"
+	"regexp"
+	"strings"
+	"text/template"
+)
+
+// DefaultProjectIDTemplate generates the IDs of the projects if no template is configured, e.g. o-1a2b3c4d
+const DefaultProjectIDTemplate = "o-{{rand 8}}"
+
+// Labels the operator sets on the projects
+const (
+	// ClaimNameLabel is the name of the ProjectClaim of a project
+	ClaimNameLabel = "claim_name"
+	// ClaimNamespaceLabel is the namespace of the ProjectClaim of a project
+	ClaimNamespaceLabel = "claim_namespace"
+	// LegalEntityIDLabel is the ID of the legal entity of the ProjectClaim
+	LegalEntityIDLabel = "legal_entity_id"
+	// ClusterIDLabel is the ID of the cluster using the project
+	ClusterIDLabel = "cluster_id"
+	// CostCenterLabel is the cost center of the project
+	CostCenterLabel = "cost_center"
+	// OrphanedLabel marks the projects the operator retained after their ProjectClaim was deleted
+	OrphanedLabel = "orphaned"
+)
+
+// ReservedLabels are set by the operator alone, the projectLabels of the OperatorConfigMap can't set them
+var ReservedLabels = []string{ClaimNameLabel, ClaimNamespaceLabel, LegalEntityIDLabel, ClusterIDLabel, OrphanedLabel}
+
+const (
+	// maxDisplayNameLength is the longest display name GCP accepts
+	maxDisplayNameLength = 30
+	// maxLabelLength is the longest label key or value GCP accepts
+	maxLabelLength = 63
+)
+
+var (
+	// projectID matches the IDs GCP accepts: 6 to 30 lowercase letters, digits or hyphens,
+	// starting with a letter and not ending with a hyphen
+	projectID = regexp.MustCompile(`^[a-z][-a-z0-9]{4,28}[a-z0-9]$`)
+	// displayName matches the display names GCP accepts
+	displayName = regexp.MustCompile(`^[a-zA-Z0-9'" !-]{4,30}$`)
+	// notDisplayName matches the characters GCP doesn't accept in display names
+	notDisplayName = regexp.MustCompile(`[^a-zA-Z0-9'" !-]+`)
+	// notSlug matches the runs of characters slug replaces
+	notSlug = regexp.MustCompile(`[^a-z0-9]+`)
+	// labelKey matches the label keys GCP accepts: up to 63 lowercase letters, digits, underscores or hyphens,
+	// starting with a letter
+	labelKey = regexp.MustCompile(`^[a-z][-_a-z0-9]{0,62}$`)
+	// labelValue matches the label values GCP accepts, which may be empty
+	labelValue = regexp.MustCompile(`^[-_a-z0-9]{0,63}$`)
+	// notLabelValue matches the runs of characters GCP doesn't accept in label values
+	notLabelValue = regexp.MustCompile(`[^-_a-z0-9]+`)
+)
+
+// Data is what the templates are rendered with
+type Data struct {
+	ClaimName       string
+	ClaimNamespace  string
+	LegalEntityID   string
+	LegalEntityName string
+	// ProjectID is the ID of the project, it is only set for display name templates
+	ProjectID string
+}
+
+// sampleData validates the templates
+var sampleData = Data{
+	ClaimName:       "sample-claim",
+	ClaimNamespace:  "uhc-production-sample",
+	LegalEntityID:   "1a2B3c4D5e6F7g8H9i0J",
+	LegalEntityName: "Sample Legal Entity, Inc.",
+	ProjectID:       "o-1a2b3c4d",
+}
+
+var funcs = template.FuncMap{
+	// hash returns the first n hex characters of the SHA-256 of s, e.g. {{.LegalEntityID | hash 8}}
+	"hash": func(n int, s string) string {
+		return truncate(n, fmt.Sprintf("%x", sha256.Sum256([]byte(s))))
+	},
+	// rand returns n random hex characters, they differ for every project
+	"rand": func(n int) string {
+		const hex = "0123456789abcdef"
+		b := make([]byte, n)
+		for i := range b {
+			b[i] = hex[rand.IntN(len(hex))]
+		}
+		return string(b)
+	},
+	// slug lowercases s and replaces the runs of characters other than letters and digits with a hyphen
+	"slug": func(s string) string {
+		return strings.Trim(notSlug.ReplaceAllString(strings.ToLower(s), "-"), "-")
+	},
+	// trunc returns the first n characters of s
+	"trunc": truncate,
+	"lower": strings.ToLower,
+}
+
+func truncate(n int, s string) string {
+	if n < 0 || len(s) <= n {
+		return s
+	}
+	return s[:n]
+}
+
+// render executes the template text with data
+func render(text string, data Data) (string, error) {
+	tmpl, err := template.New("name").Funcs(funcs).Option("missingkey=error").Parse(text)
+	if err != nil {
+		return "", err
+	}
+	var b bytes.Buffer
+	if err := tmpl.Execute(&b, data); err != nil {
+		return "", err
+	}
+	return b.String(), nil
+}
+
+// ProjectID renders the project ID template text, DefaultProjectIDTemplate if it is empty,
+// and checks that GCP accepts the result as a project ID
+func ProjectID(text string, data Data) (string, error) {
+	if text == "" {
+		text = DefaultProjectIDTemplate
+	}
+	id, err := render(text, data)
+	if err != nil {
+		return "", fmt.Errorf("could not render project ID template: %w", err)
+	}
+	if !projectID.MatchString(id) {
+		return "", fmt.Errorf("project ID %q must be 6 to 30 lowercase letters, digits or hyphens, start with a letter and not end with a hyphen", id)
+	}
+	return id, nil
+}
+
+// DisplayName renders the display name template text, which is the project ID if it is empty.
+// The characters GCP doesn't accept in display names are replaced with hyphens, and the name is truncated to 30 characters.
+func DisplayName(text string, data Data) (string, error) {
+	if text == "" {
+		return data.ProjectID, nil
+	}
+	name, err := render(text, data)
+	if err != nil {
+		return "", fmt.Errorf("could not render display name template: %w", err)
+	}
+	name = strings.TrimSpace(truncate(maxDisplayNameLength, notDisplayName.ReplaceAllString(name, "-")))
+	if !displayName.MatchString(name) {
+		return "", fmt.Errorf("display name %q must be at least 4 characters", name)
+	}
+	return name, nil
+}
+
+// ValidateProjectIDTemplate checks that text renders valid project IDs
+func ValidateProjectIDTemplate(text string) error {
+	data := sampleData
+	data.ProjectID = ""
+	_, err := ProjectID(text, data)
+	return err
+}
+
+// ValidateDisplayNameTemplate checks that text renders valid display names
+func ValidateDisplayNameTemplate(text string) error {
+	_, err := DisplayName(text, sampleData)
+	return err
+}
+
+// LabelValue turns s into a label value GCP accepts: it is lowercased, the runs of characters other than letters, digits,
+// underscores and hyphens are replaced with an underscore, and it is truncated to 63 characters
+func LabelValue(s string) string {
+	return truncate(maxLabelLength, notLabelValue.ReplaceAllString(strings.ToLower(s), "_"))
+}
+
+// ValidateLabel checks that GCP accepts key and value as a label
+func ValidateLabel(key, value string) error {
+	if !labelKey.MatchString(key) {
+		return fmt.Errorf("label key %q must be 1 to 63 lowercase letters, digits, underscores or hyphens, and start with a letter", key)
+	}
+	if !labelValue.MatchString(value) {
+		return fmt.Errorf("value %q of label %s must be up to 63 lowercase letters, digits, underscores or hyphens", value, key)
+	}
+	return nil
+}
@@ -0,0 +1,124 @@
+package naming
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testData = Data{
+	ClaimName:       "claim",
+	ClaimNamespace:  "uhc-production-1234",
+	LegalEntityID:   "1234",
+	LegalEntityName: "ACME Corp.",
+	ProjectID:       "acme-corp-03ac67-1a2b",
+}
+
+func TestProjectID(t *testing.T) {
+	tests := []struct {
+		name        string
+		template    string
+		expected    string
+		expectedErr bool
+	}{
+		{name: "legal entity", template: "{{.LegalEntityName | slug}}-{{.LegalEntityID | hash 6}}", expected: "acme-corp-03ac67"},
+		{name: "truncated claim", template: "osd-{{.ClaimNamespace | trunc 10}}", expected: "osd-uhc-produc"},
+		{name: "too short", template: "o-{{rand 2}}", expectedErr: true},
+		{name: "too long", template: "{{.ClaimNamespace}}-{{.ClaimNamespace}}", expectedErr: true},
+		{name: "uppercase", template: "{{.LegalEntityName}}", expectedErr: true},
+		{name: "starts with a digit", template: "{{.LegalEntityID}}-project", expectedErr: true},
+		{name: "unknown field", template: "o-{{.Cluster}}", expectedErr: true},
+		{name: "malformed", template: "o-{{rand 8", expectedErr: true},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			id, err := ProjectID(test.template, testData)
+			if test.expectedErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, test.expected, id)
+		})
+	}
+}
+
+func TestProjectIDRandomness(t *testing.T) {
+	id, err := ProjectID("", testData)
+	require.NoError(t, err)
+	assert.Regexp(t, "^o-[0-9a-f]{8}$", id)
+	other, err := ProjectID("", testData)
+	require.NoError(t, err)
+	assert.NotEqual(t, id, other)
+
+	// the hashes are deterministic
+	id, err = ProjectID("p-{{.LegalEntityID | hash 8}}", testData)
+	require.NoError(t, err)
+	other, err = ProjectID("p-{{.LegalEntityID | hash 8}}", testData)
+	require.NoError(t, err)
+	assert.Equal(t, id, other)
+}
+
+func TestDisplayName(t *testing.T) {
+	tests := []struct {
+		name        string
+		template    string
+		expected    string
+		expectedErr bool
+	}{
+		{name: "project ID by default", template: "", expected: "acme-corp-03ac67-1a2b"},
+		{name: "legal entity and claim", template: "{{.LegalEntityName}} {{.ClaimName}}", expected: "ACME Corp- claim"},
+		{name: "truncated", template: "{{.LegalEntityName}} {{.ClaimNamespace}}", expected: "ACME Corp- uhc-production-1234"},
+		{name: "truncated trailing space", template: "{{.ClaimNamespace}} production claim", expected: "uhc-production-1234 production"},
+		{name: "too short", template: "{{.LegalEntityID | trunc 2}}", expectedErr: true},
+		{name: "malformed", template: "{{.ClaimName", expectedErr: true},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			name, err := DisplayName(test.template, testData)
+			if test.expectedErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, test.expected, name)
+		})
+	}
+}
+
+func TestValidateTemplates(t *testing.T) {
+	assert.NoError(t, ValidateProjectIDTemplate(""))
+	assert.NoError(t, ValidateProjectIDTemplate("{{.LegalEntityName | slug | trunc 10}}-{{.LegalEntityID | hash 6}}-{{rand 4}}"))
+	assert.Error(t, ValidateProjectIDTemplate("{{.LegalEntityName}}"))
+	// the ID of the project isn't known yet when it is generated
+	assert.Error(t, ValidateProjectIDTemplate("{{.ProjectID}}"))
+
+	assert.NoError(t, ValidateDisplayNameTemplate(""))
+	assert.NoError(t, ValidateDisplayNameTemplate("{{.LegalEntityName}} {{.ClaimName}}"))
+	assert.Error(t, ValidateDisplayNameTemplate("{{.Cluster}}"))
+}
+
+func TestLabelValue(t *testing.T) {
+	assert.Equal(t, "uhc-production-1234", LabelValue("uhc-production-1234"))
+	assert.Equal(t, "1a2b3c4d5e6f7g8h9i0j", LabelValue("1a2B3c4D5e6F7g8H9i0J"))
+	assert.Equal(t, "acme_corp_", LabelValue("ACME Corp."))
+	assert.Equal(t, "claim_v1", LabelValue("claim.v1"))
+	assert.Len(t, LabelValue(strings.Repeat("a", 100)), 63)
+	assert.Equal(t, "", LabelValue(""))
+	for _, value := range []string{"uhc-production-1234", "ACME Corp.", "claim.v1", strings.Repeat("a", 100)} {
+		assert.NoError(t, ValidateLabel("key", LabelValue(value)))
+	}
+}
+
+func TestValidateLabel(t *testing.T) {
+	assert.NoError(t, ValidateLabel("cost_center", "cc-42"))
+	assert.NoError(t, ValidateLabel("team", ""))
+	assert.Error(t, ValidateLabel("Team", "sre"))
+	assert.Error(t, ValidateLabel("1team", "sre"))
+	assert.Error(t, ValidateLabel("", "sre"))
+	assert.Error(t, ValidateLabel(strings.Repeat("a", 64), "sre"))
+	assert.Error(t, ValidateLabel("team", "SRE"))
+	assert.Error(t, ValidateLabel("team", "platform.sre"))
+}
@@ -0,0 +1,156 @@
+// Package tracing sets up the OpenTelemetry tracing of the reconciles of the operator and its GCP API calls
+package tracing
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"reflect"
+	"runtime"
+	"strings"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	tracerName  = "github.com/openshift/gcp-project-operator"
+	serviceName = "gcp-project-operator"
+)
+
+// Exporters of the spans
+const (
+	// ExporterNone disables tracing
+	ExporterNone = "none"
+	// ExporterOTLP sends the spans to an OTLP collector, configured by the OTEL_EXPORTER_OTLP_* environment variables
+	ExporterOTLP = "otlp"
+	// ExporterStdout writes the spans to stdout as JSON
+	ExporterStdout = "stdout"
+	// ExporterFile writes the spans to Config.File as JSON
+	ExporterFile = "file"
+)
+
+// Attributes of the spans
+const (
+	NamespaceKey  = attribute.Key("k8s.namespace.name")
+	NameKey       = attribute.Key("k8s.object.name")
+	ProjectIDKey  = attribute.Key("gcp.project.id")
+	StatusCodeKey = attribute.Key("http.response.status_code")
+)
+
+// Config selects where the spans are exported to
+type Config struct {
+	Exporter string
+	// File is the path the spans are written to with ExporterFile
+	File string
+}
+
+// Setup installs the global TracerProvider exporting to config.Exporter.
+// The returned function flushes the remaining spans and must be called before the operator exits.
+func Setup(ctx context.Context, config Config) (func(context.Context) error, error) {
+	var exporter sdktrace.SpanExporter
+	var closeFile func() error
+	var err error
+	switch config.Exporter {
+	case "", ExporterNone:
+		return func(context.Context) error { return nil }, nil
+	case ExporterOTLP:
+		exporter, err = newOTLPExporter(ctx)
+	case ExporterStdout:
+		exporter, err = stdouttrace.New()
+	case ExporterFile:
+		if config.File == "" {
+			return nil, errors.New("the file exporter needs a file")
+		}
+		var file *os.File
+		file, err = os.Create(config.File)
+		if err != nil {
+			return nil, fmt.Errorf("could not create the trace file: %w", err)
+		}
+		closeFile = file.Close
+		exporter, err = stdouttrace.New(stdouttrace.WithWriter(file))
+	default:
+		return nil, fmt.Errorf("unknown trace exporter %q", config.Exporter)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("could not create the %s trace exporter: %w", config.Exporter, err)
+	}
+
+	// OTEL_SERVICE_NAME and OTEL_RESOURCE_ATTRIBUTES override the defaults
+	res, err := resource.New(ctx,
+		resource.WithTelemetrySDK(),
+		resource.WithAttributes(attribute.String("service.name", serviceName)),
+		resource.WithFromEnv(),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("could not create the trace resource: %w", err)
+	}
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+	)
+	otel.SetTracerProvider(provider)
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+
+	return func(ctx context.Context) error {
+		err := provider.Shutdown(ctx)
+		if closeFile != nil {
+			err = errors.Join(err, closeFile())
+		}
+		return err
+	}, nil
+}
+
+// newOTLPExporter uses gRPC, unless OTEL_EXPORTER_OTLP_PROTOCOL selects HTTP
+func newOTLPExporter(ctx context.Context) (sdktrace.SpanExporter, error) {
+	protocol := os.Getenv("OTEL_EXPORTER_OTLP_TRACES_PROTOCOL")
+	if protocol == "" {
+		protocol = os.Getenv("OTEL_EXPORTER_OTLP_PROTOCOL")
+	}
+	switch protocol {
+	case "", "grpc":
+		return otlptracegrpc.New(ctx)
+	case "http/protobuf":
+		return otlptracehttp.New(ctx)
+	default:
+		return nil, fmt.Errorf("unsupported OTLP protocol %q", protocol)
+	}
+}
+
+// Tracer returns the tracer of the operator, which doesn't record anything unless Setup enabled tracing
+func Tracer() trace.Tracer {
+	return otel.Tracer(tracerName)
+}
+
+// OperationName returns the name of the function or method operation, e.g. "EnsureFinalizer"
+func OperationName(operation interface{}) string {
+	name := path.Base(runtime.FuncForPC(reflect.ValueOf(operation).Pointer()).Name())
+	// method values are suffixed with -fm
+	name = strings.TrimSuffix(name, "-fm")
+	return name[strings.LastIndex(name, ".")+1:]
+}
+
+// StartOperation starts a span named after the reconcile operation
+func StartOperation(ctx context.Context, operation interface{}) (context.Context, trace.Span) {
+	return Tracer().Start(ctx, OperationName(operation))
+}
+
+// End ends span, marking it as failed if err isn't nil
+func End(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
@@ -0,0 +1,70 @@
+package tracing
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/trace/noop"
+)
+
+type adapter struct{}
+
+func (adapter) EnsureFinalizer() error { return nil }
+
+func EnsureProjectCreated(*adapter) error { return nil }
+
+func TestOperationName(t *testing.T) {
+	assert.Equal(t, "EnsureProjectCreated", OperationName(EnsureProjectCreated))
+	assert.Equal(t, "EnsureFinalizer", OperationName(adapter{}.EnsureFinalizer))
+}
+
+func TestSetupFileExporter(t *testing.T) {
+	t.Cleanup(func() { otel.SetTracerProvider(noop.NewTracerProvider()) })
+	file := filepath.Join(t.TempDir(), "traces.json")
+
+	shutdown, err := Setup(context.TODO(), Config{Exporter: ExporterFile, File: file})
+	require.NoError(t, err)
+
+	ctx, reconcile := Tracer().Start(context.TODO(), "Reconcile")
+	_, operation := StartOperation(ctx, EnsureProjectCreated)
+	End(operation, errors.New("quota exceeded"))
+	reconcile.End()
+	require.NoError(t, shutdown(context.TODO()))
+
+	traces, err := os.ReadFile(file)
+	require.NoError(t, err)
+	assert.Contains(t, string(traces), `"Name":"EnsureProjectCreated"`)
+	assert.Contains(t, string(traces), `"Name":"Reconcile"`)
+	assert.Contains(t, string(traces), "quota exceeded")
+	assert.Contains(t, string(traces), serviceName)
+}
+
+func TestSetup(t *testing.T) {
+	tests := []struct {
+		name        string
+		config      Config
+		expectedErr bool
+	}{
+		{name: "disabled by default", config: Config{}},
+		{name: "disabled", config: Config{Exporter: ExporterNone}},
+		{name: "file without path", config: Config{Exporter: ExporterFile}, expectedErr: true},
+		{name: "unknown exporter", config: Config{Exporter: "jaeger"}, expectedErr: true},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			shutdown, err := Setup(context.TODO(), test.config)
+			if test.expectedErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.NoError(t, shutdown(context.TODO()))
+		})
+	}
+}
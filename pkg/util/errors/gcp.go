@@ -0,0 +1,143 @@
+package errors
+
+import (
+	"errors"
+	"net/http"
+	"slices"
+	"strings"
+
+	"golang.org/x/oauth2"
+	"google.golang.org/api/googleapi"
+)
+
+// errorInfoType is the type of the google.rpc.ErrorInfo details of a GCP API error
+const errorInfoType = "type.googleapis.com/google.rpc.ErrorInfo"
+
+// reasons of ErrorInfo details and legacy ErrorItems that mean a quota or rate limit was exceeded
+var quotaExceededReasons = []string{
+	"RATE_LIMIT_EXCEEDED",
+	"RESOURCE_EXHAUSTED",
+	"rateLimitExceeded",
+	"userRateLimitExceeded",
+	"quotaExceeded",
+	"dailyLimitExceeded",
+}
+
+// GCPError returns the *googleapi.Error in the chain of err, if it was returned by a GCP API
+func GCPError(err error) (*googleapi.Error, bool) {
+	var gcpErr *googleapi.Error
+	if !errors.As(err, &gcpErr) {
+		return nil, false
+	}
+	return gcpErr, true
+}
+
+func hasCode(err error, code int) bool {
+	gcpErr, ok := GCPError(err)
+	return ok && gcpErr.Code == code
+}
+
+// IsNotFound returns true if the resource of the GCP API call doesn't exist.
+// This includes the service account of the credentials, which Google OAuth reports as an invalid grant.
+func IsNotFound(err error) bool {
+	if hasCode(err, http.StatusNotFound) {
+		return true
+	}
+	var retrieveErr *oauth2.RetrieveError
+	return errors.As(err, &retrieveErr) &&
+		retrieveErr.ErrorCode == "invalid_grant" &&
+		strings.Contains(retrieveErr.ErrorDescription, "account not found")
+}
+
+// IsConflict returns true if the resource of the GCP API call already exists,
+// or was changed concurrently, e.g. the etag of an IAM policy doesn't match anymore.
+func IsConflict(err error) bool {
+	return hasCode(err, http.StatusConflict)
+}
+
+// IsPermissionDenied returns true if the caller isn't allowed to make the GCP API call.
+// GCP also denies the calls to the APIs that aren't enabled, see IsServiceDisabled.
+func IsPermissionDenied(err error) bool {
+	return hasCode(err, http.StatusForbidden)
+}
+
+// IsServiceDisabled returns the service, e.g. "compute.googleapis.com", if the GCP API call failed
+// because the service isn't enabled on the project yet.
+func IsServiceDisabled(err error) (string, bool) {
+	gcpErr, ok := GCPError(err)
+	if !ok {
+		return "", false
+	}
+	for _, info := range errorInfos(gcpErr) {
+		if info.reason == "SERVICE_DISABLED" {
+			return info.metadata["service"], true
+		}
+	}
+	return "", false
+}
+
+// IsQuotaExceeded returns true if the GCP API call exceeded a quota or rate limit.
+// Some APIs report this with 403 instead of 429, which is why the reasons are checked as well.
+func IsQuotaExceeded(err error) bool {
+	gcpErr, ok := GCPError(err)
+	if !ok {
+		return false
+	}
+	if gcpErr.Code == http.StatusTooManyRequests {
+		return true
+	}
+	for _, info := range errorInfos(gcpErr) {
+		if slices.Contains(quotaExceededReasons, info.reason) {
+			return true
+		}
+	}
+	for _, item := range gcpErr.Errors {
+		if slices.Contains(quotaExceededReasons, item.Reason) {
+			return true
+		}
+	}
+	return false
+}
+
+// IsRetryable returns true if the GCP API call may succeed if it's retried unchanged later
+func IsRetryable(err error) bool {
+	if IsQuotaExceeded(err) {
+		return true
+	}
+	gcpErr, ok := GCPError(err)
+	if !ok {
+		return false
+	}
+	switch gcpErr.Code {
+	case http.StatusRequestTimeout, http.StatusInternalServerError, http.StatusBadGateway,
+		http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
+
+type errorInfo struct {
+	reason   string
+	metadata map[string]string
+}
+
+// errorInfos returns the ErrorInfo details of gcpErr, which googleapi only decodes as generic JSON
+func errorInfos(gcpErr *googleapi.Error) []errorInfo {
+	var infos []errorInfo
+	for _, detail := range gcpErr.Details {
+		fields, ok := detail.(map[string]interface{})
+		if !ok || fields["@type"] != errorInfoType {
+			continue
+		}
+		info := errorInfo{metadata: map[string]string{}}
+		info.reason, _ = fields["reason"].(string)
+		metadata, _ := fields["metadata"].(map[string]interface{})
+		for key, value := range metadata {
+			if s, ok := value.(string); ok {
+				info.metadata[key] = s
+			}
+		}
+		infos = append(infos, info)
+	}
+	return infos
+}
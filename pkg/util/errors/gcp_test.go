@@ -0,0 +1,115 @@
+package errors
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"golang.org/x/oauth2"
+	"google.golang.org/api/googleapi"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+func errorInfoDetail(reason string, metadata map[string]interface{}) interface{} {
+	return map[string]interface{}{
+		"@type":    "type.googleapis.com/google.rpc.ErrorInfo",
+		"reason":   reason,
+		"domain":   "googleapis.com",
+		"metadata": metadata,
+	}
+}
+
+var _ = Describe("gcp.go", func() {
+	var (
+		notFound = &googleapi.Error{Code: http.StatusNotFound, Message: "Requested entity was not found."}
+		conflict = &googleapi.Error{Code: http.StatusConflict, Message: "Requested entity already exists"}
+		denied   = &googleapi.Error{Code: http.StatusForbidden, Message: "The caller does not have permission"}
+		disabled = &googleapi.Error{
+			Code:    http.StatusForbidden,
+			Message: "Compute Engine API has not been used in project fake before or it is disabled.",
+			Details: []interface{}{errorInfoDetail("SERVICE_DISABLED", map[string]interface{}{"service": "compute.googleapis.com"})},
+		}
+		tooManyRequests = &googleapi.Error{Code: http.StatusTooManyRequests, Message: "Quota exceeded"}
+		rateLimited     = &googleapi.Error{
+			Code:   http.StatusForbidden,
+			Errors: []googleapi.ErrorItem{{Reason: "rateLimitExceeded", Message: "Rate Limit Exceeded"}},
+		}
+		unavailable = &googleapi.Error{Code: http.StatusServiceUnavailable, Message: "The service is currently unavailable."}
+		// the token endpoint rejects the credentials of deleted service accounts
+		accountNotFound = fmt.Errorf("credentials: %w", &oauth2.RetrieveError{
+			ErrorCode:        "invalid_grant",
+			ErrorDescription: "Invalid grant: account not found",
+		})
+	)
+
+	Context("when the error isn't returned by a GCP API", func() {
+		It("doesn't classify it", func() {
+			err := errors.New("googleapi: Error 404: Not found, notFound")
+			Expect(IsNotFound(err)).To(BeFalse())
+			Expect(IsConflict(err)).To(BeFalse())
+			Expect(IsPermissionDenied(err)).To(BeFalse())
+			Expect(IsQuotaExceeded(err)).To(BeFalse())
+			Expect(IsRetryable(err)).To(BeFalse())
+			_, ok := IsServiceDisabled(err)
+			Expect(ok).To(BeFalse())
+			_, ok = GCPError(nil)
+			Expect(ok).To(BeFalse())
+		})
+	})
+
+	Context("when the error is wrapped", func() {
+		It("classifies the googleapi.Error it wraps", func() {
+			Expect(IsNotFound(Wrap(notFound, "getting the project"))).To(BeTrue())
+			Expect(IsConflict(Wrap(conflict, "creating the service account"))).To(BeTrue())
+		})
+	})
+
+	It("classifies not found errors", func() {
+		Expect(IsNotFound(notFound)).To(BeTrue())
+		Expect(IsNotFound(accountNotFound)).To(BeTrue())
+		Expect(IsNotFound(&oauth2.RetrieveError{ErrorCode: "invalid_grant", ErrorDescription: "Invalid JWT Signature."})).To(BeFalse())
+		Expect(IsNotFound(denied)).To(BeFalse())
+	})
+
+	It("classifies conflicts", func() {
+		Expect(IsConflict(conflict)).To(BeTrue())
+		Expect(IsConflict(notFound)).To(BeFalse())
+	})
+
+	It("classifies permission denied errors", func() {
+		Expect(IsPermissionDenied(denied)).To(BeTrue())
+		Expect(IsPermissionDenied(disabled)).To(BeTrue())
+		Expect(IsPermissionDenied(notFound)).To(BeFalse())
+	})
+
+	It("returns the service of service disabled errors", func() {
+		service, ok := IsServiceDisabled(Wrap(disabled, "listing zones"))
+		Expect(ok).To(BeTrue())
+		Expect(service).To(Equal("compute.googleapis.com"))
+
+		_, ok = IsServiceDisabled(denied)
+		Expect(ok).To(BeFalse())
+	})
+
+	It("classifies quota errors", func() {
+		Expect(IsQuotaExceeded(tooManyRequests)).To(BeTrue())
+		Expect(IsQuotaExceeded(rateLimited)).To(BeTrue())
+		Expect(IsQuotaExceeded(&googleapi.Error{
+			Code:    http.StatusForbidden,
+			Details: []interface{}{errorInfoDetail("RATE_LIMIT_EXCEEDED", nil)},
+		})).To(BeTrue())
+		Expect(IsQuotaExceeded(denied)).To(BeFalse())
+	})
+
+	It("classifies retryable errors", func() {
+		Expect(IsRetryable(tooManyRequests)).To(BeTrue())
+		Expect(IsRetryable(rateLimited)).To(BeTrue())
+		Expect(IsRetryable(unavailable)).To(BeTrue())
+		Expect(IsRetryable(&googleapi.Error{Code: http.StatusNotImplemented})).To(BeFalse())
+		Expect(IsRetryable(notFound)).To(BeFalse())
+		Expect(IsRetryable(conflict)).To(BeFalse())
+		Expect(IsRetryable(denied)).To(BeFalse())
+	})
+})
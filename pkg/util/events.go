@@ -0,0 +1,38 @@
+package util
+
+// EventReportingController is the reporting controller of the events the operator records
+const EventReportingController = "gcp-project-operator"
+
+// Reasons of the events recorded on ProjectClaims and ProjectReferences
+const (
+	EventReasonProjectCreated           = "ProjectCreated"
+	EventReasonBillingLinked            = "BillingLinked"
+	EventReasonAPIEnabled               = "APIEnabled"
+	EventReasonServiceAccountCreated    = "ServiceAccountCreated"
+	EventReasonServiceAccountKeyCreated = "ServiceAccountKeyCreated"
+	EventReasonCredentialsWritten       = "CredentialsWritten"
+	EventReasonRegionNotSupported       = "RegionNotSupported"
+	EventReasonDeletionStarted          = "DeletionStarted"
+	EventReasonDeletionCompleted        = "DeletionCompleted"
+	EventReasonDeletionBlocked          = "DeletionBlocked"
+	EventReasonProjectRetained          = "ProjectRetained"
+	EventReasonProjectPendingDeletion   = "ProjectPendingDeletion"
+	EventReasonProjectRestored          = "ProjectRestored"
+	EventReasonProjectAdopted           = "ProjectAdopted"
+	EventReasonAdoptionRejected         = "AdoptionRejected"
+	EventReasonProjectNotFound          = "ProjectNotFound"
+	EventReasonProjectLabeled           = "ProjectLabeled"
+	EventReasonTagBound                 = "TagBound"
+	EventReasonGCPError                 = "GCPError"
+)
+
+// maxEventNoteLength is the longest note the API server accepts for an event
+const maxEventNoteLength = 1024
+
+// EventNote shortens note to the length the API server accepts for events
+func EventNote(note string) string {
+	if len(note) <= maxEventNoteLength {
+		return note
+	}
+	return note[:maxEventNoteLength-3] + "..."
+}
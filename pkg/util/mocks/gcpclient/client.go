@@ -10,10 +10,14 @@
 package gcpclient
 
 import (
+	context "context"
 	reflect "reflect"
 
+	gcpclient "github.com/openshift/gcp-project-operator/pkg/gcpclient"
 	gomock "go.uber.org/mock/gomock"
+	cloudbilling "google.golang.org/api/cloudbilling/v1"
 	cloudresourcemanager "google.golang.org/api/cloudresourcemanager/v1"
+	cloudresourcemanager0 "google.golang.org/api/cloudresourcemanager/v3"
 	iam "google.golang.org/api/iam/v1"
 )
 
@@ -41,236 +45,497 @@ func (m *MockClient) EXPECT() *MockClientMockRecorder {
 }
 
 // CreateCloudBillingAccount mocks base method.
-func (m *MockClient) CreateCloudBillingAccount(projectID, billingAccount string) error {
+func (m *MockClient) CreateCloudBillingAccount(ctx context.Context, projectID, billingAccount string) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "CreateCloudBillingAccount", projectID, billingAccount)
+	ret := m.ctrl.Call(m, "CreateCloudBillingAccount", ctx, projectID, billingAccount)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // CreateCloudBillingAccount indicates an expected call of CreateCloudBillingAccount.
-func (mr *MockClientMockRecorder) CreateCloudBillingAccount(projectID, billingAccount any) *gomock.Call {
+func (mr *MockClientMockRecorder) CreateCloudBillingAccount(ctx, projectID, billingAccount any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateCloudBillingAccount", reflect.TypeOf((*MockClient)(nil).CreateCloudBillingAccount), projectID, billingAccount)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateCloudBillingAccount", reflect.TypeOf((*MockClient)(nil).CreateCloudBillingAccount), ctx, projectID, billingAccount)
 }
 
 // CreateProject mocks base method.
-func (m *MockClient) CreateProject(parentFolder, claimName string) (*cloudresourcemanager.Operation, error) {
+func (m *MockClient) CreateProject(ctx context.Context, parentFolder, displayName string, labels map[string]string) (*cloudresourcemanager.Operation, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "CreateProject", parentFolder, claimName)
+	ret := m.ctrl.Call(m, "CreateProject", ctx, parentFolder, displayName, labels)
 	ret0, _ := ret[0].(*cloudresourcemanager.Operation)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // CreateProject indicates an expected call of CreateProject.
-func (mr *MockClientMockRecorder) CreateProject(parentFolder, claimName any) *gomock.Call {
+func (mr *MockClientMockRecorder) CreateProject(ctx, parentFolder, displayName, labels any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateProject", reflect.TypeOf((*MockClient)(nil).CreateProject), parentFolder, claimName)
-}
-
-// CreateProjectLabels mocks base method.
-func (m *MockClient) CreateProjectLabels(project *cloudresourcemanager.Project, labels map[string]string) error {
-	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "CreateProjectLabels", project, labels)
-	ret0, _ := ret[0].(error)
-	return ret0
-}
-
-// CreateProjectLabels indicates an expected call of CreateProjectLabels.
-func (mr *MockClientMockRecorder) CreateProjectLabels(project, labels any) *gomock.Call {
-	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateProjectLabels", reflect.TypeOf((*MockClient)(nil).CreateProjectLabels), project, labels)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateProject", reflect.TypeOf((*MockClient)(nil).CreateProject), ctx, parentFolder, displayName, labels)
 }
 
 // CreateServiceAccount mocks base method.
-func (m *MockClient) CreateServiceAccount(name, displayName string) (*iam.ServiceAccount, error) {
+func (m *MockClient) CreateServiceAccount(ctx context.Context, name, displayName string) (*iam.ServiceAccount, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "CreateServiceAccount", name, displayName)
+	ret := m.ctrl.Call(m, "CreateServiceAccount", ctx, name, displayName)
 	ret0, _ := ret[0].(*iam.ServiceAccount)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // CreateServiceAccount indicates an expected call of CreateServiceAccount.
-func (mr *MockClientMockRecorder) CreateServiceAccount(name, displayName any) *gomock.Call {
+func (mr *MockClientMockRecorder) CreateServiceAccount(ctx, name, displayName any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateServiceAccount", reflect.TypeOf((*MockClient)(nil).CreateServiceAccount), name, displayName)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateServiceAccount", reflect.TypeOf((*MockClient)(nil).CreateServiceAccount), ctx, name, displayName)
 }
 
 // CreateServiceAccountKey mocks base method.
-func (m *MockClient) CreateServiceAccountKey(serviceAccountEmail string) (*iam.ServiceAccountKey, error) {
+func (m *MockClient) CreateServiceAccountKey(ctx context.Context, serviceAccountEmail string) (*iam.ServiceAccountKey, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "CreateServiceAccountKey", serviceAccountEmail)
+	ret := m.ctrl.Call(m, "CreateServiceAccountKey", ctx, serviceAccountEmail)
 	ret0, _ := ret[0].(*iam.ServiceAccountKey)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // CreateServiceAccountKey indicates an expected call of CreateServiceAccountKey.
-func (mr *MockClientMockRecorder) CreateServiceAccountKey(serviceAccountEmail any) *gomock.Call {
+func (mr *MockClientMockRecorder) CreateServiceAccountKey(ctx, serviceAccountEmail any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateServiceAccountKey", reflect.TypeOf((*MockClient)(nil).CreateServiceAccountKey), serviceAccountEmail)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateServiceAccountKey", reflect.TypeOf((*MockClient)(nil).CreateServiceAccountKey), ctx, serviceAccountEmail)
+}
+
+// CreateTagBinding mocks base method.
+func (m *MockClient) CreateTagBinding(ctx context.Context, projectNumber int64, tagValue string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateTagBinding", ctx, projectNumber, tagValue)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateTagBinding indicates an expected call of CreateTagBinding.
+func (mr *MockClientMockRecorder) CreateTagBinding(ctx, projectNumber, tagValue any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateTagBinding", reflect.TypeOf((*MockClient)(nil).CreateTagBinding), ctx, projectNumber, tagValue)
+}
+
+// CreateWorkloadIdentityPool mocks base method.
+func (m *MockClient) CreateWorkloadIdentityPool(ctx context.Context, projectID, poolID string, pool *iam.WorkloadIdentityPool) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateWorkloadIdentityPool", ctx, projectID, poolID, pool)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateWorkloadIdentityPool indicates an expected call of CreateWorkloadIdentityPool.
+func (mr *MockClientMockRecorder) CreateWorkloadIdentityPool(ctx, projectID, poolID, pool any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateWorkloadIdentityPool", reflect.TypeOf((*MockClient)(nil).CreateWorkloadIdentityPool), ctx, projectID, poolID, pool)
+}
+
+// CreateWorkloadIdentityPoolProvider mocks base method.
+func (m *MockClient) CreateWorkloadIdentityPoolProvider(ctx context.Context, projectID, poolID, providerID string, provider *iam.WorkloadIdentityPoolProvider) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateWorkloadIdentityPoolProvider", ctx, projectID, poolID, providerID, provider)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateWorkloadIdentityPoolProvider indicates an expected call of CreateWorkloadIdentityPoolProvider.
+func (mr *MockClientMockRecorder) CreateWorkloadIdentityPoolProvider(ctx, projectID, poolID, providerID, provider any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateWorkloadIdentityPoolProvider", reflect.TypeOf((*MockClient)(nil).CreateWorkloadIdentityPoolProvider), ctx, projectID, poolID, providerID, provider)
 }
 
 // DeleteProject mocks base method.
-func (m *MockClient) DeleteProject(parentFolder string) (*cloudresourcemanager.Empty, error) {
+func (m *MockClient) DeleteProject(ctx context.Context, parentFolder string) (*cloudresourcemanager.Empty, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "DeleteProject", parentFolder)
+	ret := m.ctrl.Call(m, "DeleteProject", ctx, parentFolder)
 	ret0, _ := ret[0].(*cloudresourcemanager.Empty)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // DeleteProject indicates an expected call of DeleteProject.
-func (mr *MockClientMockRecorder) DeleteProject(parentFolder any) *gomock.Call {
+func (mr *MockClientMockRecorder) DeleteProject(ctx, parentFolder any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteProject", reflect.TypeOf((*MockClient)(nil).DeleteProject), parentFolder)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteProject", reflect.TypeOf((*MockClient)(nil).DeleteProject), ctx, parentFolder)
 }
 
 // DeleteServiceAccount mocks base method.
-func (m *MockClient) DeleteServiceAccount(accountEmail string) error {
+func (m *MockClient) DeleteServiceAccount(ctx context.Context, accountEmail string) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "DeleteServiceAccount", accountEmail)
+	ret := m.ctrl.Call(m, "DeleteServiceAccount", ctx, accountEmail)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // DeleteServiceAccount indicates an expected call of DeleteServiceAccount.
-func (mr *MockClientMockRecorder) DeleteServiceAccount(accountEmail any) *gomock.Call {
+func (mr *MockClientMockRecorder) DeleteServiceAccount(ctx, accountEmail any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteServiceAccount", reflect.TypeOf((*MockClient)(nil).DeleteServiceAccount), ctx, accountEmail)
+}
+
+// DeleteServiceAccountKey mocks base method.
+func (m *MockClient) DeleteServiceAccountKey(ctx context.Context, keyName string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteServiceAccountKey", ctx, keyName)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteServiceAccountKey indicates an expected call of DeleteServiceAccountKey.
+func (mr *MockClientMockRecorder) DeleteServiceAccountKey(ctx, keyName any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteServiceAccount", reflect.TypeOf((*MockClient)(nil).DeleteServiceAccount), accountEmail)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteServiceAccountKey", reflect.TypeOf((*MockClient)(nil).DeleteServiceAccountKey), ctx, keyName)
 }
 
 // DeleteServiceAccountKeys mocks base method.
-func (m *MockClient) DeleteServiceAccountKeys(serviceAccountEmail string) error {
+func (m *MockClient) DeleteServiceAccountKeys(ctx context.Context, serviceAccountEmail string) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "DeleteServiceAccountKeys", serviceAccountEmail)
+	ret := m.ctrl.Call(m, "DeleteServiceAccountKeys", ctx, serviceAccountEmail)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // DeleteServiceAccountKeys indicates an expected call of DeleteServiceAccountKeys.
-func (mr *MockClientMockRecorder) DeleteServiceAccountKeys(serviceAccountEmail any) *gomock.Call {
+func (mr *MockClientMockRecorder) DeleteServiceAccountKeys(ctx, serviceAccountEmail any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteServiceAccountKeys", reflect.TypeOf((*MockClient)(nil).DeleteServiceAccountKeys), serviceAccountEmail)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteServiceAccountKeys", reflect.TypeOf((*MockClient)(nil).DeleteServiceAccountKeys), ctx, serviceAccountEmail)
+}
+
+// DeleteWorkloadIdentityPool mocks base method.
+func (m *MockClient) DeleteWorkloadIdentityPool(ctx context.Context, projectID, poolID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteWorkloadIdentityPool", ctx, projectID, poolID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteWorkloadIdentityPool indicates an expected call of DeleteWorkloadIdentityPool.
+func (mr *MockClientMockRecorder) DeleteWorkloadIdentityPool(ctx, projectID, poolID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteWorkloadIdentityPool", reflect.TypeOf((*MockClient)(nil).DeleteWorkloadIdentityPool), ctx, projectID, poolID)
 }
 
 // EnableAPI mocks base method.
-func (m *MockClient) EnableAPI(projectID, api string) error {
+func (m *MockClient) EnableAPI(ctx context.Context, projectID, api string) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "EnableAPI", projectID, api)
+	ret := m.ctrl.Call(m, "EnableAPI", ctx, projectID, api)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // EnableAPI indicates an expected call of EnableAPI.
-func (mr *MockClientMockRecorder) EnableAPI(projectID, api any) *gomock.Call {
+func (mr *MockClientMockRecorder) EnableAPI(ctx, projectID, api any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EnableAPI", reflect.TypeOf((*MockClient)(nil).EnableAPI), ctx, projectID, api)
+}
+
+// ForProject mocks base method.
+func (m *MockClient) ForProject(projectName string) gcpclient.Client {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ForProject", projectName)
+	ret0, _ := ret[0].(gcpclient.Client)
+	return ret0
+}
+
+// ForProject indicates an expected call of ForProject.
+func (mr *MockClientMockRecorder) ForProject(projectName any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ForProject", reflect.TypeOf((*MockClient)(nil).ForProject), projectName)
+}
+
+// GetBillingInfo mocks base method.
+func (m *MockClient) GetBillingInfo(ctx context.Context, projectID string) (*cloudbilling.ProjectBillingInfo, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBillingInfo", ctx, projectID)
+	ret0, _ := ret[0].(*cloudbilling.ProjectBillingInfo)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBillingInfo indicates an expected call of GetBillingInfo.
+func (mr *MockClientMockRecorder) GetBillingInfo(ctx, projectID any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EnableAPI", reflect.TypeOf((*MockClient)(nil).EnableAPI), projectID, api)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBillingInfo", reflect.TypeOf((*MockClient)(nil).GetBillingInfo), ctx, projectID)
 }
 
 // GetIamPolicy mocks base method.
-func (m *MockClient) GetIamPolicy(projectName string) (*cloudresourcemanager.Policy, error) {
+func (m *MockClient) GetIamPolicy(ctx context.Context, projectName string) (*cloudresourcemanager.Policy, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetIamPolicy", projectName)
+	ret := m.ctrl.Call(m, "GetIamPolicy", ctx, projectName)
 	ret0, _ := ret[0].(*cloudresourcemanager.Policy)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetIamPolicy indicates an expected call of GetIamPolicy.
-func (mr *MockClientMockRecorder) GetIamPolicy(projectName any) *gomock.Call {
+func (mr *MockClientMockRecorder) GetIamPolicy(ctx, projectName any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetIamPolicy", reflect.TypeOf((*MockClient)(nil).GetIamPolicy), ctx, projectName)
+}
+
+// GetOperation mocks base method.
+func (m *MockClient) GetOperation(ctx context.Context, name string) (*cloudresourcemanager.Operation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetOperation", ctx, name)
+	ret0, _ := ret[0].(*cloudresourcemanager.Operation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetOperation indicates an expected call of GetOperation.
+func (mr *MockClientMockRecorder) GetOperation(ctx, name any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetIamPolicy", reflect.TypeOf((*MockClient)(nil).GetIamPolicy), projectName)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOperation", reflect.TypeOf((*MockClient)(nil).GetOperation), ctx, name)
 }
 
 // GetProject mocks base method.
-func (m *MockClient) GetProject(projectID string) (*cloudresourcemanager.Project, error) {
+func (m *MockClient) GetProject(ctx context.Context, projectID string) (*cloudresourcemanager.Project, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetProject", projectID)
+	ret := m.ctrl.Call(m, "GetProject", ctx, projectID)
 	ret0, _ := ret[0].(*cloudresourcemanager.Project)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetProject indicates an expected call of GetProject.
-func (mr *MockClientMockRecorder) GetProject(projectID any) *gomock.Call {
+func (mr *MockClientMockRecorder) GetProject(ctx, projectID any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetProject", reflect.TypeOf((*MockClient)(nil).GetProject), projectID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetProject", reflect.TypeOf((*MockClient)(nil).GetProject), ctx, projectID)
 }
 
 // GetServiceAccount mocks base method.
-func (m *MockClient) GetServiceAccount(accountName string) (*iam.ServiceAccount, error) {
+func (m *MockClient) GetServiceAccount(ctx context.Context, accountName string) (*iam.ServiceAccount, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetServiceAccount", accountName)
+	ret := m.ctrl.Call(m, "GetServiceAccount", ctx, accountName)
 	ret0, _ := ret[0].(*iam.ServiceAccount)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetServiceAccount indicates an expected call of GetServiceAccount.
-func (mr *MockClientMockRecorder) GetServiceAccount(accountName any) *gomock.Call {
+func (mr *MockClientMockRecorder) GetServiceAccount(ctx, accountName any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetServiceAccount", reflect.TypeOf((*MockClient)(nil).GetServiceAccount), ctx, accountName)
+}
+
+// GetServiceAccountIamPolicy mocks base method.
+func (m *MockClient) GetServiceAccountIamPolicy(ctx context.Context, serviceAccountEmail string) (*iam.Policy, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetServiceAccountIamPolicy", ctx, serviceAccountEmail)
+	ret0, _ := ret[0].(*iam.Policy)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetServiceAccountIamPolicy indicates an expected call of GetServiceAccountIamPolicy.
+func (mr *MockClientMockRecorder) GetServiceAccountIamPolicy(ctx, serviceAccountEmail any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetServiceAccount", reflect.TypeOf((*MockClient)(nil).GetServiceAccount), accountName)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetServiceAccountIamPolicy", reflect.TypeOf((*MockClient)(nil).GetServiceAccountIamPolicy), ctx, serviceAccountEmail)
+}
+
+// GetTagValue mocks base method.
+func (m *MockClient) GetTagValue(ctx context.Context, namespacedName string) (*cloudresourcemanager0.TagValue, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTagValue", ctx, namespacedName)
+	ret0, _ := ret[0].(*cloudresourcemanager0.TagValue)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTagValue indicates an expected call of GetTagValue.
+func (mr *MockClientMockRecorder) GetTagValue(ctx, namespacedName any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTagValue", reflect.TypeOf((*MockClient)(nil).GetTagValue), ctx, namespacedName)
+}
+
+// GetWorkloadIdentityPool mocks base method.
+func (m *MockClient) GetWorkloadIdentityPool(ctx context.Context, projectID, poolID string) (*iam.WorkloadIdentityPool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWorkloadIdentityPool", ctx, projectID, poolID)
+	ret0, _ := ret[0].(*iam.WorkloadIdentityPool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWorkloadIdentityPool indicates an expected call of GetWorkloadIdentityPool.
+func (mr *MockClientMockRecorder) GetWorkloadIdentityPool(ctx, projectID, poolID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWorkloadIdentityPool", reflect.TypeOf((*MockClient)(nil).GetWorkloadIdentityPool), ctx, projectID, poolID)
+}
+
+// GetWorkloadIdentityPoolProvider mocks base method.
+func (m *MockClient) GetWorkloadIdentityPoolProvider(ctx context.Context, projectID, poolID, providerID string) (*iam.WorkloadIdentityPoolProvider, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWorkloadIdentityPoolProvider", ctx, projectID, poolID, providerID)
+	ret0, _ := ret[0].(*iam.WorkloadIdentityPoolProvider)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWorkloadIdentityPoolProvider indicates an expected call of GetWorkloadIdentityPoolProvider.
+func (mr *MockClientMockRecorder) GetWorkloadIdentityPoolProvider(ctx, projectID, poolID, providerID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWorkloadIdentityPoolProvider", reflect.TypeOf((*MockClient)(nil).GetWorkloadIdentityPoolProvider), ctx, projectID, poolID, providerID)
 }
 
 // ListAPIs mocks base method.
-func (m *MockClient) ListAPIs(projectID string) ([]string, error) {
+func (m *MockClient) ListAPIs(ctx context.Context, projectID string) ([]string, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "ListAPIs", projectID)
+	ret := m.ctrl.Call(m, "ListAPIs", ctx, projectID)
 	ret0, _ := ret[0].([]string)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // ListAPIs indicates an expected call of ListAPIs.
-func (mr *MockClientMockRecorder) ListAPIs(projectID any) *gomock.Call {
+func (mr *MockClientMockRecorder) ListAPIs(ctx, projectID any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAPIs", reflect.TypeOf((*MockClient)(nil).ListAPIs), projectID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAPIs", reflect.TypeOf((*MockClient)(nil).ListAPIs), ctx, projectID)
 }
 
 // ListAvailabilityZones mocks base method.
-func (m *MockClient) ListAvailabilityZones(projectID, region string) ([]string, error) {
+func (m *MockClient) ListAvailabilityZones(ctx context.Context, projectID, region string) ([]string, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "ListAvailabilityZones", projectID, region)
+	ret := m.ctrl.Call(m, "ListAvailabilityZones", ctx, projectID, region)
 	ret0, _ := ret[0].([]string)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // ListAvailabilityZones indicates an expected call of ListAvailabilityZones.
-func (mr *MockClientMockRecorder) ListAvailabilityZones(projectID, region any) *gomock.Call {
+func (mr *MockClientMockRecorder) ListAvailabilityZones(ctx, projectID, region any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAvailabilityZones", reflect.TypeOf((*MockClient)(nil).ListAvailabilityZones), projectID, region)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAvailabilityZones", reflect.TypeOf((*MockClient)(nil).ListAvailabilityZones), ctx, projectID, region)
 }
 
 // ListProjects mocks base method.
-func (m *MockClient) ListProjects() ([]*cloudresourcemanager.Project, error) {
+func (m *MockClient) ListProjects(ctx context.Context, parentFolderID string) ([]*cloudresourcemanager.Project, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "ListProjects")
+	ret := m.ctrl.Call(m, "ListProjects", ctx, parentFolderID)
 	ret0, _ := ret[0].([]*cloudresourcemanager.Project)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // ListProjects indicates an expected call of ListProjects.
-func (mr *MockClientMockRecorder) ListProjects() *gomock.Call {
+func (mr *MockClientMockRecorder) ListProjects(ctx, parentFolderID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListProjects", reflect.TypeOf((*MockClient)(nil).ListProjects), ctx, parentFolderID)
+}
+
+// ListServiceAccountKeys mocks base method.
+func (m *MockClient) ListServiceAccountKeys(ctx context.Context, serviceAccountEmail string) ([]*iam.ServiceAccountKey, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListServiceAccountKeys", ctx, serviceAccountEmail)
+	ret0, _ := ret[0].([]*iam.ServiceAccountKey)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListServiceAccountKeys indicates an expected call of ListServiceAccountKeys.
+func (mr *MockClientMockRecorder) ListServiceAccountKeys(ctx, serviceAccountEmail any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListServiceAccountKeys", reflect.TypeOf((*MockClient)(nil).ListServiceAccountKeys), ctx, serviceAccountEmail)
+}
+
+// ListTagBindings mocks base method.
+func (m *MockClient) ListTagBindings(ctx context.Context, projectNumber int64) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListTagBindings", ctx, projectNumber)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListTagBindings indicates an expected call of ListTagBindings.
+func (mr *MockClientMockRecorder) ListTagBindings(ctx, projectNumber any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListProjects", reflect.TypeOf((*MockClient)(nil).ListProjects))
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListTagBindings", reflect.TypeOf((*MockClient)(nil).ListTagBindings), ctx, projectNumber)
 }
 
 // SetIamPolicy mocks base method.
-func (m *MockClient) SetIamPolicy(setIamPolicyRequest *cloudresourcemanager.SetIamPolicyRequest) (*cloudresourcemanager.Policy, error) {
+func (m *MockClient) SetIamPolicy(ctx context.Context, setIamPolicyRequest *cloudresourcemanager.SetIamPolicyRequest) (*cloudresourcemanager.Policy, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "SetIamPolicy", setIamPolicyRequest)
+	ret := m.ctrl.Call(m, "SetIamPolicy", ctx, setIamPolicyRequest)
 	ret0, _ := ret[0].(*cloudresourcemanager.Policy)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // SetIamPolicy indicates an expected call of SetIamPolicy.
-func (mr *MockClientMockRecorder) SetIamPolicy(setIamPolicyRequest any) *gomock.Call {
+func (mr *MockClientMockRecorder) SetIamPolicy(ctx, setIamPolicyRequest any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetIamPolicy", reflect.TypeOf((*MockClient)(nil).SetIamPolicy), ctx, setIamPolicyRequest)
+}
+
+// SetServiceAccountIamPolicy mocks base method.
+func (m *MockClient) SetServiceAccountIamPolicy(ctx context.Context, serviceAccountEmail string, policy *iam.Policy) (*iam.Policy, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetServiceAccountIamPolicy", ctx, serviceAccountEmail, policy)
+	ret0, _ := ret[0].(*iam.Policy)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SetServiceAccountIamPolicy indicates an expected call of SetServiceAccountIamPolicy.
+func (mr *MockClientMockRecorder) SetServiceAccountIamPolicy(ctx, serviceAccountEmail, policy any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetServiceAccountIamPolicy", reflect.TypeOf((*MockClient)(nil).SetServiceAccountIamPolicy), ctx, serviceAccountEmail, policy)
+}
+
+// UndeleteProject mocks base method.
+func (m *MockClient) UndeleteProject(ctx context.Context, projectID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UndeleteProject", ctx, projectID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UndeleteProject indicates an expected call of UndeleteProject.
+func (mr *MockClientMockRecorder) UndeleteProject(ctx, projectID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UndeleteProject", reflect.TypeOf((*MockClient)(nil).UndeleteProject), ctx, projectID)
+}
+
+// UndeleteWorkloadIdentityPool mocks base method.
+func (m *MockClient) UndeleteWorkloadIdentityPool(ctx context.Context, projectID, poolID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UndeleteWorkloadIdentityPool", ctx, projectID, poolID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UndeleteWorkloadIdentityPool indicates an expected call of UndeleteWorkloadIdentityPool.
+func (mr *MockClientMockRecorder) UndeleteWorkloadIdentityPool(ctx, projectID, poolID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UndeleteWorkloadIdentityPool", reflect.TypeOf((*MockClient)(nil).UndeleteWorkloadIdentityPool), ctx, projectID, poolID)
+}
+
+// UpdateProjectLabels mocks base method.
+func (m *MockClient) UpdateProjectLabels(ctx context.Context, projectID string, labels map[string]string, remove []string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateProjectLabels", ctx, projectID, labels, remove)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateProjectLabels indicates an expected call of UpdateProjectLabels.
+func (mr *MockClientMockRecorder) UpdateProjectLabels(ctx, projectID, labels, remove any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateProjectLabels", reflect.TypeOf((*MockClient)(nil).UpdateProjectLabels), ctx, projectID, labels, remove)
+}
+
+// UpdateWorkloadIdentityPoolProvider mocks base method.
+func (m *MockClient) UpdateWorkloadIdentityPoolProvider(ctx context.Context, projectID, poolID, providerID string, provider *iam.WorkloadIdentityPoolProvider, updateMask string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateWorkloadIdentityPoolProvider", ctx, projectID, poolID, providerID, provider, updateMask)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateWorkloadIdentityPoolProvider indicates an expected call of UpdateWorkloadIdentityPoolProvider.
+func (mr *MockClientMockRecorder) UpdateWorkloadIdentityPoolProvider(ctx, projectID, poolID, providerID, provider, updateMask any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetIamPolicy", reflect.TypeOf((*MockClient)(nil).SetIamPolicy), setIamPolicyRequest)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateWorkloadIdentityPoolProvider", reflect.TypeOf((*MockClient)(nil).UpdateWorkloadIdentityPoolProvider), ctx, projectID, poolID, providerID, provider, updateMask)
 }
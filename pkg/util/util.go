@@ -2,8 +2,12 @@ package util
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
 	"fmt"
 	"reflect"
+	"time"
 
 	"google.golang.org/api/cloudresourcemanager/v1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -11,6 +15,7 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	kubetypes "k8s.io/apimachinery/pkg/types"
+	utilrand "k8s.io/apimachinery/pkg/util/rand"
 )
 
 // IamMemberType represents different type of IAM members.
@@ -22,18 +27,18 @@ const (
 )
 
 // SecretExists returns a boolean to the caller based on the secretName and namespace args.
-func SecretExists(kubeClient client.Client, secretName, namespace string) bool {
+func SecretExists(ctx context.Context, kubeClient client.Client, secretName, namespace string) bool {
 	s := &corev1.Secret{}
 
-	err := kubeClient.Get(context.TODO(), kubetypes.NamespacedName{Name: secretName, Namespace: namespace}, s)
+	err := kubeClient.Get(ctx, kubetypes.NamespacedName{Name: secretName, Namespace: namespace}, s)
 	return err == nil
 }
 
 // GetSecret returns a secret based on a secretName and namespace.
-func GetSecret(kubeClient client.Client, secretName, namespace string) (*corev1.Secret, error) {
+func GetSecret(ctx context.Context, kubeClient client.Client, secretName, namespace string) (*corev1.Secret, error) {
 	s := &corev1.Secret{}
 
-	err := kubeClient.Get(context.TODO(), kubetypes.NamespacedName{Name: secretName, Namespace: namespace}, s)
+	err := kubeClient.Get(ctx, kubetypes.NamespacedName{Name: secretName, Namespace: namespace}, s)
 
 	if err != nil {
 		return &corev1.Secret{}, err
@@ -41,6 +46,24 @@ func GetSecret(kubeClient client.Client, secretName, namespace string) (*corev1.
 	return s, nil
 }
 
+// ManagedServiceAccountNamePrefix is the prefix of the generated names of the managed service accounts
+const ManagedServiceAccountNamePrefix = "osd-managed-admin"
+
+// managedServiceAccountNameSuffixLength keeps the generated names within the 30 characters GCP allows
+const managedServiceAccountNameSuffixLength = 8
+
+// NewManagedServiceAccountName returns a name for the managed service account of a project, with a random suffix
+func NewManagedServiceAccountName() string {
+	return ManagedServiceAccountNamePrefix + "-" + utilrand.String(managedServiceAccountNameSuffixLength)
+}
+
+// ManagedServiceAccountName returns the name of the managed service account of the project of a ProjectClaim,
+// with a suffix derived from the UID of the claim so the name is the same however often it is generated
+func ManagedServiceAccountName(claimUID kubetypes.UID) string {
+	sum := sha256.Sum256([]byte(claimUID))
+	return ManagedServiceAccountNamePrefix + "-" + hex.EncodeToString(sum[:])[:managedServiceAccountNameSuffixLength]
+}
+
 // NewGCPSecretCR returns a Secret CR formatted for GCP for use in projectreference controller.
 func NewGCPSecretCR(creds string, namespacedNamed kubetypes.NamespacedName) *corev1.Secret {
 	return &corev1.Secret{
@@ -59,10 +82,31 @@ func NewGCPSecretCR(creds string, namespacedNamed kubetypes.NamespacedName) *cor
 	}
 }
 
+// NewExternalAccountCredentials returns an external_account credential config, which exchanges the OIDC token in tokenFile
+// with the workload identity provider for a token of the service account serviceAccountEmail.
+// provider is the resource name of the provider, e.g. projects/123/locations/global/workloadIdentityPools/pool/providers/oidc.
+func NewExternalAccountCredentials(provider, serviceAccountEmail, tokenFile string) (string, error) {
+	creds, err := json.Marshal(map[string]interface{}{
+		"type":                              "external_account",
+		"audience":                          "//iam.googleapis.com/" + provider,
+		"subject_token_type":                "urn:ietf:params:oauth:token-type:jwt",
+		"token_url":                         "https://sts.googleapis.com/v1/token",
+		"service_account_impersonation_url": fmt.Sprintf("https://iamcredentials.googleapis.com/v1/projects/-/serviceAccounts/%s:generateAccessToken", serviceAccountEmail),
+		"credential_source": map[string]interface{}{
+			"file":   tokenFile,
+			"format": map[string]string{"type": "text"},
+		},
+	})
+	if err != nil {
+		return "", err
+	}
+	return string(creds), nil
+}
+
 // GetGCPCredentialsFromSecret extracts the gcp credentials from a secret. return value is a bytearray
-func GetGCPCredentialsFromSecret(kubeClient client.Client, namespace, name string) ([]byte, error) {
+func GetGCPCredentialsFromSecret(ctx context.Context, kubeClient client.Client, namespace, name string) ([]byte, error) {
 	secret := &corev1.Secret{}
-	err := kubeClient.Get(context.TODO(),
+	err := kubeClient.Get(ctx,
 		kubetypes.NamespacedName{
 			Namespace: namespace,
 			Name:      name,
@@ -71,6 +115,11 @@ func GetGCPCredentialsFromSecret(kubeClient client.Client, namespace, name strin
 	if err != nil {
 		return []byte{}, fmt.Errorf("GetGCPCredentialsFromSecret.Get %v", err)
 	}
+	return GCPCredentialsFromSecret(secret)
+}
+
+// GCPCredentialsFromSecret returns the gcp credentials stored in secret
+func GCPCredentialsFromSecret(secret *corev1.Secret) ([]byte, error) {
 	var osServiceAccountJSON []byte
 	var ok bool
 	osServiceAccountJSON, ok = secret.Data["osServiceAccount.json"]
@@ -79,19 +128,23 @@ func GetGCPCredentialsFromSecret(kubeClient client.Client, namespace, name strin
 	}
 	if !ok {
 		return []byte{}, fmt.Errorf("GCP credentials secret %v did not contain key %v",
-			name, "{osServiceAccount,key}.json")
+			secret.Name, "{osServiceAccount,key}.json")
 	}
 
 	return osServiceAccountJSON, nil
 }
 
-func RemoveOrUpdateBinding(existingBindings []*cloudresourcemanager.Binding, serviceAccountEmail string, memberType IamMemberType) ([]*cloudresourcemanager.Binding, bool) {
-	prefix := "serviceAccount:"
+// IamMember returns the IAM policy member for email
+func IamMember(email string, memberType IamMemberType) string {
 	if memberType == GoogleGroup {
-		prefix = "group:"
+		return "group:" + email
 	}
+	return "serviceAccount:" + email
+}
+
+func RemoveOrUpdateBinding(existingBindings []*cloudresourcemanager.Binding, serviceAccountEmail string, memberType IamMemberType) ([]*cloudresourcemanager.Binding, bool) {
 	modified := false
-	memberToRemove := prefix + serviceAccountEmail
+	memberToRemove := IamMember(serviceAccountEmail, memberType)
 	for i, binding := range existingBindings {
 		for index, v := range binding.Members {
 			if v == memberToRemove {
@@ -106,6 +159,26 @@ func RemoveOrUpdateBinding(existingBindings []*cloudresourcemanager.Binding, ser
 	return existingBindings, modified
 }
 
+// RemoveRoles removes the member from the bindings of roles, and drops the bindings left without members.
+// It returns true if any binding was modified.
+func RemoveRoles(existingBindings []*cloudresourcemanager.Binding, roles []string, email string, memberType IamMemberType) ([]*cloudresourcemanager.Binding, bool) {
+	member := IamMember(email, memberType)
+	modified := false
+	var result []*cloudresourcemanager.Binding
+	for _, binding := range existingBindings {
+		if Contains(roles, binding.Role) {
+			if exist, _ := InArray(member, binding.Members); exist {
+				modified = true
+				binding = &cloudresourcemanager.Binding{Role: binding.Role, Members: Filter(binding.Members, member)}
+			}
+		}
+		if len(binding.Members) > 0 {
+			result = append(result, binding)
+		}
+	}
+	return result, modified
+}
+
 // AddOrUpdateBinding checks if a binding from a map of bindings whose keys are the binding.Role exists in a list and if so it appends any new members to that binding.
 // If the required binding does not exist it creates a new binding for the role
 // it returns a []*cloudresourcemanager.Binding that contains all the previous bindings and the new ones if no new bindings are required it returns false
@@ -150,14 +223,10 @@ func AddOrUpdateBinding(existingBindings []*cloudresourcemanager.Binding, requir
 
 // roleBindingMap returns a map of requiredBindings role bindings for the added members
 func rolebindingMap(roles []string, member string, memberType IamMemberType) map[string]cloudresourcemanager.Binding {
-	prefix := "serviceAccount:"
-	if memberType == GoogleGroup {
-		prefix = "group:"
-	}
 	requiredBindings := make(map[string]cloudresourcemanager.Binding)
 	for _, role := range roles {
 		requiredBindings[role] = cloudresourcemanager.Binding{
-			Members: []string{prefix + member},
+			Members: []string{IamMember(member, memberType)},
 			Role:    role,
 		}
 	}
@@ -204,3 +273,15 @@ func Filter(list []string, strToFilter string) (newList []string) {
 	}
 	return
 }
+
+// Sleep pauses for d, or until ctx is done in which case it returns the context error.
+func Sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
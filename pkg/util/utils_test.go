@@ -1,9 +1,12 @@
-package util
+package util_test
 
 import (
+	"context"
 	"fmt"
 	"testing"
+	"time"
 
+	"github.com/openshift/gcp-project-operator/pkg/util"
 	"github.com/openshift/gcp-project-operator/pkg/util/errors"
 	"github.com/stretchr/testify/assert"
 	"google.golang.org/api/cloudresourcemanager/v1"
@@ -46,7 +49,7 @@ func TestSecretExists(t *testing.T) {
 		t.Run(test.name, func(t *testing.T) {
 			mocks := builders.SetupDefaultMocks(t, test.localObjects)
 
-			result := SecretExists(mocks.FakeKubeClient, test.secretName, test.secretNamespace)
+			result := util.SecretExists(context.TODO(), mocks.FakeKubeClient, test.secretName, test.secretNamespace)
 			assert.Equal(t, test.expectedResult, result)
 		})
 	}
@@ -99,7 +102,7 @@ func TestGetSecret(t *testing.T) {
 		t.Run(test.name, func(t *testing.T) {
 			mocks := builders.SetupDefaultMocks(t, test.localObjects)
 
-			result, err := GetSecret(mocks.FakeKubeClient, test.secretName, test.secretNamespace)
+			result, err := util.GetSecret(context.TODO(), mocks.FakeKubeClient, test.secretName, test.secretNamespace)
 
 			if test.expectedErr {
 				assert.Error(t, err)
@@ -179,7 +182,7 @@ func TestGetGCPCredentialsFromSecret(t *testing.T) {
 		t.Run(test.name, func(t *testing.T) {
 			mocks := builders.SetupDefaultMocks(t, test.localObjects)
 
-			result, err := GetGCPCredentialsFromSecret(mocks.FakeKubeClient, test.secretNamespace, "testCreds")
+			result, err := util.GetGCPCredentialsFromSecret(context.TODO(), mocks.FakeKubeClient, test.secretNamespace, "testCreds")
 
 			if test.expectedErr != nil {
 				assert.Error(t, err)
@@ -203,7 +206,7 @@ func TestRemoveOrUpdateBinding(t *testing.T) {
 	tests := []struct {
 		name               string
 		UserToDelete       string
-		UserType           IamMemberType
+		UserType           util.IamMemberType
 		inputBindings      []*cloudresourcemanager.Binding
 		expectedBindings   []*cloudresourcemanager.Binding
 		expectModification bool
@@ -211,7 +214,7 @@ func TestRemoveOrUpdateBinding(t *testing.T) {
 		{
 			name:         "User Group Binding Exists",
 			UserToDelete: "CCSConsoleAccess",
-			UserType:     GoogleGroup,
+			UserType:     util.GoogleGroup,
 			inputBindings: []*cloudresourcemanager.Binding{
 				{Role: "role/admin", Members: []string{"group:CCSConsoleAccess", "serviceAccount:customerAcc"}},
 				{Role: "role/viewer", Members: []string{"group:CCSReadOnlyConsoleAccess", "serviceAccount:customerAcc"}},
@@ -227,7 +230,7 @@ func TestRemoveOrUpdateBinding(t *testing.T) {
 		{
 			name:         "User Serviceaccount Binding Exists",
 			UserToDelete: "CCSReadOnlyConsoleAccess",
-			UserType:     ServiceAccount,
+			UserType:     util.ServiceAccount,
 			inputBindings: []*cloudresourcemanager.Binding{
 				{Role: "role/admin", Members: []string{"group:CCSConsoleAccess", "serviceAccount:customerAcc"}},
 				{Role: "role/viewer", Members: []string{"serviceAccount:CCSReadOnlyConsoleAccess", "serviceAccount:customerAcc"}},
@@ -243,7 +246,7 @@ func TestRemoveOrUpdateBinding(t *testing.T) {
 		{
 			name:         "User Binding doesn't Exists",
 			UserToDelete: "CCSConsoleAccess",
-			UserType:     GoogleGroup,
+			UserType:     util.GoogleGroup,
 			inputBindings: []*cloudresourcemanager.Binding{
 				{Role: "role/admin", Members: []string{"serviceAccount:customerAcc"}},
 				{Role: "role/dev", Members: []string{"group:customerGroup"}},
@@ -258,10 +261,83 @@ func TestRemoveOrUpdateBinding(t *testing.T) {
 
 	for _, test := range tests {
 		t.Run(test.name, func(t *testing.T) {
-			result, modified := RemoveOrUpdateBinding(test.inputBindings, test.UserToDelete, test.UserType)
+			result, modified := util.RemoveOrUpdateBinding(test.inputBindings, test.UserToDelete, test.UserType)
 			assert.Equal(t, modified, test.expectModification)
 			assert.Equal(t, result, test.expectedBindings)
 		})
 	}
 
 }
+
+func TestRemoveRoles(t *testing.T) {
+	tests := []struct {
+		name               string
+		roles              []string
+		inputBindings      []*cloudresourcemanager.Binding
+		expectedBindings   []*cloudresourcemanager.Binding
+		expectModification bool
+	}{
+		{
+			name:  "Member is removed from the listed roles only",
+			roles: []string{"role/admin", "role/viewer"},
+			inputBindings: []*cloudresourcemanager.Binding{
+				{Role: "role/admin", Members: []string{"serviceAccount:osd", "serviceAccount:customerAcc"}},
+				{Role: "role/viewer", Members: []string{"serviceAccount:osd"}},
+				{Role: "role/dev", Members: []string{"serviceAccount:osd"}},
+			},
+			expectedBindings: []*cloudresourcemanager.Binding{
+				{Role: "role/admin", Members: []string{"serviceAccount:customerAcc"}},
+				{Role: "role/dev", Members: []string{"serviceAccount:osd"}},
+			},
+			expectModification: true,
+		},
+		{
+			name:  "Member has none of the roles",
+			roles: []string{"role/admin"},
+			inputBindings: []*cloudresourcemanager.Binding{
+				{Role: "role/admin", Members: []string{"group:osd"}},
+			},
+			expectedBindings: []*cloudresourcemanager.Binding{
+				{Role: "role/admin", Members: []string{"group:osd"}},
+			},
+			expectModification: false,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			result, modified := util.RemoveRoles(test.inputBindings, test.roles, "osd", util.ServiceAccount)
+			assert.Equal(t, test.expectModification, modified)
+			assert.Equal(t, test.expectedBindings, result)
+		})
+	}
+}
+
+func TestSleep(t *testing.T) {
+	assert.NoError(t, util.Sleep(context.TODO(), time.Millisecond))
+
+	ctx, cancel := context.WithCancel(context.TODO())
+	cancel()
+	start := time.Now()
+	assert.ErrorIs(t, util.Sleep(ctx, time.Minute), context.Canceled)
+	assert.Less(t, time.Since(start), time.Second)
+}
+
+func TestManagedServiceAccountName(t *testing.T) {
+	name := util.ManagedServiceAccountName("6a1f1c6e-8d2b-4b8e-9f4c-2f0e5a7d3c1b")
+	assert.Regexp(t, `^osd-managed-admin-[0-9a-f]{8}$`, name)
+	assert.Equal(t, name, util.ManagedServiceAccountName("6a1f1c6e-8d2b-4b8e-9f4c-2f0e5a7d3c1b"))
+	assert.NotEqual(t, name, util.ManagedServiceAccountName("0b9e7c2d-3f4a-4c1e-8a6b-5d2e1f0c9a87"))
+}
+
+func TestNewExternalAccountCredentials(t *testing.T) {
+	creds, err := util.NewExternalAccountCredentials("projects/123/locations/global/workloadIdentityPools/pool/providers/oidc", "sa@project.iam.gserviceaccount.com", "/token")
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{
+		"type": "external_account",
+		"audience": "//iam.googleapis.com/projects/123/locations/global/workloadIdentityPools/pool/providers/oidc",
+		"subject_token_type": "urn:ietf:params:oauth:token-type:jwt",
+		"token_url": "https://sts.googleapis.com/v1/token",
+		"service_account_impersonation_url": "https://iamcredentials.googleapis.com/v1/projects/-/serviceAccounts/sa@project.iam.gserviceaccount.com:generateAccessToken",
+		"credential_source": {"file": "/token", "format": {"type": "text"}}
+	}`, creds)
+}
@@ -0,0 +1,24 @@
+package webhooks
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/conversion"
+
+	gcpv1alpha1 "github.com/openshift/gcp-project-operator/api/v1alpha1"
+	gcpv1beta1 "github.com/openshift/gcp-project-operator/api/v1beta1"
+)
+
+func TestConvertible(t *testing.T) {
+	scheme := runtime.NewScheme()
+	assert.NoError(t, gcpv1alpha1.AddToScheme(scheme))
+	assert.NoError(t, gcpv1beta1.AddToScheme(scheme))
+
+	for _, obj := range []runtime.Object{&gcpv1beta1.ProjectClaim{}, &gcpv1beta1.ProjectReference{}} {
+		convertible, err := conversion.IsConvertible(scheme, obj)
+		assert.NoError(t, err)
+		assert.True(t, convertible, "%T", obj)
+	}
+}
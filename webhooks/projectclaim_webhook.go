@@ -0,0 +1,182 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhooks
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"regexp"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	gcpv1alpha1 "github.com/openshift/gcp-project-operator/api/v1alpha1"
+	"github.com/openshift/gcp-project-operator/pkg/configmap"
+	"github.com/openshift/gcp-project-operator/pkg/util"
+	operrors "github.com/openshift/gcp-project-operator/pkg/util/errors"
+)
+
+// regionName matches GCP region names, e.g. us-east1 or northamerica-northeast2
+var regionName = regexp.MustCompile(`^[a-z]+-[a-z]+[0-9]+$`)
+
+//+kubebuilder:webhook:path=/mutate-gcp-managed-openshift-io-v1alpha1-projectclaim,mutating=true,failurePolicy=fail,sideEffects=None,groups=gcp.managed.openshift.io,resources=projectclaims,verbs=create,versions=v1alpha1,name=mprojectclaim.gcp.managed.openshift.io,admissionReviewVersions=v1
+
+// ProjectClaimDefaulter fills in the defaults of new ProjectClaims, which the controllers would otherwise write in extra updates
+type ProjectClaimDefaulter struct{}
+
+// SetupWithManager registers the defaulting webhook with the Manager.
+func (d *ProjectClaimDefaulter) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr, &gcpv1alpha1.ProjectClaim{}).
+		WithDefaulter(d).
+		Complete()
+}
+
+// Default sets the namespace of the GCPCredentialSecret and the link to the ProjectReference
+func (d *ProjectClaimDefaulter) Default(ctx context.Context, claim *gcpv1alpha1.ProjectClaim) error {
+	// the namespace may only be part of the request URL
+	if claim.Namespace == "" {
+		if req, err := admission.RequestFromContext(ctx); err == nil {
+			claim.Namespace = req.Namespace
+		}
+	}
+
+	if claim.Spec.GCPCredentialSecret.Namespace == "" {
+		claim.Spec.GCPCredentialSecret.Namespace = claim.Namespace
+	}
+	// names generated from generateName are only known after admission
+	if claim.Spec.ProjectReferenceCRLink == (gcpv1alpha1.NamespacedName{}) && claim.Name != "" {
+		claim.Spec.ProjectReferenceCRLink = claim.ProjectReferenceLink()
+	}
+	return nil
+}
+
+//+kubebuilder:webhook:path=/validate-gcp-managed-openshift-io-v1alpha1-projectclaim,mutating=false,failurePolicy=fail,sideEffects=None,groups=gcp.managed.openshift.io,resources=projectclaims,verbs=create;update,versions=v1alpha1,name=vprojectclaim.gcp.managed.openshift.io,admissionReviewVersions=v1
+//+kubebuilder:webhook:path=/validate-gcp-managed-openshift-io-v1alpha1-projectclaim,mutating=false,failurePolicy=ignore,sideEffects=None,groups=gcp.managed.openshift.io,resources=projectclaims,verbs=delete,versions=v1alpha1,name=vprojectclaim-delete.gcp.managed.openshift.io,admissionReviewVersions=v1
+
+// ProjectClaimValidator rejects invalid ProjectClaims at admission time, instead of the reconcile loop flagging them later
+type ProjectClaimValidator struct {
+	Client client.Client
+}
+
+// SetupWithManager registers the validating webhook with the Manager.
+func (v *ProjectClaimValidator) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr, &gcpv1alpha1.ProjectClaim{}).
+		WithValidator(v).
+		Complete()
+}
+
+// ValidateCreate validates the spec of a new ProjectClaim
+func (v *ProjectClaimValidator) ValidateCreate(ctx context.Context, claim *gcpv1alpha1.ProjectClaim) (admission.Warnings, error) {
+	allErrs := validateProjectClaimSpec(claim)
+
+	// CCS projects exist already, their region isn't checked
+	if !claim.Spec.CCS && regionName.MatchString(claim.Spec.Region) {
+		operatorConfigMap, err := configmap.GetOperatorConfigMap(ctx, v.Client)
+		if err != nil {
+			return nil, operrors.Wrap(err, "could not find the OperatorConfigMap")
+		}
+		if util.Contains(operatorConfigMap.DisabledRegions, claim.Spec.Region) {
+			allErrs = append(allErrs, field.Forbidden(field.NewPath("spec", "region"), operrors.ErrRegionNotSupported.Error()))
+		}
+	}
+
+	return nil, projectClaimInvalid(claim, allErrs)
+}
+
+// ValidateUpdate validates changes to the spec of a ProjectClaim.
+// Updates that leave the spec alone, e.g. of finalizers, are always allowed so invalid claims can still be deleted.
+func (v *ProjectClaimValidator) ValidateUpdate(ctx context.Context, oldClaim, claim *gcpv1alpha1.ProjectClaim) (admission.Warnings, error) {
+	if reflect.DeepEqual(oldClaim.Spec, claim.Spec) {
+		return nil, nil
+	}
+
+	specPath := field.NewPath("spec")
+	allErrs := validateProjectClaimSpec(claim)
+	allErrs = append(allErrs, validateImmutable(specPath.Child("region"), oldClaim.Spec.Region, claim.Spec.Region)...)
+	allErrs = append(allErrs, validateImmutable(specPath.Child("ccs"), oldClaim.Spec.CCS, claim.Spec.CCS)...)
+	allErrs = append(allErrs, validateImmutable(specPath.Child("legalEntity"), oldClaim.Spec.LegalEntity, claim.Spec.LegalEntity)...)
+	// a project the operator created can't be adopted afterwards, nor the other way round
+	allErrs = append(allErrs, validateImmutable(specPath.Child("adoptProject"), oldClaim.Spec.AdoptProject, claim.Spec.AdoptProject)...)
+	// the operator fills in the ID of the project it created
+	if oldClaim.Spec.GCPProjectID != "" {
+		allErrs = append(allErrs, validateImmutable(specPath.Child("gcpProjectID"), oldClaim.Spec.GCPProjectID, claim.Spec.GCPProjectID)...)
+	}
+
+	return nil, projectClaimInvalid(claim, allErrs)
+}
+
+// ValidateDelete rejects the deletion of ProjectClaims protected by the DeletionProtectionAnnotation.
+// Its webhook ignores failures so claims can be deleted while the operator is down, the operator keeps
+// the projects of protected claims when cleaning up anyway.
+func (v *ProjectClaimValidator) ValidateDelete(ctx context.Context, claim *gcpv1alpha1.ProjectClaim) (admission.Warnings, error) {
+	if claim.IsDeletionProtected() {
+		return nil, apierrors.NewForbidden(gcpv1alpha1.GroupVersion.WithResource("projectclaims").GroupResource(), claim.Name,
+			fmt.Errorf("deletion is blocked by the %s annotation, remove it to delete the ProjectClaim", gcpv1alpha1.DeletionProtectionAnnotation))
+	}
+	return nil, nil
+}
+
+func validateProjectClaimSpec(claim *gcpv1alpha1.ProjectClaim) field.ErrorList {
+	specPath := field.NewPath("spec")
+	allErrs := field.ErrorList{}
+
+	if err := claim.Validate(); err != nil {
+		switch {
+		case errors.Is(err, gcpv1alpha1.ErrCCSSecretRefNamespaceMismatch):
+			allErrs = append(allErrs, field.Forbidden(specPath.Child("ccsSecretRef", "namespace"), err.Error()))
+		case errors.Is(err, gcpv1alpha1.ErrGCPCredentialSecretNamespaceMismatch):
+			allErrs = append(allErrs, field.Forbidden(specPath.Child("gcpCredentialSecret", "namespace"), err.Error()))
+		case errors.Is(err, gcpv1alpha1.ErrWorkloadIdentityIssuerMissing):
+			allErrs = append(allErrs, field.Required(specPath.Child("workloadIdentity", "issuerURI"), err.Error()))
+		case errors.Is(err, gcpv1alpha1.ErrAdoptedProjectIDMissing):
+			allErrs = append(allErrs, field.Required(specPath.Child("gcpProjectID"), err.Error()))
+		case errors.Is(err, gcpv1alpha1.ErrAdoptCCSProject):
+			allErrs = append(allErrs, field.Forbidden(specPath.Child("adoptProject"), err.Error()))
+		default:
+			allErrs = append(allErrs, field.Invalid(specPath, claim.Spec, err.Error()))
+		}
+	}
+
+	if claim.Spec.CCS && claim.Spec.CCSSecretRef.Name == "" {
+		allErrs = append(allErrs, field.Required(specPath.Child("ccsSecretRef", "name"), "CCS projects are configured with the credentials of the ccsSecretRef"))
+	}
+
+	if !regionName.MatchString(claim.Spec.Region) {
+		allErrs = append(allErrs, field.Invalid(specPath.Child("region"), claim.Spec.Region, "must be a GCP region name, e.g. us-east1"))
+	}
+
+	return allErrs
+}
+
+func validateImmutable(path *field.Path, oldValue, newValue interface{}) field.ErrorList {
+	if reflect.DeepEqual(oldValue, newValue) {
+		return nil
+	}
+	return field.ErrorList{field.Invalid(path, newValue, "field is immutable")}
+}
+
+func projectClaimInvalid(claim *gcpv1alpha1.ProjectClaim, allErrs field.ErrorList) error {
+	if len(allErrs) == 0 {
+		return nil
+	}
+	return apierrors.NewInvalid(gcpv1alpha1.GroupVersion.WithKind("ProjectClaim").GroupKind(), claim.Name, allErrs)
+}
@@ -0,0 +1,302 @@
+package webhooks
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/runtime"
+	fakekubeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	api "github.com/openshift/gcp-project-operator/api/v1alpha1"
+	"github.com/openshift/gcp-project-operator/pkg/configmap"
+	builders "github.com/openshift/gcp-project-operator/pkg/util/mocks/structs"
+	admissionv1 "k8s.io/api/admission/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func newProjectClaimValidator(config string) *ProjectClaimValidator {
+	objects := []runtime.Object{}
+	if config != "" {
+		objects = append(objects, &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: configmap.OperatorConfigMapName, Namespace: configmap.OperatorConfigMapNamespace},
+			Data:       map[string]string{configmap.OperatorConfigMapKey: config},
+		})
+	}
+	return &ProjectClaimValidator{Client: fakekubeclient.NewClientBuilder().WithRuntimeObjects(objects...).Build()}
+}
+
+func TestProjectClaimDefault(t *testing.T) {
+	tests := []struct {
+		name         string
+		mutate       func(*api.ProjectClaim)
+		requestNS    string
+		expectedSpec func(*api.ProjectClaim) api.ProjectClaimSpec
+	}{
+		{
+			name: "defaults are filled in",
+			expectedSpec: func(claim *api.ProjectClaim) api.ProjectClaimSpec {
+				spec := claim.Spec
+				spec.GCPCredentialSecret.Namespace = claim.Namespace
+				spec.ProjectReferenceCRLink = api.NamespacedName{Name: "fakeNamespace-fakeProjectClaim", Namespace: api.ProjectReferenceNamespace}
+				return spec
+			},
+		},
+		{
+			name:      "namespace of the request",
+			mutate:    func(claim *api.ProjectClaim) { claim.Namespace = "" },
+			requestNS: "tenant",
+			expectedSpec: func(claim *api.ProjectClaim) api.ProjectClaimSpec {
+				spec := claim.Spec
+				spec.GCPCredentialSecret.Namespace = "tenant"
+				spec.ProjectReferenceCRLink = api.NamespacedName{Name: "tenant-fakeProjectClaim", Namespace: api.ProjectReferenceNamespace}
+				return spec
+			},
+		},
+		{
+			name: "set fields are kept",
+			mutate: func(claim *api.ProjectClaim) {
+				claim.Spec.GCPCredentialSecret.Namespace = "other"
+				claim.Spec.ProjectReferenceCRLink = api.NamespacedName{Name: "other", Namespace: "other"}
+			},
+			expectedSpec: func(claim *api.ProjectClaim) api.ProjectClaimSpec { return claim.Spec },
+		},
+		{
+			name:   "generated name",
+			mutate: func(claim *api.ProjectClaim) { claim.Name = "" },
+			expectedSpec: func(claim *api.ProjectClaim) api.ProjectClaimSpec {
+				spec := claim.Spec
+				spec.GCPCredentialSecret.Namespace = claim.Namespace
+				return spec
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			claim := builders.NewProjectClaimBuilder().GetProjectClaim()
+			claim.Spec.GCPCredentialSecret.Name = "gcp-secret"
+			if test.mutate != nil {
+				test.mutate(claim)
+			}
+			expected := test.expectedSpec(claim.DeepCopy())
+
+			ctx := admission.NewContextWithRequest(context.TODO(), admission.Request{AdmissionRequest: admissionv1.AdmissionRequest{Namespace: test.requestNS}})
+			assert.NoError(t, (&ProjectClaimDefaulter{}).Default(ctx, claim))
+			assert.Equal(t, expected, claim.Spec)
+		})
+	}
+}
+
+func TestProjectClaimValidateCreate(t *testing.T) {
+	const config = "billingAccount: ABCDEF-123456\nparentFolderID: \"123456789\"\ndisabledRegions:\n- us-west2\n"
+
+	tests := []struct {
+		name          string
+		config        string
+		mutate        func(*api.ProjectClaim)
+		expectedField string
+	}{
+		{
+			name:   "valid claim",
+			config: config,
+			mutate: func(*api.ProjectClaim) {},
+		},
+		{
+			name:   "valid CCS claim",
+			config: config,
+			mutate: func(claim *api.ProjectClaim) {
+				claim.Spec.CCS = true
+				claim.Spec.CCSSecretRef = api.NamespacedName{Name: "ccs-secret", Namespace: claim.Namespace}
+			},
+		},
+		{
+			name:   "GCPCredentialSecret in another namespace",
+			config: config,
+			mutate: func(claim *api.ProjectClaim) {
+				claim.Spec.GCPCredentialSecret = api.NamespacedName{Name: "gcp-secret", Namespace: "other"}
+			},
+			expectedField: "spec.gcpCredentialSecret.namespace",
+		},
+		{
+			name:   "CCS claim without CCSSecretRef",
+			config: config,
+			mutate: func(claim *api.ProjectClaim) {
+				claim.Spec.CCS = true
+			},
+			expectedField: "spec.ccsSecretRef.name",
+		},
+		{
+			name:   "malformed region",
+			config: config,
+			mutate: func(claim *api.ProjectClaim) {
+				claim.Spec.Region = "us-east1-b"
+			},
+			expectedField: "spec.region",
+		},
+		{
+			name:   "disabled region",
+			config: config,
+			mutate: func(claim *api.ProjectClaim) {
+				claim.Spec.Region = "us-west2"
+			},
+			expectedField: "spec.region",
+		},
+		{
+			name:   "adopting claim without project ID",
+			config: config,
+			mutate: func(claim *api.ProjectClaim) {
+				claim.Spec.AdoptProject = true
+			},
+			expectedField: "spec.gcpProjectID",
+		},
+		{
+			name:   "adopting CCS claim",
+			config: config,
+			mutate: func(claim *api.ProjectClaim) {
+				claim.Spec.AdoptProject = true
+				claim.Spec.GCPProjectID = "customer-project"
+				claim.Spec.CCS = true
+				claim.Spec.CCSSecretRef = api.NamespacedName{Name: "ccs-secret", Namespace: claim.Namespace}
+			},
+			expectedField: "spec.adoptProject",
+		},
+		{
+			name: "disabled region of a CCS claim",
+			mutate: func(claim *api.ProjectClaim) {
+				claim.Spec.Region = "us-west2"
+				claim.Spec.CCS = true
+				claim.Spec.CCSSecretRef = api.NamespacedName{Name: "ccs-secret", Namespace: claim.Namespace}
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			claim := builders.NewProjectClaimBuilder().GetProjectClaim()
+			test.mutate(claim)
+
+			_, err := newProjectClaimValidator(test.config).ValidateCreate(context.TODO(), claim)
+			assertInvalidField(t, err, test.expectedField)
+		})
+	}
+}
+
+func TestProjectClaimValidateCreateWithoutOperatorConfig(t *testing.T) {
+	claim := builders.NewProjectClaimBuilder().GetProjectClaim()
+	_, err := newProjectClaimValidator("").ValidateCreate(context.TODO(), claim)
+	assert.Error(t, err)
+}
+
+func TestProjectClaimValidateUpdate(t *testing.T) {
+	tests := []struct {
+		name          string
+		oldMutate     func(*api.ProjectClaim)
+		mutate        func(*api.ProjectClaim)
+		expectedField string
+	}{
+		{
+			name:   "operator sets the project ID",
+			mutate: func(claim *api.ProjectClaim) { claim.Spec.GCPProjectID = "o-12345678" },
+		},
+		{
+			name:          "project ID changes",
+			oldMutate:     func(claim *api.ProjectClaim) { claim.Spec.GCPProjectID = "o-12345678" },
+			mutate:        func(claim *api.ProjectClaim) { claim.Spec.GCPProjectID = "o-87654321" },
+			expectedField: "spec.gcpProjectID",
+		},
+		{
+			name:          "region changes",
+			mutate:        func(claim *api.ProjectClaim) { claim.Spec.Region = "europe-west4" },
+			expectedField: "spec.region",
+		},
+		{
+			name: "CCS changes",
+			mutate: func(claim *api.ProjectClaim) {
+				claim.Spec.CCS = true
+				claim.Spec.CCSSecretRef = api.NamespacedName{Name: "ccs-secret", Namespace: claim.Namespace}
+			},
+			expectedField: "spec.ccs",
+		},
+		{
+			name:          "legal entity changes",
+			mutate:        func(claim *api.ProjectClaim) { claim.Spec.LegalEntity.ID = "otherLegalEntityID" },
+			expectedField: "spec.legalEntity",
+		},
+		{
+			name: "project of a claim is adopted afterwards",
+			mutate: func(claim *api.ProjectClaim) {
+				claim.Spec.GCPProjectID = "existing-project"
+				claim.Spec.AdoptProject = true
+			},
+			expectedField: "spec.adoptProject",
+		},
+		{
+			name:      "finalizer of an invalid claim is removed",
+			oldMutate: func(claim *api.ProjectClaim) { claim.Spec.Region = "invalid"; claim.Finalizers = []string{"finalizer"} },
+			mutate:    func(claim *api.ProjectClaim) { claim.Spec.Region = "invalid" },
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			oldClaim := builders.NewProjectClaimBuilder().GetProjectClaim()
+			if test.oldMutate != nil {
+				test.oldMutate(oldClaim)
+			}
+			claim := oldClaim.DeepCopy()
+			claim.Finalizers = nil
+			test.mutate(claim)
+
+			// updates don't read the operator config
+			_, err := newProjectClaimValidator("").ValidateUpdate(context.TODO(), oldClaim, claim)
+			assertInvalidField(t, err, test.expectedField)
+		})
+	}
+}
+
+func TestProjectClaimValidateDelete(t *testing.T) {
+	tests := []struct {
+		name        string
+		annotations map[string]string
+		expectedErr bool
+	}{
+		{name: "unprotected claim"},
+		{name: "protected claim", annotations: map[string]string{api.DeletionProtectionAnnotation: "true"}, expectedErr: true},
+		{name: "protection disabled", annotations: map[string]string{api.DeletionProtectionAnnotation: "false"}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			claim := builders.NewProjectClaimBuilder().GetProjectClaim()
+			claim.Annotations = test.annotations
+
+			_, err := newProjectClaimValidator("").ValidateDelete(context.TODO(), claim)
+			if test.expectedErr {
+				assert.True(t, apierrors.IsForbidden(err), "expected a Forbidden error, got %v", err)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+// assertInvalidField asserts that err rejects expectedField, or that there is no error if expectedField is empty
+func assertInvalidField(t *testing.T, err error, expectedField string) {
+	t.Helper()
+	if expectedField == "" {
+		assert.NoError(t, err)
+		return
+	}
+	if !assert.True(t, apierrors.IsInvalid(err), "expected an Invalid error, got %v", err) {
+		return
+	}
+	fields := []string{}
+	for _, cause := range err.(*apierrors.StatusError).ErrStatus.Details.Causes {
+		fields = append(fields, cause.Field)
+	}
+	assert.Contains(t, fields, expectedField)
+}
@@ -0,0 +1,98 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhooks
+
+import (
+	"context"
+	"errors"
+	"reflect"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	gcpv1alpha1 "github.com/openshift/gcp-project-operator/api/v1alpha1"
+)
+
+//+kubebuilder:webhook:path=/validate-gcp-managed-openshift-io-v1alpha1-projectreference,mutating=false,failurePolicy=fail,sideEffects=None,groups=gcp.managed.openshift.io,resources=projectreferences,verbs=create;update,versions=v1alpha1,name=vprojectreference.gcp.managed.openshift.io,admissionReviewVersions=v1
+
+// ProjectReferenceValidator rejects invalid ProjectReferences at admission time
+type ProjectReferenceValidator struct{}
+
+// SetupWithManager registers the validating webhook with the Manager.
+func (v *ProjectReferenceValidator) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr, &gcpv1alpha1.ProjectReference{}).
+		WithValidator(v).
+		Complete()
+}
+
+// ValidateCreate validates the spec of a new ProjectReference
+func (v *ProjectReferenceValidator) ValidateCreate(ctx context.Context, reference *gcpv1alpha1.ProjectReference) (admission.Warnings, error) {
+	return nil, projectReferenceInvalid(reference, validateProjectReferenceSpec(reference))
+}
+
+// ValidateUpdate validates changes to the spec of a ProjectReference.
+// Updates that leave the spec alone, e.g. of finalizers, are always allowed so invalid references can still be deleted.
+func (v *ProjectReferenceValidator) ValidateUpdate(ctx context.Context, oldReference, reference *gcpv1alpha1.ProjectReference) (admission.Warnings, error) {
+	if reflect.DeepEqual(oldReference.Spec, reference.Spec) {
+		return nil, nil
+	}
+
+	specPath := field.NewPath("spec")
+	allErrs := validateProjectReferenceSpec(reference)
+	allErrs = append(allErrs, validateImmutable(specPath.Child("projectClaimCRLink"), oldReference.Spec.ProjectClaimCRLink, reference.Spec.ProjectClaimCRLink)...)
+	allErrs = append(allErrs, validateImmutable(specPath.Child("ccs"), oldReference.Spec.CCS, reference.Spec.CCS)...)
+	allErrs = append(allErrs, validateImmutable(specPath.Child("legalEntity"), oldReference.Spec.LegalEntity, reference.Spec.LegalEntity)...)
+	// the operator picks another ID when the generated one is taken, until the project is created
+	if oldReference.Spec.GCPProjectID != "" && (reference.Spec.GCPProjectID != "" || oldReference.Status.State == gcpv1alpha1.ProjectReferenceStatusReady) {
+		allErrs = append(allErrs, validateImmutable(specPath.Child("gcpProjectID"), oldReference.Spec.GCPProjectID, reference.Spec.GCPProjectID)...)
+	}
+
+	return nil, projectReferenceInvalid(reference, allErrs)
+}
+
+// ValidateDelete allows every deletion
+func (v *ProjectReferenceValidator) ValidateDelete(ctx context.Context, reference *gcpv1alpha1.ProjectReference) (admission.Warnings, error) {
+	return nil, nil
+}
+
+func validateProjectReferenceSpec(reference *gcpv1alpha1.ProjectReference) field.ErrorList {
+	specPath := field.NewPath("spec")
+	allErrs := field.ErrorList{}
+
+	if err := reference.Validate(); err != nil {
+		if errors.Is(err, gcpv1alpha1.ErrProjectRefCCSSecretRefNamespaceMismatch) {
+			allErrs = append(allErrs, field.Forbidden(specPath.Child("ccsSecretRef", "namespace"), err.Error()))
+		} else {
+			allErrs = append(allErrs, field.Invalid(specPath, reference.Spec, err.Error()))
+		}
+	}
+
+	if reference.Spec.CCS && reference.Spec.CCSSecretRef.Name == "" {
+		allErrs = append(allErrs, field.Required(specPath.Child("ccsSecretRef", "name"), "CCS projects are configured with the credentials of the ccsSecretRef"))
+	}
+
+	return allErrs
+}
+
+func projectReferenceInvalid(reference *gcpv1alpha1.ProjectReference, allErrs field.ErrorList) error {
+	if len(allErrs) == 0 {
+		return nil
+	}
+	return apierrors.NewInvalid(gcpv1alpha1.GroupVersion.WithKind("ProjectReference").GroupKind(), reference.Name, allErrs)
+}
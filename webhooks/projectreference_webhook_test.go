@@ -0,0 +1,105 @@
+package webhooks
+
+import (
+	"context"
+	"testing"
+
+	api "github.com/openshift/gcp-project-operator/api/v1alpha1"
+	builders "github.com/openshift/gcp-project-operator/pkg/util/mocks/structs"
+)
+
+func TestProjectReferenceValidateCreate(t *testing.T) {
+	tests := []struct {
+		name          string
+		mutate        func(*api.ProjectReference)
+		expectedField string
+	}{
+		{
+			name:   "valid reference",
+			mutate: func(*api.ProjectReference) {},
+		},
+		{
+			name: "CCSSecretRef in another namespace than the claim",
+			mutate: func(reference *api.ProjectReference) {
+				reference.Spec.CCS = true
+				reference.Spec.CCSSecretRef = api.NamespacedName{Name: "ccs-secret", Namespace: "other"}
+			},
+			expectedField: "spec.ccsSecretRef.namespace",
+		},
+		{
+			name: "CCS reference without CCSSecretRef",
+			mutate: func(reference *api.ProjectReference) {
+				reference.Spec.CCS = true
+			},
+			expectedField: "spec.ccsSecretRef.name",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			reference := builders.NewProjectReferenceBuilder().GetProjectReference()
+			test.mutate(reference)
+
+			_, err := (&ProjectReferenceValidator{}).ValidateCreate(context.TODO(), reference)
+			assertInvalidField(t, err, test.expectedField)
+		})
+	}
+}
+
+func TestProjectReferenceValidateUpdate(t *testing.T) {
+	tests := []struct {
+		name          string
+		oldMutate     func(*api.ProjectReference)
+		mutate        func(*api.ProjectReference)
+		expectedField string
+	}{
+		{
+			name:   "operator sets the project ID",
+			mutate: func(reference *api.ProjectReference) { reference.Spec.GCPProjectID = "o-12345678" },
+		},
+		{
+			name:      "operator clears a taken project ID",
+			oldMutate: func(reference *api.ProjectReference) { reference.Spec.GCPProjectID = "o-12345678" },
+			mutate:    func(reference *api.ProjectReference) { reference.Spec.GCPProjectID = "" },
+		},
+		{
+			name: "project ID of a ready project is cleared",
+			oldMutate: func(reference *api.ProjectReference) {
+				reference.Spec.GCPProjectID = "o-12345678"
+				reference.Status.State = api.ProjectReferenceStatusReady
+			},
+			mutate:        func(reference *api.ProjectReference) { reference.Spec.GCPProjectID = "" },
+			expectedField: "spec.gcpProjectID",
+		},
+		{
+			name:          "project ID changes",
+			oldMutate:     func(reference *api.ProjectReference) { reference.Spec.GCPProjectID = "o-12345678" },
+			mutate:        func(reference *api.ProjectReference) { reference.Spec.GCPProjectID = "o-87654321" },
+			expectedField: "spec.gcpProjectID",
+		},
+		{
+			name:          "claim link changes",
+			mutate:        func(reference *api.ProjectReference) { reference.Spec.ProjectClaimCRLink.Name = "other" },
+			expectedField: "spec.projectClaimCRLink",
+		},
+		{
+			name:          "legal entity changes",
+			mutate:        func(reference *api.ProjectReference) { reference.Spec.LegalEntity.Name = "other" },
+			expectedField: "spec.legalEntity",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			oldReference := builders.NewProjectReferenceBuilder().GetProjectReference()
+			if test.oldMutate != nil {
+				test.oldMutate(oldReference)
+			}
+			reference := oldReference.DeepCopy()
+			test.mutate(reference)
+
+			_, err := (&ProjectReferenceValidator{}).ValidateUpdate(context.TODO(), oldReference, reference)
+			assertInvalidField(t, err, test.expectedField)
+		})
+	}
+}